@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"lk/internal/config"
+	"lk/internal/repository"
+	"lk/internal/services"
+)
+
+// runCreateSuperAdmin реализует подкоманду "create-superadmin": создает первого
+// суперадминистратора в пустой базе. Пароль берется из флага -password или
+// переменной ADMIN_BOOTSTRAP_PASSWORD; если он не задан, генерируется временный,
+// который нужно сменить при первом входе.
+//
+// Пример: app create-superadmin -login root -name "Иванов Иван"
+func runCreateSuperAdmin(args []string) int {
+	fs := flag.NewFlagSet("create-superadmin", flag.ContinueOnError)
+	login := fs.String("login", "", "логин суперадминистратора")
+	fullName := fs.String("name", "", "ФИО суперадминистратора")
+	password := fs.String("password", os.Getenv("ADMIN_BOOTSTRAP_PASSWORD"), "пароль (не менее 8 символов)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *login == "" || *fullName == "" {
+		fmt.Fprintln(os.Stderr, "флаги -login и -name обязательны")
+		fs.Usage()
+		return 2
+	}
+
+	cfg := config.MustLoad()
+	gormDB, err := gorm.Open(postgres.Open(cfg.Database.URL), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "не удалось подключиться к базе данных: %v\n", err)
+		return 1
+	}
+	if db, err := gormDB.DB(); err == nil {
+		defer db.Close()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	generated := *password == ""
+	resultPassword, err := services.BootstrapSuperAdmin(ctx, repository.NewAdminPostgres(gormDB),
+		*login, *fullName, *password)
+	if err != nil {
+		if errors.Is(err, services.ErrAdminsAlreadyExist) {
+			fmt.Fprintln(os.Stderr, "в базе уже есть администраторы; используйте API управления администраторами")
+			return 1
+		}
+		fmt.Fprintf(os.Stderr, "не удалось создать суперадминистратора: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("суперадминистратор %q создан\n", *login)
+	if generated {
+		fmt.Printf("временный пароль: %s\n(его потребуется сменить при первом входе)\n", resultPassword)
+	}
+	return 0
+}
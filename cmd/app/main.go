@@ -23,12 +23,15 @@ import (
 
 	"lk/internal/config"
 	"lk/internal/logger"
+	"lk/internal/mail"
 	"lk/internal/models"
 	"lk/internal/repository"
 	"lk/internal/server"
 	"lk/internal/services"
+	"lk/internal/sms"
 	"lk/internal/storage"
 	httptransport "lk/internal/transport/http"
+	"lk/internal/utils"
 )
 
 // @title API Личного Кабинета
@@ -59,13 +62,18 @@ type _ struct {
 	_ models.Appointment
 	_ models.UserProfile
 	_ models.Specialty
-	_ models.PaginatedDoctorsResponse
+	_ models.DoctorSearchResponse
 	_ models.Recommendation
 	_ models.AvailableDatesResponse
 	_ models.AvailableSlotsResponse
 }
 
 func main() {
+	// Подкоманды CLI
+	if len(os.Args) > 1 && os.Args[1] == "create-superadmin" {
+		os.Exit(runCreateSuperAdmin(os.Args[2:]))
+	}
+
 	// 1. Инициализация логгера
 	logger.Init(os.Getenv("LOG_DIR"))
 	logger.Default().Info("логгер инициализирован")
@@ -109,6 +117,25 @@ func main() {
 	}
 	logger.Default().Info("соединение с MinIO установлено")
 
+	mailer, err := mail.NewSender(cfg.Mail)
+	if err != nil {
+		logger.Default().WithError(err).Fatal("не удалось инициализировать отправку почты")
+	}
+
+	var jwtKeys *utils.KeySet
+	switch {
+	case cfg.Auth.JWTPrivateKeyFile != "":
+		jwtKeys, err = utils.LoadKeySet(cfg.Auth.JWTPrivateKeyFile, cfg.Auth.JWTPublicKeyFiles)
+	case cfg.Env == "local":
+		logger.Default().Warn("JWT_PRIVATE_KEY_FILE не задан: используется временный ключ, токены не переживут перезапуск")
+		jwtKeys, err = utils.GenerateKeySet()
+	default:
+		err = errors.New("JWT_PRIVATE_KEY_FILE is required outside of the local environment")
+	}
+	if err != nil {
+		logger.Default().WithError(err).Fatal("не удалось загрузить ключи подписи JWT")
+	}
+
 	// 3. Dependency Injection: собираем все зависимости
 	repos := repository.NewRepository(gormDB, redisClient)
 	serviceDeps := services.ServiceDependencies{
@@ -116,16 +143,42 @@ func main() {
 		Storage:    storageClient,
 		Location:   location,
 		SigningKey: cfg.Auth.JWTSecretKey,
-		TokenTTL:   cfg.Auth.TokenTTL,
+		Keys:       jwtKeys,
+		PatientTokens: utils.TokenScope{
+			Issuer:   cfg.Auth.PatientIssuer,
+			Audience: cfg.Auth.PatientAudience,
+			TTL:      cfg.Auth.TokenTTL,
+		},
+		AdminTokens: utils.TokenScope{
+			Issuer:   cfg.Auth.AdminIssuer,
+			Audience: cfg.Auth.AdminAudience,
+			TTL:      cfg.Auth.TokenTTL,
+		},
+		Security:     cfg.Security,
+		SMS:          sms.NewLogSender(cfg.SMS.SenderName),
+		Mailer:       mailer,
+		Mail:         cfg.Mail,
+		Account:      cfg.Account,
+		Retention:    cfg.Retention,
+		Certificates: cfg.Certificates,
 	}
 	services := services.NewService(serviceDeps)
 
+	// Фоновые задачи: выгрузки данных, удаление аккаунтов пациентов, окончательное удаление
+	// мягко удаленных записей, предупреждения об истечении сертификатов врачей и напоминания о приеме
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go services.Account.RunJobs(jobsCtx, cfg.Account.JobInterval)
+	go services.Admin.RunPurgeJob(jobsCtx, cfg.Retention.PurgeInterval)
+	go services.Admin.RunCertificateAlertJob(jobsCtx, cfg.Certificates.CheckInterval)
+	go services.Appointment.RunReminderJob(jobsCtx, cfg.Reminders.CheckInterval)
+
 	handler := httptransport.NewHandler(services, repos.User, repos.Admin)
 	logger.Default().Info("слои приложения инициализированы")
 
 	// 4. Инициализация HTTP-роутера и middleware
 	router := gin.New()
-	router.Use(logger.GinLogger(), gin.Recovery(), httptransport.ErrorMiddleware())
+	router.Use(logger.GinLogger(), gin.Recovery(), httptransport.RequestContext(), httptransport.ErrorMiddleware())
 	handler.InitRoutes(router)
 
 	// 5. Запуск HTTP-сервера с Graceful Shutdown
@@ -143,6 +196,7 @@ func main() {
 	<-quit
 
 	logger.Default().Info("сервер выключается")
+	stopJobs()
 	ctx, shutdown := context.WithTimeout(context.Background(), 5*time.Second)
 	defer shutdown()
 
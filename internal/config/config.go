@@ -17,8 +17,14 @@ type Config struct {
 	Database       DBConfig
 	HTTPServer     HTTPServerConfig
 	Auth           AuthConfig
+	Security       SecurityConfig
 	Minio          MinioConfig
 	SMS            SMSConfig
+	Mail           MailConfig
+	Account        AccountConfig
+	Retention      RetentionConfig
+	Certificates   CertificateConfig
+	Reminders      ReminderConfig
 	Redis          RedisConfig
 }
 
@@ -40,9 +46,33 @@ type HTTPServerConfig struct {
 }
 
 // AuthConfig содержит параметры для аутентификации (JWT).
+// Access токены подписываются асимметричным ключом (RS256 или EdDSA) из JWTPrivateKeyFile;
+// JWTPublicKeyFiles - открытые ключи предыдущих поколений, принимаемые на время ротации.
+// JWTSecretKey используется только для одноразовых ссылок в письмах.
 type AuthConfig struct {
-	JWTSecretKey string        `yaml:"jwt_secret_key" env:"JWT_SECRET_KEY" env-required:"true"`
-	TokenTTL     time.Duration `yaml:"token_ttl" env:"TOKEN_TTL" env-default:"1h"`
+	JWTSecretKey      string        `yaml:"jwt_secret_key" env:"JWT_SECRET_KEY" env-required:"true"`
+	JWTPrivateKeyFile string        `yaml:"jwt_private_key_file" env:"JWT_PRIVATE_KEY_FILE"`
+	JWTPublicKeyFiles []string      `yaml:"jwt_public_key_files" env:"JWT_PUBLIC_KEY_FILES" env-separator:","`
+	TokenTTL          time.Duration `yaml:"token_ttl" env:"TOKEN_TTL" env-default:"1h"`
+	PatientIssuer     string        `yaml:"patient_issuer" env:"JWT_PATIENT_ISSUER" env-default:"lk-auth"`
+	PatientAudience   string        `yaml:"patient_audience" env:"JWT_PATIENT_AUDIENCE" env-default:"lk-patient"`
+	AdminIssuer       string        `yaml:"admin_issuer" env:"JWT_ADMIN_ISSUER" env-default:"lk-admin-auth"`
+	AdminAudience     string        `yaml:"admin_audience" env:"JWT_ADMIN_AUDIENCE" env-default:"lk-admin"`
+}
+
+// SecurityConfig содержит параметры защиты от перебора паролей и кодов подтверждения,
+// лимиты отправки SMS-кодов, а также 2FA администраторов.
+type SecurityConfig struct {
+	MaxLoginAttempts      int64         `yaml:"max_login_attempts" env:"LOGIN_MAX_ATTEMPTS" env-default:"5"`
+	MaxIPAttempts         int64         `yaml:"max_ip_attempts" env:"LOGIN_MAX_IP_ATTEMPTS" env-default:"20"`
+	AttemptWindow         time.Duration `yaml:"attempt_window" env:"LOGIN_ATTEMPT_WINDOW" env-default:"15m"`
+	LockoutBase           time.Duration `yaml:"lockout_base" env:"LOGIN_LOCKOUT_BASE" env-default:"1m"`
+	LockoutMax            time.Duration `yaml:"lockout_max" env:"LOGIN_LOCKOUT_MAX" env-default:"1h"`
+	MaxResetCodeAttempts  int64         `yaml:"max_reset_code_attempts" env:"RESET_CODE_MAX_ATTEMPTS" env-default:"5"`
+	CodeSendCooldown      time.Duration `yaml:"code_send_cooldown" env:"CODE_SEND_COOLDOWN" env-default:"1m"`
+	MaxCodeSendsPerHour   int64         `yaml:"max_code_sends_per_hour" env:"CODE_SEND_MAX_PER_HOUR" env-default:"5"`
+	MaxIPCodeSendsPerHour int64         `yaml:"max_ip_code_sends_per_hour" env:"CODE_SEND_MAX_PER_IP_HOUR" env-default:"20"`
+	TOTPIssuer            string        `yaml:"totp_issuer" env:"ADMIN_TOTP_ISSUER" env-default:"MedCenter"`
 }
 
 // MinioConfig содержит параметры для подключения к S3-совместимому хранилищу MinIO.
@@ -60,6 +90,46 @@ type SMSConfig struct {
 	SenderName string `yaml:"sender_name" env:"SMS_SENDER_NAME" env-required:"true"`
 }
 
+// MailConfig содержит параметры отправки электронной почты и ссылок в письмах.
+type MailConfig struct {
+	Driver           string        `yaml:"driver" env:"MAIL_DRIVER" env-default:"file"` // smtp | file
+	From             string        `yaml:"from" env:"MAIL_FROM" env-default:"no-reply@medcenter.local"`
+	SMTPHost         string        `yaml:"smtp_host" env:"MAIL_SMTP_HOST" env-default:"localhost"`
+	SMTPPort         string        `yaml:"smtp_port" env:"MAIL_SMTP_PORT" env-default:"587"`
+	SMTPUsername     string        `yaml:"smtp_username" env:"MAIL_SMTP_USERNAME"`
+	SMTPPassword     string        `yaml:"smtp_password" env:"MAIL_SMTP_PASSWORD"`
+	FileDir          string        `yaml:"file_dir" env:"MAIL_FILE_DIR" env-default:"./mail"`
+	VerifyEmailURL   string        `yaml:"verify_email_url" env:"MAIL_VERIFY_EMAIL_URL" env-default:"http://localhost:8080/api/v1/auth/verify-email"`
+	ResetPasswordURL string        `yaml:"reset_password_url" env:"MAIL_RESET_PASSWORD_URL" env-default:"http://localhost:3000/reset-password"`
+	VerifyLinkTTL    time.Duration `yaml:"verify_link_ttl" env:"MAIL_VERIFY_LINK_TTL" env-default:"24h"`
+	ResetLinkTTL     time.Duration `yaml:"reset_link_ttl" env:"MAIL_RESET_LINK_TTL" env-default:"30m"`
+}
+
+// AccountConfig содержит параметры выгрузки персональных данных и удаления аккаунтов пациентов.
+type AccountConfig struct {
+	DeletionGracePeriod time.Duration `yaml:"deletion_grace_period" env:"ACCOUNT_DELETION_GRACE_PERIOD" env-default:"720h"`
+	ExportTTL           time.Duration `yaml:"export_ttl" env:"ACCOUNT_EXPORT_TTL" env-default:"168h"`
+	JobInterval         time.Duration `yaml:"job_interval" env:"ACCOUNT_JOB_INTERVAL" env-default:"1m"`
+}
+
+// RetentionConfig содержит параметры хранения мягко удаленных записей.
+type RetentionConfig struct {
+	DeletedRetention time.Duration `yaml:"deleted_retention" env:"RETENTION_DELETED" env-default:"2160h"`
+	PurgeInterval    time.Duration `yaml:"purge_interval" env:"RETENTION_PURGE_INTERVAL" env-default:"24h"`
+}
+
+// CertificateConfig содержит параметры предупреждений об истечении сертификатов врачей.
+type CertificateConfig struct {
+	AlertBefore   time.Duration `yaml:"alert_before" env:"CERTIFICATE_ALERT_BEFORE" env-default:"720h"`
+	CheckInterval time.Duration `yaml:"check_interval" env:"CERTIFICATE_CHECK_INTERVAL" env-default:"24h"`
+}
+
+// ReminderConfig содержит параметры SMS-напоминаний о приеме. За сколько до приема их отправлять,
+// задается в настройках клиники (reminderOffsetsMinutes).
+type ReminderConfig struct {
+	CheckInterval time.Duration `yaml:"check_interval" env:"REMINDER_CHECK_INTERVAL" env-default:"5m"`
+}
+
 // RedisConfig содержит параметры для подключения к Redis.
 type RedisConfig struct {
 	Addr     string `env:"REDIS_ADDR" env-required:"true"`
@@ -0,0 +1,131 @@
+// Package imaging проверяет и обрабатывает загружаемые изображения: определяет формат по содержимому,
+// ограничивает размеры, учитывает EXIF-ориентацию и готовит миниатюры без метаданных.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif" // регистрирует декодер GIF
+	"image/jpeg"
+	_ "image/png" // регистрирует декодер PNG
+	"io"
+	"net/http"
+)
+
+const (
+	// MaxPixels - максимальное число пикселей исходного изображения.
+	// Ограничивает память на декодирование: сжатый файл может быть маленьким, а картинка - огромной.
+	MaxPixels = 25_000_000
+	// MaxSide - максимальная длина стороны исходного изображения в пикселях.
+	MaxSide = 10_000
+
+	// jpegQuality - качество сохранения миниатюр.
+	jpegQuality = 85
+)
+
+var (
+	// ErrUnsupportedFormat возвращается, если содержимое файла не является изображением допустимого формата.
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	// ErrTooLarge возвращается, если размеры изображения превышают MaxSide или MaxPixels.
+	ErrTooLarge = errors.New("image dimensions are too large")
+)
+
+// allowedContentTypes - форматы, определяемые по сигнатуре содержимого, а не по расширению или заголовку.
+var allowedContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// Image - декодированное изображение с учетом EXIF-ориентации исходного файла.
+type Image struct {
+	img         image.Image
+	orientation int
+}
+
+// Decode проверяет формат по содержимому, размеры по заголовку и только после этого
+// декодирует изображение целиком.
+func Decode(data []byte) (*Image, error) {
+	contentType := http.DetectContentType(data)
+	if !allowedContentTypes[contentType] {
+		return nil, ErrUnsupportedFormat
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedFormat, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width > MaxSide || cfg.Height > MaxSide ||
+		cfg.Width*cfg.Height > MaxPixels {
+		return nil, ErrTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedFormat, err)
+	}
+
+	orientation := 1
+	if contentType == "image/jpeg" {
+		orientation = jpegOrientation(data)
+	}
+	return &Image{img: img, orientation: orientation}, nil
+}
+
+// Thumbnail возвращает квадратную миниатюру со стороной size из центра изображения.
+// Изображения меньше size не увеличиваются. Прозрачные области заливаются белым,
+// так как миниатюры сохраняются в JPEG.
+func (i *Image) Thumbnail(size int) image.Image {
+	bounds := i.img.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	offset := image.Pt(bounds.Min.X+(bounds.Dx()-side)/2, bounds.Min.Y+(bounds.Dy()-side)/2)
+
+	square := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(square, square.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(square, square.Bounds(), i.img, offset, draw.Over)
+
+	if side > size {
+		square = resizeBox(square, size)
+	}
+	// Поворот квадрата из центра совпадает с центром повернутого изображения,
+	// поэтому ориентацию дешевле применять к уже уменьшенной миниатюре.
+	return orient(square, i.orientation)
+}
+
+// EncodeJPEG сохраняет изображение в JPEG. Метаданные исходного файла (EXIF, геометки) не переносятся.
+func EncodeJPEG(w io.Writer, img image.Image) error {
+	return jpeg.Encode(w, img, &jpeg.Options{Quality: jpegQuality})
+}
+
+// resizeBox уменьшает квадратное изображение до size×size усреднением пикселей исходных областей.
+func resizeBox(src *image.RGBA, size int) *image.RGBA {
+	side := src.Bounds().Dx()
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		y0, y1 := y*side/size, (y+1)*side/size
+		for x := 0; x < size; x++ {
+			x0, x1 := x*side/size, (x+1)*side/size
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				off := src.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += uint64(src.Pix[off])
+					g += uint64(src.Pix[off+1])
+					b += uint64(src.Pix[off+2])
+					a += uint64(src.Pix[off+3])
+					off += 4
+					n++
+				}
+			}
+			d := dst.PixOffset(x, y)
+			dst.Pix[d] = uint8(r / n)
+			dst.Pix[d+1] = uint8(g / n)
+			dst.Pix[d+2] = uint8(b / n)
+			dst.Pix[d+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
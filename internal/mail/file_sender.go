@@ -0,0 +1,42 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"lk/internal/logger"
+)
+
+// FileSender - заглушка почтового сервера для локальной разработки.
+// Каждое письмо сохраняется в отдельный .eml-файл в указанной директории.
+type FileSender struct {
+	dir  string
+	from string
+}
+
+// NewFileSender создает новый экземпляр файлового отправителя.
+func NewFileSender(dir, from string) *FileSender {
+	return &FileSender{dir: dir, from: from}
+}
+
+// Send записывает письмо в файл вместо реальной отправки.
+func (s *FileSender) Send(_ context.Context, msg Message) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create mail directory: %w", err)
+	}
+
+	recipient := strings.NewReplacer("@", "_at_", "/", "_", "\\", "_").Replace(msg.To)
+	name := fmt.Sprintf("%s_%s.eml", time.Now().Format("20060102T150405.000000000"), recipient)
+	path := filepath.Join(s.dir, name)
+	if err := os.WriteFile(path, buildMessage(s.from, msg), 0o600); err != nil {
+		return fmt.Errorf("failed to write mail file: %w", err)
+	}
+
+	logger.Default().WithField("module", "MAIL").Info(
+		fmt.Sprintf("письмо для %s сохранено в %s", msg.To, path))
+	return nil
+}
@@ -0,0 +1,53 @@
+// Package mail предоставляет абстракцию для отправки электронных писем.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"time"
+
+	"lk/internal/config"
+)
+
+// Message - электронное письмо в виде простого текста.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender определяет интерфейс для отправки электронных писем.
+type Sender interface {
+	// Send отправляет письмо одному получателю.
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewSender создает отправителя в соответствии с конфигурацией:
+// "smtp" - реальный SMTP-сервер, "file" - запись писем в локальную директорию.
+func NewSender(cfg config.MailConfig) (Sender, error) {
+	switch cfg.Driver {
+	case "smtp":
+		return NewSMTPSender(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.From), nil
+	case "file":
+		return NewFileSender(cfg.FileDir, cfg.From), nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", cfg.Driver)
+	}
+}
+
+// buildMessage формирует письмо в формате RFC 5322.
+func buildMessage(from string, msg Message) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(msg.Body)
+	return buf.Bytes()
+}
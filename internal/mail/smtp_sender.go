@@ -0,0 +1,36 @@
+package mail
+
+import (
+	"context"
+	"net"
+	"net/smtp"
+)
+
+// SMTPSender отправляет письма через SMTP-сервер.
+type SMTPSender struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+}
+
+// NewSMTPSender создает новый экземпляр SMTP-отправителя.
+func NewSMTPSender(host, port, username, password, from string) *SMTPSender {
+	return &SMTPSender{
+		addr:     net.JoinHostPort(host, port),
+		host:     host,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+// Send отправляет письмо. Аутентификация выполняется только при заданном имени пользователя.
+func (s *SMTPSender) Send(_ context.Context, msg Message) error {
+	var auth smtp.Auth
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+	return smtp.SendMail(s.addr, auth, s.from, []string{msg.To}, buildMessage(s.from, msg))
+}
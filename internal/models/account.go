@@ -0,0 +1,109 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Статусы выгрузки персональных данных.
+const (
+	DataExportPending    = "pending"
+	DataExportProcessing = "processing"
+	DataExportReady      = "ready"
+	DataExportFailed     = "failed"
+	DataExportExpired    = "expired"
+)
+
+// Статусы запроса на удаление аккаунта.
+const (
+	AccountDeletionPending   = "pending"
+	AccountDeletionCancelled = "cancelled"
+	AccountDeletionCompleted = "completed"
+)
+
+// DataExport - запрос пациента на выгрузку его данных и результат выгрузки (ZIP-архив в хранилище).
+type DataExport struct {
+	ID          uint64         `gorm:"primarykey" json:"id"`
+	UserID      uint64         `json:"-"`
+	Status      string         `json:"status" example:"ready"`
+	FileKey     sql.NullString `json:"-"`
+	FileSize    sql.NullInt64  `json:"fileSize,omitzero"`
+	Error       sql.NullString `json:"-"`
+	CreatedAt   time.Time      `json:"createdAt"`
+	StartedAt   sql.NullTime   `json:"-"`
+	CompletedAt sql.NullTime   `json:"completedAt,omitzero"`
+	ExpiresAt   sql.NullTime   `json:"expiresAt,omitzero"`
+}
+
+// TableName возвращает имя таблицы в базе данных.
+func (DataExport) TableName() string {
+	return "medical_center.data_export_requests"
+}
+
+// AccountDeletion - запрос пациента на удаление аккаунта.
+// До ScheduledAt запрос можно отменить, после - аккаунт обезличивается.
+type AccountDeletion struct {
+	ID          uint64         `gorm:"primarykey" json:"id"`
+	UserID      uint64         `json:"-"`
+	Status      string         `json:"status" example:"pending"`
+	Reason      sql.NullString `json:"reason,omitzero"`
+	RequestedAt time.Time      `json:"requestedAt"`
+	ScheduledAt time.Time      `json:"scheduledAt"`
+	CancelledAt sql.NullTime   `json:"cancelledAt,omitzero"`
+	CompletedAt sql.NullTime   `json:"completedAt,omitzero"`
+}
+
+// TableName возвращает имя таблицы в базе данных.
+func (AccountDeletion) TableName() string {
+	return "medical_center.account_deletion_requests"
+}
+
+// ExportAppointment - запись на прием в выгрузке данных пациента: с именами врача,
+// услуги и клиники вместо внутренних идентификаторов. File - путь к файлу результата внутри архива.
+type ExportAppointment struct {
+	ID                   uint64    `json:"id"`
+	AppointmentDate      time.Time `json:"date"`
+	AppointmentTime      string    `json:"time"`
+	Status               string    `json:"status"`
+	DoctorName           string    `json:"doctor"`
+	ServiceName          string    `json:"service"`
+	ClinicName           string    `json:"clinic"`
+	PriceAtBooking       float64   `json:"price"`
+	IsDMS                bool      `json:"isDMS"`
+	PreVisitInstructions *string   `json:"preVisitInstructions,omitempty"`
+	Diagnosis            *string   `json:"diagnosis,omitempty"`
+	Recommendations      *string   `json:"recommendations,omitempty"`
+	ResultFileURL        string    `json:"-"`
+	File                 string    `json:"file,omitempty"`
+	CreatedAt            time.Time `json:"createdAt"`
+}
+
+// ExportAnalysis - лабораторный анализ в выгрузке данных пациента.
+// File - путь к файлу результата внутри архива.
+type ExportAnalysis struct {
+	ID            uint64    `json:"id"`
+	Name          string    `json:"name"`
+	AssignedDate  time.Time `json:"assignedDate"`
+	Status        string    `json:"status"`
+	AppointmentID *uint64   `json:"appointmentId,omitempty"`
+	ResultFileURL string    `json:"-"`
+	FileName      string    `json:"-"`
+	File          string    `json:"file,omitempty"`
+}
+
+// DataExportManifest - оглавление архива выгрузки (manifest.json).
+type DataExportManifest struct {
+	FormatVersion int              `json:"formatVersion"`
+	GeneratedAt   time.Time        `json:"generatedAt"`
+	UserID        uint64           `json:"userId"`
+	Files         []DataExportFile `json:"files"`
+}
+
+// DataExportFile описывает один файл архива выгрузки.
+// Missing означает, что файл числится в базе, но не найден в хранилище.
+type DataExportFile struct {
+	Path        string `json:"path"`
+	Description string `json:"description"`
+	Records     int    `json:"records,omitempty"`
+	Missing     bool   `json:"missing,omitempty"`
+}
@@ -1,24 +1,35 @@
 package models
 
-import "time"
+import (
+	"database/sql"
+	"time"
+)
 
 // AdminRole определяет тип для ролей администратора.
 type AdminRole string
 
 const (
-	RoleSuperAdmin AdminRole = "superadmin"
-	RoleAdmin      AdminRole = "admin"
+	RoleSuperAdmin    AdminRole = "superadmin"
+	RoleAdmin         AdminRole = "admin"
+	RoleReceptionist  AdminRole = "receptionist"
+	RoleLabTechnician AdminRole = "lab_technician"
 )
 
 // Admin представляет пользователя-администратора в системе.
 type Admin struct {
-	ID           uint64    `gorm:"primarykey" json:"id"`
-	Login        string    `gorm:"unique;not null" json:"login"`
-	PasswordHash string    `json:"-"`
-	FullName     string    `gorm:"not null" json:"fullName"`
-	Role         AdminRole `gorm:"type:varchar(50);not null" json:"role"`
-	CreatedAt    time.Time `json:"createdAt"`
-	UpdatedAt    time.Time `json:"updatedAt"`
+	ID                 uint64         `gorm:"primarykey" json:"id"`
+	Login              string         `gorm:"unique;not null" json:"login"`
+	PasswordHash       string         `json:"-"`
+	FullName           string         `gorm:"not null" json:"fullName"`
+	Email              sql.NullString `json:"email,omitzero"` // Адрес для служебных уведомлений
+	Role               AdminRole      `gorm:"type:varchar(50);not null" json:"role"`
+	IsActive           bool           `gorm:"not null" json:"isActive"`
+	MustChangePassword bool           `gorm:"not null" json:"mustChangePassword"`
+	TOTPSecret         sql.NullString `gorm:"column:totp_secret" json:"-"`
+	TOTPEnabledAt      sql.NullTime   `gorm:"column:totp_enabled_at" json:"totpEnabledAt,omitzero"`
+	LastLoginAt        sql.NullTime   `json:"lastLoginAt,omitzero"`
+	CreatedAt          time.Time      `json:"createdAt"`
+	UpdatedAt          time.Time      `json:"updatedAt"`
 }
 
 // TableName возвращает имя таблицы в базе данных.
@@ -26,6 +37,40 @@ func (Admin) TableName() string {
 	return "medical_center.admins"
 }
 
+// AdminRecoveryCode - резервный код для входа администратора без приложения-аутентификатора.
+// Хранится только хэш кода; каждый код одноразовый.
+type AdminRecoveryCode struct {
+	ID        uint64       `gorm:"primarykey" json:"id"`
+	AdminID   uint64       `gorm:"not null" json:"adminId"`
+	CodeHash  string       `gorm:"type:varchar(64);not null" json:"-"`
+	UsedAt    sql.NullTime `json:"usedAt,omitzero"`
+	CreatedAt time.Time    `json:"createdAt"`
+}
+
+// TableName возвращает имя таблицы в базе данных.
+func (AdminRecoveryCode) TableName() string {
+	return "medical_center.admin_recovery_codes"
+}
+
+// AdminTwoFactorStatus - DTO с состоянием двухфакторной аутентификации администратора.
+type AdminTwoFactorStatus struct {
+	Enabled           bool  `json:"enabled"`
+	Required          bool  `json:"required"`
+	RecoveryCodesLeft int64 `json:"recoveryCodesLeft"`
+}
+
+// AdminTwoFactorEnrollment - DTO с данными для подключения приложения-аутентификатора.
+type AdminTwoFactorEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+// AdminRoleInfo - DTO с описанием роли и ее прав.
+type AdminRoleInfo struct {
+	Role        AdminRole    `json:"role"`
+	Permissions []Permission `json:"permissions"`
+}
+
 // AdminDashboardStats представляет DTO для статистики на дашборде.
 type AdminDashboardStats struct {
 	ActiveUsers    int64   `json:"activeUsers"`
@@ -33,4 +78,14 @@ type AdminDashboardStats struct {
 	Appointments   int64   `json:"appointments"`
 	CompletedTotal int64   `json:"completedTotal"`
 	TotalRevenue   float64 `json:"totalRevenue"`
+	// ExpiringCertificates - сертификаты врачей, которые истекают в ближайшее время или уже истекли.
+	ExpiringCertificates int64 `json:"expiringCertificates"`
+}
+
+// PurgeResult - итог окончательного удаления мягко удаленных записей одной таблицы.
+// Kept - записи, которые нельзя удалить, пока на них ссылаются другие данные.
+type PurgeResult struct {
+	Table  string `json:"table"`
+	Purged int64  `json:"purged"`
+	Kept   int64  `json:"kept"`
 }
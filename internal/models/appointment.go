@@ -3,6 +3,8 @@ package models
 import (
 	"database/sql"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // Appointment представляет запись на прием к врачу
@@ -23,6 +25,7 @@ type Appointment struct {
 	ResultFileURL        sql.NullString `db:"result_file_url" json:"-"`
 	CreatedAt            time.Time      `db:"created_at" json:"createdAt"`
 	UpdatedAt            time.Time      `db:"updated_at" json:"updatedAt"`
+	DeletedAt            gorm.DeletedAt `db:"deleted_at" json:"deletedAt,omitzero"`
 	Doctor               Doctor         `gorm:"foreignKey:DoctorID"`
 	Service              Service        `gorm:"foreignKey:ServiceID"`
 }
@@ -30,3 +33,13 @@ type Appointment struct {
 func (Appointment) TableName() string {
 	return "medical_center.appointments"
 }
+
+// AppointmentReminder - предстоящий прием с данными, нужными для SMS-напоминания пациенту.
+type AppointmentReminder struct {
+	ID              uint64
+	Phone           string
+	AppointmentDate time.Time
+	AppointmentTime string
+	DoctorName      string
+	ClinicAddress   string
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Типы действующих лиц в журнале аудита.
+const (
+	AuditActorAdmin     = "admin"
+	AuditActorUser      = "user"
+	AuditActorAnonymous = "anonymous"
+	AuditActorSystem    = "system"
+)
+
+// AuditChange - значение поля до и после изменения.
+type AuditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// AuditChanges - набор измененных полей (ключ - путь к полю, например profile.email).
+// Хранится в колонке jsonb.
+type AuditChanges map[string]AuditChange
+
+// Value реализует driver.Valuer для записи в jsonb.
+func (c AuditChanges) Value() (driver.Value, error) {
+	if len(c) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan реализует sql.Scanner для чтения из jsonb.
+func (c *AuditChanges) Scan(value any) error {
+	if value == nil {
+		*c = nil
+		return nil
+	}
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.New("unsupported type for AuditChanges")
+	}
+	return json.Unmarshal(data, c)
+}
+
+// AuditLog - запись журнала аудита: кто, когда и что сделал с какой сущностью.
+type AuditLog struct {
+	ID             uint64       `gorm:"primarykey" json:"id"`
+	ActorType      string       `gorm:"type:varchar(20);not null" json:"actorType"`
+	ActorID        *uint64      `json:"actorId,omitempty"`
+	ActorName      string       `gorm:"type:varchar(255)" json:"actorName,omitempty"`
+	ImpersonatorID *uint64      `json:"impersonatorId,omitempty"`
+	Action         string       `gorm:"type:varchar(100);not null" json:"action"`
+	EntityType     string       `gorm:"type:varchar(50);not null" json:"entityType"`
+	EntityID       string       `gorm:"type:varchar(64)" json:"entityId,omitempty"`
+	TargetUserID   *uint64      `json:"targetUserId,omitempty"`
+	Changes        AuditChanges `gorm:"type:jsonb" json:"changes,omitempty"`
+	IPAddress      string       `gorm:"type:varchar(64)" json:"ipAddress,omitempty"`
+	RequestID      string       `gorm:"type:varchar(64)" json:"requestId,omitempty"`
+	CreatedAt      time.Time    `json:"createdAt"`
+}
+
+// TableName возвращает имя таблицы в базе данных.
+func (AuditLog) TableName() string {
+	return "medical_center.audit_logs"
+}
+
+// AuditLogFilter - фильтры журнала аудита; нулевые значения не ограничивают выборку.
+type AuditLogFilter struct {
+	ActorType    string
+	ActorID      uint64
+	Action       string
+	EntityType   string
+	EntityID     string
+	TargetUserID uint64
+	RequestID    string
+	From         time.Time
+	To           time.Time
+}
+
+// Кто обращался к медицинским данным пациента.
+const (
+	AccessorPatient       = "patient"
+	AccessorStaff         = "staff"
+	AccessorImpersonation = "impersonation"
+)
+
+// MedicalDataAccess - запись истории обращений к медкарте, которую видит сам пациент.
+// Для сотрудников клиники показываются ФИО и роль, но не логин и IP-адрес.
+type MedicalDataAccess struct {
+	ID           uint64    `json:"id"`
+	AccessedAt   time.Time `json:"accessedAt"`
+	Action       string    `json:"action"`
+	Section      string    `json:"section"`
+	ResourceID   string    `json:"resourceId,omitempty"`
+	AccessorType string    `json:"accessorType" enums:"patient,staff,impersonation"`
+	StaffName    string    `json:"staffName,omitempty"`
+	StaffRole    AdminRole `json:"staffRole,omitempty"`
+}
+
+// MedicalDataAccessFilter - фильтры истории обращений к медкарте.
+type MedicalDataAccessFilter struct {
+	UserID    uint64
+	Actions   []string
+	StaffOnly bool
+}
@@ -1,33 +1,40 @@
 package models
 
-import "database/sql"
+import (
+	"database/sql"
 
-// Clinic представляет медицинский центр
+	"gorm.io/gorm"
+)
+
+// Clinic представляет медицинский центр (филиал сети)
 type Clinic struct {
-	ID        uint64 `gorm:"primarykey" db:"id" json:"id"`
-	Name      string `db:"name" json:"name"`
-	Address   string `db:"address" json:"address"`
-	WorkHours string `db:"work_hours" json:"workHours"`
-	Phone     string `db:"phone" json:"phone"`
+	ID           uint64               `gorm:"primarykey" db:"id" json:"id"`
+	Name         string               `db:"name" json:"name"`
+	Address      string               `db:"address" json:"address"`
+	Phone        string               `db:"phone" json:"phone"`
+	Email        *string              `db:"email" json:"email,omitempty"`
+	CityID       sql.NullInt32        `db:"city_id" json:"cityID,omitzero"`
+	Latitude     *float64             `db:"latitude" json:"latitude,omitempty" example:"55.751244"`
+	Longitude    *float64             `db:"longitude" json:"longitude,omitempty" example:"37.618423"`
+	IsMain       bool                 `db:"is_main" json:"isMain"`
+	DeletedAt    gorm.DeletedAt       `db:"deleted_at" json:"deletedAt,omitzero"`
+	WorkingHours []ClinicWorkingHours `gorm:"foreignKey:ClinicID" json:"workingHours,omitempty"`
 }
 
 func (Clinic) TableName() string {
 	return "medical_center.clinics"
 }
 
-// Service представляет медицинскую услугу, оказываемую врачом
-type Service struct {
-	ID              uint64         `gorm:"primarykey" db:"id" json:"id"`
-	Name            string         `db:"name" json:"name"`
-	Price           float64        `db:"price" json:"price"`
-	DurationMinutes uint16         `db:"duration_minutes" json:"durationMinutes"`
-	Description     sql.NullString `db:"description" json:"description,omitzero"`
-	Recommendations sql.NullString `json:"recommendations,omitzero"`
-	DoctorID        uint64         `db:"doctor_id" json:"doctorID"`
+// ClinicWorkingHours - часы работы клиники в один день недели. Дни без записи - выходные.
+type ClinicWorkingHours struct {
+	ClinicID uint64 `gorm:"primaryKey" db:"clinic_id" json:"-"`
+	Weekday  uint8  `gorm:"primaryKey" db:"weekday" json:"weekday" example:"1"` // 1 - понедельник, 7 - воскресенье
+	OpensAt  string `db:"opens_at" json:"opensAt" example:"08:00"`
+	ClosesAt string `db:"closes_at" json:"closesAt" example:"20:00"`
 }
 
-func (Service) TableName() string {
-	return "medical_center.services"
+func (ClinicWorkingHours) TableName() string {
+	return "medical_center.clinic_working_hours"
 }
 
 // DoctorClinic связывает врачей и клиники
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ClinicSettings - настройки работы клиники, которые сервисы читают во время работы.
+// Значения хранятся версиями; действует последняя версия.
+type ClinicSettings struct {
+	// CancellationWindowHours - за сколько часов до приема пациент еще может отменить запись онлайн (0 - в любое время).
+	CancellationWindowHours int `json:"cancellationWindowHours" example:"2"`
+	// ReminderOffsetsMinutes - за сколько минут до приема отправлять SMS-напоминания (см. RunReminderJob).
+	ReminderOffsetsMinutes []int `json:"reminderOffsetsMinutes" example:"1440,120"`
+	// SlotGranularityMinutes - шаг начала слотов для записи (0 - равен длительности услуги).
+	SlotGranularityMinutes int `json:"slotGranularityMinutes" example:"15"`
+	// BookingHorizonDays - на сколько дней вперед открыта запись.
+	BookingHorizonDays int    `json:"bookingHorizonDays" example:"90"`
+	SupportPhone       string `json:"supportPhone" example:"+7 (800) 555-35-35"`
+	SupportEmail       string `json:"supportEmail" example:"support@clinic.ru"`
+	// WorkingHours - часы работы для сводки о клинике; если не заданы, берутся часы основной клиники.
+	WorkingHours []WorkHours `json:"workingHours"`
+}
+
+// DefaultClinicSettings возвращает настройки, действующие, пока в БД нет ни одной версии
+// или хранилище недоступно.
+func DefaultClinicSettings() ClinicSettings {
+	return ClinicSettings{
+		ReminderOffsetsMinutes: []int{1440, 120},
+		BookingHorizonDays:     90,
+		WorkingHours:           []WorkHours{},
+	}
+}
+
+// ClinicSettingsVersion - сохраненная версия настроек клиники. Версии не изменяются,
+// каждое сохранение создает следующую.
+type ClinicSettingsVersion struct {
+	Version   uint64         `gorm:"primarykey;autoIncrement:false" json:"version" example:"3"`
+	Settings  ClinicSettings `gorm:"serializer:json" json:"settings"`
+	CreatedBy sql.NullInt64  `json:"updatedBy,omitzero"`
+	CreatedAt time.Time      `json:"updatedAt"`
+}
+
+func (ClinicSettingsVersion) TableName() string {
+	return "medical_center.clinic_settings_versions"
+}
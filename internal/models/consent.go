@@ -0,0 +1,48 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// LegalDocumentType - тип юридического документа. Документы обязательных типов
+// принимаются при регистрации и повторно после публикации новой версии.
+type LegalDocumentType struct {
+	Type        string `gorm:"primarykey" json:"type" example:"personal_data_consent"`
+	Title       string `json:"title" example:"Согласие на обработку персональных данных"`
+	IsMandatory bool   `json:"isMandatory"`
+}
+
+// TableName возвращает имя таблицы в базе данных.
+func (LegalDocumentType) TableName() string {
+	return "medical_center.legal_document_types"
+}
+
+// UserConsent - факт принятия пользователем конкретной версии документа.
+// Отзыв согласия фиксируется в RevokedAt, запись при этом не удаляется.
+type UserConsent struct {
+	ID           uint64       `gorm:"primarykey" json:"id"`
+	UserID       uint64       `json:"-"`
+	DocumentID   uint64       `json:"documentId"`
+	DocumentType string       `json:"documentType" example:"privacy_policy"`
+	Version      string       `json:"version" example:"2.0"`
+	AcceptedAt   time.Time    `json:"acceptedAt"`
+	RevokedAt    sql.NullTime `json:"revokedAt,omitzero"`
+	IPAddress    string       `json:"-"`
+	UserAgent    string       `json:"-"`
+}
+
+// TableName возвращает имя таблицы в базе данных.
+func (UserConsent) TableName() string {
+	return "medical_center.user_consents"
+}
+
+// ConsentStatus - состояние согласия пользователя по действующей версии документа.
+// RequiresAcceptance означает, что без принятия документа пользоваться кабинетом нельзя.
+type ConsentStatus struct {
+	Document           LegalDocument `json:"document"`
+	Accepted           bool          `json:"accepted"`
+	AcceptedVersion    string        `json:"acceptedVersion,omitempty" example:"1.0"`
+	AcceptedAt         *time.Time    `json:"acceptedAt,omitempty"`
+	RequiresAcceptance bool          `json:"requiresAcceptance"`
+}
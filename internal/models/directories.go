@@ -1,5 +1,7 @@
 package models
 
+import "gorm.io/gorm"
+
 // City представляет город, где живёт (прописан) клиент
 type City struct {
 	ID   uint32 `gorm:"primarykey" db:"id" json:"id"`
@@ -12,8 +14,9 @@ func (City) TableName() string {
 
 // Department представляет отделение клиники
 type Department struct {
-	ID   uint32 `gorm:"primarykey" db:"id" json:"id"`
-	Name string `db:"name" json:"name"`
+	ID        uint32         `gorm:"primarykey" db:"id" json:"id"`
+	Name      string         `db:"name" json:"name"`
+	DeletedAt gorm.DeletedAt `db:"deleted_at" json:"deletedAt,omitzero"`
 }
 
 func (Department) TableName() string {
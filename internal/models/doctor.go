@@ -3,6 +3,8 @@ package models
 import (
 	"database/sql"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // Doctor представляет профиль врача
@@ -15,9 +17,12 @@ type Doctor struct {
 	ExperienceYears uint16         `db:"experience_years" json:"experienceYears"`
 	Rating          float32        `db:"rating" json:"rating"`
 	ReviewCount     uint32         `db:"review_count" json:"reviewCount"`
+	Gender          sql.NullString `db:"gender" json:"gender,omitempty"`
+	AcceptsDMS      bool           `db:"accepts_dms" json:"acceptsDMS"`
 	AvatarURL       sql.NullString `db:"avatar_url" json:"avatarURL,omitempty"`
 	Recommendations sql.NullString `json:"recommendations,omitempty"`
 	CreatedAt       time.Time      `db:"created_at" json:"createdAt"`
+	DeletedAt       gorm.DeletedAt `db:"deleted_at" json:"deletedAt,omitzero"`
 	Specialty       Specialty      `gorm:"foreignKey:SpecialtyID" db:"specialty" json:"specialty"`
 }
 
@@ -67,10 +72,13 @@ func (DoctorCourse) TableName() string {
 
 // DoctorCertificate описывает сертификаты врача
 type DoctorCertificate struct {
-	ID         uint64         `gorm:"primarykey" db:"id" json:"id"`
-	DoctorID   uint64         `db:"doctor_id" json:"doctorID"`
-	CertName   string         `db:"cert_name" json:"certName"`
-	CertNumber sql.NullString `db:"cert_number" json:"certNumber,omitempty"`
+	ID              uint64         `gorm:"primarykey" db:"id" json:"id"`
+	DoctorID        uint64         `db:"doctor_id" json:"doctorID"`
+	CertName        string         `db:"cert_name" json:"certName"`
+	CertNumber      sql.NullString `db:"cert_number" json:"certNumber,omitempty"`
+	IssuedAt        sql.NullTime   `db:"issued_at" json:"issuedAt,omitzero"`
+	ExpiresAt       sql.NullTime   `db:"expires_at" json:"expiresAt,omitzero"`
+	ExpiryAlertedAt sql.NullTime   `db:"expiry_alerted_at" json:"-"`
 }
 
 func (DoctorCertificate) TableName() string {
@@ -99,8 +107,98 @@ func (DoctorSpecialization) TableName() string {
 	return "medical_center.doctorspecializations"
 }
 
-// PaginatedDoctorsResponse - это DTO для возврата пагинированного списка врачей.
-type PaginatedDoctorsResponse struct {
-	Total int64    `json:"total" example:"25"`
-	Items []Doctor `json:"items"`
+// DoctorProfile - полный профиль врача: базовые данные и все разделы
+// (образование, ординатура, курсы, сертификаты, навыки, специализации).
+type DoctorProfile struct {
+	Doctor
+	Clinics         []Clinic               `json:"clinics"`
+	Services        []DoctorServiceOffer   `json:"services"`
+	Education       []DoctorEducation      `json:"education"`
+	Residency       []DoctorResidency      `json:"residency"`
+	Courses         []DoctorCourse         `json:"courses"`
+	Certificates    []DoctorCertificate    `json:"certificates"`
+	Skills          []DoctorSkill          `json:"skills"`
+	Specializations []DoctorSpecialization `json:"specializations"`
+}
+
+// ExpiringCertificate - DTO сертификата врача, срок действия которого скоро истекает или истек.
+type ExpiringCertificate struct {
+	ID         uint64    `json:"id"`
+	DoctorID   uint64    `json:"doctorID"`
+	DoctorName string    `json:"doctorName"`
+	CertName   string    `json:"certName"`
+	CertNumber string    `json:"certNumber,omitempty"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	Expired    bool      `json:"expired"`
+}
+
+// DoctorSearchFilter - критерии поиска врачей. Нулевое значение поля означает, что фильтр не задан.
+type DoctorSearchFilter struct {
+	Query           string  // Свободный текст: ФИО или специальность
+	SpecialtyID     uint32  // Специальность
+	Service         string  // Часть названия услуги
+	ClinicID        uint64  // Клиника, в которой работает врач
+	CityID          uint32  // Город клиники
+	MinRating       float32 // Минимальный рейтинг
+	MinExperience   uint16  // Минимальный стаж в годах
+	Gender          string  // male или female
+	MinPrice        float64 // Нижняя граница цены услуги
+	MaxPrice        float64 // Верхняя граница цены услуги
+	AcceptsDMS      bool    // Только врачи, принимающие по ДМС
+	SlotsWithinDays int     // Есть свободное время в расписании в ближайшие N дней
+}
+
+// DoctorSearchItem - врач в результатах поиска.
+type DoctorSearchItem struct {
+	Doctor
+	Clinics   []Clinic        `json:"clinics"`
+	MinPrice  sql.NullFloat64 `json:"minPrice,omitzero"`   // Минимальная цена подходящих услуг врача
+	Highlight string          `json:"highlight,omitempty"` // Фрагменты документа (HTML-экранированы) с совпадениями в <mark>
+}
+
+// FacetCount - значение фасета и число врачей с этим значением.
+type FacetCount struct {
+	ID    uint64 `json:"id"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// ValueCount - строковое значение фасета и число врачей с этим значением.
+type ValueCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// DoctorSearchFacets - счетчики для фильтров поиска. Каждый фасет считается по всем фильтрам,
+// кроме своего собственного, чтобы в интерфейсе были видны альтернативы выбранному значению.
+type DoctorSearchFacets struct {
+	Specialties []FacetCount    `json:"specialties"`
+	Clinics     []FacetCount    `json:"clinics"`
+	Cities      []FacetCount    `json:"cities"`
+	Genders     []ValueCount    `json:"genders"`
+	AcceptsDMS  int64           `json:"acceptsDMS"`
+	MinPrice    sql.NullFloat64 `json:"minPrice,omitzero"`
+	MaxPrice    sql.NullFloat64 `json:"maxPrice,omitzero"`
+}
+
+// Типы подсказок поиска.
+const (
+	SuggestionDoctor    = "doctor"
+	SuggestionSpecialty = "specialty"
+	SuggestionService   = "service"
+)
+
+// SearchSuggestion - подсказка для строки поиска.
+type SearchSuggestion struct {
+	Type string `json:"type"` // doctor, specialty или service
+	ID   uint64 `json:"id"`
+	Text string `json:"text"`
+	Hint string `json:"hint,omitempty"` // Для врача - специальность
+}
+
+// DoctorSearchResponse - DTO страницы результатов поиска врачей.
+type DoctorSearchResponse struct {
+	Total  int64              `json:"total" example:"25"`
+	Items  []DoctorSearchItem `json:"items"`
+	Facets DoctorSearchFacets `json:"facets"`
 }
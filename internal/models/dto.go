@@ -13,15 +13,20 @@ type AvailableDatesResponse struct {
 }
 
 // AvailableSlotsResponse представляет DTO для ответа со свободными слотами на ОДНУ дату.
+// ClinicID заполняется, если все интервалы приема в этот день относятся к одной клинике;
+// Intervals содержит слоты каждого интервала вместе с его клиникой.
 type AvailableSlotsResponse struct {
-	SpecialistID   uint64   `json:"specialistId"`
-	Date           string   `json:"date"`
-	AvailableSlots []string `json:"availableSlots"`
+	SpecialistID   uint64        `json:"specialistId"`
+	Date           string        `json:"date"`
+	ClinicID       *uint64       `json:"clinicId,omitempty"`
+	AvailableSlots []string      `json:"availableSlots"`
+	Intervals      []SlotsForDay `json:"intervals,omitempty"`
 }
 
 // SlotsForDay - вспомогательная структура для ответа по диапазону.
 type SlotsForDay struct {
 	Date           string   `json:"date"`
+	ClinicID       *uint64  `json:"clinicId,omitempty"`
 	AvailableSlots []string `json:"availableSlots"`
 }
 
@@ -1,5 +1,10 @@
 package models
 
+import (
+	"database/sql"
+	"time"
+)
+
 // ClinicInfo представляет DTO с контактной информацией о клинике.
 type ClinicInfo struct {
 	Name         string      `json:"name" example:"Клиника 'Здоровье'"`
@@ -10,7 +15,7 @@ type ClinicInfo struct {
 
 // Contact представляет контактные данные (телефон, email).
 type Contact struct {
-	Type  string `json:"type" example:"phone"`
+	Type  string `json:"type" example:"phone" enums:"phone,email,support_phone,support_email"`
 	Value string `json:"value" example:"+7 (495) 222-33-44"`
 }
 
@@ -27,14 +32,36 @@ type WorkHours struct {
 	Hours string `json:"hours" example:"08:00 - 20:00"`
 }
 
-// LegalDocument представляет DTO для юридического документа.
+// Статусы версии юридического документа.
+const (
+	LegalDocumentDraft     = "draft"
+	LegalDocumentPublished = "published"
+)
+
+// LegalDocument представляет версию юридического документа.
+// Черновик можно править; опубликованная версия неизменна, а действующей версией типа
+// считается последняя опубликованная. IsMandatory берется из типа документа и заполняется только при чтении.
 type LegalDocument struct {
-	ID         uint64 `gorm:"primarykey" json:"-"`
-	Type       string `json:"type" example:"privacy_policy"`
-	Title      string `json:"title" example:"Политика конфиденциальности"`
-	URL        string `json:"url" example:"/legal/privacy.pdf"`
-	Version    string `json:"version" example:"2.0"`
-	UpdateDate string `json:"updateDate" example:"2023-09-15"`
+	ID          uint64        `gorm:"primarykey" json:"id"`
+	Type        string        `json:"type" example:"privacy_policy"`
+	Title       string        `json:"title" example:"Политика конфиденциальности"`
+	URL         string        `json:"url" example:"/api/v1/legal/documents/privacy_policy/file"`
+	Version     string        `json:"version" example:"2.0"`
+	UpdateDate  time.Time     `gorm:"type:date" json:"updateDate" example:"2023-09-15T00:00:00Z"`
+	Status      string        `json:"status" enums:"draft,published"`
+	PublishedAt sql.NullTime  `json:"publishedAt,omitzero"`
+	FileKey     string        `json:"-"`
+	FileName    string        `json:"fileName,omitempty" example:"privacy-2.0.pdf"`
+	FileSize    int64         `json:"fileSize,omitempty"`
+	CreatedBy   sql.NullInt64 `json:"-"`
+	CreatedAt   time.Time     `json:"createdAt"`
+	IsMandatory bool          `gorm:"->" json:"isMandatory"`
+}
+
+// LegalDocumentFilter - фильтры списка версий юридических документов в админ-панели.
+type LegalDocumentFilter struct {
+	Type   string
+	Status string
 }
 
 func (LegalDocument) TableName() string {
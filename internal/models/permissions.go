@@ -0,0 +1,124 @@
+package models
+
+// Permission - право на выполнение группы операций в админ-панели.
+type Permission string
+
+const (
+	PermDashboardRead Permission = "dashboard:read"
+
+	PermUsersRead   Permission = "users:read"
+	PermUsersWrite  Permission = "users:write"
+	PermUsersDelete Permission = "users:delete"
+	// PermUsersImpersonate - просмотр личного кабинета пациента от его имени (только чтение).
+	PermUsersImpersonate Permission = "users:impersonate"
+
+	PermDoctorsRead    Permission = "doctors:read"
+	PermDoctorsWrite   Permission = "doctors:write"
+	PermSchedulesRead  Permission = "schedules:read"
+	PermSchedulesWrite Permission = "schedules:write"
+
+	PermAppointmentsRead   Permission = "appointments:read"
+	PermAppointmentsWrite  Permission = "appointments:write"
+	PermAppointmentsDelete Permission = "appointments:delete"
+
+	PermServicesRead  Permission = "services:read"
+	PermServicesWrite Permission = "services:write"
+	PermClinicsRead   Permission = "clinics:read"
+	PermClinicsWrite  Permission = "clinics:write"
+
+	PermReviewsRead     Permission = "reviews:read"
+	PermReviewsModerate Permission = "reviews:moderate"
+
+	PermAnalysesRead       Permission = "analyses:read"
+	PermAnalysesWrite      Permission = "analyses:write"
+	PermPrescriptionsRead  Permission = "prescriptions:read"
+	PermPrescriptionsWrite Permission = "prescriptions:write"
+
+	PermFamilyRead  Permission = "family:read"
+	PermFamilyWrite Permission = "family:write"
+
+	PermAuditRead             Permission = "audit:read"
+	PermSecurityRead          Permission = "security:read"
+	PermSecurityWrite         Permission = "security:write"
+	PermSecuritySettingsWrite Permission = "security:settings"
+	PermSettingsRead          Permission = "settings:read"
+	PermSettingsWrite         Permission = "settings:write"
+	PermLegalRead             Permission = "legal:read"
+	PermLegalWrite            Permission = "legal:write"
+
+	PermBackupsRead    Permission = "backups:read"
+	PermBackupsCreate  Permission = "backups:create"
+	PermBackupsRestore Permission = "backups:restore"
+
+	PermAdminsManage Permission = "admins:manage"
+)
+
+// AllPermissions - полный список прав; суперадминистратор обладает всеми.
+var AllPermissions = []Permission{
+	PermDashboardRead,
+	PermUsersRead, PermUsersWrite, PermUsersDelete, PermUsersImpersonate,
+	PermDoctorsRead, PermDoctorsWrite, PermSchedulesRead, PermSchedulesWrite,
+	PermAppointmentsRead, PermAppointmentsWrite, PermAppointmentsDelete,
+	PermServicesRead, PermServicesWrite, PermClinicsRead, PermClinicsWrite,
+	PermReviewsRead, PermReviewsModerate,
+	PermAnalysesRead, PermAnalysesWrite, PermPrescriptionsRead, PermPrescriptionsWrite,
+	PermFamilyRead, PermFamilyWrite,
+	PermAuditRead, PermSecurityRead, PermSecurityWrite, PermSecuritySettingsWrite,
+	PermSettingsRead, PermSettingsWrite, PermLegalRead, PermLegalWrite,
+	PermBackupsRead, PermBackupsCreate, PermBackupsRestore,
+	PermAdminsManage,
+}
+
+// rolePermissions сопоставляет роли и их права.
+var rolePermissions = map[AdminRole][]Permission{
+	RoleSuperAdmin: AllPermissions,
+	RoleAdmin: {
+		PermDashboardRead,
+		PermUsersRead, PermUsersWrite, PermUsersDelete, PermUsersImpersonate,
+		PermDoctorsRead, PermDoctorsWrite, PermSchedulesRead, PermSchedulesWrite,
+		PermAppointmentsRead, PermAppointmentsWrite, PermAppointmentsDelete,
+		PermServicesRead, PermServicesWrite, PermClinicsRead, PermClinicsWrite,
+		PermReviewsRead, PermReviewsModerate,
+		PermAnalysesRead, PermAnalysesWrite, PermPrescriptionsRead, PermPrescriptionsWrite,
+		PermFamilyRead, PermFamilyWrite,
+		PermAuditRead, PermSecurityRead, PermSecurityWrite,
+		PermSettingsRead, PermSettingsWrite, PermLegalRead, PermLegalWrite,
+		PermBackupsRead, PermBackupsCreate,
+	},
+	RoleReceptionist: {
+		PermDashboardRead,
+		PermUsersRead, PermUsersWrite, PermUsersImpersonate,
+		PermDoctorsRead, PermSchedulesRead, PermSchedulesWrite,
+		PermAppointmentsRead, PermAppointmentsWrite,
+		PermServicesRead, PermClinicsRead,
+		PermReviewsRead,
+		PermFamilyRead, PermFamilyWrite,
+	},
+	RoleLabTechnician: {
+		PermDashboardRead,
+		PermUsersRead,
+		PermAnalysesRead, PermAnalysesWrite,
+		PermPrescriptionsRead,
+	},
+}
+
+// IsValid сообщает, является ли роль известной системе.
+func (r AdminRole) IsValid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Permissions возвращает список прав роли.
+func (r AdminRole) Permissions() []Permission {
+	return rolePermissions[r]
+}
+
+// HasPermission проверяет, обладает ли роль указанным правом.
+func (r AdminRole) HasPermission(permission Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -5,17 +5,44 @@ import (
 	"time"
 )
 
+// Статусы модерации отзыва.
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
 // Review представляет отзыв пациента о враче
 type Review struct {
-	ID          uint64         `gorm:"primarykey" db:"id" json:"id"`
-	UserID      uint64         `db:"user_id" json:"userID"`
-	DoctorID    uint64         `db:"doctor_id" json:"doctorID"`
-	Rating      uint16         `db:"rating" json:"rating"`
-	Comment     sql.NullString `db:"comment" json:"comment,omitzero"`
-	IsModerated bool           `db:"is_moderated" json:"isModerated"`
-	CreatedAt   time.Time      `db:"created_at" json:"createdAt"`
+	ID              uint64         `gorm:"primarykey" db:"id" json:"id"`
+	UserID          uint64         `db:"user_id" json:"userID"`
+	DoctorID        uint64         `db:"doctor_id" json:"doctorID"`
+	AppointmentID   sql.NullInt64  `db:"appointment_id" json:"appointmentID,omitzero"`
+	Rating          uint16         `db:"rating" json:"rating"`
+	Comment         sql.NullString `db:"comment" json:"comment,omitzero"`
+	Status          string         `db:"status" json:"status" example:"pending"`
+	RejectionReason sql.NullString `db:"rejection_reason" json:"rejectionReason,omitzero"`
+	ModeratedBy     sql.NullInt64  `db:"moderated_by" json:"moderatedBy,omitzero"`
+	ModeratedAt     sql.NullTime   `db:"moderated_at" json:"moderatedAt,omitzero"`
+	CreatedAt       time.Time      `db:"created_at" json:"createdAt"`
 }
 
 func (Review) TableName() string {
 	return "medical_center.reviews"
 }
+
+// DoctorReview - DTO одобренного отзыва на странице врача.
+// Вместо ID пациента показывается только имя и первая буква фамилии.
+type DoctorReview struct {
+	ID         uint64    `json:"id"`
+	Rating     uint16    `json:"rating" example:"5"`
+	Comment    string    `json:"comment,omitempty"`
+	AuthorName string    `json:"authorName" example:"Анна П."`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// ReviewFilter - параметры фильтрации отзывов в админ-панели.
+type ReviewFilter struct {
+	Status   string
+	DoctorID uint64
+}
@@ -9,6 +9,7 @@ type Schedule struct {
 	Date      time.Time `gorm:"type:date"`
 	StartTime time.Time `gorm:"type:time"`
 	EndTime   time.Time `gorm:"type:time"`
+	ClinicID  *uint64   // Клиника приема; пусто у расписания, созданного до назначения врачей в клиники
 }
 
 func (Schedule) TableName() string {
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Области (scope) защиты от перебора.
+const (
+	LockoutScopeUserLogin     = "user_login"
+	LockoutScopeAdminLogin    = "admin_login"
+	LockoutScopePasswordReset = "password_reset"
+	LockoutScopePhoneVerify   = "phone_verify"
+	LockoutScopeAdminTwoFA    = "admin_2fa"
+	LockoutScopeIP            = "ip"
+)
+
+// LockoutEvent фиксирует факт временной блокировки после серии неудачных попыток.
+type LockoutEvent struct {
+	ID             uint64    `gorm:"primarykey" json:"id"`
+	Scope          string    `gorm:"type:varchar(50);not null" json:"scope"`
+	Identifier     string    `gorm:"type:varchar(255);not null" json:"identifier"`
+	IPAddress      string    `gorm:"type:varchar(64)" json:"ipAddress"`
+	FailedAttempts int64     `json:"failedAttempts"`
+	LockedUntil    time.Time `json:"lockedUntil"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// TableName возвращает имя таблицы в базе данных.
+func (LockoutEvent) TableName() string {
+	return "medical_center.lockout_events"
+}
+
+// SecuritySettings - глобальные настройки безопасности (единственная запись с ID = 1).
+type SecuritySettings struct {
+	ID              uint8         `gorm:"primarykey" json:"-"`
+	RequireAdmin2FA bool          `gorm:"column:require_admin_2fa;not null" json:"requireAdmin2fa"`
+	UpdatedBy       sql.NullInt64 `json:"updatedBy,omitzero"`
+	UpdatedAt       time.Time     `json:"updatedAt"`
+}
+
+// TableName возвращает имя таблицы в базе данных.
+func (SecuritySettings) TableName() string {
+	return "medical_center.security_settings"
+}
+
+// ImpersonationLog фиксирует запрос, выполненный администратором от имени пациента.
+type ImpersonationLog struct {
+	ID         uint64    `gorm:"primarykey" json:"id"`
+	AdminID    uint64    `gorm:"not null" json:"adminId"`
+	UserID     uint64    `gorm:"not null" json:"userId"`
+	Method     string    `gorm:"type:varchar(10);not null" json:"method"`
+	Route      string    `gorm:"type:varchar(255);not null" json:"route"`
+	Path       string    `gorm:"type:varchar(2048);not null" json:"path"`
+	StatusCode int       `gorm:"not null" json:"statusCode"`
+	IPAddress  string    `gorm:"type:varchar(64)" json:"ipAddress"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// TableName возвращает имя таблицы в базе данных.
+func (ImpersonationLog) TableName() string {
+	return "medical_center.impersonation_logs"
+}
+
+// ImpersonationLogFilter - фильтры журнала имперсонации; нулевые значения не ограничивают выборку.
+type ImpersonationLogFilter struct {
+	AdminID uint64
+	UserID  uint64
+}
+
+// ImpersonationToken - DTO с access токеном для просмотра кабинета пациента от его имени.
+type ImpersonationToken struct {
+	AccessToken string    `json:"accessToken"`
+	UserID      uint64    `json:"userId"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+	ReadOnly    bool      `json:"readOnly"`
+}
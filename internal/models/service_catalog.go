@@ -0,0 +1,116 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ServiceCategory - категория каталога услуг внутри отделения
+type ServiceCategory struct {
+	ID           uint32 `gorm:"primarykey" db:"id" json:"id"`
+	Name         string `db:"name" json:"name"`
+	DepartmentID uint32 `db:"department_id" json:"departmentID"`
+}
+
+func (ServiceCategory) TableName() string {
+	return "medical_center.service_categories"
+}
+
+// Service представляет позицию каталога услуг. Услугу оказывают врачи, назначенные на нее
+// (DoctorService): у каждого из них цена и длительность могут отличаться от базовых.
+type Service struct {
+	ID              uint64         `gorm:"primarykey" db:"id" json:"id"`
+	Code            string         `db:"code" json:"code" example:"SRV-0001"`
+	Name            string         `db:"name" json:"name"`
+	CategoryID      uint32         `db:"category_id" json:"categoryID"`
+	BasePrice       float64        `db:"base_price" json:"basePrice"`
+	DurationMinutes uint16         `db:"duration_minutes" json:"durationMinutes"`
+	Description     sql.NullString `db:"description" json:"description,omitzero"`
+	Recommendations sql.NullString `json:"recommendations,omitzero"`
+	DeletedAt       gorm.DeletedAt `db:"deleted_at" json:"deletedAt,omitzero"`
+}
+
+func (Service) TableName() string {
+	return "medical_center.services"
+}
+
+// DoctorService назначает услугу каталога врачу. Пустые цена и длительность означают базовые значения услуги.
+type DoctorService struct {
+	DoctorID        uint64   `gorm:"primaryKey" db:"doctor_id" json:"doctorID"`
+	ServiceID       uint64   `gorm:"primaryKey" db:"service_id" json:"serviceID"`
+	Price           *float64 `db:"price" json:"price,omitempty"`
+	DurationMinutes *uint16  `db:"duration_minutes" json:"durationMinutes,omitempty"`
+}
+
+func (DoctorService) TableName() string {
+	return "medical_center.doctor_services"
+}
+
+// ServicePriceChange - запись истории цен услуги. Без DoctorID - изменение базовой цены, с DoctorID -
+// изменение цены для врача. Пустая OldPrice - цены еще не было, пустая NewPrice - врач перестал оказывать услугу.
+type ServicePriceChange struct {
+	ID        uint64    `gorm:"primarykey" db:"id" json:"id"`
+	ServiceID uint64    `db:"service_id" json:"serviceID"`
+	DoctorID  *uint64   `db:"doctor_id" json:"doctorID,omitempty"`
+	OldPrice  *float64  `db:"old_price" json:"oldPrice"`
+	NewPrice  *float64  `db:"new_price" json:"newPrice"`
+	ChangedBy *uint64   `db:"changed_by" json:"changedBy,omitempty"`
+	ChangedAt time.Time `gorm:"autoCreateTime" db:"changed_at" json:"changedAt"`
+}
+
+func (ServicePriceChange) TableName() string {
+	return "medical_center.service_price_history"
+}
+
+// DoctorServiceOffer - DTO услуги врача с действующими для этого врача ценой и длительностью.
+type DoctorServiceOffer struct {
+	ServiceID       uint64  `json:"serviceID"`
+	Code            string  `json:"code"`
+	Name            string  `json:"name"`
+	CategoryID      uint32  `json:"categoryID"`
+	Price           float64 `json:"price"`
+	DurationMinutes uint16  `json:"durationMinutes"`
+}
+
+// PriceListRow - строка прейскуранта: услуга с отделением, категорией и диапазоном цен у ее врачей.
+type PriceListRow struct {
+	DepartmentID    uint32
+	DepartmentName  string
+	CategoryID      uint32
+	CategoryName    string
+	ServiceID       uint64
+	Code            string
+	Name            string
+	Description     sql.NullString
+	DurationMinutes uint16
+	PriceFrom       float64
+	PriceTo         float64
+}
+
+// PriceListItem - DTO услуги в прейскуранте. Если врачи оказывают услугу по разной цене,
+// priceFrom и priceTo показывают диапазон, иначе совпадают.
+type PriceListItem struct {
+	ID              uint64  `json:"id"`
+	Code            string  `json:"code"`
+	Name            string  `json:"name"`
+	Description     string  `json:"description,omitempty"`
+	DurationMinutes uint16  `json:"durationMinutes"`
+	PriceFrom       float64 `json:"priceFrom"`
+	PriceTo         float64 `json:"priceTo"`
+}
+
+// PriceListCategory - категория услуг в прейскуранте.
+type PriceListCategory struct {
+	ID       uint32          `json:"id"`
+	Name     string          `json:"name"`
+	Services []PriceListItem `json:"services"`
+}
+
+// PriceListDepartment - раздел прейскуранта по отделению.
+type PriceListDepartment struct {
+	ID         uint32              `json:"id"`
+	Name       string              `json:"name"`
+	Categories []PriceListCategory `json:"categories"`
+}
@@ -13,3 +13,23 @@ type RefreshToken struct {
 func (RefreshToken) TableName() string {
 	return "medical_center.refresh_tokens"
 }
+
+// AdminSession - сессия администратора с ротируемым refresh-токеном.
+// В отличие от пациентов, у администратора может быть несколько сессий (по одной на устройство).
+// Хранится только хэш секретной части токена; PreviousTokenHash - хэш токена до последней ротации.
+type AdminSession struct {
+	ID                uint64    `gorm:"primarykey" json:"id"`
+	AdminID           uint64    `gorm:"not null" json:"adminId"`
+	TokenHash         string    `gorm:"type:varchar(64);not null" json:"-"`
+	PreviousTokenHash string    `gorm:"type:varchar(64);not null;default:''" json:"-"`
+	IPAddress         string    `gorm:"type:varchar(45)" json:"ipAddress"`
+	UserAgent         string    `json:"userAgent"`
+	CreatedAt         time.Time `json:"createdAt"`
+	LastUsedAt        time.Time `gorm:"not null" json:"lastUsedAt"`
+	ExpiresAt         time.Time `gorm:"not null" json:"expiresAt"`
+	Current           bool      `gorm:"-" json:"current"`
+}
+
+func (AdminSession) TableName() string {
+	return "medical_center.admin_sessions"
+}
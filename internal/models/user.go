@@ -6,17 +6,22 @@ package models
 import (
 	"database/sql"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // User представляет пользователя системы
 type User struct {
-	ID           uint64         `gorm:"primarykey" db:"id" json:"id"`
-	Phone        string         `gorm:"unique" db:"phone" json:"phone"`
-	PasswordHash string         `db:"password_hash" json:"-"`
-	GosuslugiID  sql.NullString `gorm:"unique" db:"gosuslugi_id" json:"gosuslugiID,omitempty"`
-	IsActive     bool           `db:"is_active" json:"isActive"`
-	CreatedAt    time.Time      `db:"created_at" json:"createdAt"`
-	UpdatedAt    time.Time      `db:"updated_at" json:"updatedAt"`
+	ID              uint64         `gorm:"primarykey" db:"id" json:"id"`
+	Phone           string         `gorm:"unique" db:"phone" json:"phone"`
+	PasswordHash    string         `db:"password_hash" json:"-"`
+	GosuslugiID     sql.NullString `gorm:"unique" db:"gosuslugi_id" json:"gosuslugiID,omitempty"`
+	IsActive        bool           `db:"is_active" json:"isActive"`
+	PhoneVerifiedAt sql.NullTime   `db:"phone_verified_at" json:"phoneVerifiedAt,omitzero"`
+	AnonymizedAt    sql.NullTime   `db:"anonymized_at" json:"anonymizedAt,omitzero"`
+	CreatedAt       time.Time      `db:"created_at" json:"createdAt"`
+	UpdatedAt       time.Time      `db:"updated_at" json:"updatedAt"`
+	DeletedAt       gorm.DeletedAt `db:"deleted_at" json:"deletedAt,omitzero"`
 }
 
 func (User) TableName() string {
@@ -25,18 +30,25 @@ func (User) TableName() string {
 
 // UserProfile содержит расширенную информацию о пользователе
 type UserProfile struct {
-	ID         uint64         `gorm:"primarykey" db:"id" json:"id"`
-	UserID     uint64         `gorm:"uniqueIndex" db:"user_id" json:"userID"`
-	FirstName  string         `db:"first_name" json:"firstName"`
-	LastName   string         `db:"last_name" json:"lastName"`
-	Patronymic sql.NullString `db:"patronymic" json:"patronymic,omitempty"`
-	BirthDate  time.Time      `db:"birth_date" json:"birthDate"`
-	Gender     string         `db:"gender" json:"gender"`
-	CityID     uint32         `db:"city_id" json:"cityID"`
-	Email      sql.NullString `gorm:"unique" db:"email" json:"email,omitempty"`
-	AvatarURL  sql.NullString `db:"avatar_url" json:"avatarURL,omitempty"`
+	ID              uint64         `gorm:"primarykey" db:"id" json:"id"`
+	UserID          uint64         `gorm:"uniqueIndex" db:"user_id" json:"userID"`
+	FirstName       string         `db:"first_name" json:"firstName"`
+	LastName        string         `db:"last_name" json:"lastName"`
+	Patronymic      sql.NullString `db:"patronymic" json:"patronymic,omitempty"`
+	BirthDate       time.Time      `db:"birth_date" json:"birthDate"`
+	Gender          string         `db:"gender" json:"gender"`
+	CityID          uint32         `db:"city_id" json:"cityID"`
+	Email           sql.NullString `gorm:"unique" db:"email" json:"email,omitempty"`
+	EmailVerifiedAt sql.NullTime   `db:"email_verified_at" json:"emailVerifiedAt,omitzero"`
+	AvatarURL       sql.NullString `db:"avatar_url" json:"avatarURL,omitempty"`
 }
 
 func (UserProfile) TableName() string {
 	return "medical_center.user_profiles"
 }
+
+// Avatar - загруженный аватар: ключ основного изображения и ключи миниатюр по размеру стороны в пикселях.
+type Avatar struct {
+	URL        string         `json:"avatarURL"`
+	Thumbnails map[int]string `json:"thumbnails"`
+}
@@ -0,0 +1,310 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"lk/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// anonymizedName - имя и фамилия, которые получает обезличенный пациент.
+const anonymizedName = "Удалено"
+
+// AccountPostgres реализует AccountRepository для PostgreSQL.
+type AccountPostgres struct {
+	db *gorm.DB
+}
+
+// NewAccountPostgres создает новый экземпляр репозитория выгрузок и удаления аккаунтов.
+func NewAccountPostgres(db *gorm.DB) *AccountPostgres {
+	return &AccountPostgres{db: db}
+}
+
+// CreateExport ставит в очередь выгрузку данных пациента.
+// Если выгрузка уже собирается, возвращает ошибку уникальности.
+func (r *AccountPostgres) CreateExport(ctx context.Context, userID uint64) (models.DataExport, error) {
+	export := models.DataExport{UserID: userID, Status: models.DataExportPending, CreatedAt: time.Now()}
+	err := r.db.WithContext(ctx).Create(&export).Error
+	return export, err
+}
+
+// GetExports возвращает последние выгрузки пациента, новые сверху.
+func (r *AccountPostgres) GetExports(ctx context.Context, userID uint64, limit int) ([]models.DataExport, error) {
+	var exports []models.DataExport
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").Limit(limit).Find(&exports).Error
+	return exports, err
+}
+
+// GetExport возвращает выгрузку пациента по ID.
+func (r *AccountPostgres) GetExport(ctx context.Context, userID, exportID uint64) (models.DataExport, error) {
+	var export models.DataExport
+	err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", exportID, userID).First(&export).Error
+	return export, err
+}
+
+// ClaimExport забирает из очереди самую старую выгрузку и переводит ее в processing.
+// Выгрузки, зависшие в processing дольше staleBefore (например, после падения экземпляра),
+// забираются повторно. SKIP LOCKED позволяет нескольким экземплярам разбирать очередь параллельно.
+// Возвращает gorm.ErrRecordNotFound, если очередь пуста.
+func (r *AccountPostgres) ClaimExport(ctx context.Context, staleBefore time.Time) (models.DataExport, error) {
+	var export models.DataExport
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE medical_center.data_export_requests
+		SET status = ?, started_at = CURRENT_TIMESTAMP
+		WHERE id = (
+			SELECT id FROM medical_center.data_export_requests
+			WHERE status = ? OR (status = ? AND started_at < ?)
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		models.DataExportProcessing, models.DataExportPending, models.DataExportProcessing, staleBefore).
+		Scan(&export).Error
+	if err != nil {
+		return models.DataExport{}, err
+	}
+	if export.ID == 0 {
+		return models.DataExport{}, gorm.ErrRecordNotFound
+	}
+	return export, nil
+}
+
+// CompleteExport отмечает выгрузку готовой к скачиванию.
+// Возвращает gorm.ErrRecordNotFound, если выгрузка больше не в processing
+// (например, аккаунт успели удалить, пока собирался архив).
+func (r *AccountPostgres) CompleteExport(ctx context.Context, exportID uint64, fileKey string, fileSize int64,
+	expiresAt time.Time,
+) error {
+	result := r.db.WithContext(ctx).Model(&models.DataExport{}).
+		Where("id = ? AND status = ?", exportID, models.DataExportProcessing).
+		Updates(map[string]any{
+			"status":       models.DataExportReady,
+			"file_key":     fileKey,
+			"file_size":    fileSize,
+			"completed_at": time.Now(),
+			"expires_at":   expiresAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// FailExport отмечает выгрузку как неудавшуюся.
+func (r *AccountPostgres) FailExport(ctx context.Context, exportID uint64, reason string) error {
+	return r.db.WithContext(ctx).Model(&models.DataExport{}).
+		Where("id = ? AND status = ?", exportID, models.DataExportProcessing).
+		Updates(map[string]any{
+			"status":       models.DataExportFailed,
+			"error":        reason,
+			"completed_at": time.Now(),
+		}).Error
+}
+
+// GetExpiredExports возвращает готовые выгрузки с истекшим сроком хранения.
+func (r *AccountPostgres) GetExpiredExports(ctx context.Context, now time.Time, limit int) (
+	[]models.DataExport, error,
+) {
+	var exports []models.DataExport
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND expires_at <= ?", models.DataExportReady, now).
+		Order("expires_at").Limit(limit).Find(&exports).Error
+	return exports, err
+}
+
+// MarkExportExpired отмечает выгрузку истекшей после удаления архива из хранилища.
+func (r *AccountPostgres) MarkExportExpired(ctx context.Context, exportID uint64) error {
+	return r.db.WithContext(ctx).Model(&models.DataExport{}).
+		Where("id = ?", exportID).
+		Updates(map[string]any{"status": models.DataExportExpired, "file_key": nil}).Error
+}
+
+// GetExportAppointments возвращает все записи пациента на прием с именами врача, услуги и клиники
+// и ключом файла результата.
+func (r *AccountPostgres) GetExportAppointments(ctx context.Context, userID uint64) (
+	[]models.ExportAppointment, error,
+) {
+	var appointments []models.ExportAppointment
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT a.id, a.appointment_date, a.appointment_time, st.name AS status,
+			concat_ws(' ', d.last_name, d.first_name, d.patronymic) AS doctor_name,
+			s.name AS service_name, c.name AS clinic_name,
+			a.price_at_booking, a.is_dms, a.pre_visit_instructions, a.diagnosis, a.recommendations,
+			COALESCE(a.result_file_url, '') AS result_file_url, a.created_at
+		FROM medical_center.appointments a
+		JOIN medical_center.appointmentstatuses st ON st.id = a.status_id
+		JOIN medical_center.doctors d ON d.id = a.doctor_id
+		JOIN medical_center.services s ON s.id = a.service_id
+		JOIN medical_center.clinics c ON c.id = a.clinic_id
+		WHERE a.user_id = ? AND a.deleted_at IS NULL
+		ORDER BY a.appointment_date DESC, a.appointment_time DESC`, userID).
+		Scan(&appointments).Error
+	return appointments, err
+}
+
+// GetExportAnalyses возвращает все анализы пациента с названием статуса и ключом файла результата.
+func (r *AccountPostgres) GetExportAnalyses(ctx context.Context, userID uint64) ([]models.ExportAnalysis, error) {
+	var analyses []models.ExportAnalysis
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT la.id, la.name, la.assigned_date, st.name AS status, la.appointment_id,
+			COALESCE(la.result_file_url, '') AS result_file_url, COALESCE(la.result_file_name, '') AS file_name
+		FROM medical_center.labanalyses la
+		JOIN medical_center.analysisstatuses st ON st.id = la.status_id
+		WHERE la.user_id = ?
+		ORDER BY la.assigned_date DESC, la.id DESC`, userID).
+		Scan(&analyses).Error
+	return analyses, err
+}
+
+// GetPrescriptions возвращает все назначения пациента, включая архивные.
+func (r *AccountPostgres) GetPrescriptions(ctx context.Context, userID uint64) ([]models.Prescription, error) {
+	var prescriptions []models.Prescription
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).
+		Order("created_at DESC").Find(&prescriptions).Error
+	return prescriptions, err
+}
+
+// GetConsents возвращает всю историю согласий пациента.
+func (r *AccountPostgres) GetConsents(ctx context.Context, userID uint64) ([]models.UserConsent, error) {
+	var consents []models.UserConsent
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).
+		Order("accepted_at DESC, id DESC").Find(&consents).Error
+	return consents, err
+}
+
+// CreateDeletion сохраняет запрос на удаление аккаунта.
+// Если у пациента уже есть ожидающий запрос, возвращает ошибку уникальности.
+func (r *AccountPostgres) CreateDeletion(ctx context.Context, deletion models.AccountDeletion) (
+	models.AccountDeletion, error,
+) {
+	err := r.db.WithContext(ctx).Create(&deletion).Error
+	return deletion, err
+}
+
+// GetPendingDeletion возвращает ожидающий запрос пациента на удаление аккаунта.
+func (r *AccountPostgres) GetPendingDeletion(ctx context.Context, userID uint64) (models.AccountDeletion, error) {
+	var deletion models.AccountDeletion
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND status = ?", userID, models.AccountDeletionPending).
+		First(&deletion).Error
+	return deletion, err
+}
+
+// CancelDeletion отменяет ожидающий запрос на удаление, если срок ожидания еще не истек.
+// Возвращает количество отмененных запросов.
+func (r *AccountPostgres) CancelDeletion(ctx context.Context, userID uint64) (int64, error) {
+	now := time.Now()
+	result := r.db.WithContext(ctx).Model(&models.AccountDeletion{}).
+		Where("user_id = ? AND status = ? AND scheduled_at > ?", userID, models.AccountDeletionPending, now).
+		Updates(map[string]any{"status": models.AccountDeletionCancelled, "cancelled_at": now})
+	return result.RowsAffected, result.Error
+}
+
+// GetDueDeletions возвращает ожидающие запросы, срок ожидания которых истек.
+func (r *AccountPostgres) GetDueDeletions(ctx context.Context, now time.Time, limit int) (
+	[]models.AccountDeletion, error,
+) {
+	var deletions []models.AccountDeletion
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND scheduled_at <= ?", models.AccountDeletionPending, now).
+		Order("scheduled_at").Limit(limit).Find(&deletions).Error
+	return deletions, err
+}
+
+// AnonymizeUser выполняет запрос на удаление аккаунта в одной транзакции: обезличивает
+// учетную запись и профиль, отменяет будущие записи на прием, завершает сессию, отзывает согласия,
+// отвязывает файлы и стирает значения полей в журнале аудита. Медицинские записи (приемы, анализы, назначения) сохраняются, но больше
+// не связаны с персональными данными. Возвращает ключи файлов, которые нужно удалить из хранилища.
+// Если запрос уже выполнен или отменен, возвращает gorm.ErrRecordNotFound.
+func (r *AccountPostgres) AnonymizeUser(ctx context.Context, deletionID, userID uint64) ([]string, error) {
+	var fileKeys []string
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&models.AccountDeletion{}).
+			Where("id = ? AND user_id = ? AND status = ? AND scheduled_at <= ?",
+				deletionID, userID, models.AccountDeletionPending, now).
+			Updates(map[string]any{"status": models.AccountDeletionCompleted, "completed_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		err := tx.Raw(`
+			SELECT avatar_url FROM medical_center.user_profiles
+			WHERE user_id = @user AND COALESCE(avatar_url, '') <> ''
+			UNION ALL
+			SELECT result_file_url FROM medical_center.labanalyses
+			WHERE user_id = @user AND COALESCE(result_file_url, '') <> ''
+			UNION ALL
+			SELECT result_file_url FROM medical_center.appointments
+			WHERE user_id = @user AND COALESCE(result_file_url, '') <> ''
+			UNION ALL
+			SELECT file_key FROM medical_center.data_export_requests
+			WHERE user_id = @user AND file_key IS NOT NULL`,
+			map[string]any{"user": userID}).Scan(&fileKeys).Error
+		if err != nil {
+			return err
+		}
+
+		statements := []struct {
+			sql  string
+			args []any
+		}{
+			{`UPDATE medical_center.users
+				SET phone = 'deleted:' || id, password_hash = '', gosuslugi_id = NULL,
+					is_active = false, anonymized_at = ?, updated_at = ?
+				WHERE id = ?`, []any{now, now, userID}},
+			// Год рождения сохраняется для статистики, остальные персональные данные стираются
+			{`UPDATE medical_center.user_profiles
+				SET first_name = ?, last_name = ?, patronymic = NULL,
+					birth_date = date_trunc('year', birth_date), email = NULL, email_verified_at = NULL,
+					avatar_url = NULL
+				WHERE user_id = ?`, []any{anonymizedName, anonymizedName, userID}},
+			{`UPDATE medical_center.appointments SET status_id = ?, updated_at = ?
+				WHERE user_id = ? AND status_id = ? AND appointment_date >= CURRENT_DATE`,
+				[]any{models.StatusCancelledByPatient, now, userID, models.StatusScheduled}},
+			{`UPDATE medical_center.appointments SET result_file_url = NULL WHERE user_id = ?`, []any{userID}},
+			{`UPDATE medical_center.labanalyses SET result_file_url = NULL, result_file_name = NULL
+				WHERE user_id = ?`, []any{userID}},
+			{`UPDATE medical_center.data_export_requests
+				SET file_key = NULL,
+					status = CASE WHEN status IN ('pending', 'processing', 'ready') THEN 'expired' ELSE status END
+				WHERE user_id = ?`, []any{userID}},
+			// Факт принятия документов сохраняется как доказательство, сведения об устройстве стираются
+			{`UPDATE medical_center.user_consents
+				SET revoked_at = COALESCE(revoked_at, ?), ip_address = NULL, user_agent = NULL
+				WHERE user_id = ?`, []any{now, userID}},
+			// Журнал аудита сохраняет факт действий и названия измененных полей, но значения
+			// (телефон, ФИО, email, состояния записей) заменяются, иначе данные пережили бы обезличивание
+			{`UPDATE medical_center.audit_logs
+				SET changes = (
+					SELECT jsonb_object_agg(key, jsonb_build_object(
+						'before', CASE WHEN COALESCE(jsonb_typeof(value->'before'), 'null') = 'null'
+							THEN 'null'::jsonb ELSE to_jsonb(?::text) END,
+						'after', CASE WHEN COALESCE(jsonb_typeof(value->'after'), 'null') = 'null'
+							THEN 'null'::jsonb ELSE to_jsonb(?::text) END))
+					FROM jsonb_each(changes))
+				WHERE changes IS NOT NULL
+					AND (target_user_id = ? OR (actor_type = ? AND actor_id = ?))`,
+				[]any{anonymizedName, anonymizedName, userID, models.AuditActorUser, userID}},
+			{`DELETE FROM medical_center.refresh_tokens WHERE user_id = ?`, []any{userID}},
+			{`DELETE FROM medical_center.phone_normalization_conflicts WHERE user_id = ?`, []any{userID}},
+		}
+		for _, stmt := range statements {
+			if err := tx.Exec(stmt.sql, stmt.args...).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return fileKeys, err
+}
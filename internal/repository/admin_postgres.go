@@ -2,6 +2,9 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"slices"
 	"time"
 
 	"lk/internal/models"
@@ -10,6 +13,13 @@ import (
 	"gorm.io/gorm/clause"
 )
 
+// Ошибки удаления справочников, на которые еще ссылаются действующие записи.
+var (
+	ErrServiceHasUpcomingAppointments = errors.New("service has upcoming appointments")
+	ErrDepartmentHasServices          = errors.New("department has active services")
+	ErrDepartmentHasDoctors           = errors.New("department has active specialists")
+)
+
 type AdminPostgres struct {
 	db *gorm.DB
 }
@@ -32,6 +42,194 @@ func (r *AdminPostgres) GetByID(ctx context.Context, id uint64) (models.Admin, e
 	return admin, err
 }
 
+// GetAllAdmins возвращает пагинированный список администраторов.
+func (r *AdminPostgres) GetAllAdmins(ctx context.Context, params models.PaginationParams) (
+	[]models.Admin, int64, error,
+) {
+	var admins []models.Admin
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Admin{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (params.Page - 1) * params.Limit
+	err := query.Order("id ASC").Limit(params.Limit).Offset(offset).Find(&admins).Error
+	return admins, total, err
+}
+
+// UpdateAdminRole изменяет роль администратора.
+func (r *AdminPostgres) UpdateAdminRole(ctx context.Context, adminID uint64, role models.AdminRole) error {
+	result := r.db.WithContext(ctx).Model(&models.Admin{}).Where("id = ?", adminID).Update("role", role)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// CountAdminsByRole возвращает количество активных администраторов с указанной ролью.
+func (r *AdminPostgres) CountAdminsByRole(ctx context.Context, role models.AdminRole) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Admin{}).Where("role = ? AND is_active", role).Count(&count).Error
+	return count, err
+}
+
+// CountAdmins возвращает общее количество учетных записей администраторов.
+func (r *AdminPostgres) CountAdmins(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Admin{}).Count(&count).Error
+	return count, err
+}
+
+// CreateAdmin создает учетную запись администратора и возвращает ее ID.
+func (r *AdminPostgres) CreateAdmin(ctx context.Context, admin models.Admin) (uint64, error) {
+	if err := r.db.WithContext(ctx).Create(&admin).Error; err != nil {
+		return 0, err
+	}
+	return admin.ID, nil
+}
+
+// UpdateAdmin обновляет логин, ФИО и адрес электронной почты администратора.
+func (r *AdminPostgres) UpdateAdmin(ctx context.Context, admin models.Admin) error {
+	result := r.db.WithContext(ctx).Model(&models.Admin{}).Where("id = ?", admin.ID).
+		Updates(map[string]interface{}{
+			"login":      admin.Login,
+			"full_name":  admin.FullName,
+			"email":      admin.Email,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// SetAdminActive активирует или деактивирует учетную запись администратора.
+func (r *AdminPostgres) SetAdminActive(ctx context.Context, adminID uint64, active bool) error {
+	result := r.db.WithContext(ctx).Model(&models.Admin{}).Where("id = ?", adminID).
+		Updates(map[string]interface{}{"is_active": active, "updated_at": time.Now()})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetActiveAdminsWithEmail получает действующих администраторов, у которых указан адрес электронной почты.
+func (r *AdminPostgres) GetActiveAdminsWithEmail(ctx context.Context) ([]models.Admin, error) {
+	var admins []models.Admin
+	err := r.db.WithContext(ctx).Where("is_active AND COALESCE(email, '') <> ''").Order("id").Find(&admins).Error
+	return admins, err
+}
+
+// UpdateAdminPassword сохраняет новый хэш пароля и признак обязательной смены пароля.
+func (r *AdminPostgres) UpdateAdminPassword(ctx context.Context, adminID uint64, passwordHash string,
+	mustChange bool,
+) error {
+	result := r.db.WithContext(ctx).Model(&models.Admin{}).Where("id = ?", adminID).
+		Updates(map[string]interface{}{
+			"password_hash":        passwordHash,
+			"must_change_password": mustChange,
+			"updated_at":           time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// UpdateLastLogin фиксирует время последнего успешного входа.
+func (r *AdminPostgres) UpdateLastLogin(ctx context.Context, adminID uint64) error {
+	return r.db.WithContext(ctx).Model(&models.Admin{}).Where("id = ?", adminID).
+		Update("last_login_at", time.Now()).Error
+}
+
+// --- Two-factor ---
+
+// EnableTOTP сохраняет секрет TOTP, включает 2FA и заменяет резервные коды.
+func (r *AdminPostgres) EnableTOTP(ctx context.Context, adminID uint64, secret string,
+	recoveryCodeHashes []string,
+) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.Admin{}).Where("id = ?", adminID).Updates(map[string]interface{}{
+			"totp_secret":     secret,
+			"totp_enabled_at": time.Now(),
+		}).Error
+		if err != nil {
+			return err
+		}
+		return replaceRecoveryCodes(tx, adminID, recoveryCodeHashes)
+	})
+}
+
+// DisableTOTP отключает 2FA и удаляет резервные коды.
+func (r *AdminPostgres) DisableTOTP(ctx context.Context, adminID uint64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.Admin{}).Where("id = ?", adminID).Updates(map[string]interface{}{
+			"totp_secret":     nil,
+			"totp_enabled_at": nil,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("admin_id = ?", adminID).Delete(&models.AdminRecoveryCode{}).Error
+	})
+}
+
+// ReplaceRecoveryCodes удаляет все резервные коды администратора и сохраняет новые.
+func (r *AdminPostgres) ReplaceRecoveryCodes(ctx context.Context, adminID uint64, recoveryCodeHashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, adminID, recoveryCodeHashes)
+	})
+}
+
+// UseRecoveryCode помечает неиспользованный резервный код как использованный.
+// Возвращает false, если такого кода нет или он уже был использован.
+func (r *AdminPostgres) UseRecoveryCode(ctx context.Context, adminID uint64, codeHash string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.AdminRecoveryCode{}).
+		Where("admin_id = ? AND code_hash = ? AND used_at IS NULL", adminID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CountRecoveryCodes возвращает количество неиспользованных резервных кодов.
+func (r *AdminPostgres) CountRecoveryCodes(ctx context.Context, adminID uint64) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.AdminRecoveryCode{}).
+		Where("admin_id = ? AND used_at IS NULL", adminID).Count(&count).Error
+	return count, err
+}
+
+// replaceRecoveryCodes заменяет резервные коды внутри переданной транзакции.
+func replaceRecoveryCodes(tx *gorm.DB, adminID uint64, recoveryCodeHashes []string) error {
+	if err := tx.Where("admin_id = ?", adminID).Delete(&models.AdminRecoveryCode{}).Error; err != nil {
+		return err
+	}
+	if len(recoveryCodeHashes) == 0 {
+		return nil
+	}
+	codes := make([]models.AdminRecoveryCode, 0, len(recoveryCodeHashes))
+	for _, hash := range recoveryCodeHashes {
+		codes = append(codes, models.AdminRecoveryCode{AdminID: adminID, CodeHash: hash})
+	}
+	return tx.Create(&codes).Error
+}
+
 // --- User ---
 
 func (r *AdminPostgres) GetAllUsers(ctx context.Context, params models.PaginationParams) ([]models.User, int64, error) {
@@ -62,8 +260,25 @@ func (r *AdminPostgres) UpdateUser(ctx context.Context, user models.User, profil
 	})
 }
 
+// DeleteUser мягко удаляет пользователя и завершает его сессию.
+// Профиль и медицинские данные сохраняются до окончательного удаления.
 func (r *AdminPostgres) DeleteUser(ctx context.Context, userID uint64) error {
-	return r.db.WithContext(ctx).Select(clause.Associations).Delete(&models.User{}, userID).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := softDelete[models.User](ctx, tx, userID); err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.RefreshToken{}).Error
+	})
+}
+
+func (r *AdminPostgres) GetDeletedUsers(ctx context.Context, params models.PaginationParams) (
+	[]models.User, int64, error,
+) {
+	return findDeleted[models.User](ctx, r.db, params)
+}
+
+func (r *AdminPostgres) RestoreUser(ctx context.Context, userID uint64) error {
+	return restoreDeleted[models.User](ctx, r.db, userID)
 }
 
 func (r *AdminPostgres) GetUserAppointments(ctx context.Context, userID uint64, params models.PaginationParams) (
@@ -117,11 +332,21 @@ func (r *AdminPostgres) CreateDoctor(ctx context.Context, doctor models.Doctor)
 }
 
 func (r *AdminPostgres) UpdateDoctor(ctx context.Context, doctor models.Doctor) error {
-	return r.db.WithContext(ctx).Save(&doctor).Error
+	return saveExisting(ctx, r.db, doctor.ID, &doctor)
 }
 
 func (r *AdminPostgres) DeleteDoctor(ctx context.Context, doctorID uint64) error {
-	return r.db.WithContext(ctx).Delete(&models.Doctor{}, doctorID).Error
+	return softDelete[models.Doctor](ctx, r.db, doctorID)
+}
+
+func (r *AdminPostgres) GetDeletedSpecialists(ctx context.Context, params models.PaginationParams) (
+	[]models.Doctor, int64, error,
+) {
+	return findDeleted[models.Doctor](ctx, r.db, params)
+}
+
+func (r *AdminPostgres) RestoreDoctor(ctx context.Context, doctorID uint64) error {
+	return restoreDeleted[models.Doctor](ctx, r.db, doctorID)
 }
 
 func (r *AdminPostgres) GetDoctorSchedule(ctx context.Context, doctorID uint64) ([]models.Schedule, error) {
@@ -148,6 +373,132 @@ func (r *AdminPostgres) UpdateDoctorSchedule(ctx context.Context, doctorID uint6
 	})
 }
 
+// --- Doctor profile ---
+
+// CreateDoctorProfileItem добавляет запись в раздел профиля врача.
+// item - указатель на модель раздела (DoctorEducation, DoctorCertificate и т.д.).
+func (r *AdminPostgres) CreateDoctorProfileItem(ctx context.Context, item any) error {
+	return r.db.WithContext(ctx).Create(item).Error
+}
+
+// UpdateDoctorProfileItem перезаписывает запись раздела профиля врача.
+// Возвращает gorm.ErrRecordNotFound, если записи нет или она относится к другому врачу.
+func (r *AdminPostgres) UpdateDoctorProfileItem(ctx context.Context, doctorID, itemID uint64, item any) error {
+	result := r.db.WithContext(ctx).Model(item).Where("id = ? AND doctor_id = ?", itemID, doctorID).
+		Select("*").Omit("id", "doctor_id").Updates(item)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteDoctorProfileItem удаляет запись раздела профиля врача.
+func (r *AdminPostgres) DeleteDoctorProfileItem(ctx context.Context, doctorID, itemID uint64, item any) error {
+	result := r.db.WithContext(ctx).Where("doctor_id = ?", doctorID).Delete(item, itemID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// UpdateDoctorAvatar сохраняет ключ аватара врача. Пустая строка очищает аватар.
+func (r *AdminPostgres) UpdateDoctorAvatar(ctx context.Context, doctorID uint64, avatarURL string) error {
+	result := r.db.WithContext(ctx).Model(&models.Doctor{}).Where("id = ?", doctorID).
+		Update("avatar_url", sql.NullString{String: avatarURL, Valid: avatarURL != ""})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetClinicsByIDs получает клиники по списку идентификаторов.
+func (r *AdminPostgres) GetClinicsByIDs(ctx context.Context, ids []uint64) ([]models.Clinic, error) {
+	var clinics []models.Clinic
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Order("name, id").Find(&clinics).Error
+	return clinics, err
+}
+
+// SetDoctorClinics заменяет набор клиник врача. Снятие назначения, на которое ссылается
+// расписание врача, завершается ошибкой внешнего ключа.
+func (r *AdminPostgres) SetDoctorClinics(ctx context.Context, doctorID uint64, clinicIDs []uint64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		remove := tx.Where("doctor_id = ?", doctorID)
+		if len(clinicIDs) > 0 {
+			remove = remove.Where("clinic_id NOT IN ?", clinicIDs)
+		}
+		if err := remove.Delete(&models.DoctorClinic{}).Error; err != nil {
+			return err
+		}
+		if len(clinicIDs) == 0 {
+			return nil
+		}
+		links := make([]models.DoctorClinic, len(clinicIDs))
+		for i, clinicID := range clinicIDs {
+			links[i] = models.DoctorClinic{DoctorID: doctorID, ClinicID: clinicID}
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&links).Error
+	})
+}
+
+// expiringCertificateColumns - поля ExpiringCertificate в запросах к сертификатам (c) и врачам (d).
+const expiringCertificateColumns = `c.id, c.doctor_id, d.last_name || ' ' || d.first_name AS doctor_name,
+	c.cert_name, COALESCE(c.cert_number, '') AS cert_number, c.expires_at, c.expires_at < CURRENT_DATE AS expired`
+
+// expiringCertificates - сертификаты действующих врачей, срок которых истекает до before (включая истекшие).
+func (r *AdminPostgres) expiringCertificates(ctx context.Context, before time.Time) *gorm.DB {
+	return r.db.WithContext(ctx).Table("medical_center.doctorcertificates c").
+		Joins("JOIN medical_center.doctors d ON d.id = c.doctor_id AND d.deleted_at IS NULL").
+		Where("c.expires_at IS NOT NULL AND c.expires_at <= ?", before)
+}
+
+// GetExpiringCertificates возвращает сертификаты, срок которых истекает до before, ближайшие сверху.
+func (r *AdminPostgres) GetExpiringCertificates(ctx context.Context, before time.Time, params models.PaginationParams) (
+	[]models.ExpiringCertificate, int64, error,
+) {
+	var certificates []models.ExpiringCertificate
+	var total int64
+	query := r.expiringCertificates(ctx, before)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (params.Page - 1) * params.Limit
+	err := query.Select(expiringCertificateColumns).Order("c.expires_at ASC, c.id ASC").
+		Limit(params.Limit).Offset(offset).Scan(&certificates).Error
+	return certificates, total, err
+}
+
+// CountExpiringCertificates возвращает число сертификатов, срок которых истекает до before.
+func (r *AdminPostgres) CountExpiringCertificates(ctx context.Context, before time.Time) (int64, error) {
+	var total int64
+	err := r.expiringCertificates(ctx, before).Count(&total).Error
+	return total, err
+}
+
+// ClaimCertificateAlerts отмечает и возвращает истекающие сертификаты, о которых еще не предупреждали.
+// Отметка снимается при изменении сертификата, например после продления.
+func (r *AdminPostgres) ClaimCertificateAlerts(ctx context.Context, before time.Time) (
+	[]models.ExpiringCertificate, error,
+) {
+	var certificates []models.ExpiringCertificate
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE medical_center.doctorcertificates c
+		SET expiry_alerted_at = CURRENT_TIMESTAMP
+		FROM medical_center.doctors d
+		WHERE d.id = c.doctor_id AND d.deleted_at IS NULL
+			AND c.expires_at IS NOT NULL AND c.expires_at <= ? AND c.expiry_alerted_at IS NULL
+		RETURNING `+expiringCertificateColumns, before).Scan(&certificates).Error
+	return certificates, err
+}
+
 // --- Appointment ---
 
 func (r *AdminPostgres) GetAllAppointments(
@@ -169,7 +520,17 @@ func (r *AdminPostgres) GetAllAppointments(
 }
 
 func (r *AdminPostgres) DeleteAppointment(ctx context.Context, appointmentID uint64) error {
-	return r.db.WithContext(ctx).Delete(&models.Appointment{}, appointmentID).Error
+	return softDelete[models.Appointment](ctx, r.db, appointmentID)
+}
+
+func (r *AdminPostgres) GetDeletedAppointments(ctx context.Context, params models.PaginationParams) (
+	[]models.Appointment, int64, error,
+) {
+	return findDeleted[models.Appointment](ctx, r.db, params)
+}
+
+func (r *AdminPostgres) RestoreAppointment(ctx context.Context, appointmentID uint64) error {
+	return restoreDeleted[models.Appointment](ctx, r.db, appointmentID)
 }
 
 func (r *AdminPostgres) GetAppointmentStats(ctx context.Context) (map[string]int64, error) {
@@ -197,17 +558,243 @@ func (r *AdminPostgres) GetAllServices(ctx context.Context) ([]models.Service, e
 	return services, err
 }
 
-func (r *AdminPostgres) CreateService(ctx context.Context, service models.Service) (uint64, error) {
-	result := r.db.WithContext(ctx).Create(&service)
-	return service.ID, result.Error
+// GetServiceByID получает действующую услугу каталога.
+func (r *AdminPostgres) GetServiceByID(ctx context.Context, serviceID uint64) (models.Service, error) {
+	var service models.Service
+	err := r.db.WithContext(ctx).First(&service, serviceID).Error
+	return service, err
+}
+
+// CreateService сохраняет услугу каталога и начинает ее историю цен с базовой цены.
+func (r *AdminPostgres) CreateService(ctx context.Context, service models.Service, changedBy *uint64) (uint64, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&service).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.ServicePriceChange{
+			ServiceID: service.ID, NewPrice: &service.BasePrice, ChangedBy: changedBy,
+		}).Error
+	})
+	return service.ID, err
 }
 
-func (r *AdminPostgres) UpdateService(ctx context.Context, service models.Service) error {
-	return r.db.WithContext(ctx).Save(&service).Error
+// UpdateService перезаписывает действующую услугу каталога. Изменение базовой цены попадает
+// в историю цен вместе с изменением цены у врачей, для которых цена не переопределена.
+// Возвращает gorm.ErrRecordNotFound, если услуги нет или она удалена.
+func (r *AdminPostgres) UpdateService(ctx context.Context, service models.Service, changedBy *uint64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var before models.Service
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "base_price").First(&before, service.ID).Error
+		if err != nil {
+			return err
+		}
+		if err := saveExisting(ctx, tx, service.ID, &service); err != nil {
+			return err
+		}
+		if before.BasePrice == service.BasePrice {
+			return nil
+		}
+
+		var doctorIDs []uint64
+		err = tx.Model(&models.DoctorService{}).Where("service_id = ? AND price IS NULL", service.ID).
+			Order("doctor_id").Pluck("doctor_id", &doctorIDs).Error
+		if err != nil {
+			return err
+		}
+		changes := []models.ServicePriceChange{{
+			ServiceID: service.ID, OldPrice: &before.BasePrice, NewPrice: &service.BasePrice, ChangedBy: changedBy,
+		}}
+		for _, doctorID := range doctorIDs {
+			changes = append(changes, models.ServicePriceChange{
+				ServiceID: service.ID, DoctorID: &doctorID,
+				OldPrice: &before.BasePrice, NewPrice: &service.BasePrice, ChangedBy: changedBy,
+			})
+		}
+		return tx.Create(&changes).Error
+	})
 }
 
+// GetServicePriceHistory возвращает историю цен услуги, новые изменения сверху.
+func (r *AdminPostgres) GetServicePriceHistory(ctx context.Context, serviceID uint64, params models.PaginationParams) (
+	[]models.ServicePriceChange, int64, error,
+) {
+	var changes []models.ServicePriceChange
+	var total int64
+	query := r.db.WithContext(ctx).Model(&models.ServicePriceChange{}).Where("service_id = ?", serviceID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (params.Page - 1) * params.Limit
+	err := query.Order("changed_at DESC, id DESC").Limit(params.Limit).Offset(offset).Find(&changes).Error
+	return changes, total, err
+}
+
+// DeleteService удаляет услугу, если на нее нет предстоящих записей. Строка услуги блокируется
+// до конца транзакции: запись на прием берет разделяемую блокировку той же строки
+// (см. AppointmentPostgres.CreateAppointment) и не может появиться между проверкой и удалением.
 func (r *AdminPostgres) DeleteService(ctx context.Context, serviceID uint64) error {
-	return r.db.WithContext(ctx).Delete(&models.Service{}, serviceID).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+			First(&models.Service{}, serviceID).Error; err != nil {
+			return err
+		}
+		var upcoming bool
+		err := tx.Raw(`SELECT EXISTS (SELECT 1 FROM medical_center.appointments
+			WHERE service_id = ? AND status_id = ? AND appointment_date >= CURRENT_DATE AND deleted_at IS NULL)`,
+			serviceID, models.StatusScheduled).Scan(&upcoming).Error
+		if err != nil {
+			return err
+		}
+		if upcoming {
+			return ErrServiceHasUpcomingAppointments
+		}
+		return tx.Delete(&models.Service{}, serviceID).Error
+	})
+}
+
+func (r *AdminPostgres) GetDeletedServices(ctx context.Context, params models.PaginationParams) (
+	[]models.Service, int64, error,
+) {
+	return findDeleted[models.Service](ctx, r.db, params)
+}
+
+func (r *AdminPostgres) RestoreService(ctx context.Context, serviceID uint64) error {
+	return restoreDeleted[models.Service](ctx, r.db, serviceID)
+}
+
+// GetActiveServicesByIDs получает действующие услуги каталога по списку идентификаторов.
+func (r *AdminPostgres) GetActiveServicesByIDs(ctx context.Context, ids []uint64) ([]models.Service, error) {
+	var services []models.Service
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Order("name, id").Find(&services).Error
+	return services, err
+}
+
+// GetDoctorServiceAssignments получает назначения услуг врачу, включая назначения удаленных услуг.
+func (r *AdminPostgres) GetDoctorServiceAssignments(ctx context.Context, doctorID uint64) (
+	[]models.DoctorService, error,
+) {
+	assignments := []models.DoctorService{}
+	err := r.db.WithContext(ctx).Where("doctor_id = ?", doctorID).Order("service_id").Find(&assignments).Error
+	return assignments, err
+}
+
+// SetDoctorServices заменяет набор услуг врача вместе с переопределениями цены и длительности.
+// Изменившиеся цены врача, в том числе у добавленных и снятых услуг, попадают в историю цен.
+func (r *AdminPostgres) SetDoctorServices(
+	ctx context.Context, doctorID uint64, assignments []models.DoctorService, changedBy *uint64,
+) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := doctorServicePrices(tx, doctorID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("doctor_id = ?", doctorID).Delete(&models.DoctorService{}).Error; err != nil {
+			return err
+		}
+		if len(assignments) > 0 {
+			for i := range assignments {
+				assignments[i].DoctorID = doctorID
+			}
+			if err := tx.Create(&assignments).Error; err != nil {
+				return err
+			}
+		}
+		after, err := doctorServicePrices(tx, doctorID)
+		if err != nil {
+			return err
+		}
+
+		serviceIDs := make([]uint64, 0, len(before)+len(after))
+		for id := range before {
+			serviceIDs = append(serviceIDs, id)
+		}
+		for id := range after {
+			if _, ok := before[id]; !ok {
+				serviceIDs = append(serviceIDs, id)
+			}
+		}
+		slices.Sort(serviceIDs)
+
+		var changes []models.ServicePriceChange
+		for _, serviceID := range serviceIDs {
+			oldPrice, hadPrice := before[serviceID]
+			newPrice, hasPrice := after[serviceID]
+			if hadPrice && hasPrice && oldPrice == newPrice {
+				continue
+			}
+			change := models.ServicePriceChange{ServiceID: serviceID, DoctorID: &doctorID, ChangedBy: changedBy}
+			if hadPrice {
+				change.OldPrice = &oldPrice
+			}
+			if hasPrice {
+				change.NewPrice = &newPrice
+			}
+			changes = append(changes, change)
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		return tx.Create(&changes).Error
+	})
+}
+
+// doctorServicePrices возвращает цены, по которым врач оказывает услуги, по ID услуги.
+func doctorServicePrices(tx *gorm.DB, doctorID uint64) (map[uint64]float64, error) {
+	var rows []struct {
+		ServiceID uint64
+		Price     float64
+	}
+	err := tx.Table("medical_center.doctor_services ds").
+		Select("ds.service_id, COALESCE(ds.price, s.base_price) AS price").
+		Joins("JOIN medical_center.services s ON s.id = ds.service_id").
+		Where("ds.doctor_id = ?", doctorID).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	prices := make(map[uint64]float64, len(rows))
+	for _, row := range rows {
+		prices[row.ServiceID] = row.Price
+	}
+	return prices, nil
+}
+
+// --- Категории услуг ---
+
+func (r *AdminPostgres) GetServiceCategories(ctx context.Context) ([]models.ServiceCategory, error) {
+	categories := []models.ServiceCategory{}
+	err := r.db.WithContext(ctx).Order("department_id, name").Find(&categories).Error
+	return categories, err
+}
+
+func (r *AdminPostgres) CreateServiceCategory(ctx context.Context, category models.ServiceCategory) (uint32, error) {
+	result := r.db.WithContext(ctx).Create(&category)
+	return category.ID, result.Error
+}
+
+// UpdateServiceCategory перезаписывает категорию. Возвращает gorm.ErrRecordNotFound, если ее нет.
+func (r *AdminPostgres) UpdateServiceCategory(ctx context.Context, category models.ServiceCategory) error {
+	result := r.db.WithContext(ctx).Model(&models.ServiceCategory{}).Where("id = ?", category.ID).
+		Updates(map[string]any{"name": category.Name, "department_id": category.DepartmentID})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteServiceCategory удаляет категорию. Категорию, в которой есть услуги (в том числе удаленные),
+// удалить не дает внешний ключ.
+func (r *AdminPostgres) DeleteServiceCategory(ctx context.Context, categoryID uint32) error {
+	result := r.db.WithContext(ctx).Delete(&models.ServiceCategory{}, categoryID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
 
 func (r *AdminPostgres) CreateDepartment(ctx context.Context, department models.Department) (uint32, error) {
@@ -216,11 +803,77 @@ func (r *AdminPostgres) CreateDepartment(ctx context.Context, department models.
 }
 
 func (r *AdminPostgres) UpdateDepartment(ctx context.Context, department models.Department) error {
-	return r.db.WithContext(ctx).Save(&department).Error
+	return saveExisting(ctx, r.db, department.ID, &department)
 }
 
+// DeleteDepartment удаляет отделение, если в его категориях нет действующих услуг и нет
+// действующих врачей с его специальностями. Проверки и удаление выполняются в одной транзакции
+// под блокировкой строки отделения.
 func (r *AdminPostgres) DeleteDepartment(ctx context.Context, departmentID uint32) error {
-	return r.db.WithContext(ctx).Delete(&models.Department{}, departmentID).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+			First(&models.Department{}, departmentID).Error; err != nil {
+			return err
+		}
+		var hasServices bool
+		err := tx.Raw(`SELECT EXISTS (SELECT 1 FROM medical_center.services s
+			JOIN medical_center.service_categories sc ON sc.id = s.category_id
+			WHERE sc.department_id = ? AND s.deleted_at IS NULL)`, departmentID).Scan(&hasServices).Error
+		if err != nil {
+			return err
+		}
+		if hasServices {
+			return ErrDepartmentHasServices
+		}
+		var hasDoctors bool
+		err = tx.Raw(`SELECT EXISTS (SELECT 1 FROM medical_center.doctors d
+			JOIN medical_center.specialties sp ON sp.id = d.specialty_id
+			WHERE sp.department_id = ? AND d.deleted_at IS NULL)`, departmentID).Scan(&hasDoctors).Error
+		if err != nil {
+			return err
+		}
+		if hasDoctors {
+			return ErrDepartmentHasDoctors
+		}
+		return tx.Delete(&models.Department{}, departmentID).Error
+	})
+}
+
+func (r *AdminPostgres) GetDeletedDepartments(ctx context.Context, params models.PaginationParams) (
+	[]models.Department, int64, error,
+) {
+	return findDeleted[models.Department](ctx, r.db, params)
+}
+
+func (r *AdminPostgres) RestoreDepartment(ctx context.Context, departmentID uint32) error {
+	return restoreDeleted[models.Department](ctx, r.db, departmentID)
+}
+
+// --- Мягкое удаление ---
+
+// softDeleteTables - таблицы с мягким удалением в порядке окончательного удаления:
+// записи на прием ссылаются на услуги, врачей и пользователей, поэтому удаляются первыми.
+// Пользователи с согласиями, журналом имперсонации или запросами на выгрузку и удаление данных
+// не удаляются (ON DELETE RESTRICT) и попадают в Kept.
+var softDeleteTables = []string{
+	"medical_center.appointments",
+	"medical_center.services",
+	"medical_center.doctors",
+	"medical_center.departments",
+	"medical_center.users",
+}
+
+// PurgeDeleted окончательно удаляет записи, мягко удаленные раньше before.
+func (r *AdminPostgres) PurgeDeleted(ctx context.Context, before time.Time) ([]models.PurgeResult, error) {
+	results := make([]models.PurgeResult, 0, len(softDeleteTables))
+	for _, table := range softDeleteTables {
+		result, err := purgeDeleted(ctx, r.db, table, before)
+		results = append(results, result)
+		if err != nil {
+			return results, err
+		}
+	}
+	return results, nil
 }
 
 // --- Статистика ---
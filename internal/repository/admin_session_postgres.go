@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"lk/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// AdminSessionPostgres реализует AdminSessionRepository для PostgreSQL.
+type AdminSessionPostgres struct {
+	db *gorm.DB
+}
+
+// NewAdminSessionPostgres создает новый экземпляр репозитория сессий администраторов.
+func NewAdminSessionPostgres(db *gorm.DB) *AdminSessionPostgres {
+	return &AdminSessionPostgres{db: db}
+}
+
+// Create сохраняет новую сессию и попутно удаляет истекшие сессии того же администратора.
+func (r *AdminSessionPostgres) Create(ctx context.Context, session models.AdminSession) (uint64, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("admin_id = ? AND expires_at <= ?", session.AdminID, time.Now()).
+			Delete(&models.AdminSession{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&session).Error
+	})
+	return session.ID, err
+}
+
+// GetActive находит неистекшую сессию по ID.
+func (r *AdminSessionPostgres) GetActive(ctx context.Context, sessionID uint64) (models.AdminSession, error) {
+	var session models.AdminSession
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND expires_at > ?", sessionID, time.Now()).
+		First(&session).Error
+	return session, err
+}
+
+// ListActive возвращает неистекшие сессии администратора, последние использованные сверху.
+func (r *AdminSessionPostgres) ListActive(ctx context.Context, adminID uint64) ([]models.AdminSession, error) {
+	var sessions []models.AdminSession
+	err := r.db.WithContext(ctx).
+		Where("admin_id = ? AND expires_at > ?", adminID, time.Now()).
+		Order("last_used_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// Rotate заменяет хэш refresh-токена, если текущий хэш совпадает с ожидаемым.
+// Прежний хэш сохраняется, чтобы распознать повторное предъявление ротированного токена.
+// Возвращает gorm.ErrRecordNotFound, если токен уже был ротирован параллельным запросом.
+func (r *AdminSessionPostgres) Rotate(ctx context.Context, sessionID uint64, oldHash, newHash string,
+	clientIP, userAgent string, expiresAt time.Time,
+) error {
+	result := r.db.WithContext(ctx).Model(&models.AdminSession{}).
+		Where("id = ? AND token_hash = ?", sessionID, oldHash).
+		Updates(map[string]interface{}{
+			"token_hash":          newHash,
+			"previous_token_hash": oldHash,
+			"ip_address":          clientIP,
+			"user_agent":          userAgent,
+			"last_used_at":        time.Now(),
+			"expires_at":          expiresAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Delete удаляет сессию администратора. Возвращает gorm.ErrRecordNotFound, если сессии нет.
+func (r *AdminSessionPostgres) Delete(ctx context.Context, adminID, sessionID uint64) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND admin_id = ?", sessionID, adminID).
+		Delete(&models.AdminSession{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteAll удаляет все сессии администратора, кроме exceptID (0 - удалить все).
+func (r *AdminSessionPostgres) DeleteAll(ctx context.Context, adminID, exceptID uint64) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("admin_id = ? AND id <> ?", adminID, exceptID).
+		Delete(&models.AdminSession{})
+	return result.RowsAffected, result.Error
+}
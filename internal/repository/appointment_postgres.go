@@ -7,6 +7,7 @@ import (
 	"lk/internal/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // AppointmentPostgres реализует AppointmentRepository для PostgreSQL.
@@ -19,14 +20,28 @@ func NewAppointmentPostgres(db *gorm.DB) *AppointmentPostgres {
 	return &AppointmentPostgres{db: db}
 }
 
-// GetDoctorScheduleForDate получает расписание врача на конкретную дату.
-func (r *AppointmentPostgres) GetDoctorScheduleForDate(
-	ctx context.Context, doctorID uint64, date time.Time,
-) (models.Schedule, error) {
-	var schedule models.Schedule
-	err := r.db.WithContext(ctx).Where(
-		"doctor_id = ? AND date = ?", doctorID, date).First(&schedule).Error
-	return schedule, err
+// scheduleClinicCondition отбирает расписания, по которым врач принимает в клинике (ID передается дважды).
+// Расписание без клиники относится к единственной клинике врача.
+const scheduleClinicCondition = `(clinic_id = ? OR clinic_id IS NULL AND ARRAY[?]::bigint[] =
+	(SELECT array_agg(dc.clinic_id) FROM medical_center.doctorclinics dc WHERE dc.doctor_id = schedules.doctor_id))`
+
+// inClinic ограничивает запрос расписаний клиникой; нулевой clinicID означает любую клинику.
+func inClinic(query *gorm.DB, clinicID uint64) *gorm.DB {
+	if clinicID == 0 {
+		return query
+	}
+	return query.Where(scheduleClinicCondition, clinicID, clinicID)
+}
+
+// GetDoctorSchedulesForDate получает интервалы приема врача на конкретную дату в клинике clinicID
+// (0 - в любой) в порядке начала. В один день врач может принимать в нескольких клиниках.
+func (r *AppointmentPostgres) GetDoctorSchedulesForDate(
+	ctx context.Context, doctorID, clinicID uint64, date time.Time,
+) ([]models.Schedule, error) {
+	var schedules []models.Schedule
+	query := r.db.WithContext(ctx).Where("doctor_id = ? AND date = ?", doctorID, date)
+	err := inClinic(query, clinicID).Order("start_time ASC").Find(&schedules).Error
+	return schedules, err
 }
 
 // GetAppointmentsByDoctorAndDate получает все записи к врачу на конкретную дату.
@@ -51,46 +66,50 @@ func (r *AppointmentPostgres) GetAppointmentsByDoctorAndDateRange(
 	return appointments, err
 }
 
-// GetDoctorScheduleForDateRange получает все расписания врача в диапазоне дат.
+// GetDoctorScheduleForDateRange получает все расписания врача в диапазоне дат в клинике clinicID (0 - в любой).
 func (r *AppointmentPostgres) GetDoctorScheduleForDateRange(
-	ctx context.Context, doctorID uint64, startDate, endDate time.Time,
+	ctx context.Context, doctorID, clinicID uint64, startDate, endDate time.Time,
 ) ([]models.Schedule, error) {
 	var schedules []models.Schedule
-	err := r.db.WithContext(ctx).Where(
+	query := r.db.WithContext(ctx).Where(
 		"doctor_id = ? AND date BETWEEN ? AND ?",
 		doctorID, startDate, endDate,
-	).Order("date ASC").Find(&schedules).Error
+	)
+	err := inClinic(query, clinicID).Order("date ASC, start_time ASC").Find(&schedules).Error
 	return schedules, err
 }
 
-// GetServiceDurationMinutes получает длительность услуги в минутах.
-func (r *AppointmentPostgres) GetServiceDurationMinutes(ctx context.Context, serviceID uint64) (uint16, error) {
-	var service models.Service
-	err := r.db.WithContext(ctx).Select("duration_minutes").First(&service, serviceID).Error
-	return service.DurationMinutes, err
-}
-
-// GetServicesByIDs получает информацию о нескольких услугах одним запросом.
-func (r *AppointmentPostgres) GetServicesByIDs(ctx context.Context, serviceIDs []uint64) ([]models.Service, error) {
+// GetServicesByIDs получает информацию о нескольких услугах одним запросом. Длительность
+// услуги - та, с которой ее оказывает врач doctorID. Удаленные услуги и снятые с врача назначения
+// тоже учитываются: по ним считается длительность уже существующих записей.
+func (r *AppointmentPostgres) GetServicesByIDs(ctx context.Context, doctorID uint64, serviceIDs []uint64) (
+	[]models.Service, error,
+) {
 	var services []models.Service
 	if len(serviceIDs) == 0 {
 		return services, nil // Возвращаем пустой слайс, если нет ID для поиска
 	}
-	err := r.db.WithContext(ctx).Where("id IN ?", serviceIDs).Find(&services).Error
+	err := r.db.WithContext(ctx).Unscoped().
+		Select(`services.id, services.code, services.name, services.category_id, services.base_price,
+			COALESCE(ds.duration_minutes, services.duration_minutes) AS duration_minutes`).
+		Joins(`LEFT JOIN medical_center.doctor_services ds
+			ON ds.service_id = services.id AND ds.doctor_id = ?`, doctorID).
+		Where("services.id IN ?", serviceIDs).Find(&services).Error
 	return services, err
 }
 
-// GetAvailableDatesForMonth возвращает дни, в которые у врача есть расписание.
+// GetAvailableDatesForMonth возвращает дни, в которые у врача есть расписание в клинике clinicID (0 - в любой).
 func (r *AppointmentPostgres) GetAvailableDatesForMonth(
-	ctx context.Context, doctorID uint64, month time.Time,
+	ctx context.Context, doctorID, clinicID uint64, month time.Time,
 ) ([]time.Time, error) {
 	var dates []time.Time
 	startOfMonth := month.Format("2006-01-02")
 	endOfMonth := month.AddDate(0, 1, -1).Format("2006-01-02")
 
-	err := r.db.WithContext(ctx).Model(&models.Schedule{}).
+	query := r.db.WithContext(ctx).Model(&models.Schedule{}).
+		Where("doctor_id = ? AND date BETWEEN ? AND ?", doctorID, startOfMonth, endOfMonth)
+	err := inClinic(query, clinicID).
 		Distinct("date").
-		Where("doctor_id = ? AND date BETWEEN ? AND ?", doctorID, startOfMonth, endOfMonth).
 		Order("date").
 		Pluck("date", &dates).Error
 
@@ -98,10 +117,18 @@ func (r *AppointmentPostgres) GetAvailableDatesForMonth(
 }
 
 // CreateAppointment создает новую запись на прием в базе данных.
+// Строка услуги берется под разделяемую блокировку, чтобы запись не появилась одновременно
+// с удалением услуги (см. AdminPostgres.DeleteService). Для удаленной услуги возвращает gorm.ErrRecordNotFound.
 func (r *AppointmentPostgres) CreateAppointment(ctx context.Context, appointment models.Appointment) (uint64, error) {
-	result := r.db.WithContext(ctx).Create(&appointment)
-	if result.Error != nil {
-		return 0, result.Error
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "SHARE"}).Select("id").
+			First(&models.Service{}, appointment.ServiceID).Error; err != nil {
+			return err
+		}
+		return tx.Create(&appointment).Error
+	})
+	if err != nil {
+		return 0, err
 	}
 	return appointment.ID, nil
 }
@@ -130,6 +157,30 @@ func (r *AppointmentPostgres) UpdateAppointmentStatus(ctx context.Context, appoi
 		"id = ?", appointmentID).Update("status_id", statusID).Error
 }
 
+// GetAppointmentsForReminder получает запланированные приемы, которые начинаются в интервале (from, to].
+// Дата и время приема хранятся в часовом поясе клиники, поэтому границы сравниваются
+// по местному времени из from и to. Обезличенные и удаленные пациенты пропускаются.
+func (r *AppointmentPostgres) GetAppointmentsForReminder(ctx context.Context, from, to time.Time) (
+	[]models.AppointmentReminder, error,
+) {
+	const layout = "2006-01-02 15:04:05"
+	var reminders []models.AppointmentReminder
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT a.id, u.phone, a.appointment_date, a.appointment_time,
+			concat_ws(' ', d.last_name, d.first_name, d.patronymic) AS doctor_name, c.address AS clinic_address
+		FROM medical_center.appointments a
+		JOIN medical_center.users u ON u.id = a.user_id
+		JOIN medical_center.doctors d ON d.id = a.doctor_id
+		JOIN medical_center.clinics c ON c.id = a.clinic_id
+		WHERE a.status_id = ? AND a.deleted_at IS NULL
+			AND u.deleted_at IS NULL AND u.anonymized_at IS NULL
+			AND a.appointment_date + a.appointment_time > ?::timestamp
+			AND a.appointment_date + a.appointment_time <= ?::timestamp
+		ORDER BY a.appointment_date, a.appointment_time, a.id`,
+		models.StatusScheduled, from.Format(layout), to.Format(layout)).Scan(&reminders).Error
+	return reminders, err
+}
+
 // GetUpcomingAppointmentsByUserID получает список предстоящих записей на прием для пользователя.
 func (r *AppointmentPostgres) GetUpcomingAppointmentsByUserID(ctx context.Context, userID uint64) (
 	[]models.Appointment, error,
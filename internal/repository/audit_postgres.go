@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"lk/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// auditExportBatchSize - размер пачки при потоковой выгрузке журнала аудита.
+const auditExportBatchSize = 500
+
+// AuditPostgres реализует AuditRepository для PostgreSQL.
+type AuditPostgres struct {
+	db *gorm.DB
+}
+
+// NewAuditPostgres создает новый экземпляр репозитория журнала аудита.
+func NewAuditPostgres(db *gorm.DB) *AuditPostgres {
+	return &AuditPostgres{db: db}
+}
+
+// Create сохраняет запись журнала аудита.
+func (r *AuditPostgres) Create(ctx context.Context, entry models.AuditLog) error {
+	return r.db.WithContext(ctx).Create(&entry).Error
+}
+
+// GetAll возвращает пагинированный журнал аудита с фильтрами, новые записи сверху.
+func (r *AuditPostgres) GetAll(ctx context.Context, filter models.AuditLogFilter, params models.PaginationParams) (
+	[]models.AuditLog, int64, error,
+) {
+	var entries []models.AuditLog
+	var total int64
+	query := r.filtered(ctx, filter)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (params.Page - 1) * params.Limit
+	err := query.Order("created_at DESC, id DESC").Limit(params.Limit).Offset(offset).Find(&entries).Error
+	return entries, total, err
+}
+
+// Export передает в fn все записи, подходящие под фильтр, пачками в порядке возрастания ID.
+func (r *AuditPostgres) Export(ctx context.Context, filter models.AuditLogFilter,
+	fn func(batch []models.AuditLog) error,
+) error {
+	var batch []models.AuditLog
+	return r.filtered(ctx, filter).FindInBatches(&batch, auditExportBatchSize, func(_ *gorm.DB, _ int) error {
+		return fn(batch)
+	}).Error
+}
+
+func (r *AuditPostgres) filtered(ctx context.Context, filter models.AuditLogFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&models.AuditLog{})
+	if filter.ActorType != "" {
+		query = query.Where("actor_type = ?", filter.ActorType)
+	}
+	if filter.ActorID != 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.TargetUserID != 0 {
+		query = query.Where("target_user_id = ?", filter.TargetUserID)
+	}
+	if filter.RequestID != "" {
+		query = query.Where("request_id = ?", filter.RequestID)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+	return query
+}
+
+// medicalDataAccessRow - строка истории обращений к медкарте вместе с данными сотрудника.
+type medicalDataAccessRow struct {
+	ID             uint64
+	CreatedAt      time.Time
+	Action         string
+	EntityType     string
+	EntityID       string
+	ActorType      string
+	ImpersonatorID *uint64
+	StaffName      *string
+	StaffRole      *string
+}
+
+// GetMedicalDataAccess возвращает обращения к медицинским данным пациента, новые сверху.
+// Сотрудник определяется по impersonator_id, а для прямых запросов администратора - по actor_id.
+func (r *AuditPostgres) GetMedicalDataAccess(ctx context.Context, filter models.MedicalDataAccessFilter,
+	params models.PaginationParams,
+) ([]models.MedicalDataAccess, int64, error) {
+	query := r.db.WithContext(ctx).Table("medical_center.audit_logs AS l").
+		Joins(`LEFT JOIN medical_center.admins a ON a.id = COALESCE(l.impersonator_id,
+			CASE WHEN l.actor_type = ? THEN l.actor_id END)`, models.AuditActorAdmin).
+		Where("l.target_user_id = ? AND l.action IN ?", filter.UserID, filter.Actions)
+	if filter.StaffOnly {
+		query = query.Where("(l.actor_type = ? OR l.impersonator_id IS NOT NULL)", models.AuditActorAdmin)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []medicalDataAccessRow
+	offset := (params.Page - 1) * params.Limit
+	err := query.Select(`l.id, l.created_at, l.action, l.entity_type, l.entity_id, l.actor_type,
+			l.impersonator_id, a.full_name AS staff_name, a.role AS staff_role`).
+		Order("l.created_at DESC, l.id DESC").Limit(params.Limit).Offset(offset).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]models.MedicalDataAccess, 0, len(rows))
+	for _, row := range rows {
+		item := models.MedicalDataAccess{
+			ID:           row.ID,
+			AccessedAt:   row.CreatedAt,
+			Action:       row.Action,
+			Section:      row.EntityType,
+			ResourceID:   row.EntityID,
+			AccessorType: models.AccessorPatient,
+		}
+		switch {
+		case row.ImpersonatorID != nil:
+			item.AccessorType = models.AccessorImpersonation
+		case row.ActorType == models.AuditActorAdmin:
+			item.AccessorType = models.AccessorStaff
+		}
+		if row.StaffName != nil {
+			item.StaffName = *row.StaffName
+		}
+		if row.StaffRole != nil {
+			item.StaffRole = models.AdminRole(*row.StaffRole)
+		}
+		items = append(items, item)
+	}
+	return items, total, nil
+}
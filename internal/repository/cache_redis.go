@@ -26,6 +26,12 @@ func (r *CacheRedis) Set(ctx context.Context, key string, value interface{}, ttl
 	return r.client.Set(ctx, key, value, ttl).Err()
 }
 
+// SetIfAbsent атомарно сохраняет значение, только если ключа еще нет (SET NX PX).
+// Возвращает false, если ключ уже существовал.
+func (r *CacheRedis) SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, key, value, ttl).Result()
+}
+
 // Get извлекает значение из кэша. Возвращает ErrNotFound, если ключ не существует.
 func (r *CacheRedis) Get(ctx context.Context, key string) (string, error) {
 	val, err := r.client.Get(ctx, key).Result()
@@ -42,3 +48,32 @@ func (r *CacheRedis) Get(ctx context.Context, key string) (string, error) {
 func (r *CacheRedis) Delete(ctx context.Context, key string) error {
 	return r.client.Del(ctx, key).Err()
 }
+
+// incrementScript увеличивает счетчик и ставит время жизни одной атомарной операцией.
+// Время жизни ставится и ключу, который по какой-то причине его лишился: иначе счетчик
+// неудачных попыток никогда бы не сбросился.
+var incrementScript = redis.NewScript(`
+local value = redis.call('INCR', KEYS[1])
+if redis.call('PTTL', KEYS[1]) < 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return value
+`)
+
+// Increment атомарно увеличивает счетчик по ключу и возвращает новое значение.
+// При первом увеличении на ключ устанавливается время жизни ttl.
+func (r *CacheRedis) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return incrementScript.Run(ctx, r.client, []string{key}, ttl.Milliseconds()).Int64()
+}
+
+// TTL возвращает оставшееся время жизни ключа. Возвращает ErrNotFound, если ключ не существует.
+func (r *CacheRedis) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := r.client.TTL(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if ttl == -2 {
+		return 0, ErrNotFound
+	}
+	return ttl, nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+
+	"lk/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ClinicPostgres реализует ClinicRepository для PostgreSQL.
+type ClinicPostgres struct {
+	db *gorm.DB
+}
+
+// NewClinicPostgres создает новый экземпляр репозитория клиник.
+func NewClinicPostgres(db *gorm.DB) *ClinicPostgres {
+	return &ClinicPostgres{db: db}
+}
+
+// withWorkingHours предзагружает часы работы клиник по дням недели во времени формата HH:MM.
+func withWorkingHours(db *gorm.DB) *gorm.DB {
+	return db.Preload("WorkingHours", func(db *gorm.DB) *gorm.DB {
+		return db.Select(`clinic_id, weekday,
+			to_char(opens_at, 'HH24:MI') AS opens_at, to_char(closes_at, 'HH24:MI') AS closes_at`).
+			Order("weekday")
+	})
+}
+
+// GetAll возвращает действующие клиники с часами работы: основная первой, остальные по названию.
+func (r *ClinicPostgres) GetAll(ctx context.Context) ([]models.Clinic, error) {
+	var clinics []models.Clinic
+	err := withWorkingHours(r.db.WithContext(ctx)).Order("is_main DESC, name, id").Find(&clinics).Error
+	return clinics, err
+}
+
+// GetByID возвращает действующую клинику с часами работы.
+func (r *ClinicPostgres) GetByID(ctx context.Context, id uint64) (models.Clinic, error) {
+	var clinic models.Clinic
+	err := withWorkingHours(r.db.WithContext(ctx)).First(&clinic, id).Error
+	return clinic, err
+}
+
+// Create сохраняет новую клинику вместе с часами работы. Новая основная клиника
+// снимает этот признак с прежней.
+func (r *ClinicPostgres) Create(ctx context.Context, clinic models.Clinic) (uint64, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if clinic.IsMain {
+			if err := resetMainClinic(tx, 0); err != nil {
+				return err
+			}
+		}
+		hours := clinic.WorkingHours
+		if err := tx.Omit("WorkingHours").Create(&clinic).Error; err != nil {
+			return err
+		}
+		return replaceWorkingHours(tx, clinic.ID, hours)
+	})
+	return clinic.ID, err
+}
+
+// Update перезаписывает данные действующей клиники и ее часы работы.
+// Возвращает gorm.ErrRecordNotFound, если клиники нет или она удалена.
+func (r *ClinicPostgres) Update(ctx context.Context, clinic models.Clinic) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if clinic.IsMain {
+			if err := resetMainClinic(tx, clinic.ID); err != nil {
+				return err
+			}
+		}
+		result := tx.Model(&models.Clinic{}).Where("id = ?", clinic.ID).Updates(map[string]any{
+			"name":      clinic.Name,
+			"address":   clinic.Address,
+			"phone":     clinic.Phone,
+			"email":     clinic.Email,
+			"city_id":   clinic.CityID,
+			"latitude":  clinic.Latitude,
+			"longitude": clinic.Longitude,
+			"is_main":   clinic.IsMain,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return replaceWorkingHours(tx, clinic.ID, clinic.WorkingHours)
+	})
+}
+
+// resetMainClinic снимает признак основной со всех действующих клиник, кроме exceptID.
+func resetMainClinic(tx *gorm.DB, exceptID uint64) error {
+	return tx.Model(&models.Clinic{}).Where("is_main AND id <> ?", exceptID).Update("is_main", false).Error
+}
+
+// replaceWorkingHours заменяет часы работы клиники.
+func replaceWorkingHours(tx *gorm.DB, clinicID uint64, hours []models.ClinicWorkingHours) error {
+	if err := tx.Where("clinic_id = ?", clinicID).Delete(&models.ClinicWorkingHours{}).Error; err != nil {
+		return err
+	}
+	if len(hours) == 0 {
+		return nil
+	}
+	for i := range hours {
+		hours[i].ClinicID = clinicID
+	}
+	return tx.Create(&hours).Error
+}
+
+func (r *ClinicPostgres) Delete(ctx context.Context, id uint64) error {
+	return softDelete[models.Clinic](ctx, r.db, id)
+}
+
+func (r *ClinicPostgres) GetDeleted(ctx context.Context, params models.PaginationParams) ([]models.Clinic, int64, error) {
+	return findDeleted[models.Clinic](ctx, r.db, params)
+}
+
+func (r *ClinicPostgres) Restore(ctx context.Context, id uint64) error {
+	return restoreDeleted[models.Clinic](ctx, r.db, id)
+}
+
+// HasUpcomingSchedules сообщает, принимают ли в клинике врачи сегодня или позже.
+func (r *ClinicPostgres) HasUpcomingSchedules(ctx context.Context, id uint64) (bool, error) {
+	var exists bool
+	err := r.db.WithContext(ctx).Raw(`SELECT EXISTS (SELECT 1 FROM medical_center.schedules
+		WHERE clinic_id = ? AND date >= CURRENT_DATE)`, id).Scan(&exists).Error
+	return exists, err
+}
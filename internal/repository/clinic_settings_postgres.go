@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+
+	"lk/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ClinicSettingsPostgres реализует ClinicSettingsRepository для PostgreSQL.
+type ClinicSettingsPostgres struct {
+	db *gorm.DB
+}
+
+// NewClinicSettingsPostgres создает новый экземпляр репозитория настроек клиники.
+func NewClinicSettingsPostgres(db *gorm.DB) *ClinicSettingsPostgres {
+	return &ClinicSettingsPostgres{db: db}
+}
+
+// GetCurrent возвращает действующую (последнюю) версию настроек.
+func (r *ClinicSettingsPostgres) GetCurrent(ctx context.Context) (models.ClinicSettingsVersion, error) {
+	var version models.ClinicSettingsVersion
+	err := r.db.WithContext(ctx).Order("version DESC").First(&version).Error
+	return version, err
+}
+
+// Create сохраняет новую версию настроек. Если версия с таким номером уже есть
+// (настройки одновременно сохранил другой администратор), нарушается первичный ключ.
+func (r *ClinicSettingsPostgres) Create(ctx context.Context, version *models.ClinicSettingsVersion) error {
+	return r.db.WithContext(ctx).Create(version).Error
+}
+
+// GetHistory возвращает версии настроек, новые сверху.
+func (r *ClinicSettingsPostgres) GetHistory(ctx context.Context, params models.PaginationParams) (
+	[]models.ClinicSettingsVersion, int64, error,
+) {
+	var versions []models.ClinicSettingsVersion
+	var total int64
+	query := r.db.WithContext(ctx).Model(&models.ClinicSettingsVersion{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (params.Page - 1) * params.Limit
+	err := query.Order("version DESC").Limit(params.Limit).Offset(offset).Find(&versions).Error
+	return versions, total, err
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"lk/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ConsentPostgres реализует ConsentRepository для PostgreSQL.
+type ConsentPostgres struct {
+	db *gorm.DB
+}
+
+// NewConsentPostgres создает новый экземпляр репозитория согласий.
+func NewConsentPostgres(db *gorm.DB) *ConsentPostgres {
+	return &ConsentPostgres{db: db}
+}
+
+// Create сохраняет факты принятия документов.
+// * Может вызываться внутри транзакции (tx) или без нее (tx == nil).
+func (r *ConsentPostgres) Create(ctx context.Context, tx *gorm.DB, consents []models.UserConsent) error {
+	if len(consents) == 0 {
+		return nil
+	}
+	if tx == nil {
+		tx = r.db
+	}
+	return tx.WithContext(ctx).Create(&consents).Error
+}
+
+// RevokeAll отзывает все действующие согласия пользователя.
+// * Эта функция должна вызываться внутри транзакции.
+func (r *ConsentPostgres) RevokeAll(ctx context.Context, tx *gorm.DB, userID uint64) error {
+	return tx.WithContext(ctx).Model(&models.UserConsent{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// GetActive возвращает неотозванные согласия пользователя: по одному, самому свежему, на тип документа.
+func (r *ConsentPostgres) GetActive(ctx context.Context, userID uint64) ([]models.UserConsent, error) {
+	var consents []models.UserConsent
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT DISTINCT ON (document_type) *
+		FROM medical_center.user_consents
+		WHERE user_id = ? AND revoked_at IS NULL
+		ORDER BY document_type, accepted_at DESC, id DESC`, userID).
+		Scan(&consents).Error
+	return consents, err
+}
+
+// GetHistory возвращает всю историю принятия и отзыва документов пользователем, новые сверху.
+func (r *ConsentPostgres) GetHistory(ctx context.Context, userID uint64, params models.PaginationParams) (
+	[]models.UserConsent, int64, error,
+) {
+	var consents []models.UserConsent
+	var total int64
+	query := r.db.WithContext(ctx).Model(&models.UserConsent{}).Where("user_id = ?", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (params.Page - 1) * params.Limit
+	err := query.Order("accepted_at DESC, id DESC").Limit(params.Limit).Offset(offset).Find(&consents).Error
+	return consents, total, err
+}
+
+// Revoke отзывает все действующие согласия пользователя по типу документа
+// и возвращает количество отозванных.
+func (r *ConsentPostgres) Revoke(ctx context.Context, userID uint64, documentType string) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.UserConsent{}).
+		Where("user_id = ? AND document_type = ? AND revoked_at IS NULL", userID, documentType).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+// CountPendingMandatory считает обязательные документы, действующую версию которых пользователь не принял.
+func (r *ConsentPostgres) CountPendingMandatory(ctx context.Context, userID uint64) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT count(*)
+		FROM (`+currentLegalDocumentsQuery+`) current
+		WHERE current.is_mandatory
+		  AND NOT EXISTS (
+			SELECT 1 FROM medical_center.user_consents uc
+			WHERE uc.user_id = ? AND uc.document_id = current.id AND uc.revoked_at IS NULL
+		  )`, userID).
+		Scan(&count).Error
+	return count, err
+}
@@ -25,11 +25,13 @@ func (r *DirectoryPostgres) GetAllDepartments(ctx context.Context) ([]models.Dep
 	return departments, err
 }
 
-// GetAllSpecialties возвращает список всех врачебных специальностей.
+// GetAllSpecialties возвращает список всех врачебных специальностей, кроме специальностей удаленных отделений.
 // * Если departmentID не является nil, фильтрует по ID отделения.
 func (r *DirectoryPostgres) GetAllSpecialties(ctx context.Context, departmentID *uint32) ([]models.Specialty, error) {
 	var specialties []models.Specialty
-	query := r.db.WithContext(ctx).Order("name")
+	query := r.db.WithContext(ctx).Order("name").
+		Where(`NOT EXISTS (SELECT 1 FROM medical_center.departments d
+			WHERE d.id = specialties.department_id AND d.deleted_at IS NOT NULL)`)
 
 	if departmentID != nil {
 		query = query.Where("department_id = ?", *departmentID)
@@ -2,8 +2,10 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"strings"
+	"unicode"
 
 	"lk/internal/models"
 
@@ -27,6 +29,103 @@ func (r *DoctorPostgres) GetDoctorByID(ctx context.Context, id uint64) (models.D
 	return doctor, err
 }
 
+// GetDoctorProfile получает врача со всеми разделами профиля.
+func (r *DoctorPostgres) GetDoctorProfile(ctx context.Context, id uint64) (models.DoctorProfile, error) {
+	doctor, err := r.GetDoctorByID(ctx, id)
+	if err != nil {
+		return models.DoctorProfile{}, err
+	}
+
+	clinics, err := r.GetDoctorClinics(ctx, id)
+	if err != nil {
+		return models.DoctorProfile{}, err
+	}
+
+	services, err := r.GetDoctorServices(ctx, id)
+	if err != nil {
+		return models.DoctorProfile{}, err
+	}
+
+	profile := models.DoctorProfile{Doctor: doctor, Clinics: clinics, Services: services}
+	sections := []struct {
+		dest  any
+		order string
+	}{
+		{&profile.Education, "end_year DESC, id"},
+		{&profile.Residency, "end_year DESC, id"},
+		{&profile.Courses, "year DESC, id"},
+		{&profile.Certificates, "id"},
+		{&profile.Skills, "id"},
+		{&profile.Specializations, "id"},
+	}
+	for _, section := range sections {
+		err := r.db.WithContext(ctx).Where("doctor_id = ?", id).Order(section.order).Find(section.dest).Error
+		if err != nil {
+			return models.DoctorProfile{}, err
+		}
+	}
+	return profile, nil
+}
+
+// GetDoctorClinics получает клиники, в которые назначен врач.
+func (r *DoctorPostgres) GetDoctorClinics(ctx context.Context, doctorID uint64) ([]models.Clinic, error) {
+	clinics, err := r.clinicsByDoctor(ctx, []uint64{doctorID})
+	if err != nil {
+		return nil, err
+	}
+	if clinics[doctorID] == nil {
+		return []models.Clinic{}, nil
+	}
+	return clinics[doctorID], nil
+}
+
+// doctorServiceOffers возвращает запрос к действующим услугам врача с ценой и длительностью для него.
+func (r *DoctorPostgres) doctorServiceOffers(ctx context.Context, doctorID uint64) *gorm.DB {
+	return r.db.WithContext(ctx).Table("medical_center.doctor_services ds").
+		Select(`s.id AS service_id, s.code, s.name, s.category_id,
+			COALESCE(ds.price, s.base_price) AS price, COALESCE(ds.duration_minutes, s.duration_minutes) AS duration_minutes`).
+		Joins("JOIN medical_center.services s ON s.id = ds.service_id AND s.deleted_at IS NULL").
+		Where("ds.doctor_id = ?", doctorID)
+}
+
+// GetDoctorServices получает действующие услуги врача с ценой и длительностью для него.
+func (r *DoctorPostgres) GetDoctorServices(ctx context.Context, doctorID uint64) ([]models.DoctorServiceOffer, error) {
+	services := []models.DoctorServiceOffer{}
+	err := r.doctorServiceOffers(ctx, doctorID).Order("s.name, s.id").Scan(&services).Error
+	return services, err
+}
+
+// GetDoctorService получает услугу врача с ценой и длительностью для него.
+// Возвращает gorm.ErrRecordNotFound, если врач не оказывает услугу или она удалена.
+func (r *DoctorPostgres) GetDoctorService(ctx context.Context, doctorID, serviceID uint64) (
+	models.DoctorServiceOffer, error,
+) {
+	var service models.DoctorServiceOffer
+	err := r.doctorServiceOffers(ctx, doctorID).Where("ds.service_id = ?", serviceID).Take(&service).Error
+	return service, err
+}
+
+// clinicsByDoctor получает действующие клиники нескольких врачей одним запросом.
+func (r *DoctorPostgres) clinicsByDoctor(ctx context.Context, doctorIDs []uint64) (map[uint64][]models.Clinic, error) {
+	var rows []struct {
+		DoctorID uint64
+		models.Clinic
+	}
+	err := r.db.WithContext(ctx).Table("medical_center.doctorclinics dc").
+		Select("dc.doctor_id, c.*").
+		Joins("JOIN medical_center.clinics c ON c.id = dc.clinic_id").
+		Where("dc.doctor_id IN ? AND c.deleted_at IS NULL", doctorIDs).
+		Order("c.name, c.id").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	clinics := make(map[uint64][]models.Clinic, len(doctorIDs))
+	for _, row := range rows {
+		clinics[row.DoctorID] = append(clinics[row.DoctorID], row.Clinic)
+	}
+	return clinics, nil
+}
+
 // GetSpecialistRecommendations получает рекомендации из поля в таблице doctors.
 func (r *DoctorPostgres) GetSpecialistRecommendations(ctx context.Context, doctorID uint64) (string, error) {
 	var doctor models.Doctor
@@ -38,80 +137,325 @@ func (r *DoctorPostgres) GetSpecialistRecommendations(ctx context.Context, docto
 	return doctor.Recommendations.String, nil
 }
 
-// GetDoctorsBySpecialty получает список врачей по ID их специальности с пагинацией и сортировкой.
-func (r *DoctorPostgres) GetDoctorsBySpecialty(
-	ctx context.Context, specialtyID uint32, params models.PaginationParams,
-) ([]models.Doctor, int64, error) {
-	var doctors []models.Doctor
-	var total int64
+// Фасеты поиска врачей: при подсчете фасета его собственный фильтр не применяется.
+const (
+	facetNone      = ""
+	facetSpecialty = "specialty"
+	facetClinic    = "clinic"
+	facetCity      = "city"
+	facetGender    = "gender"
+	facetDMS       = "dms"
+	facetPrice     = "price"
+)
+
+// freeScheduleCondition - у врача есть рабочий день в ближайшие N дней, который не занят
+// запланированными приемами целиком. Точные слоты считает сервис записи, здесь - быстрая оценка.
+const freeScheduleCondition = `EXISTS (
+	SELECT 1 FROM medical_center.schedules sc
+	WHERE sc.doctor_id = d.id AND sc.date BETWEEN CURRENT_DATE AND CURRENT_DATE + ?::int
+		AND EXTRACT(EPOCH FROM sc.end_time - sc.start_time) / 60 > COALESCE((
+			SELECT SUM(COALESCE(ds.duration_minutes, sv.duration_minutes)) FROM medical_center.appointments a
+			JOIN medical_center.services sv ON sv.id = a.service_id
+			LEFT JOIN medical_center.doctor_services ds ON ds.doctor_id = a.doctor_id AND ds.service_id = a.service_id
+			WHERE a.doctor_id = sc.doctor_id AND a.appointment_date = sc.date
+				AND a.status_id = ? AND a.deleted_at IS NULL), 0))`
+
+// headlineOptions - параметры ts_headline для подсветки совпадений в результатах поиска.
+const headlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=12, MinWords=3"
 
-	// Белый список для колонок сортировки для предотвращения SQL-инъекций
-	allowedSortBy := map[string]string{
-		"rating":           "rating",
-		"experience":       "experience_years",
-		"experience_years": "experience_years",
-		"name":             "last_name",
+// escapedSearchText - search_text врача с экранированными HTML-символами. Подсветка строится по нему,
+// чтобы единственной разметкой в highlight остались теги <mark>: имя или описание врача
+// не должны внедрять HTML на клиенте.
+const escapedSearchText = `replace(replace(replace(replace(replace(d.search_text,
+	'&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;')`
+
+// prefixTSQuery строит запрос to_tsquery, в котором каждое слово ищется как префикс: "карди ив" -> "карди:* & ив:*".
+// Все символы, кроме букв и цифр, отбрасываются, поэтому пользовательский ввод не ломает синтаксис запроса.
+func prefixTSQuery(query string) string {
+	words := strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+// textSearchCondition - префиксное совпадение слов с поисковым документом или нечеткое (с опечатками)
+// совпадение с его текстом по триграммам.
+func textSearchCondition(query string) (string, []any) {
+	tsQuery := prefixTSQuery(query)
+	if tsQuery == "" {
+		return "? <% d.search_text", []any{query}
+	}
+	return "(d.fts_document @@ to_tsquery('russian', ?) OR ? <% d.search_text)", []any{tsQuery, query}
+}
+
+// doctorServicesTable - действующие услуги врачей (алиас sv) с ценой и длительностью для каждого врача:
+// переопределенными в назначении или базовыми из каталога.
+const doctorServicesTable = `(SELECT ds.doctor_id, s.id, s.name,
+		COALESCE(ds.price, s.base_price) AS price, COALESCE(ds.duration_minutes, s.duration_minutes) AS duration_minutes
+	FROM medical_center.doctor_services ds
+	JOIN medical_center.services s ON s.id = ds.service_id AND s.deleted_at IS NULL) sv`
+
+// searchServiceConditions возвращает условия на услуги врача (алиас sv): название и, если withPrice, цену.
+func searchServiceConditions(filter models.DoctorSearchFilter, withPrice bool) (string, []any) {
+	var conditions strings.Builder
+	var args []any
+	if filter.Service != "" {
+		conditions.WriteString(" AND LOWER(sv.name) LIKE ?")
+		args = append(args, "%"+strings.ToLower(filter.Service)+"%")
+	}
+	if withPrice && filter.MinPrice > 0 {
+		conditions.WriteString(" AND sv.price >= ?")
+		args = append(args, filter.MinPrice)
+	}
+	if withPrice && filter.MaxPrice > 0 {
+		conditions.WriteString(" AND sv.price <= ?")
+		args = append(args, filter.MaxPrice)
+	}
+	return conditions.String(), args
+}
+
+// searchDoctorsQuery возвращает запрос к действующим врачам (алиас d) со всеми фильтрами поиска, кроме skip.
+func (r *DoctorPostgres) searchDoctorsQuery(ctx context.Context, filter models.DoctorSearchFilter, skip string) *gorm.DB {
+	query := r.db.WithContext(ctx).Table("medical_center.doctors d").Where("d.deleted_at IS NULL")
+
+	if filter.Query != "" {
+		condition, args := textSearchCondition(filter.Query)
+		query = query.Where(condition, args...)
+	}
+	if filter.SpecialtyID != 0 && skip != facetSpecialty {
+		query = query.Where("d.specialty_id = ?", filter.SpecialtyID)
 	}
-	orderByColumn, ok := allowedSortBy[params.SortBy]
+	if filter.MinRating > 0 {
+		query = query.Where("d.rating >= ?", filter.MinRating)
+	}
+	if filter.MinExperience > 0 {
+		query = query.Where("d.experience_years >= ?", filter.MinExperience)
+	}
+	if filter.Gender != "" && skip != facetGender {
+		query = query.Where("d.gender = ?", filter.Gender)
+	}
+	if filter.AcceptsDMS && skip != facetDMS {
+		query = query.Where("d.accepts_dms")
+	}
+	if filter.ClinicID != 0 && skip != facetClinic {
+		query = query.Where(`EXISTS (SELECT 1 FROM medical_center.doctorclinics dc
+			WHERE dc.doctor_id = d.id AND dc.clinic_id = ?)`, filter.ClinicID)
+	}
+	if filter.CityID != 0 && skip != facetCity {
+		query = query.Where(`EXISTS (SELECT 1 FROM medical_center.doctorclinics dc
+			JOIN medical_center.clinics c ON c.id = dc.clinic_id
+			WHERE dc.doctor_id = d.id AND c.city_id = ? AND c.deleted_at IS NULL)`, filter.CityID)
+	}
+	if conditions, args := searchServiceConditions(filter, skip != facetPrice); conditions != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM "+doctorServicesTable+" WHERE sv.doctor_id = d.id"+conditions+")",
+			args...)
+	}
+	if filter.SlotsWithinDays > 0 {
+		query = query.Where(freeScheduleCondition, filter.SlotsWithinDays, models.StatusScheduled)
+	}
+	return query
+}
+
+// searchOrder возвращает ORDER BY для поиска. Колонки берутся из белого списка для защиты от SQL-инъекций.
+func searchOrder(filter models.DoctorSearchFilter, params models.PaginationParams) string {
+	allowedSortBy := map[string]struct {
+		column       string
+		defaultOrder string
+	}{
+		"relevance":  {"rank", "DESC"},
+		"rating":     {"d.rating", "DESC"},
+		"experience": {"d.experience_years", "DESC"},
+		"name":       {"d.last_name", "ASC"},
+		"price":      {"min_price", "ASC"},
+	}
+	sortBy := params.SortBy
+	if sortBy == "" || (sortBy == "relevance" && filter.Query == "") {
+		sortBy = "rating"
+		if filter.Query != "" {
+			sortBy = "relevance"
+		}
+	}
+	sort, ok := allowedSortBy[sortBy]
 	if !ok {
-		orderByColumn = "rating" // Сортировка по умолчанию
+		sort = allowedSortBy["rating"]
 	}
 
-	sortOrder := "DESC"
-	if strings.ToUpper(params.SortOrder) == "ASC" {
+	sortOrder := sort.defaultOrder
+	switch strings.ToUpper(params.SortOrder) {
+	case "ASC":
 		sortOrder = "ASC"
+	case "DESC":
+		sortOrder = "DESC"
 	}
+	// id в конце делает порядок стабильным между страницами
+	return fmt.Sprintf("%s %s NULLS LAST, d.id", sort.column, sortOrder)
+}
 
-	query := r.db.WithContext(ctx).Model(&models.Doctor{}).Where("specialty_id = ?", specialtyID)
-
-	// Получаем общее количество
-	if err := query.Count(&total).Error; err != nil {
+// SearchDoctors ищет врачей по всем заданным фильтрам с пагинацией и сортировкой.
+func (r *DoctorPostgres) SearchDoctors(
+	ctx context.Context, filter models.DoctorSearchFilter, params models.PaginationParams,
+) ([]models.DoctorSearchItem, int64, error) {
+	var total int64
+	if err := r.searchDoctorsQuery(ctx, filter, facetNone).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
-
 	if total == 0 {
-		return []models.Doctor{}, 0, nil
+		return []models.DoctorSearchItem{}, 0, nil
 	}
 
-	// Получаем пагинированные данные
+	// Сначала выбираем страницу идентификаторов, затем загружаем врачей со специальностями
+	conditions, args := searchServiceConditions(filter, true)
+	columns := "d.id, (SELECT MIN(sv.price) FROM " + doctorServicesTable +
+		" WHERE sv.doctor_id = d.id" + conditions + ") AS min_price"
+	if tsQuery := prefixTSQuery(filter.Query); tsQuery != "" {
+		columns += `, ts_rank(d.fts_document, to_tsquery('russian', ?)) + word_similarity(?, d.search_text) AS rank,
+			ts_headline('russian', ` + escapedSearchText + `, to_tsquery('russian', ?), ?) AS highlight`
+		args = append(args, tsQuery, filter.Query, tsQuery, headlineOptions)
+	} else if filter.Query != "" {
+		columns += ", word_similarity(?, d.search_text) AS rank"
+		args = append(args, filter.Query)
+	}
+	var rows []struct {
+		ID        uint64
+		MinPrice  sql.NullFloat64
+		Highlight sql.NullString
+	}
 	offset := (params.Page - 1) * params.Limit
-	orderClause := fmt.Sprintf("%s %s", orderByColumn, sortOrder)
-	err := query.Preload("Specialty").Order(
-		orderClause).Limit(params.Limit).Offset(offset).Find(&doctors).Error
+	err := r.searchDoctorsQuery(ctx, filter, facetNone).Select(columns, args...).
+		Order(searchOrder(filter, params)).Limit(params.Limit).Offset(offset).Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(rows) == 0 {
+		return []models.DoctorSearchItem{}, total, nil
+	}
 
-	return doctors, total, err
+	ids := make([]uint64, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	var doctors []models.Doctor
+	if err := r.db.WithContext(ctx).Preload("Specialty").Where("id IN ?", ids).Find(&doctors).Error; err != nil {
+		return nil, 0, err
+	}
+	byID := make(map[uint64]models.Doctor, len(doctors))
+	for _, doctor := range doctors {
+		byID[doctor.ID] = doctor
+	}
+	clinics, err := r.clinicsByDoctor(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]models.DoctorSearchItem, 0, len(rows))
+	for _, row := range rows {
+		if doctor, ok := byID[row.ID]; ok {
+			if clinics[row.ID] == nil {
+				clinics[row.ID] = []models.Clinic{}
+			}
+			items = append(items, models.DoctorSearchItem{
+				Doctor: doctor, Clinics: clinics[row.ID], MinPrice: row.MinPrice, Highlight: row.Highlight.String,
+			})
+		}
+	}
+	return items, total, nil
 }
 
-// SearchDoctors выполняет поиск врачей по ФИО или названию специальности с использованием FTS.
-func (r *DoctorPostgres) SearchDoctors(ctx context.Context, searchQuery string) ([]models.Doctor, error) {
-	var doctors []models.Doctor
+// GetSearchFacets считает значения фильтров для результатов поиска.
+func (r *DoctorPostgres) GetSearchFacets(ctx context.Context, filter models.DoctorSearchFilter) (
+	models.DoctorSearchFacets, error,
+) {
+	var facets models.DoctorSearchFacets
 
-	// Преобразуем поисковый запрос в формат, понятный plainto_tsquery
-	tsQuery := strings.ReplaceAll(strings.TrimSpace(searchQuery), " ", " & ")
+	err := r.searchDoctorsQuery(ctx, filter, facetSpecialty).
+		Select("sp.id, sp.name, COUNT(*) AS count").
+		Joins("JOIN medical_center.specialties sp ON sp.id = d.specialty_id").
+		Group("sp.id, sp.name").Order("count DESC, sp.name").Scan(&facets.Specialties).Error
+	if err != nil {
+		return facets, err
+	}
 
-	// Ранжируем результаты по релевантности
-	rank := "ts_rank(fts_document, plainto_tsquery('russian', ?))"
+	err = r.searchDoctorsQuery(ctx, filter, facetClinic).
+		Select("c.id, c.name, COUNT(*) AS count").
+		Joins("JOIN medical_center.doctorclinics dc ON dc.doctor_id = d.id").
+		Joins("JOIN medical_center.clinics c ON c.id = dc.clinic_id AND c.deleted_at IS NULL").
+		Group("c.id, c.name").Order("count DESC, c.name").Scan(&facets.Clinics).Error
+	if err != nil {
+		return facets, err
+	}
 
-	err := r.db.WithContext(ctx).
-		Preload("Specialty").
-		Select("*, "+rank+" as rank", searchQuery).
-		Where("fts_document @@ plainto_tsquery('russian', ?)", tsQuery).
-		Order("rank DESC").
-		Find(&doctors).Error
+	err = r.searchDoctorsQuery(ctx, filter, facetCity).
+		Select("ci.id, ci.name, COUNT(DISTINCT d.id) AS count").
+		Joins("JOIN medical_center.doctorclinics dc ON dc.doctor_id = d.id").
+		Joins("JOIN medical_center.clinics c ON c.id = dc.clinic_id AND c.deleted_at IS NULL").
+		Joins("JOIN medical_center.cities ci ON ci.id = c.city_id").
+		Group("ci.id, ci.name").Order("count DESC, ci.name").Scan(&facets.Cities).Error
+	if err != nil {
+		return facets, err
+	}
 
-	return doctors, err
-}
+	err = r.searchDoctorsQuery(ctx, filter, facetGender).
+		Select("d.gender AS value, COUNT(*) AS count").Where("d.gender IS NOT NULL").
+		Group("d.gender").Order("d.gender").Scan(&facets.Genders).Error
+	if err != nil {
+		return facets, err
+	}
 
-// SearchDoctorsByService выполняет поиск врачей по названию предоставляемой услуги.
-func (r *DoctorPostgres) SearchDoctorsByService(ctx context.Context, serviceQuery string) ([]models.Doctor, error) {
-	var doctors []models.Doctor
-	searchPattern := "%" + strings.ToLower(serviceQuery) + "%"
+	err = r.searchDoctorsQuery(ctx, filter, facetDMS).Where("d.accepts_dms").Count(&facets.AcceptsDMS).Error
+	if err != nil {
+		return facets, err
+	}
 
-	err := r.db.WithContext(ctx).Preload("Specialty").
-		Joins("JOIN medical_center.services ON medical_center.services.doctor_id = medical_center.doctors.id").
-		Where("LOWER(medical_center.services.name) LIKE ?", searchPattern).
-		Distinct().
-		Find(&doctors).Error
+	conditions, args := searchServiceConditions(filter, false)
+	var prices struct {
+		MinPrice sql.NullFloat64
+		MaxPrice sql.NullFloat64
+	}
+	err = r.searchDoctorsQuery(ctx, filter, facetPrice).
+		Select("MIN(sv.price) AS min_price, MAX(sv.price) AS max_price").
+		Joins("JOIN "+doctorServicesTable+" ON sv.doctor_id = d.id"+conditions, args...).
+		Scan(&prices).Error
+	if err != nil {
+		return facets, err
+	}
+	facets.MinPrice, facets.MaxPrice = prices.MinPrice, prices.MaxPrice
+	return facets, nil
+}
 
-	return doctors, err
+// Autocomplete возвращает подсказки для строки поиска: врачей по ФИО, специальности и услуги.
+// Совпадение ищется по триграммам, поэтому находит и начало слова, и слово с опечаткой.
+func (r *DoctorPostgres) Autocomplete(ctx context.Context, query string, limit int) ([]models.SearchSuggestion, error) {
+	suggestions := []models.SearchSuggestion{}
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT type, id, text, hint FROM (
+			(SELECT @doctor::text AS type, d.id, concat_ws(' ', d.last_name, d.first_name, d.patronymic) AS text,
+				sp.name AS hint, word_similarity(@q, concat_ws(' ', d.last_name, d.first_name, d.patronymic)) AS score
+			FROM medical_center.doctors d
+			JOIN medical_center.specialties sp ON sp.id = d.specialty_id
+			WHERE d.deleted_at IS NULL AND @q <% d.search_text
+				AND @q <% concat_ws(' ', d.last_name, d.first_name, d.patronymic)
+			ORDER BY score DESC LIMIT @limit)
+			UNION ALL
+			(SELECT @specialty::text, sp.id, sp.name, NULL, word_similarity(@q, sp.name) AS score
+			FROM medical_center.specialties sp
+			WHERE @q <% sp.name
+			ORDER BY score DESC LIMIT @limit)
+			UNION ALL
+			(SELECT @service::text, MIN(sv.id), sv.name, NULL, word_similarity(@q, sv.name) AS score
+			FROM medical_center.services sv
+			WHERE sv.deleted_at IS NULL AND @q <% sv.name
+				AND EXISTS (SELECT 1 FROM medical_center.doctor_services ds WHERE ds.service_id = sv.id)
+			GROUP BY sv.name
+			ORDER BY score DESC LIMIT @limit)
+		) s
+		ORDER BY score DESC, text
+		LIMIT @limit`,
+		map[string]any{
+			"q": query, "limit": limit, "doctor": models.SuggestionDoctor,
+			"specialty": models.SuggestionSpecialty, "service": models.SuggestionService,
+		}).Scan(&suggestions).Error
+	return suggestions, err
 }
@@ -18,36 +18,17 @@ func NewInfoPostgres(db *gorm.DB) *InfoPostgres {
 	return &InfoPostgres{db: db}
 }
 
-// GetClinicInfo возвращает информацию о клинике из БД.
-func (r *InfoPostgres) GetClinicInfo(ctx context.Context) (models.ClinicInfo, error) {
-	var clinic models.Clinic
-	// Предполагаем, что основная клиника имеет ID = 1
-	err := r.db.WithContext(ctx).First(&clinic, 1).Error
-	if err != nil {
-		return models.ClinicInfo{}, err
-	}
-
-	// Формируем DTO на основе полученных данных
-	info := models.ClinicInfo{
-		Name: clinic.Name,
-		Contacts: []models.Contact{
-			{Type: "phone", Value: clinic.Phone},
-			{Type: "email", Value: "info@clinic.ru"},
-		},
-		Addresses: []models.Address{
-			{ID: int(clinic.ID), Address: clinic.Address, IsMain: true},
-		},
-		WorkingHours: []models.WorkHours{
-			{Days: "пн-пт", Hours: "08:00 - 20:00"},
-			{Days: "сб", Hours: "09:00 - 18:00"},
-		},
-	}
-	return info, nil
-}
-
-// GetLegalDocuments возвращает список юридических документов из БД.
+// currentLegalDocumentsQuery выбирает действующую (последнюю опубликованную) версию каждого типа документа.
+const currentLegalDocumentsQuery = `
+	SELECT DISTINCT ON (d.type) d.*, t.is_mandatory
+	FROM medical_center.legal_documents d
+	JOIN medical_center.legal_document_types t ON t.type = d.type
+	WHERE d.status = 'published'
+	ORDER BY d.type, d.published_at DESC, d.id DESC`
+
+// GetLegalDocuments возвращает действующие версии юридических документов.
 func (r *InfoPostgres) GetLegalDocuments(ctx context.Context) ([]models.LegalDocument, error) {
 	var docs []models.LegalDocument
-	err := r.db.WithContext(ctx).Find(&docs).Error
+	err := r.db.WithContext(ctx).Raw(currentLegalDocumentsQuery).Scan(&docs).Error
 	return docs, err
 }
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"lk/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// legalDocumentColumns - колонки версии документа вместе с признаком обязательности типа.
+const legalDocumentColumns = "d.*, t.is_mandatory"
+
+// LegalPostgres реализует LegalRepository для PostgreSQL.
+type LegalPostgres struct {
+	db *gorm.DB
+}
+
+// NewLegalPostgres создает новый экземпляр репозитория юридических документов.
+func NewLegalPostgres(db *gorm.DB) *LegalPostgres {
+	return &LegalPostgres{db: db}
+}
+
+// GetTypes возвращает справочник типов юридических документов.
+func (r *LegalPostgres) GetTypes(ctx context.Context) ([]models.LegalDocumentType, error) {
+	var types []models.LegalDocumentType
+	err := r.db.WithContext(ctx).Order("type").Find(&types).Error
+	return types, err
+}
+
+// GetTypeByCode находит тип документа по его коду.
+func (r *LegalPostgres) GetTypeByCode(ctx context.Context, docType string) (models.LegalDocumentType, error) {
+	var result models.LegalDocumentType
+	err := r.db.WithContext(ctx).Where("type = ?", docType).First(&result).Error
+	return result, err
+}
+
+// GetAll возвращает все версии документов с фильтрами, новые сверху.
+func (r *LegalPostgres) GetAll(ctx context.Context, filter models.LegalDocumentFilter, params models.PaginationParams) (
+	[]models.LegalDocument, int64, error,
+) {
+	var docs []models.LegalDocument
+	var total int64
+	query := r.db.WithContext(ctx).Table("medical_center.legal_documents AS d")
+	if filter.Type != "" {
+		query = query.Where("d.type = ?", filter.Type)
+	}
+	if filter.Status != "" {
+		query = query.Where("d.status = ?", filter.Status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (params.Page - 1) * params.Limit
+	err := query.Select(legalDocumentColumns).
+		Joins("JOIN medical_center.legal_document_types t ON t.type = d.type").
+		Order("d.created_at DESC, d.id DESC").Limit(params.Limit).Offset(offset).Find(&docs).Error
+	return docs, total, err
+}
+
+// GetByID находит версию документа по ID.
+func (r *LegalPostgres) GetByID(ctx context.Context, id uint64) (models.LegalDocument, error) {
+	var doc models.LegalDocument
+	err := r.withType(ctx).Where("d.id = ?", id).First(&doc).Error
+	return doc, err
+}
+
+// GetCurrentByType возвращает действующую (последнюю опубликованную) версию документа типа.
+func (r *LegalPostgres) GetCurrentByType(ctx context.Context, docType string) (models.LegalDocument, error) {
+	var doc models.LegalDocument
+	err := r.withType(ctx).
+		Where("d.type = ? AND d.status = ?", docType, models.LegalDocumentPublished).
+		Order("d.published_at DESC, d.id DESC").
+		First(&doc).Error
+	return doc, err
+}
+
+// Create сохраняет новую версию документа и возвращает ее ID.
+func (r *LegalPostgres) Create(ctx context.Context, doc models.LegalDocument) (uint64, error) {
+	err := r.db.WithContext(ctx).Create(&doc).Error
+	return doc.ID, err
+}
+
+// UpdateDraft обновляет черновик. Опубликованные версии не изменяются:
+// если черновик не найден, возвращается gorm.ErrRecordNotFound.
+func (r *LegalPostgres) UpdateDraft(ctx context.Context, doc models.LegalDocument) error {
+	result := r.db.WithContext(ctx).Model(&models.LegalDocument{}).
+		Where("id = ? AND status = ?", doc.ID, models.LegalDocumentDraft).
+		Updates(map[string]interface{}{
+			"title":       doc.Title,
+			"version":     doc.Version,
+			"update_date": doc.UpdateDate,
+			"file_key":    doc.FileKey,
+			"file_name":   doc.FileName,
+			"file_size":   doc.FileSize,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Publish публикует черновик. Если черновик не найден, возвращается gorm.ErrRecordNotFound.
+func (r *LegalPostgres) Publish(ctx context.Context, id uint64) error {
+	result := r.db.WithContext(ctx).Model(&models.LegalDocument{}).
+		Where("id = ? AND status = ?", id, models.LegalDocumentDraft).
+		Updates(map[string]interface{}{
+			"status":       models.LegalDocumentPublished,
+			"published_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteDraft удаляет черновик. Если черновик не найден, возвращается gorm.ErrRecordNotFound.
+func (r *LegalPostgres) DeleteDraft(ctx context.Context, id uint64) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND status = ?", id, models.LegalDocumentDraft).
+		Delete(&models.LegalDocument{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// withType - запрос к версиям документов с признаком обязательности из справочника типов.
+func (r *LegalPostgres) withType(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Table("medical_center.legal_documents AS d").
+		Select(legalDocumentColumns).
+		Joins("JOIN medical_center.legal_document_types t ON t.type = d.type")
+}
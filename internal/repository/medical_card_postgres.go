@@ -53,10 +53,11 @@ func (r *MedicalCardPostgres) GetCompletedVisits(
 		sortOrder = "appointment_date ASC"
 	}
 
-	// Preload загружает связанные данные одним запросом
+	// Preload загружает связанные данные одним запросом.
+	// Врач и услуга нужны и для истории, даже если их уже удалили из справочников.
 	err := query.
-		Preload("Doctor").
-		Preload("Service").
+		Preload("Doctor", withDeleted).
+		Preload("Service", withDeleted).
 		Order(sortOrder).
 		Limit(params.Limit).
 		Offset(offset).
@@ -22,10 +22,15 @@ type UserRepository interface {
 	GetUserByPhone(ctx context.Context, phone string) (models.User, error)
 	GetUserByID(ctx context.Context, id uint64) (models.User, error)
 	CreateUserProfile(ctx context.Context, tx *gorm.DB, profile models.UserProfile) (uint64, error)
+	ReplacePendingUser(ctx context.Context, tx *gorm.DB, user models.User, profile models.UserProfile) error
 	GetUserProfileByUserID(ctx context.Context, userID uint64) (models.UserProfile, error)
 	UpdateUserProfile(ctx context.Context, profile models.UserProfile) (models.UserProfile, error)
 	UpdateAvatar(ctx context.Context, userID uint64, avatarURL string) error
 	UpdatePassword(ctx context.Context, userID uint64, newPasswordHash string) error
+	MarkPhoneVerified(ctx context.Context, userID uint64) error
+	GetUserProfileByEmail(ctx context.Context, email string) (models.UserProfile, error)
+	MarkEmailVerified(ctx context.Context, userID uint64, email string) error
+	ResetEmailVerification(ctx context.Context, userID uint64) error
 }
 
 // TokenRepository определяет методы для работы с refresh-токенами.
@@ -35,14 +40,39 @@ type TokenRepository interface {
 	Delete(ctx context.Context, userID uint64) error
 }
 
+// AuditRepository определяет методы для работы с журналом аудита.
+type AuditRepository interface {
+	Create(ctx context.Context, entry models.AuditLog) error
+	GetAll(ctx context.Context, filter models.AuditLogFilter, params models.PaginationParams) (
+		[]models.AuditLog, int64, error)
+	Export(ctx context.Context, filter models.AuditLogFilter, fn func(batch []models.AuditLog) error) error
+	GetMedicalDataAccess(ctx context.Context, filter models.MedicalDataAccessFilter, params models.PaginationParams) (
+		[]models.MedicalDataAccess, int64, error)
+}
+
+// AdminSessionRepository определяет методы для работы с сессиями администраторов.
+type AdminSessionRepository interface {
+	Create(ctx context.Context, session models.AdminSession) (uint64, error)
+	GetActive(ctx context.Context, sessionID uint64) (models.AdminSession, error)
+	ListActive(ctx context.Context, adminID uint64) ([]models.AdminSession, error)
+	Rotate(ctx context.Context, sessionID uint64, oldHash, newHash, clientIP, userAgent string,
+		expiresAt time.Time) error
+	Delete(ctx context.Context, adminID, sessionID uint64) error
+	DeleteAll(ctx context.Context, adminID, exceptID uint64) (int64, error)
+}
+
 // DoctorRepository определяет методы для работы с врачами.
 type DoctorRepository interface {
 	GetDoctorByID(ctx context.Context, id uint64) (models.Doctor, error)
-	GetDoctorsBySpecialty(ctx context.Context, specialtyID uint32, params models.PaginationParams) (
-		[]models.Doctor, int64, error)
-	SearchDoctors(ctx context.Context, query string) ([]models.Doctor, error)
-	SearchDoctorsByService(ctx context.Context, serviceQuery string) ([]models.Doctor, error)
+	SearchDoctors(ctx context.Context, filter models.DoctorSearchFilter, params models.PaginationParams) (
+		[]models.DoctorSearchItem, int64, error)
+	GetSearchFacets(ctx context.Context, filter models.DoctorSearchFilter) (models.DoctorSearchFacets, error)
+	Autocomplete(ctx context.Context, query string, limit int) ([]models.SearchSuggestion, error)
 	GetSpecialistRecommendations(ctx context.Context, doctorID uint64) (string, error)
+	GetDoctorProfile(ctx context.Context, id uint64) (models.DoctorProfile, error)
+	GetDoctorClinics(ctx context.Context, doctorID uint64) ([]models.Clinic, error)
+	GetDoctorServices(ctx context.Context, doctorID uint64) ([]models.DoctorServiceOffer, error)
+	GetDoctorService(ctx context.Context, doctorID, serviceID uint64) (models.DoctorServiceOffer, error)
 }
 
 // AppointmentRepository определяет методы для работы с записями на прием.
@@ -52,15 +82,16 @@ type AppointmentRepository interface {
 	GetUpcomingAppointmentsByUserID(ctx context.Context, userID uint64) ([]models.Appointment, error)
 	GetAppointmentByID(ctx context.Context, appointmentID uint64) (models.Appointment, error)
 	UpdateAppointmentStatus(ctx context.Context, appointmentID uint64, statusID uint32) error
+	GetAppointmentsForReminder(ctx context.Context, from, to time.Time) ([]models.AppointmentReminder, error)
 
 	// Методы для работы с реальным расписанием
-	GetAvailableDatesForMonth(ctx context.Context, doctorID uint64, month time.Time) ([]time.Time, error)
-	GetDoctorScheduleForDate(ctx context.Context, doctorID uint64, date time.Time) (models.Schedule, error)
-	GetServiceDurationMinutes(ctx context.Context, serviceID uint64) (uint16, error)
+	GetAvailableDatesForMonth(ctx context.Context, doctorID, clinicID uint64, month time.Time) ([]time.Time, error)
+	GetDoctorSchedulesForDate(ctx context.Context, doctorID, clinicID uint64, date time.Time) ([]models.Schedule, error)
 	GetAppointmentsByDoctorAndDate(ctx context.Context, doctorID uint64, date time.Time) ([]models.Appointment, error)
-	GetServicesByIDs(ctx context.Context, serviceIDs []uint64) ([]models.Service, error)
+	GetServicesByIDs(ctx context.Context, doctorID uint64, serviceIDs []uint64) ([]models.Service, error)
 	GetAppointmentsByDoctorAndDateRange(ctx context.Context, doctorID uint64, startDate, endDate time.Time) ([]models.Appointment, error)
-	GetDoctorScheduleForDateRange(ctx context.Context, doctorID uint64, startDate, endDate time.Time) ([]models.Schedule, error)
+	GetDoctorScheduleForDateRange(ctx context.Context, doctorID, clinicID uint64, startDate, endDate time.Time) (
+		[]models.Schedule, error)
 }
 
 // DirectoryRepository определяет методы для работы со справочниками.
@@ -71,10 +102,88 @@ type DirectoryRepository interface {
 
 // InfoRepository определяет методы для работы с общей информацией.
 type InfoRepository interface {
-	GetClinicInfo(ctx context.Context) (models.ClinicInfo, error)
 	GetLegalDocuments(ctx context.Context) ([]models.LegalDocument, error)
 }
 
+// LegalRepository определяет методы для управления версиями юридических документов.
+type LegalRepository interface {
+	GetTypes(ctx context.Context) ([]models.LegalDocumentType, error)
+	GetTypeByCode(ctx context.Context, docType string) (models.LegalDocumentType, error)
+	GetAll(ctx context.Context, filter models.LegalDocumentFilter, params models.PaginationParams) (
+		[]models.LegalDocument, int64, error)
+	GetByID(ctx context.Context, id uint64) (models.LegalDocument, error)
+	GetCurrentByType(ctx context.Context, docType string) (models.LegalDocument, error)
+	Create(ctx context.Context, doc models.LegalDocument) (uint64, error)
+	UpdateDraft(ctx context.Context, doc models.LegalDocument) error
+	Publish(ctx context.Context, id uint64) error
+	DeleteDraft(ctx context.Context, id uint64) error
+}
+
+// ConsentRepository определяет методы для работы с согласиями пользователей.
+type ConsentRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, consents []models.UserConsent) error
+	RevokeAll(ctx context.Context, tx *gorm.DB, userID uint64) error
+	GetActive(ctx context.Context, userID uint64) ([]models.UserConsent, error)
+	GetHistory(ctx context.Context, userID uint64, params models.PaginationParams) ([]models.UserConsent, int64, error)
+	Revoke(ctx context.Context, userID uint64, documentType string) (int64, error)
+	CountPendingMandatory(ctx context.Context, userID uint64) (int64, error)
+}
+
+// AccountRepository определяет методы для выгрузки данных пациента и удаления аккаунта.
+type AccountRepository interface {
+	CreateExport(ctx context.Context, userID uint64) (models.DataExport, error)
+	GetExports(ctx context.Context, userID uint64, limit int) ([]models.DataExport, error)
+	GetExport(ctx context.Context, userID, exportID uint64) (models.DataExport, error)
+	ClaimExport(ctx context.Context, staleBefore time.Time) (models.DataExport, error)
+	CompleteExport(ctx context.Context, exportID uint64, fileKey string, fileSize int64, expiresAt time.Time) error
+	FailExport(ctx context.Context, exportID uint64, reason string) error
+	GetExpiredExports(ctx context.Context, now time.Time, limit int) ([]models.DataExport, error)
+	MarkExportExpired(ctx context.Context, exportID uint64) error
+
+	// Данные для архива выгрузки
+	GetExportAppointments(ctx context.Context, userID uint64) ([]models.ExportAppointment, error)
+	GetExportAnalyses(ctx context.Context, userID uint64) ([]models.ExportAnalysis, error)
+	GetPrescriptions(ctx context.Context, userID uint64) ([]models.Prescription, error)
+	GetConsents(ctx context.Context, userID uint64) ([]models.UserConsent, error)
+
+	// Удаление аккаунта
+	CreateDeletion(ctx context.Context, deletion models.AccountDeletion) (models.AccountDeletion, error)
+	GetPendingDeletion(ctx context.Context, userID uint64) (models.AccountDeletion, error)
+	CancelDeletion(ctx context.Context, userID uint64) (int64, error)
+	GetDueDeletions(ctx context.Context, now time.Time, limit int) ([]models.AccountDeletion, error)
+	AnonymizeUser(ctx context.Context, deletionID, userID uint64) ([]string, error)
+}
+
+// ClinicRepository определяет методы для работы с клиниками сети.
+type ClinicRepository interface {
+	GetAll(ctx context.Context) ([]models.Clinic, error)
+	GetByID(ctx context.Context, id uint64) (models.Clinic, error)
+	Create(ctx context.Context, clinic models.Clinic) (uint64, error)
+	Update(ctx context.Context, clinic models.Clinic) error
+	Delete(ctx context.Context, id uint64) error
+	GetDeleted(ctx context.Context, params models.PaginationParams) ([]models.Clinic, int64, error)
+	Restore(ctx context.Context, id uint64) error
+	HasUpcomingSchedules(ctx context.Context, id uint64) (bool, error)
+}
+
+// ClinicSettingsRepository определяет методы для хранения версий настроек клиники.
+type ClinicSettingsRepository interface {
+	GetCurrent(ctx context.Context) (models.ClinicSettingsVersion, error)
+	Create(ctx context.Context, version *models.ClinicSettingsVersion) error
+	GetHistory(ctx context.Context, params models.PaginationParams) ([]models.ClinicSettingsVersion, int64, error)
+}
+
+// ReviewRepository определяет методы для работы с отзывами о врачах.
+type ReviewRepository interface {
+	Create(ctx context.Context, review models.Review) (models.Review, error)
+	GetByUser(ctx context.Context, userID uint64, params models.PaginationParams) ([]models.Review, int64, error)
+	GetApprovedByDoctor(ctx context.Context, doctorID uint64, params models.PaginationParams) (
+		[]models.DoctorReview, int64, error)
+	GetAll(ctx context.Context, filter models.ReviewFilter, params models.PaginationParams) ([]models.Review, int64, error)
+	GetByID(ctx context.Context, id uint64) (models.Review, error)
+	Moderate(ctx context.Context, id uint64, status, reason string, adminID uint64) (models.Review, error)
+}
+
 // PrescriptionRepository определяет методы для работы с назначениями.
 type PrescriptionRepository interface {
 	GetActiveByUserID(ctx context.Context, userID uint64) ([]models.Prescription, error)
@@ -85,6 +194,7 @@ type PrescriptionRepository interface {
 // ServiceRepository определяет методы для работы с услугами.
 type ServiceRepository interface {
 	GetServiceRecommendations(ctx context.Context, serviceID uint64) (string, error)
+	GetPriceList(ctx context.Context) ([]models.PriceListRow, error)
 }
 
 // MedicalCardRepository определяет методы для работы с данными медкарты.
@@ -101,14 +211,45 @@ type MedicalCardRepository interface {
 // CacheRepository определяет интерфейс для работы с key-value хранилищем (кэшем).
 type CacheRepository interface {
 	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+	SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error)
 	Get(ctx context.Context, key string) (string, error)
 	Delete(ctx context.Context, key string) error
+	Increment(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	TTL(ctx context.Context, key string) (time.Duration, error)
+}
+
+// SecurityRepository определяет методы для хранения событий безопасности.
+type SecurityRepository interface {
+	CreateLockoutEvent(ctx context.Context, event models.LockoutEvent) error
+	GetLockoutEvents(ctx context.Context, params models.PaginationParams) ([]models.LockoutEvent, int64, error)
+	GetSettings(ctx context.Context) (models.SecuritySettings, error)
+	UpdateSettings(ctx context.Context, settings models.SecuritySettings) error
+	CreateImpersonationLog(ctx context.Context, entry models.ImpersonationLog) error
+	GetImpersonationLogs(ctx context.Context, filter models.ImpersonationLogFilter, params models.PaginationParams) (
+		[]models.ImpersonationLog, int64, error)
 }
 
 // AdminRepository определяет методы для работы с администраторами.
 type AdminRepository interface {
 	GetByLogin(ctx context.Context, login string) (models.Admin, error)
 	GetByID(ctx context.Context, id uint64) (models.Admin, error)
+	GetAllAdmins(ctx context.Context, params models.PaginationParams) ([]models.Admin, int64, error)
+	UpdateAdminRole(ctx context.Context, adminID uint64, role models.AdminRole) error
+	CountAdminsByRole(ctx context.Context, role models.AdminRole) (int64, error)
+	CountAdmins(ctx context.Context) (int64, error)
+	CreateAdmin(ctx context.Context, admin models.Admin) (uint64, error)
+	UpdateAdmin(ctx context.Context, admin models.Admin) error
+	GetActiveAdminsWithEmail(ctx context.Context) ([]models.Admin, error)
+	SetAdminActive(ctx context.Context, adminID uint64, active bool) error
+	UpdateAdminPassword(ctx context.Context, adminID uint64, passwordHash string, mustChange bool) error
+	UpdateLastLogin(ctx context.Context, adminID uint64) error
+
+	// Двухфакторная аутентификация
+	EnableTOTP(ctx context.Context, adminID uint64, secret string, recoveryCodeHashes []string) error
+	DisableTOTP(ctx context.Context, adminID uint64) error
+	ReplaceRecoveryCodes(ctx context.Context, adminID uint64, recoveryCodeHashes []string) error
+	UseRecoveryCode(ctx context.Context, adminID uint64, codeHash string) (bool, error)
+	CountRecoveryCodes(ctx context.Context, adminID uint64) (int64, error)
 
 	// User
 	GetAllUsers(ctx context.Context, params models.PaginationParams) ([]models.User, int64, error)
@@ -118,6 +259,8 @@ type AdminRepository interface {
 	UpdateUser(ctx context.Context, user models.User, profile models.UserProfile) error
 
 	DeleteUser(ctx context.Context, userID uint64) error
+	GetDeletedUsers(ctx context.Context, params models.PaginationParams) ([]models.User, int64, error)
+	RestoreUser(ctx context.Context, userID uint64) error
 	GetUserAppointments(ctx context.Context, userID uint64, params models.PaginationParams) ([]models.Appointment, int64, error)
 	GetUserAnalyses(ctx context.Context, userID uint64, params models.PaginationParams) ([]models.LabAnalysis, int64, error)
 
@@ -126,22 +269,55 @@ type AdminRepository interface {
 	CreateDoctor(ctx context.Context, doctor models.Doctor) (uint64, error)
 	UpdateDoctor(ctx context.Context, doctor models.Doctor) error
 	DeleteDoctor(ctx context.Context, doctorID uint64) error
+	GetDeletedSpecialists(ctx context.Context, params models.PaginationParams) ([]models.Doctor, int64, error)
+	RestoreDoctor(ctx context.Context, doctorID uint64) error
 	GetDoctorSchedule(ctx context.Context, doctorID uint64) ([]models.Schedule, error)
 	UpdateDoctorSchedule(ctx context.Context, doctorID uint64, schedule []models.Schedule) error
 
+	// Разделы профиля врача; item - указатель на модель раздела
+	CreateDoctorProfileItem(ctx context.Context, item any) error
+	UpdateDoctorProfileItem(ctx context.Context, doctorID, itemID uint64, item any) error
+	DeleteDoctorProfileItem(ctx context.Context, doctorID, itemID uint64, item any) error
+	UpdateDoctorAvatar(ctx context.Context, doctorID uint64, avatarURL string) error
+	GetClinicsByIDs(ctx context.Context, ids []uint64) ([]models.Clinic, error)
+	SetDoctorClinics(ctx context.Context, doctorID uint64, clinicIDs []uint64) error
+	GetExpiringCertificates(ctx context.Context, before time.Time, params models.PaginationParams) (
+		[]models.ExpiringCertificate, int64, error)
+	CountExpiringCertificates(ctx context.Context, before time.Time) (int64, error)
+	ClaimCertificateAlerts(ctx context.Context, before time.Time) ([]models.ExpiringCertificate, error)
+
 	// Appointment
 	GetAllAppointments(ctx context.Context, params models.PaginationParams, filters map[string]any) ([]models.Appointment, int64, error)
 	DeleteAppointment(ctx context.Context, appointmentID uint64) error
+	GetDeletedAppointments(ctx context.Context, params models.PaginationParams) ([]models.Appointment, int64, error)
+	RestoreAppointment(ctx context.Context, appointmentID uint64) error
 	GetAppointmentStats(ctx context.Context) (map[string]int64, error)
 
 	// Service & Department
 	GetAllServices(ctx context.Context) ([]models.Service, error)
-	CreateService(ctx context.Context, service models.Service) (uint64, error)
-	UpdateService(ctx context.Context, service models.Service) error
+	GetServiceByID(ctx context.Context, serviceID uint64) (models.Service, error)
+	CreateService(ctx context.Context, service models.Service, changedBy *uint64) (uint64, error)
+	UpdateService(ctx context.Context, service models.Service, changedBy *uint64) error
+	GetServicePriceHistory(ctx context.Context, serviceID uint64, params models.PaginationParams) (
+		[]models.ServicePriceChange, int64, error)
 	DeleteService(ctx context.Context, serviceID uint64) error
+	GetDeletedServices(ctx context.Context, params models.PaginationParams) ([]models.Service, int64, error)
+	RestoreService(ctx context.Context, serviceID uint64) error
+	GetActiveServicesByIDs(ctx context.Context, ids []uint64) ([]models.Service, error)
+	GetDoctorServiceAssignments(ctx context.Context, doctorID uint64) ([]models.DoctorService, error)
+	SetDoctorServices(ctx context.Context, doctorID uint64, assignments []models.DoctorService, changedBy *uint64) error
+	GetServiceCategories(ctx context.Context) ([]models.ServiceCategory, error)
+	CreateServiceCategory(ctx context.Context, category models.ServiceCategory) (uint32, error)
+	UpdateServiceCategory(ctx context.Context, category models.ServiceCategory) error
+	DeleteServiceCategory(ctx context.Context, categoryID uint32) error
 	CreateDepartment(ctx context.Context, department models.Department) (uint32, error)
 	UpdateDepartment(ctx context.Context, department models.Department) error
 	DeleteDepartment(ctx context.Context, departmentID uint32) error
+	GetDeletedDepartments(ctx context.Context, params models.PaginationParams) ([]models.Department, int64, error)
+	RestoreDepartment(ctx context.Context, departmentID uint32) error
+
+	// Мягкое удаление
+	PurgeDeleted(ctx context.Context, before time.Time) ([]models.PurgeResult, error)
 
 	// Статистика
 	GetDashboardStats(ctx context.Context) (models.AdminDashboardStats, error)
@@ -155,11 +331,20 @@ type Repository struct {
 	Appointment  AppointmentRepository
 	Directory    DirectoryRepository
 	Info         InfoRepository
+	Consent      ConsentRepository
+	Legal        LegalRepository
+	Account      AccountRepository
+	Review       ReviewRepository
+	Clinic       ClinicRepository
+	Settings     ClinicSettingsRepository
 	Prescription PrescriptionRepository
 	Service      ServiceRepository
 	MedicalCard  MedicalCardRepository
 	Cache        CacheRepository
 	Admin        AdminRepository
+	AdminSession AdminSessionRepository
+	Security     SecurityRepository
+	Audit        AuditRepository
 	Transactor
 }
 
@@ -172,11 +357,20 @@ func NewRepository(db *gorm.DB, redisClient *redis.Client) *Repository {
 		Appointment:  NewAppointmentPostgres(db),
 		Directory:    NewDirectoryPostgres(db),
 		Info:         NewInfoPostgres(db),
+		Consent:      NewConsentPostgres(db),
+		Legal:        NewLegalPostgres(db),
+		Account:      NewAccountPostgres(db),
+		Review:       NewReviewPostgres(db),
+		Clinic:       NewClinicPostgres(db),
+		Settings:     NewClinicSettingsPostgres(db),
 		Prescription: NewPrescriptionPostgres(db),
 		Service:      NewServicePostgres(db),
 		MedicalCard:  NewMedicalCardPostgres(db),
 		Cache:        NewCacheRedis(redisClient),
 		Admin:        NewAdminPostgres(db),
+		AdminSession: NewAdminSessionPostgres(db),
+		Security:     NewSecurityPostgres(db),
+		Audit:        NewAuditPostgres(db),
 		Transactor:   NewTransactor(db),
 	}
 }
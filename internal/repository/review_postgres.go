@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"lk/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReviewPostgres реализует ReviewRepository для PostgreSQL.
+type ReviewPostgres struct {
+	db *gorm.DB
+}
+
+// NewReviewPostgres создает новый экземпляр репозитория отзывов.
+func NewReviewPostgres(db *gorm.DB) *ReviewPostgres {
+	return &ReviewPostgres{db: db}
+}
+
+// Create сохраняет новый отзыв. Повторный отзыв на тот же прием нарушает уникальный индекс.
+func (r *ReviewPostgres) Create(ctx context.Context, review models.Review) (models.Review, error) {
+	err := r.db.WithContext(ctx).Create(&review).Error
+	return review, err
+}
+
+// GetByUser возвращает отзывы пациента, новые сверху.
+func (r *ReviewPostgres) GetByUser(ctx context.Context, userID uint64, params models.PaginationParams) (
+	[]models.Review, int64, error,
+) {
+	var reviews []models.Review
+	var total int64
+	query := r.db.WithContext(ctx).Model(&models.Review{}).Where("user_id = ?", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (params.Page - 1) * params.Limit
+	err := query.Order("created_at DESC, id DESC").Limit(params.Limit).Offset(offset).Find(&reviews).Error
+	return reviews, total, err
+}
+
+// GetApprovedByDoctor возвращает одобренные отзывы о враче, новые сверху.
+func (r *ReviewPostgres) GetApprovedByDoctor(ctx context.Context, doctorID uint64, params models.PaginationParams) (
+	[]models.DoctorReview, int64, error,
+) {
+	var reviews []models.DoctorReview
+	var total int64
+	query := r.db.WithContext(ctx).Table("medical_center.reviews r").
+		Where("r.doctor_id = ? AND r.status = ?", doctorID, models.ReviewApproved)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (params.Page - 1) * params.Limit
+	err := query.Select(`r.id, r.rating, COALESCE(r.comment, '') AS comment, r.created_at,
+			COALESCE(p.first_name || ' ' || LEFT(p.last_name, 1) || '.', 'Пациент') AS author_name`).
+		Joins("LEFT JOIN medical_center.user_profiles p ON p.user_id = r.user_id").
+		Order("r.created_at DESC, r.id DESC").Limit(params.Limit).Offset(offset).Scan(&reviews).Error
+	return reviews, total, err
+}
+
+// GetAll возвращает отзывы для модерации. Старые сверху, чтобы очередь разбиралась по порядку.
+func (r *ReviewPostgres) GetAll(ctx context.Context, filter models.ReviewFilter, params models.PaginationParams) (
+	[]models.Review, int64, error,
+) {
+	var reviews []models.Review
+	var total int64
+	query := r.db.WithContext(ctx).Model(&models.Review{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.DoctorID != 0 {
+		query = query.Where("doctor_id = ?", filter.DoctorID)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (params.Page - 1) * params.Limit
+	err := query.Order("created_at ASC, id ASC").Limit(params.Limit).Offset(offset).Find(&reviews).Error
+	return reviews, total, err
+}
+
+// GetByID возвращает отзыв по ID.
+func (r *ReviewPostgres) GetByID(ctx context.Context, id uint64) (models.Review, error) {
+	var review models.Review
+	err := r.db.WithContext(ctx).First(&review, id).Error
+	return review, err
+}
+
+// Moderate меняет статус отзыва и в той же транзакции пересчитывает рейтинг
+// и число отзывов врача по одобренным отзывам.
+func (r *ReviewPostgres) Moderate(ctx context.Context, id uint64, status, reason string, adminID uint64) (
+	models.Review, error,
+) {
+	var review models.Review
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&review, id).Error; err != nil {
+			return err
+		}
+		review.Status = status
+		review.RejectionReason.String, review.RejectionReason.Valid = reason, reason != ""
+		review.ModeratedBy.Int64, review.ModeratedBy.Valid = int64(adminID), true
+		review.ModeratedAt.Time, review.ModeratedAt.Valid = time.Now(), true
+		err := tx.Model(&review).Select("status", "rejection_reason", "moderated_by", "moderated_at").
+			Updates(&review).Error
+		if err != nil {
+			return err
+		}
+		return tx.Exec(`
+			UPDATE medical_center.doctors SET (rating, review_count) = (
+				SELECT COALESCE(ROUND(AVG(rating)::numeric, 2), 0), COUNT(*)
+				FROM medical_center.reviews
+				WHERE doctor_id = ? AND status = ?
+			)
+			WHERE id = ?`, review.DoctorID, models.ReviewApproved, review.DoctorID).Error
+	})
+	return review, err
+}
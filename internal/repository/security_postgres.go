@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"lk/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// SecurityPostgres реализует SecurityRepository для PostgreSQL.
+type SecurityPostgres struct {
+	db *gorm.DB
+}
+
+// NewSecurityPostgres создает новый экземпляр репозитория событий безопасности.
+func NewSecurityPostgres(db *gorm.DB) *SecurityPostgres {
+	return &SecurityPostgres{db: db}
+}
+
+// CreateLockoutEvent сохраняет событие блокировки.
+func (r *SecurityPostgres) CreateLockoutEvent(ctx context.Context, event models.LockoutEvent) error {
+	return r.db.WithContext(ctx).Create(&event).Error
+}
+
+// GetLockoutEvents возвращает пагинированный список событий блокировки, новые сверху.
+func (r *SecurityPostgres) GetLockoutEvents(ctx context.Context, params models.PaginationParams) (
+	[]models.LockoutEvent, int64, error,
+) {
+	var events []models.LockoutEvent
+	var total int64
+	query := r.db.WithContext(ctx).Model(&models.LockoutEvent{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (params.Page - 1) * params.Limit
+	err := query.Order("created_at DESC").Limit(params.Limit).Offset(offset).Find(&events).Error
+	return events, total, err
+}
+
+// CreateImpersonationLog сохраняет запись о запросе, выполненном от имени пациента.
+func (r *SecurityPostgres) CreateImpersonationLog(ctx context.Context, entry models.ImpersonationLog) error {
+	return r.db.WithContext(ctx).Create(&entry).Error
+}
+
+// GetImpersonationLogs возвращает пагинированный журнал имперсонации, новые записи сверху.
+func (r *SecurityPostgres) GetImpersonationLogs(ctx context.Context, filter models.ImpersonationLogFilter,
+	params models.PaginationParams,
+) ([]models.ImpersonationLog, int64, error) {
+	var entries []models.ImpersonationLog
+	var total int64
+	query := r.db.WithContext(ctx).Model(&models.ImpersonationLog{})
+	if filter.AdminID != 0 {
+		query = query.Where("admin_id = ?", filter.AdminID)
+	}
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (params.Page - 1) * params.Limit
+	err := query.Order("created_at DESC").Limit(params.Limit).Offset(offset).Find(&entries).Error
+	return entries, total, err
+}
+
+// GetSettings возвращает глобальные настройки безопасности.
+func (r *SecurityPostgres) GetSettings(ctx context.Context) (models.SecuritySettings, error) {
+	var settings models.SecuritySettings
+	err := r.db.WithContext(ctx).First(&settings, 1).Error
+	return settings, err
+}
+
+// UpdateSettings сохраняет глобальные настройки безопасности.
+func (r *SecurityPostgres) UpdateSettings(ctx context.Context, settings models.SecuritySettings) error {
+	return r.db.WithContext(ctx).Model(&models.SecuritySettings{}).Where("id = ?", 1).
+		Updates(map[string]interface{}{
+			"require_admin_2fa": settings.RequireAdmin2FA,
+			"updated_by":        settings.UpdatedBy,
+			"updated_at":        time.Now(),
+		}).Error
+}
@@ -27,3 +27,23 @@ func (s *ServicePostgres) GetServiceRecommendations(ctx context.Context, service
 	}
 	return service.Recommendations.String, nil
 }
+
+// GetPriceList возвращает строки прейскуранта: действующие услуги действующих отделений с диапазоном
+// цен у их действующих врачей. Услуга без врачей показывается по базовой цене.
+func (s *ServicePostgres) GetPriceList(ctx context.Context) ([]models.PriceListRow, error) {
+	var rows []models.PriceListRow
+	err := s.db.WithContext(ctx).Raw(`
+		SELECT dp.id AS department_id, dp.name AS department_name, sc.id AS category_id, sc.name AS category_name,
+			s.id AS service_id, s.code, s.name, s.description, s.duration_minutes,
+			COALESCE(MIN(COALESCE(ds.price, s.base_price)), s.base_price) AS price_from,
+			COALESCE(MAX(COALESCE(ds.price, s.base_price)), s.base_price) AS price_to
+		FROM medical_center.services s
+		JOIN medical_center.service_categories sc ON sc.id = s.category_id
+		JOIN medical_center.departments dp ON dp.id = sc.department_id AND dp.deleted_at IS NULL
+		LEFT JOIN medical_center.doctor_services ds ON ds.service_id = s.id
+			AND EXISTS (SELECT 1 FROM medical_center.doctors d WHERE d.id = ds.doctor_id AND d.deleted_at IS NULL)
+		WHERE s.deleted_at IS NULL
+		GROUP BY dp.id, sc.id, s.id
+		ORDER BY dp.name, dp.id, sc.name, sc.id, s.name, s.id`).Scan(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"lk/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// purgeBatchSize - сколько записей одной таблицы проверяется за один запрос при окончательном удалении.
+const purgeBatchSize = 500
+
+// withDeleted снимает фильтр мягкого удаления, например при предзагрузке связей для истории.
+func withDeleted(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
+// softDelete помечает запись удаленной.
+// Возвращает gorm.ErrRecordNotFound, если записи нет или она уже удалена.
+func softDelete[T any](ctx context.Context, db *gorm.DB, id any) error {
+	result := db.WithContext(ctx).Delete(new(T), id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// findDeleted возвращает пагинированный список мягко удаленных записей, последние удаленные сверху.
+func findDeleted[T any](ctx context.Context, db *gorm.DB, params models.PaginationParams) ([]T, int64, error) {
+	var items []T
+	var total int64
+	query := db.WithContext(ctx).Unscoped().Model(new(T)).Where("deleted_at IS NOT NULL")
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (params.Page - 1) * params.Limit
+	err := query.Order("deleted_at DESC").Limit(params.Limit).Offset(offset).Find(&items).Error
+	return items, total, err
+}
+
+// restoreDeleted снимает с записи отметку об удалении.
+// Возвращает gorm.ErrRecordNotFound, если удаленной записи с таким ID нет.
+func restoreDeleted[T any](ctx context.Context, db *gorm.DB, id any) error {
+	result := db.WithContext(ctx).Unscoped().Model(new(T)).
+		Where("id = ? AND deleted_at IS NOT NULL", id).Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// purgeDeleted окончательно удаляет записи таблицы, удаленные раньше before.
+// Записи, на которые еще ссылаются другие таблицы (например, медицинские записи), пропускаются
+// и остаются мягко удаленными.
+func purgeDeleted(ctx context.Context, db *gorm.DB, table string, before time.Time) (models.PurgeResult, error) {
+	result := models.PurgeResult{Table: table}
+	var lastID uint64
+	for {
+		var ids []uint64
+		err := db.WithContext(ctx).Table(table).
+			Where("deleted_at < ? AND id > ?", before, lastID).
+			Order("id").Limit(purgeBatchSize).Pluck("id", &ids).Error
+		if err != nil {
+			return result, err
+		}
+		if len(ids) == 0 {
+			return result, nil
+		}
+		lastID = ids[len(ids)-1]
+
+		for _, id := range ids {
+			err := db.WithContext(ctx).
+				Exec(fmt.Sprintf("DELETE FROM %s WHERE id = ? AND deleted_at < ?", table), id, before).Error
+			if err != nil {
+				if strings.Contains(err.Error(), "violates foreign key constraint") {
+					result.Kept++
+					continue
+				}
+				return result, err
+			}
+			result.Purged++
+		}
+	}
+}
+
+// saveExisting перезаписывает все поля существующей неудаленной записи.
+// В отличие от Save не вставляет запись, если ее нет, и не восстанавливает удаленную.
+func saveExisting[T any](ctx context.Context, db *gorm.DB, id any, value *T) error {
+	result := db.WithContext(ctx).Model(value).Where("id = ?", id).
+		Select("*").Omit("id", "deleted_at").Updates(value)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
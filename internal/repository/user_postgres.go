@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"lk/internal/models"
 
@@ -39,6 +40,35 @@ func (r *UserPostgres) CreateUserProfile(ctx context.Context, tx *gorm.DB, profi
 	return profile.ID, nil
 }
 
+// ReplacePendingUser перезаписывает пароль и профиль аккаунта, телефон которого еще не подтвержден.
+// Возвращает gorm.ErrRecordNotFound, если телефон успели подтвердить.
+// * Эта функция должна вызываться внутри транзакции.
+func (r *UserPostgres) ReplacePendingUser(ctx context.Context, tx *gorm.DB, user models.User,
+	profile models.UserProfile,
+) error {
+	result := tx.WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND phone_verified_at IS NULL", user.ID).
+		Update("password_hash", user.PasswordHash)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return tx.WithContext(ctx).Model(&models.UserProfile{}).Where("user_id = ?", user.ID).
+		Updates(map[string]interface{}{
+			"first_name":        profile.FirstName,
+			"last_name":         profile.LastName,
+			"patronymic":        profile.Patronymic,
+			"birth_date":        profile.BirthDate,
+			"gender":            profile.Gender,
+			"city_id":           profile.CityID,
+			"email":             nil,
+			"email_verified_at": nil,
+			"avatar_url":        nil,
+		}).Error
+}
+
 // GetUserByPhone находит пользователя по номеру телефона.
 func (r *UserPostgres) GetUserByPhone(ctx context.Context, phone string) (models.User, error) {
 	var user models.User
@@ -54,9 +84,12 @@ func (r *UserPostgres) GetUserByID(ctx context.Context, id uint64) (models.User,
 }
 
 // GetUserProfileByUserID находит профиль пользователя по ID пользователя.
+// Профиль удаленного пользователя не возвращается.
 func (r *UserPostgres) GetUserProfileByUserID(ctx context.Context, userID uint64) (models.UserProfile, error) {
 	var profile models.UserProfile
-	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&profile).Error
+	err := r.db.WithContext(ctx).
+		Joins("JOIN medical_center.users u ON u.id = user_profiles.user_id AND u.deleted_at IS NULL").
+		Where("user_profiles.user_id = ?", userID).First(&profile).Error
 	return profile, err
 }
 
@@ -65,7 +98,9 @@ func (r *UserPostgres) UpdateUserProfile(ctx context.Context, profile models.Use
 	updateData := make(map[string]interface{})
 
 	if profile.Email.Valid {
+		// Новый адрес требует повторного подтверждения.
 		updateData["email"] = profile.Email
+		updateData["email_verified_at"] = nil
 	}
 	if profile.CityID > 0 {
 		updateData["city_id"] = profile.CityID
@@ -113,3 +148,47 @@ func (r *UserPostgres) UpdatePassword(ctx context.Context, userID uint64, newPas
 	}
 	return nil
 }
+
+// MarkPhoneVerified отмечает номер телефона как подтвержденный и активирует аккаунт.
+func (r *UserPostgres) MarkPhoneVerified(ctx context.Context, userID uint64) error {
+	result := r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", userID).Updates(
+		map[string]interface{}{
+			"is_active":         true,
+			"phone_verified_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("user with id %d not found for phone verification", userID)
+	}
+	return nil
+}
+
+// GetUserProfileByEmail находит профиль пользователя по адресу электронной почты (без учета регистра).
+func (r *UserPostgres) GetUserProfileByEmail(ctx context.Context, email string) (models.UserProfile, error) {
+	var profile models.UserProfile
+	err := r.db.WithContext(ctx).Where("lower(email) = lower(?)", email).First(&profile).Error
+	return profile, err
+}
+
+// MarkEmailVerified отмечает адрес как подтвержденный, если он все еще указан в профиле.
+// Возвращает gorm.ErrRecordNotFound, если адрес в профиле уже изменился.
+func (r *UserPostgres) MarkEmailVerified(ctx context.Context, userID uint64, email string) error {
+	result := r.db.WithContext(ctx).Model(&models.UserProfile{}).
+		Where("user_id = ? AND lower(email) = lower(?)", userID, email).
+		Update("email_verified_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ResetEmailVerification снимает отметку о подтверждении адреса электронной почты.
+func (r *UserPostgres) ResetEmailVerification(ctx context.Context, userID uint64) error {
+	return r.db.WithContext(ctx).Model(&models.UserProfile{}).Where("user_id = ?", userID).
+		Update("email_verified_at", nil).Error
+}
@@ -0,0 +1,253 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"lk/internal/config"
+	"lk/internal/models"
+	"lk/internal/repository"
+	"lk/internal/storage"
+	"lk/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// accountExportsListLimit - сколько последних выгрузок показывается пациенту.
+	accountExportsListLimit = 20
+	// accountJobBatchSize - сколько удалений и истекших выгрузок обрабатывается за один проход.
+	accountJobBatchSize = 50
+	// exportStaleAfter - через сколько выгрузка, зависшая в processing, забирается повторно.
+	exportStaleAfter = time.Hour
+)
+
+// accountService реализует интерфейс AccountService.
+type accountService struct {
+	repo     repository.AccountRepository
+	userRepo repository.UserRepository
+	storage  storage.FileStorage
+	cfg      config.AccountConfig
+	audit    *auditor
+}
+
+// NewAccountService создает новый сервис выгрузки данных и удаления аккаунта.
+func NewAccountService(repo repository.AccountRepository, userRepo repository.UserRepository,
+	storage storage.FileStorage, cfg config.AccountConfig, audit *auditor,
+) AccountService {
+	return &accountService{repo: repo, userRepo: userRepo, storage: storage, cfg: cfg, audit: audit}
+}
+
+// RequestExport ставит в очередь выгрузку данных пациента. Архив собирается в фоне.
+func (s *accountService) RequestExport(ctx context.Context, userID uint64) (models.DataExport, error) {
+	export, err := s.repo.CreateExport(ctx, userID)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key value") {
+			return models.DataExport{}, NewConflictError("data export is already in progress", err)
+		}
+		return models.DataExport{}, NewInternalServerError("failed to create data export request", err)
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditAccountExportRequest, EntityType: auditEntityDataExport, EntityID: export.ID, TargetUserID: userID,
+	})
+	return export, nil
+}
+
+// GetExports возвращает последние выгрузки пациента.
+func (s *accountService) GetExports(ctx context.Context, userID uint64) ([]models.DataExport, error) {
+	exports, err := s.repo.GetExports(ctx, userID, accountExportsListLimit)
+	if err != nil {
+		return nil, NewInternalServerError("failed to get data exports", err)
+	}
+	return exports, nil
+}
+
+// DownloadExport открывает готовый архив выгрузки. Поток должен закрыть вызывающий.
+func (s *accountService) DownloadExport(ctx context.Context, userID, exportID uint64) (
+	io.ReadCloser, models.DataExport, error,
+) {
+	export, err := s.repo.GetExport(ctx, userID, exportID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.DataExport{}, NewNotFoundError("data export not found", err)
+		}
+		return nil, models.DataExport{}, NewInternalServerError("failed to get data export", err)
+	}
+	switch {
+	case export.Status == models.DataExportExpired,
+		export.Status == models.DataExportReady && export.ExpiresAt.Valid && export.ExpiresAt.Time.Before(time.Now()):
+		return nil, models.DataExport{}, NewNotFoundError("data export has expired", nil)
+	case export.Status != models.DataExportReady || !export.FileKey.Valid:
+		return nil, models.DataExport{}, NewConflictError("data export is not ready", nil)
+	}
+
+	file, err := s.storage.Download(ctx, export.FileKey.String)
+	if err != nil {
+		return nil, models.DataExport{}, NewInternalServerError("could not get data export from storage", err)
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditAccountExportDownload, EntityType: auditEntityDataExport, EntityID: export.ID, TargetUserID: userID,
+	})
+	return file, export, nil
+}
+
+// RequestDeletion создает запрос на удаление аккаунта. Удаление выполняется после
+// периода ожидания, в течение которого запрос можно отменить. Требует подтверждения паролем.
+func (s *accountService) RequestDeletion(ctx context.Context, userID uint64, password, reason string) (
+	models.AccountDeletion, error,
+) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return models.AccountDeletion{}, NewInternalServerError("failed to get user from db", err)
+	}
+	if err := utils.CheckPasswordHash(password, user.PasswordHash); err != nil {
+		return models.AccountDeletion{}, NewUnauthorizedError("password is incorrect", nil)
+	}
+
+	now := time.Now()
+	deletion, err := s.repo.CreateDeletion(ctx, models.AccountDeletion{
+		UserID:      userID,
+		Status:      models.AccountDeletionPending,
+		Reason:      sql.NullString{String: reason, Valid: reason != ""},
+		RequestedAt: now,
+		ScheduledAt: now.Add(s.cfg.DeletionGracePeriod),
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key value") {
+			return models.AccountDeletion{}, NewConflictError("account deletion is already requested", err)
+		}
+		return models.AccountDeletion{}, NewInternalServerError("failed to create account deletion request", err)
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditAccountDeletionRequest, EntityType: auditEntityUserDeletion, EntityID: deletion.ID,
+		TargetUserID: userID, After: deletion,
+	})
+	return deletion, nil
+}
+
+// GetDeletion возвращает ожидающий запрос пациента на удаление аккаунта.
+func (s *accountService) GetDeletion(ctx context.Context, userID uint64) (models.AccountDeletion, error) {
+	deletion, err := s.repo.GetPendingDeletion(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.AccountDeletion{}, NewNotFoundError("no pending account deletion request", err)
+		}
+		return models.AccountDeletion{}, NewInternalServerError("failed to get account deletion request", err)
+	}
+	return deletion, nil
+}
+
+// CancelDeletion отменяет ожидающий запрос на удаление аккаунта.
+func (s *accountService) CancelDeletion(ctx context.Context, userID uint64) error {
+	cancelled, err := s.repo.CancelDeletion(ctx, userID)
+	if err != nil {
+		return NewInternalServerError("failed to cancel account deletion request", err)
+	}
+	if cancelled == 0 {
+		return NewNotFoundError("no pending account deletion request", nil)
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditAccountDeletionCancel, EntityType: auditEntityUserDeletion, TargetUserID: userID,
+	})
+	return nil
+}
+
+// RunJobs периодически собирает выгрузки из очереди, выполняет запросы на удаление
+// с истекшим периодом ожидания и удаляет просроченные архивы. Блокируется до отмены ctx.
+func (s *accountService) RunJobs(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		s.processExports(ctx)
+		s.processDeletions(ctx)
+		s.cleanupExpiredExports(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// processExports собирает все выгрузки, стоящие в очереди.
+func (s *accountService) processExports(ctx context.Context) {
+	for ctx.Err() == nil {
+		export, err := s.repo.ClaimExport(ctx, time.Now().Add(-exportStaleAfter))
+		if err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Printf("ERROR: failed to claim data export: %v", err)
+			}
+			return
+		}
+		if err := s.buildExport(ctx, export); err != nil {
+			log.Printf("ERROR: data export %d for user %d failed: %v", export.ID, export.UserID, err)
+			if err := s.repo.FailExport(context.WithoutCancel(ctx), export.ID, err.Error()); err != nil {
+				log.Printf("ERROR: failed to mark data export %d as failed: %v", export.ID, err)
+			}
+		}
+	}
+}
+
+// processDeletions обезличивает аккаунты, период ожидания удаления которых истек,
+// и удаляет их файлы из хранилища. Файлы удаляются только после фиксации транзакции.
+func (s *accountService) processDeletions(ctx context.Context) {
+	deletions, err := s.repo.GetDueDeletions(ctx, time.Now(), accountJobBatchSize)
+	if err != nil {
+		log.Printf("ERROR: failed to get due account deletions: %v", err)
+		return
+	}
+	for _, deletion := range deletions {
+		fileKeys, err := s.repo.AnonymizeUser(ctx, deletion.ID, deletion.UserID)
+		if err != nil {
+			// Запрос успели отменить или выполнил другой экземпляр
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Printf("ERROR: failed to anonymize user %d: %v", deletion.UserID, err)
+			}
+			continue
+		}
+		s.audit.Record(ctx, auditEvent{
+			Action: AuditAccountAnonymize, EntityType: auditEntityUser, EntityID: deletion.UserID,
+			TargetUserID: deletion.UserID,
+		})
+		for _, key := range fileKeys {
+			for _, objectKey := range expandAvatarKey(key) {
+				s.deleteStoredFile(ctx, objectKey)
+			}
+		}
+	}
+}
+
+// cleanupExpiredExports удаляет из хранилища архивы с истекшим сроком хранения.
+func (s *accountService) cleanupExpiredExports(ctx context.Context) {
+	exports, err := s.repo.GetExpiredExports(ctx, time.Now(), accountJobBatchSize)
+	if err != nil {
+		log.Printf("ERROR: failed to get expired data exports: %v", err)
+		return
+	}
+	for _, export := range exports {
+		if export.FileKey.Valid {
+			if err := s.storage.Delete(ctx, export.FileKey.String); err != nil {
+				// Повторим на следующем проходе
+				log.Printf("WARN: failed to delete expired data export %d: %v", export.ID, err)
+				continue
+			}
+		}
+		if err := s.repo.MarkExportExpired(ctx, export.ID); err != nil {
+			log.Printf("ERROR: failed to mark data export %d as expired: %v", export.ID, err)
+		}
+	}
+}
+
+// deleteStoredFile удаляет файл из хранилища. Ошибка только логируется:
+// осиротевший объект не должен мешать удалению аккаунта.
+func (s *accountService) deleteStoredFile(ctx context.Context, objectKey string) {
+	if err := s.storage.Delete(ctx, objectKey); err != nil {
+		log.Printf("WARN: failed to delete file %s from storage: %v", objectKey, err)
+	}
+}
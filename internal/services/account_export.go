@@ -0,0 +1,234 @@
+package services
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"lk/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// dataExportFormatVersion - версия структуры архива выгрузки; увеличивается при несовместимых изменениях.
+const dataExportFormatVersion = 1
+
+// buildExport собирает архив выгрузки во временный файл, загружает его в хранилище
+// и отмечает выгрузку готовой.
+func (s *accountService) buildExport(ctx context.Context, export models.DataExport) error {
+	tmp, err := os.CreateTemp("", "data-export-*.zip")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if err := s.writeExportArchive(ctx, tmp, export.UserID); err != nil {
+		return err
+	}
+	size, err := tmp.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("get archive size: %w", err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("rewind archive: %w", err)
+	}
+
+	objectKey := fmt.Sprintf("exports/%d/%s.zip", export.UserID, uuid.New().String())
+	if err := s.storage.Upload(ctx, tmp, size, "application/zip", objectKey); err != nil {
+		return fmt.Errorf("upload archive: %w", err)
+	}
+	err = s.repo.CompleteExport(ctx, export.ID, objectKey, size, time.Now().Add(s.cfg.ExportTTL))
+	if err != nil {
+		s.deleteStoredFile(ctx, objectKey)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Аккаунт удалили, пока собирался архив
+			return nil
+		}
+		return fmt.Errorf("complete export: %w", err)
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditAccountExportReady, EntityType: auditEntityDataExport, EntityID: export.ID,
+		TargetUserID: export.UserID,
+	})
+	return nil
+}
+
+// writeExportArchive записывает в w ZIP-архив с данными пациента: JSON-файлы по разделам,
+// аватар и файлы результатов анализов. Оглавление (manifest.json) пишется последним.
+func (s *accountService) writeExportArchive(ctx context.Context, w io.Writer, userID uint64) error {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get user: %w", err)
+	}
+	profile, err := s.userRepo.GetUserProfileByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get profile: %w", err)
+	}
+	appointments, err := s.repo.GetExportAppointments(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get appointments: %w", err)
+	}
+	analyses, err := s.repo.GetExportAnalyses(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get analyses: %w", err)
+	}
+	prescriptions, err := s.repo.GetPrescriptions(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get prescriptions: %w", err)
+	}
+	consents, err := s.repo.GetConsents(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get consents: %w", err)
+	}
+
+	archive := &exportArchive{zip: zip.NewWriter(w), manifest: models.DataExportManifest{
+		FormatVersion: dataExportFormatVersion,
+		GeneratedAt:   time.Now(),
+		UserID:        userID,
+	}}
+
+	profileData := map[string]any{"account": user, "profile": profile}
+	if err := archive.writeJSON("profile.json", "Учетная запись и профиль", 1, profileData); err != nil {
+		return err
+	}
+	if profile.AvatarURL.Valid && profile.AvatarURL.String != "" {
+		avatarPath := "profile/avatar" + path.Ext(profile.AvatarURL.String)
+		if err := s.copyStoredFile(ctx, archive, avatarPath, "Аватар", profile.AvatarURL.String); err != nil {
+			return err
+		}
+	}
+
+	for i := range appointments {
+		if appointments[i].ResultFileURL == "" {
+			continue
+		}
+		// Исходное имя файла результата приема не хранится, используется имя объекта в хранилище
+		filePath := fmt.Sprintf("appointments/%d_%s", appointments[i].ID,
+			exportFileName(appointments[i].ResultFileURL, "result"))
+		description := fmt.Sprintf("Результат приема «%s» от %s", appointments[i].ServiceName,
+			appointments[i].AppointmentDate.Format("02.01.2006"))
+		if err := s.copyStoredFile(ctx, archive, filePath, description, appointments[i].ResultFileURL); err != nil {
+			return err
+		}
+		appointments[i].File = filePath
+	}
+
+	for i := range analyses {
+		if analyses[i].ResultFileURL == "" {
+			continue
+		}
+		filePath := fmt.Sprintf("analyses/%d_%s", analyses[i].ID, exportFileName(analyses[i].FileName, "result"))
+		description := fmt.Sprintf("Результат анализа «%s»", analyses[i].Name)
+		if err := s.copyStoredFile(ctx, archive, filePath, description, analyses[i].ResultFileURL); err != nil {
+			return err
+		}
+		analyses[i].File = filePath
+	}
+
+	sections := []struct {
+		path, description string
+		records           int
+		data              any
+	}{
+		{"appointments.json", "Записи на прием", len(appointments), appointments},
+		{"analyses.json", "Лабораторные анализы", len(analyses), analyses},
+		{"prescriptions.json", "Назначения", len(prescriptions), prescriptions},
+		{"consents.json", "История принятия юридических документов", len(consents), consents},
+	}
+	for _, section := range sections {
+		if err := archive.writeJSON(section.path, section.description, section.records, section.data); err != nil {
+			return err
+		}
+	}
+
+	if err := archive.writeManifest(); err != nil {
+		return err
+	}
+	return archive.zip.Close()
+}
+
+// copyStoredFile копирует файл из хранилища в архив. Если файла в хранилище нет,
+// он отмечается в оглавлении как отсутствующий, а выгрузка продолжается.
+func (s *accountService) copyStoredFile(ctx context.Context, archive *exportArchive, filePath, description,
+	objectKey string,
+) error {
+	entry := models.DataExportFile{Path: filePath, Description: description}
+
+	object, err := s.storage.Download(ctx, objectKey)
+	if err == nil {
+		defer object.Close()
+		// Ошибка хранилища проявляется только при первом чтении; проверяем до создания записи в архиве
+		reader := bufio.NewReader(object)
+		if _, err = reader.Peek(1); err == nil || errors.Is(err, io.EOF) {
+			file, err := archive.zip.Create(filePath)
+			if err != nil {
+				return fmt.Errorf("create %s: %w", filePath, err)
+			}
+			if _, err := io.Copy(file, reader); err != nil {
+				return fmt.Errorf("copy %s: %w", objectKey, err)
+			}
+			archive.manifest.Files = append(archive.manifest.Files, entry)
+			return nil
+		}
+	}
+
+	log.Printf("WARN: file %s is missing in storage, skipped in data export: %v", objectKey, err)
+	entry.Missing = true
+	archive.manifest.Files = append(archive.manifest.Files, entry)
+	return nil
+}
+
+// exportArchive - ZIP-архив выгрузки и его оглавление.
+type exportArchive struct {
+	zip      *zip.Writer
+	manifest models.DataExportManifest
+}
+
+// writeJSON добавляет в архив JSON-файл и описывает его в оглавлении.
+func (a *exportArchive) writeJSON(filePath, description string, records int, data any) error {
+	file, err := a.zip.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", filePath, err)
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(data); err != nil {
+		return fmt.Errorf("encode %s: %w", filePath, err)
+	}
+	a.manifest.Files = append(a.manifest.Files, models.DataExportFile{
+		Path: filePath, Description: description, Records: records,
+	})
+	return nil
+}
+
+// writeManifest добавляет в архив оглавление.
+func (a *exportArchive) writeManifest() error {
+	file, err := a.zip.Create("manifest.json")
+	if err != nil {
+		return fmt.Errorf("create manifest: %w", err)
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(a.manifest)
+}
+
+// exportFileName оставляет от исходного имени файла только базовое имя,
+// чтобы оно не могло выйти за пределы каталога в архиве.
+func exportFileName(name, fallback string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" || name == ".." {
+		return fallback
+	}
+	return name
+}
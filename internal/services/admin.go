@@ -2,105 +2,424 @@ package services
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"log"
 	"strings"
 	"time"
 
+	"lk/internal/config"
+	"lk/internal/mail"
 	"lk/internal/models"
 	"lk/internal/repository"
+	"lk/internal/storage"
 	"lk/internal/utils"
 
-	"github.com/golang-jwt/jwt/v5"
 	"gorm.io/gorm"
 )
 
+// temporaryPasswordLength - длина временного пароля, выдаваемого при создании или сбросе.
+const temporaryPasswordLength = 16
+
 // adminService реализует интерфейс AdminService.
 type adminService struct {
-	repos      *repository.Repository
-	signingKey string
-	tokenTTL   time.Duration
+	repos        *repository.Repository
+	guard        *bruteForceGuard
+	keys         *utils.KeySet
+	tokenScope   utils.TokenScope
+	patientScope utils.TokenScope
+	storage      storage.FileStorage
+	retention    config.RetentionConfig
+	certificates config.CertificateConfig
+	settings     *clinicSettingsStore
+	audit        *auditor
+	totpIssuer   string
+	mailer       mail.Sender
 }
 
 // NewAdminService создает новый сервис для администрирования.
-func NewAdminService(repos *repository.Repository, signingKey string, tokenTTL time.Duration) AdminService {
+// patientScope нужен для выпуска пациентских токенов имперсонации, totpIssuer - название
+// сервиса в приложении-аутентификаторе, mailer - отправка служебных уведомлений администраторам.
+func NewAdminService(repos *repository.Repository, guard *bruteForceGuard, keys *utils.KeySet,
+	tokenScope, patientScope utils.TokenScope, storage storage.FileStorage, retention config.RetentionConfig,
+	certificates config.CertificateConfig, settings *clinicSettingsStore, audit *auditor, totpIssuer string,
+	mailer mail.Sender,
+) AdminService {
 	return &adminService{
-		repos:      repos,
-		signingKey: signingKey,
-		tokenTTL:   tokenTTL,
+		repos:        repos,
+		guard:        guard,
+		keys:         keys,
+		tokenScope:   tokenScope,
+		patientScope: patientScope,
+		storage:      storage,
+		retention:    retention,
+		certificates: certificates,
+		settings:     settings,
+		audit:        audit,
+		totpIssuer:   totpIssuer,
+		mailer:       mailer,
 	}
 }
 
 // --- Auth & Dashboard ---
 
-// Login аутентифицирует администратора и возвращает access токен.
-func (s *adminService) Login(ctx context.Context, login, password string) (map[string]string, error) {
+// Login аутентифицирует администратора, открывает новую сессию и возвращает пару токенов.
+// Если у администратора включена 2FA, вместо access токена возвращается
+// промежуточный mfaToken, который нужно подтвердить кодом в LoginTwoFactor.
+func (s *adminService) Login(ctx context.Context, login, password, clientIP, userAgent string) (
+	map[string]string, error,
+) {
+	if err := s.guard.Check(ctx, models.LockoutScopeAdminLogin, login, clientIP); err != nil {
+		return nil, err
+	}
+
 	admin, err := s.repos.Admin.GetByLogin(ctx, login)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.guard.RegisterFailure(ctx, models.LockoutScopeAdminLogin, login, clientIP)
+			s.recordLoginFailure(ctx, models.Admin{Login: login})
 			return nil, NewUnauthorizedError("invalid login or password", nil)
 		}
 		return nil, NewInternalServerError("database error while getting admin", err)
 	}
 
 	if err := utils.CheckPasswordHash(password, admin.PasswordHash); err != nil {
+		s.guard.RegisterFailure(ctx, models.LockoutScopeAdminLogin, login, clientIP)
+		s.recordLoginFailure(ctx, admin)
 		return nil, NewUnauthorizedError("invalid login or password", nil)
 	}
+	s.guard.Reset(ctx, models.LockoutScopeAdminLogin, login)
+
+	if !admin.IsActive {
+		return nil, NewForbiddenError("admin account is disabled", nil)
+	}
+	if admin.TOTPEnabledAt.Valid {
+		return s.issueMFAToken(ctx, admin.ID)
+	}
+	return s.issueAdminToken(ctx, admin, clientIP, userAgent)
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"exp":    time.Now().Add(s.tokenTTL).Unix(),
-		"iat":    time.Now().Unix(),
-		"sub":    admin.ID,
-		"role":   admin.Role,
-		"is_adm": true,
+// recordLoginFailure записывает в журнал аудита неудачную попытку входа в админ-панель.
+// Для несуществующего логина ID действующего лица не заполняется.
+func (s *adminService) recordLoginFailure(ctx context.Context, admin models.Admin) {
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditLoginFailed, EntityType: auditEntityAdmin, EntityID: optionalEntityID(admin.ID),
+		Actor: &AuditActor{Type: models.AuditActorAnonymous, ID: admin.ID, Name: admin.Login},
 	})
+}
 
-	accessToken, err := token.SignedString([]byte(s.signingKey))
+// GetDashboardStats получает статистику для дашборда.
+func (s *adminService) GetDashboardStats(ctx context.Context) (models.AdminDashboardStats, error) {
+	stats, err := s.repos.Admin.GetDashboardStats(ctx)
 	if err != nil {
-		return nil, NewInternalServerError("failed to generate admin token", err)
+		return models.AdminDashboardStats{}, NewInternalServerError("failed to get dashboard stats", err)
+	}
+	stats.ExpiringCertificates, err = s.repos.Admin.CountExpiringCertificates(ctx,
+		time.Now().Add(s.certificates.AlertBefore))
+	if err != nil {
+		return models.AdminDashboardStats{}, NewInternalServerError("failed to count expiring certificates", err)
+	}
+	return stats, nil
+}
+
+// --- Admins & Roles ---
+
+// GetRoles возвращает список ролей с их правами.
+func (s *adminService) GetRoles() []models.AdminRoleInfo {
+	roles := []models.AdminRole{
+		models.RoleSuperAdmin, models.RoleAdmin, models.RoleReceptionist, models.RoleLabTechnician,
 	}
+	result := make([]models.AdminRoleInfo, 0, len(roles))
+	for _, role := range roles {
+		result = append(result, models.AdminRoleInfo{Role: role, Permissions: role.Permissions()})
+	}
+	return result
+}
 
-	return map[string]string{"accessToken": accessToken}, nil
+// GetAllAdmins возвращает список администраторов.
+func (s *adminService) GetAllAdmins(ctx context.Context, params models.PaginationParams) (
+	[]models.Admin, int64, error,
+) {
+	admins, total, err := s.repos.Admin.GetAllAdmins(ctx, params)
+	if err != nil {
+		return nil, 0, NewInternalServerError("failed to get admins", err)
+	}
+	return admins, total, nil
 }
 
-// ParseAdminToken проверяет токен админа и возвращает его ID.
-func (s *adminService) ParseAdminToken(accessToken string) (uint64, error) {
-	token, err := jwt.ParseWithClaims(accessToken, &jwt.MapClaims{},
-		func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return []byte(s.signingKey), nil
-		})
+// ChangeAdminRole назначает администратору новую роль.
+// Нельзя менять собственную роль и лишать систему последнего суперадминистратора.
+func (s *adminService) ChangeAdminRole(ctx context.Context, actor models.Admin, adminID uint64,
+	role models.AdminRole,
+) error {
+	if !role.IsValid() {
+		return NewBadRequestError("unknown role", nil)
+	}
+	if actor.ID == adminID {
+		return NewConflictError("cannot change your own role", nil)
+	}
+
+	target, err := s.repos.Admin.GetByID(ctx, adminID)
 	if err != nil {
-		return 0, NewUnauthorizedError("invalid admin token", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewNotFoundError("admin not found", err)
+		}
+		return NewInternalServerError("failed to get admin", err)
+	}
+	if target.Role == role {
+		return nil
 	}
+	if target.Role == models.RoleSuperAdmin {
+		count, err := s.repos.Admin.CountAdminsByRole(ctx, models.RoleSuperAdmin)
+		if err != nil {
+			return NewInternalServerError("failed to count superadmins", err)
+		}
+		if count <= 1 {
+			return NewConflictError("cannot demote the last superadmin", nil)
+		}
+	}
+
+	if err := s.repos.Admin.UpdateAdminRole(ctx, adminID, role); err != nil {
+		return NewInternalServerError("failed to update admin role", err)
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditAdminRoleChange, EntityType: auditEntityAdmin, EntityID: adminID,
+		Before: map[string]any{"role": target.Role}, After: map[string]any{"role": role},
+	})
+	return nil
+}
 
-	claims, ok := token.Claims.(*jwt.MapClaims)
-	if !ok || !token.Valid {
-		return 0, NewUnauthorizedError("invalid admin token claims", nil)
+// GetAdminByID возвращает учетную запись администратора.
+func (s *adminService) GetAdminByID(ctx context.Context, adminID uint64) (models.Admin, error) {
+	admin, err := s.repos.Admin.GetByID(ctx, adminID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Admin{}, NewNotFoundError("admin not found", err)
+		}
+		return models.Admin{}, NewInternalServerError("failed to get admin", err)
 	}
+	return admin, nil
+}
 
-	isAdmin, ok := (*claims)["is_adm"].(bool)
-	if !ok || !isAdmin {
-		return 0, NewUnauthorizedError("token is not an admin token", nil)
+// CreateAdmin создает учетную запись администратора. Если пароль не задан, генерируется
+// временный. В любом случае при первом входе администратор обязан сменить пароль.
+// Возвращает созданную запись и пароль, который нужно передать администратору.
+func (s *adminService) CreateAdmin(ctx context.Context, input CreateAdminInput) (models.Admin, string, error) {
+	email, err := adminEmail(input.Email)
+	if err != nil {
+		return models.Admin{}, "", err
+	}
+	password := input.Password
+	if password == "" {
+		password, err = utils.GenerateTemporaryPassword(temporaryPasswordLength)
+		if err != nil {
+			return models.Admin{}, "", NewInternalServerError("failed to generate temporary password", err)
+		}
+	}
+	passwordHash, err := utils.HashPassword(password)
+	if err != nil {
+		return models.Admin{}, "", NewInternalServerError("failed to hash password", err)
 	}
 
-	subFloat, ok := (*claims)["sub"].(float64)
-	if !ok {
-		return 0, NewUnauthorizedError("invalid subject claim in admin token", nil)
+	admin := models.Admin{
+		Login:              strings.TrimSpace(input.Login),
+		PasswordHash:       passwordHash,
+		FullName:           strings.TrimSpace(input.FullName),
+		Email:              email,
+		Role:               input.Role,
+		IsActive:           true,
+		MustChangePassword: true,
+	}
+	admin.ID, err = s.repos.Admin.CreateAdmin(ctx, admin)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key value") {
+			return models.Admin{}, "", NewConflictError("admin with this login already exists", err)
+		}
+		return models.Admin{}, "", NewInternalServerError("failed to create admin", err)
 	}
 
-	return uint64(subFloat), nil
+	created, err := s.GetAdminByID(ctx, admin.ID)
+	if err != nil {
+		return models.Admin{}, "", err
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditAdminCreate, EntityType: auditEntityAdmin, EntityID: created.ID, After: created,
+	})
+	return created, password, nil
 }
 
-// GetDashboardStats получает статистику для дашборда.
-func (s *adminService) GetDashboardStats(ctx context.Context) (models.AdminDashboardStats, error) {
-	stats, err := s.repos.Admin.GetDashboardStats(ctx)
+// UpdateAdmin изменяет логин, ФИО и адрес электронной почты администратора.
+func (s *adminService) UpdateAdmin(ctx context.Context, adminID uint64, input UpdateAdminInput) (models.Admin, error) {
+	admin, err := s.GetAdminByID(ctx, adminID)
 	if err != nil {
-		return models.AdminDashboardStats{}, NewInternalServerError("failed to get dashboard stats", err)
+		return models.Admin{}, err
 	}
-	return stats, nil
+	before := admin
+	if input.Login != nil {
+		admin.Login = strings.TrimSpace(*input.Login)
+	}
+	if input.FullName != nil {
+		admin.FullName = strings.TrimSpace(*input.FullName)
+	}
+	if input.Email != nil {
+		if admin.Email, err = adminEmail(*input.Email); err != nil {
+			return models.Admin{}, err
+		}
+	}
+
+	if err := s.repos.Admin.UpdateAdmin(ctx, admin); err != nil {
+		if strings.Contains(err.Error(), "duplicate key value") {
+			return models.Admin{}, NewConflictError("admin with this login already exists", err)
+		}
+		return models.Admin{}, NewInternalServerError("failed to update admin", err)
+	}
+	updated, err := s.GetAdminByID(ctx, adminID)
+	if err != nil {
+		return models.Admin{}, err
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditAdminUpdate, EntityType: auditEntityAdmin, EntityID: adminID, Before: before, After: updated,
+	})
+	return updated, nil
+}
+
+// adminEmail нормализует адрес администратора; пустой адрес хранится как NULL.
+func adminEmail(email string) (sql.NullString, error) {
+	if strings.TrimSpace(email) == "" {
+		return sql.NullString{}, nil
+	}
+	normalized, err := normalizeEmail(email)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: normalized, Valid: true}, nil
+}
+
+// SetAdminActive активирует или деактивирует администратора. Деактивация действует сразу:
+// middleware проверяет состояние учетной записи при каждом запросе.
+func (s *adminService) SetAdminActive(ctx context.Context, actor models.Admin, adminID uint64, active bool) error {
+	if actor.ID == adminID && !active {
+		return NewConflictError("cannot deactivate your own account", nil)
+	}
+
+	target, err := s.GetAdminByID(ctx, adminID)
+	if err != nil {
+		return err
+	}
+	if target.IsActive == active {
+		return nil
+	}
+	if !active && target.Role == models.RoleSuperAdmin {
+		count, err := s.repos.Admin.CountAdminsByRole(ctx, models.RoleSuperAdmin)
+		if err != nil {
+			return NewInternalServerError("failed to count superadmins", err)
+		}
+		if count <= 1 {
+			return NewConflictError("cannot deactivate the last superadmin", nil)
+		}
+	}
+
+	if err := s.repos.Admin.SetAdminActive(ctx, adminID, active); err != nil {
+		return NewInternalServerError("failed to change admin state", err)
+	}
+	action := AuditAdminActivate
+	if !active {
+		action = AuditAdminDeactivate
+		s.revokeAllSessions(ctx, adminID)
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: action, EntityType: auditEntityAdmin, EntityID: adminID,
+		Before: map[string]any{"isActive": target.IsActive}, After: map[string]any{"isActive": active},
+	})
+	return nil
+}
+
+// ResetAdminPassword устанавливает администратору временный пароль,
+// который нужно сменить при следующем входе.
+func (s *adminService) ResetAdminPassword(ctx context.Context, adminID uint64) (string, error) {
+	if _, err := s.GetAdminByID(ctx, adminID); err != nil {
+		return "", err
+	}
+
+	password, err := utils.GenerateTemporaryPassword(temporaryPasswordLength)
+	if err != nil {
+		return "", NewInternalServerError("failed to generate temporary password", err)
+	}
+	passwordHash, err := utils.HashPassword(password)
+	if err != nil {
+		return "", NewInternalServerError("failed to hash password", err)
+	}
+	if err := s.repos.Admin.UpdateAdminPassword(ctx, adminID, passwordHash, true); err != nil {
+		return "", NewInternalServerError("failed to reset admin password", err)
+	}
+	s.revokeAllSessions(ctx, adminID)
+	s.audit.Record(ctx, auditEvent{Action: AuditAdminPasswordReset, EntityType: auditEntityAdmin, EntityID: adminID})
+	return password, nil
+}
+
+// ResetAdminTwoFactor отключает 2FA администратора, потерявшего доступ к приложению
+// и резервным кодам. При обязательной 2FA ему придется подключить ее заново.
+func (s *adminService) ResetAdminTwoFactor(ctx context.Context, adminID uint64) error {
+	if _, err := s.GetAdminByID(ctx, adminID); err != nil {
+		return err
+	}
+	if err := s.repos.Admin.DisableTOTP(ctx, adminID); err != nil {
+		return NewInternalServerError("failed to reset two-factor authentication", err)
+	}
+	s.revokeAllSessions(ctx, adminID)
+	s.audit.Record(ctx, auditEvent{Action: AuditAdminTwoFactorReset, EntityType: auditEntityAdmin, EntityID: adminID})
+	return nil
+}
+
+// ChangeOwnPassword меняет пароль текущего администратора, снимает требование смены пароля
+// и завершает все его сессии, кроме текущей.
+func (s *adminService) ChangeOwnPassword(ctx context.Context, admin models.Admin, sessionID uint64,
+	input ChangeOwnPasswordInput,
+) error {
+	if err := utils.CheckPasswordHash(input.CurrentPassword, admin.PasswordHash); err != nil {
+		return NewUnauthorizedError("current password is incorrect", nil)
+	}
+	if input.CurrentPassword == input.NewPassword {
+		return NewBadRequestError("new password must differ from the current one", nil)
+	}
+
+	passwordHash, err := utils.HashPassword(input.NewPassword)
+	if err != nil {
+		return NewInternalServerError("failed to hash password", err)
+	}
+	if err := s.repos.Admin.UpdateAdminPassword(ctx, admin.ID, passwordHash, false); err != nil {
+		return NewInternalServerError("failed to update password", err)
+	}
+	if _, err := s.repos.AdminSession.DeleteAll(ctx, admin.ID, sessionID); err != nil {
+		log.Printf("WARN: failed to revoke other sessions of admin %d: %v", admin.ID, err)
+	}
+	s.audit.Record(ctx, auditEvent{Action: AuditPasswordChange, EntityType: auditEntityAdmin, EntityID: admin.ID})
+	return nil
+}
+
+// --- Security ---
+
+// GetLockoutEvents возвращает журнал блокировок после неудачных попыток входа.
+func (s *adminService) GetLockoutEvents(ctx context.Context, params models.PaginationParams) (
+	[]models.LockoutEvent, int64, error,
+) {
+	events, total, err := s.repos.Security.GetLockoutEvents(ctx, params)
+	if err != nil {
+		return nil, 0, NewInternalServerError("failed to get lockout events", err)
+	}
+	return events, total, nil
+}
+
+// Unlock досрочно снимает блокировку с телефона, логина или IP-адреса.
+func (s *adminService) Unlock(ctx context.Context, input UnlockInput) error {
+	if err := s.guard.Unlock(ctx, input.Scope, input.Identifier); err != nil {
+		return NewInternalServerError("failed to remove lockout", err)
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditSecurityUnlock, EntityType: auditEntityLockout, EntityID: input.Scope + ":" + input.Identifier,
+	})
+	return nil
 }
 
 // --- User (Пациент) ---
@@ -135,9 +454,14 @@ func (s *adminService) UpdateUser(ctx context.Context, userID uint64, input Upda
 	if err != nil {
 		return err // Ошибка уже обернута в GetUserByID
 	}
+	before := auditUserState(*user, *profile)
 
 	if input.Phone != nil {
-		user.Phone = *input.Phone
+		phone, err := normalizePhone(*input.Phone)
+		if err != nil {
+			return err
+		}
+		user.Phone = phone
 	}
 	if input.IsActive != nil {
 		user.IsActive = *input.IsActive
@@ -148,8 +472,14 @@ func (s *adminService) UpdateUser(ctx context.Context, userID uint64, input Upda
 	if input.LastName != nil {
 		profile.LastName = *input.LastName
 	}
+	emailChanged := false
 	if input.Email != nil {
-		profile.Email.String = *input.Email
+		email, err := normalizeEmail(*input.Email)
+		if err != nil {
+			return err
+		}
+		emailChanged = !strings.EqualFold(profile.Email.String, email)
+		profile.Email.String = email
 		profile.Email.Valid = true
 	} else {
 		profile.Email.Valid = false
@@ -162,26 +492,62 @@ func (s *adminService) UpdateUser(ctx context.Context, userID uint64, input Upda
 		}
 		return NewInternalServerError("failed to update user in db", err)
 	}
+	if emailChanged {
+		if err := s.repos.User.ResetEmailVerification(ctx, userID); err != nil {
+			return NewInternalServerError("failed to reset email verification", err)
+		}
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditUserUpdate, EntityType: auditEntityUser, EntityID: userID, TargetUserID: userID,
+		Before: before, After: auditUserState(*user, *profile),
+	})
 	return nil
 }
 
 func (s *adminService) DeleteUser(ctx context.Context, userID uint64) error {
+	user, profile, err := s.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
 	if err := s.repos.Admin.DeleteUser(ctx, userID); err != nil {
 		return NewInternalServerError("failed to delete user", err)
 	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditUserDelete, EntityType: auditEntityUser, EntityID: userID, TargetUserID: userID,
+		Before: auditUserState(*user, *profile),
+	})
 	return nil
 }
 
+// auditUserState объединяет учетную запись и профиль пациента для журнала аудита.
+func auditUserState(user models.User, profile models.UserProfile) map[string]any {
+	return map[string]any{"user": user, "profile": profile}
+}
+
 func (s *adminService) GetUserAppointments(ctx context.Context, userID uint64, params models.PaginationParams) (
 	[]models.Appointment, int64, error,
 ) {
-	return s.repos.Admin.GetUserAppointments(ctx, userID, params)
+	appointments, total, err := s.repos.Admin.GetUserAppointments(ctx, userID, params)
+	if err != nil {
+		return nil, 0, err
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditMedicalDataRead, EntityType: auditEntityMedicalCard, EntityID: "appointments", TargetUserID: userID,
+	})
+	return appointments, total, nil
 }
 
 func (s *adminService) GetUserAnalyses(ctx context.Context, userID uint64, params models.PaginationParams) (
 	[]models.LabAnalysis, int64, error,
 ) {
-	return s.repos.Admin.GetUserAnalyses(ctx, userID, params)
+	analyses, total, err := s.repos.Admin.GetUserAnalyses(ctx, userID, params)
+	if err != nil {
+		return nil, 0, err
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditMedicalDataRead, EntityType: auditEntityMedicalCard, EntityID: "analyses", TargetUserID: userID,
+	})
+	return analyses, total, nil
 }
 
 // --- Doctor (Специалист aka врач) ---
@@ -198,6 +564,7 @@ func (s *adminService) CreateSpecialist(ctx context.Context, input CreateDoctorI
 		LastName:        input.LastName,
 		SpecialtyID:     input.SpecialtyID,
 		ExperienceYears: input.ExperienceYears,
+		AcceptsDMS:      input.AcceptsDMS,
 	}
 	if input.Patronymic != nil {
 		doctor.Patronymic.String, doctor.Patronymic.Valid = *input.Patronymic, true
@@ -205,16 +572,28 @@ func (s *adminService) CreateSpecialist(ctx context.Context, input CreateDoctorI
 	if input.Recommendations != nil {
 		doctor.Recommendations.String, doctor.Recommendations.Valid = *input.Recommendations, true
 	}
+	if input.Gender != nil {
+		doctor.Gender.String, doctor.Gender.Valid = *input.Gender, true
+	}
 
 	id, err := s.repos.Admin.CreateDoctor(ctx, doctor)
 	if err != nil {
 		return 0, NewInternalServerError("failed to create specialist", err)
 	}
+	doctor.ID = id
+	s.audit.Record(ctx, auditEvent{Action: AuditDoctorCreate, EntityType: auditEntityDoctor, EntityID: id, After: doctor})
 	return id, nil
 }
 
-func (s *adminService) GetSpecialistByID(ctx context.Context, doctorID uint64) (models.Doctor, error) {
-	return s.repos.Doctor.GetDoctorByID(ctx, doctorID)
+func (s *adminService) GetSpecialistByID(ctx context.Context, doctorID uint64) (models.DoctorProfile, error) {
+	profile, err := s.repos.Doctor.GetDoctorProfile(ctx, doctorID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.DoctorProfile{}, NewNotFoundError("specialist not found", err)
+		}
+		return models.DoctorProfile{}, NewInternalServerError("failed to get specialist", err)
+	}
+	return profile, nil
 }
 
 func (s *adminService) UpdateSpecialist(ctx context.Context, doctorID uint64, input UpdateDoctorInput) error {
@@ -225,6 +604,7 @@ func (s *adminService) UpdateSpecialist(ctx context.Context, doctorID uint64, in
 		}
 		return NewInternalServerError("failed to get specialist", err)
 	}
+	before := doctor
 
 	if input.FirstName != nil {
 		doctor.FirstName = *input.FirstName
@@ -244,12 +624,34 @@ func (s *adminService) UpdateSpecialist(ctx context.Context, doctorID uint64, in
 	if input.Recommendations != nil {
 		doctor.Recommendations.String, doctor.Recommendations.Valid = *input.Recommendations, true
 	}
+	if input.Gender != nil {
+		doctor.Gender.String, doctor.Gender.Valid = *input.Gender, true
+	}
+	if input.AcceptsDMS != nil {
+		doctor.AcceptsDMS = *input.AcceptsDMS
+	}
 
-	return s.repos.Admin.UpdateDoctor(ctx, doctor)
+	if err := s.repos.Admin.UpdateDoctor(ctx, doctor); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewNotFoundError("specialist to update not found", err)
+		}
+		return NewInternalServerError("failed to update specialist", err)
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditDoctorUpdate, EntityType: auditEntityDoctor, EntityID: doctorID, Before: before, After: doctor,
+	})
+	return nil
 }
 
 func (s *adminService) DeleteSpecialist(ctx context.Context, doctorID uint64) error {
-	return s.repos.Admin.DeleteDoctor(ctx, doctorID)
+	if err := s.repos.Admin.DeleteDoctor(ctx, doctorID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewNotFoundError("specialist not found", err)
+		}
+		return NewInternalServerError("failed to delete specialist", err)
+	}
+	s.audit.Record(ctx, auditEvent{Action: AuditDoctorDelete, EntityType: auditEntityDoctor, EntityID: doctorID})
+	return nil
 }
 
 func (s *adminService) GetSpecialistSchedule(ctx context.Context, doctorID uint64) ([]models.Schedule, error) {
@@ -257,6 +659,15 @@ func (s *adminService) GetSpecialistSchedule(ctx context.Context, doctorID uint6
 }
 
 func (s *adminService) UpdateSpecialistSchedule(ctx context.Context, doctorID uint64, input UpdateScheduleInput) error {
+	clinics, err := s.repos.Doctor.GetDoctorClinics(ctx, doctorID)
+	if err != nil {
+		return NewInternalServerError("failed to get specialist clinics", err)
+	}
+	assigned := make(map[uint64]bool, len(clinics))
+	for _, clinic := range clinics {
+		assigned[clinic.ID] = true
+	}
+
 	schedules := make([]models.Schedule, len(input.Schedules))
 	for i, item := range input.Schedules {
 		date, err := time.Parse("2006-01-02", item.Date)
@@ -272,14 +683,38 @@ func (s *adminService) UpdateSpecialistSchedule(ctx context.Context, doctorID ui
 			return NewBadRequestError("invalid end time format: "+item.EndTime, err)
 		}
 
+		clinicID := item.ClinicID
+		if clinicID == 0 {
+			if len(clinics) != 1 {
+				return NewBadRequestError("clinicId is required for date "+item.Date+
+					": specialist is not assigned to exactly one clinic", nil)
+			}
+			clinicID = clinics[0].ID
+		}
+		if !assigned[clinicID] {
+			return NewBadRequestError(fmt.Sprintf("specialist is not assigned to clinic %d", clinicID), nil)
+		}
+
 		schedules[i] = models.Schedule{
 			DoctorID:  doctorID,
 			Date:      date,
 			StartTime: startTime,
 			EndTime:   endTime,
+			ClinicID:  &clinicID,
 		}
 	}
-	return s.repos.Admin.UpdateDoctorSchedule(ctx, doctorID, schedules)
+	before, err := s.repos.Admin.GetDoctorSchedule(ctx, doctorID)
+	if err != nil {
+		return NewInternalServerError("failed to get current schedule", err)
+	}
+	if err := s.repos.Admin.UpdateDoctorSchedule(ctx, doctorID, schedules); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditScheduleUpdate, EntityType: auditEntitySchedule, EntityID: doctorID,
+		Before: map[string]any{"schedules": before}, After: map[string]any{"schedules": schedules},
+	})
+	return nil
 }
 
 // --- Appointment ---
@@ -306,11 +741,46 @@ func (s *adminService) GetAppointmentDetails(ctx context.Context, appointmentID
 }
 
 func (s *adminService) UpdateAppointmentStatus(ctx context.Context, appointmentID uint64, statusID uint32) error {
-	return s.repos.Appointment.UpdateAppointmentStatus(ctx, appointmentID, statusID)
+	appointment, err := s.repos.Appointment.GetAppointmentByID(ctx, appointmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewNotFoundError("appointment not found", err)
+		}
+		return NewInternalServerError("failed to get appointment", err)
+	}
+	if err := s.repos.Appointment.UpdateAppointmentStatus(ctx, appointmentID, statusID); err != nil {
+		if strings.Contains(err.Error(), "violates foreign key constraint") {
+			return NewBadRequestError("appointment status not found", err)
+		}
+		return NewInternalServerError("failed to update appointment status", err)
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditAppointmentStatus, EntityType: auditEntityAppointment, EntityID: appointmentID,
+		TargetUserID: appointment.UserID,
+		Before:       map[string]any{"statusId": appointment.StatusID}, After: map[string]any{"statusId": statusID},
+	})
+	return nil
 }
 
 func (s *adminService) DeleteAppointment(ctx context.Context, appointmentID uint64) error {
-	return s.repos.Admin.DeleteAppointment(ctx, appointmentID)
+	appointment, err := s.repos.Appointment.GetAppointmentByID(ctx, appointmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewNotFoundError("appointment not found", err)
+		}
+		return NewInternalServerError("failed to get appointment", err)
+	}
+	if err := s.repos.Admin.DeleteAppointment(ctx, appointmentID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewNotFoundError("appointment not found", err)
+		}
+		return NewInternalServerError("failed to delete appointment", err)
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditAppointmentDelete, EntityType: auditEntityAppointment, EntityID: appointmentID,
+		TargetUserID: appointment.UserID, Before: appointment,
+	})
+	return nil
 }
 
 // --- Service & Department ---
@@ -319,12 +789,25 @@ func (s *adminService) GetAllServices(ctx context.Context) ([]models.Service, er
 	return s.repos.Admin.GetAllServices(ctx)
 }
 
-func (s *adminService) CreateService(ctx context.Context, input CreateServiceInput) (uint64, error) {
+func (s *adminService) GetService(ctx context.Context, serviceID uint64) (models.Service, error) {
+	service, err := s.repos.Admin.GetServiceByID(ctx, serviceID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Service{}, NewNotFoundError("service not found", err)
+		}
+		return models.Service{}, NewInternalServerError("failed to get service", err)
+	}
+	return service, nil
+}
+
+// CreateService добавляет услугу в каталог. Базовая цена становится первой записью истории цен.
+func (s *adminService) CreateService(ctx context.Context, actor models.Admin, input CreateServiceInput) (uint64, error) {
 	service := models.Service{
-		Name:            input.Name,
-		Price:           input.Price,
+		Code:            strings.TrimSpace(input.Code),
+		Name:            strings.TrimSpace(input.Name),
+		CategoryID:      input.CategoryID,
+		BasePrice:       input.BasePrice,
 		DurationMinutes: input.DurationMinutes,
-		DoctorID:        input.DoctorID,
 	}
 	if input.Description != nil {
 		service.Description.String, service.Description.Valid = *input.Description, true
@@ -332,21 +815,108 @@ func (s *adminService) CreateService(ctx context.Context, input CreateServiceInp
 	if input.Recommendations != nil {
 		service.Recommendations.String, service.Recommendations.Valid = *input.Recommendations, true
 	}
+	if service.Code == "" || service.Name == "" {
+		return 0, NewBadRequestError("service code and name must not be empty", nil)
+	}
 
-	id, err := s.repos.Admin.CreateService(ctx, service)
+	id, err := s.repos.Admin.CreateService(ctx, service, optionalID(actor.ID))
 	if err != nil {
-		return 0, NewInternalServerError("failed to create service", err)
+		return 0, serviceSaveError("create", err)
 	}
+	service.ID = id
+	s.audit.Record(ctx, auditEvent{Action: AuditServiceCreate, EntityType: auditEntityService, EntityID: id, After: service})
 	return id, nil
 }
 
-func (s *adminService) UpdateService(ctx context.Context, serviceID uint64, input UpdateServiceInput) error {
-	// TODO: Реализовать логику получения сервиса по ID и его обновления
-	return NewInternalServerError("Not implemented yet", nil)
+// UpdateService изменяет переданные поля услуги. Изменение базовой цены попадает в историю цен;
+// уже созданные записи сохраняют цену, по которой были оформлены.
+func (s *adminService) UpdateService(ctx context.Context, actor models.Admin, serviceID uint64,
+	input UpdateServiceInput,
+) error {
+	service, err := s.repos.Admin.GetServiceByID(ctx, serviceID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewNotFoundError("service to update not found", err)
+		}
+		return NewInternalServerError("failed to get service", err)
+	}
+	before := service
+
+	if input.Code != nil {
+		service.Code = strings.TrimSpace(*input.Code)
+	}
+	if input.Name != nil {
+		service.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.CategoryID != nil {
+		service.CategoryID = *input.CategoryID
+	}
+	if input.BasePrice != nil {
+		service.BasePrice = *input.BasePrice
+	}
+	if input.DurationMinutes != nil {
+		service.DurationMinutes = *input.DurationMinutes
+	}
+	if input.Description != nil {
+		service.Description.String, service.Description.Valid = *input.Description, true
+	}
+	if input.Recommendations != nil {
+		service.Recommendations.String, service.Recommendations.Valid = *input.Recommendations, true
+	}
+	if service.Code == "" || service.Name == "" {
+		return NewBadRequestError("service code and name must not be empty", nil)
+	}
+
+	if err := s.repos.Admin.UpdateService(ctx, service, optionalID(actor.ID)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewNotFoundError("service to update not found", err)
+		}
+		return serviceSaveError("update", err)
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditServiceUpdate, EntityType: auditEntityService, EntityID: serviceID, Before: before, After: service,
+	})
+	return nil
+}
+
+// serviceSaveError переводит ошибку сохранения услуги каталога в ошибку приложения.
+func serviceSaveError(action string, err error) error {
+	if strings.Contains(err.Error(), "duplicate key value") {
+		return NewConflictError("service with this code already exists", err)
+	}
+	if strings.Contains(err.Error(), "violates foreign key constraint") {
+		return NewBadRequestError("service category not found", err)
+	}
+	return NewInternalServerError("failed to "+action+" service", err)
 }
 
+// DeleteService удаляет услугу из каталога. Услугу с предстоящими записями удалить нельзя:
+// записи нужно сначала отменить или перенести.
 func (s *adminService) DeleteService(ctx context.Context, serviceID uint64) error {
-	return s.repos.Admin.DeleteService(ctx, serviceID)
+	if err := s.repos.Admin.DeleteService(ctx, serviceID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewNotFoundError("service not found", err)
+		}
+		if errors.Is(err, repository.ErrServiceHasUpcomingAppointments) {
+			return NewConflictError("service has upcoming appointments", err)
+		}
+		return NewInternalServerError("failed to delete service", err)
+	}
+	s.audit.Record(ctx, auditEvent{Action: AuditServiceDelete, EntityType: auditEntityService, EntityID: serviceID})
+	return nil
+}
+
+func (s *adminService) GetServicePriceHistory(ctx context.Context, serviceID uint64, params models.PaginationParams) (
+	[]models.ServicePriceChange, int64, error,
+) {
+	if _, err := s.GetService(ctx, serviceID); err != nil {
+		return nil, 0, err
+	}
+	changes, total, err := s.repos.Admin.GetServicePriceHistory(ctx, serviceID, params)
+	if err != nil {
+		return nil, 0, NewInternalServerError("failed to get service price history", err)
+	}
+	return changes, total, nil
 }
 
 func (s *adminService) GetAllDepartments(ctx context.Context) ([]models.Department, error) {
@@ -354,7 +924,10 @@ func (s *adminService) GetAllDepartments(ctx context.Context) ([]models.Departme
 }
 
 func (s *adminService) CreateDepartment(ctx context.Context, input CreateDepartmentInput) (uint32, error) {
-	department := models.Department{Name: input.Name}
+	department := models.Department{Name: strings.TrimSpace(input.Name)}
+	if department.Name == "" {
+		return 0, NewBadRequestError("department name must not be empty", nil)
+	}
 	id, err := s.repos.Admin.CreateDepartment(ctx, department)
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate key value") {
@@ -362,6 +935,10 @@ func (s *adminService) CreateDepartment(ctx context.Context, input CreateDepartm
 		}
 		return 0, NewInternalServerError("failed to create department", err)
 	}
+	department.ID = id
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditDepartmentCreate, EntityType: auditEntityDepartment, EntityID: id, After: department,
+	})
 	return id, nil
 }
 
@@ -369,19 +946,43 @@ func (s *adminService) UpdateDepartment(ctx context.Context, departmentID uint32
 	if input.Name == nil {
 		return NewBadRequestError("name is required for update", nil)
 	}
-	department := models.Department{ID: departmentID, Name: *input.Name}
+	department := models.Department{ID: departmentID, Name: strings.TrimSpace(*input.Name)}
+	if department.Name == "" {
+		return NewBadRequestError("department name must not be empty", nil)
+	}
 	err := s.repos.Admin.UpdateDepartment(ctx, department)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewNotFoundError("department not found", err)
+		}
 		if strings.Contains(err.Error(), "duplicate key value") {
 			return NewConflictError("department with this name already exists", err)
 		}
 		return NewInternalServerError("failed to update department", err)
 	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditDepartmentUpdate, EntityType: auditEntityDepartment, EntityID: departmentID,
+		After: map[string]any{"name": department.Name},
+	})
 	return nil
 }
 
+// DeleteDepartment удаляет отделение, в котором не осталось действующих услуг и врачей.
 func (s *adminService) DeleteDepartment(ctx context.Context, departmentID uint32) error {
-	return s.repos.Admin.DeleteDepartment(ctx, departmentID)
+	if err := s.repos.Admin.DeleteDepartment(ctx, departmentID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewNotFoundError("department not found", err)
+		}
+		if errors.Is(err, repository.ErrDepartmentHasServices) {
+			return NewConflictError("department has active services", err)
+		}
+		if errors.Is(err, repository.ErrDepartmentHasDoctors) {
+			return NewConflictError("department has active specialists", err)
+		}
+		return NewInternalServerError("failed to delete department", err)
+	}
+	s.audit.Record(ctx, auditEvent{Action: AuditDepartmentDelete, EntityType: auditEntityDepartment, EntityID: departmentID})
+	return nil
 }
 
 // TODO: Реализовать остальные методы AdminService
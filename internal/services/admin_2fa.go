@@ -0,0 +1,322 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"lk/internal/models"
+	"lk/internal/repository"
+	"lk/internal/utils"
+)
+
+const (
+	// adminMFATokenPrefix - промежуточный токен, выдаваемый после проверки пароля до ввода кода 2FA.
+	adminMFATokenPrefix = "admin_mfa:"
+	adminMFATokenTTL    = 5 * time.Minute
+	// adminTOTPPendingPrefix - секрет, ожидающий подтверждения при подключении 2FA.
+	adminTOTPPendingPrefix = "admin_totp_pending:"
+	adminTOTPPendingTTL    = 10 * time.Minute
+	// adminTOTPUsedPrefix - уже использованные коды (по шагу времени), защита от повторного ввода.
+	adminTOTPUsedPrefix = "admin_totp_used:"
+	adminTOTPUsedTTL    = 2 * time.Minute
+	recoveryCodesCount  = 10
+)
+
+// LoginTwoFactor завершает вход администратора: проверяет промежуточный токен
+// и код из приложения-аутентификатора (или резервный код) и выдает пару токенов.
+func (s *adminService) LoginTwoFactor(ctx context.Context, mfaToken, code, clientIP, userAgent string) (
+	map[string]string, error,
+) {
+	key := adminMFATokenPrefix + mfaToken
+	adminIDStr, err := s.repos.Cache.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, NewUnauthorizedError("two-factor session is invalid or expired", nil)
+		}
+		return nil, NewInternalServerError("failed to get two-factor session", err)
+	}
+	adminID, err := strconv.ParseUint(adminIDStr, 10, 64)
+	if err != nil {
+		return nil, NewInternalServerError("invalid two-factor session", err)
+	}
+
+	admin, err := s.repos.Admin.GetByID(ctx, adminID)
+	if err != nil {
+		return nil, NewInternalServerError("failed to get admin", err)
+	}
+	if !admin.IsActive {
+		return nil, NewForbiddenError("admin account is disabled", nil)
+	}
+	if !admin.TOTPEnabledAt.Valid {
+		return nil, NewUnauthorizedError("two-factor authentication is not enabled", nil)
+	}
+
+	if err := s.checkSecondFactor(ctx, admin, code, clientIP, true); err != nil {
+		return nil, err
+	}
+
+	_ = s.repos.Cache.Delete(ctx, key)
+	return s.issueAdminToken(ctx, admin, clientIP, userAgent)
+}
+
+// GetTwoFactorStatus возвращает состояние 2FA администратора.
+func (s *adminService) GetTwoFactorStatus(ctx context.Context, admin models.Admin) (
+	models.AdminTwoFactorStatus, error,
+) {
+	required, err := s.IsTwoFactorRequired(ctx)
+	if err != nil {
+		return models.AdminTwoFactorStatus{}, err
+	}
+	status := models.AdminTwoFactorStatus{
+		Enabled:  admin.TOTPEnabledAt.Valid,
+		Required: required,
+	}
+	if status.Enabled {
+		status.RecoveryCodesLeft, err = s.repos.Admin.CountRecoveryCodes(ctx, admin.ID)
+		if err != nil {
+			return models.AdminTwoFactorStatus{}, NewInternalServerError("failed to count recovery codes", err)
+		}
+	}
+	return status, nil
+}
+
+// StartTwoFactorEnrollment генерирует новый секрет TOTP и URI для QR-кода.
+// Секрет начинает действовать только после подтверждения кодом (ConfirmTwoFactorEnrollment).
+func (s *adminService) StartTwoFactorEnrollment(ctx context.Context, admin models.Admin) (
+	models.AdminTwoFactorEnrollment, error,
+) {
+	if admin.TOTPEnabledAt.Valid {
+		return models.AdminTwoFactorEnrollment{}, NewConflictError("two-factor authentication is already enabled", nil)
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return models.AdminTwoFactorEnrollment{}, NewInternalServerError("failed to generate totp secret", err)
+	}
+	key := adminTOTPPendingPrefix + strconv.FormatUint(admin.ID, 10)
+	if err := s.repos.Cache.Set(ctx, key, secret, adminTOTPPendingTTL); err != nil {
+		return models.AdminTwoFactorEnrollment{}, NewInternalServerError("failed to save totp secret", err)
+	}
+
+	return models.AdminTwoFactorEnrollment{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(s.totpIssuer, admin.Login, secret),
+	}, nil
+}
+
+// ConfirmTwoFactorEnrollment включает 2FA после ввода первого кода из приложения
+// и возвращает резервные коды. Коды показываются только один раз.
+func (s *adminService) ConfirmTwoFactorEnrollment(ctx context.Context, admin models.Admin, code, clientIP string) (
+	[]string, error,
+) {
+	if admin.TOTPEnabledAt.Valid {
+		return nil, NewConflictError("two-factor authentication is already enabled", nil)
+	}
+
+	key := adminTOTPPendingPrefix + strconv.FormatUint(admin.ID, 10)
+	secret, err := s.repos.Cache.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, NewBadRequestError("two-factor enrollment is not started or has expired", nil)
+		}
+		return nil, NewInternalServerError("failed to get pending totp secret", err)
+	}
+
+	admin.TOTPSecret = sql.NullString{String: secret, Valid: true}
+	if err := s.checkSecondFactor(ctx, admin, code, clientIP, false); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, NewInternalServerError("failed to generate recovery codes", err)
+	}
+	if err := s.repos.Admin.EnableTOTP(ctx, admin.ID, secret, hashes); err != nil {
+		return nil, NewInternalServerError("failed to enable two-factor authentication", err)
+	}
+	_ = s.repos.Cache.Delete(ctx, key)
+	return codes, nil
+}
+
+// RegenerateRecoveryCodes выпускает новый набор резервных кодов; старые перестают действовать.
+func (s *adminService) RegenerateRecoveryCodes(ctx context.Context, admin models.Admin, code, clientIP string) (
+	[]string, error,
+) {
+	if !admin.TOTPEnabledAt.Valid {
+		return nil, NewConflictError("two-factor authentication is not enabled", nil)
+	}
+	if err := s.checkSecondFactor(ctx, admin, code, clientIP, false); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, NewInternalServerError("failed to generate recovery codes", err)
+	}
+	if err := s.repos.Admin.ReplaceRecoveryCodes(ctx, admin.ID, hashes); err != nil {
+		return nil, NewInternalServerError("failed to save recovery codes", err)
+	}
+	return codes, nil
+}
+
+// DisableTwoFactor отключает 2FA, если это не запрещено политикой безопасности.
+func (s *adminService) DisableTwoFactor(ctx context.Context, admin models.Admin, code, clientIP string) error {
+	if !admin.TOTPEnabledAt.Valid {
+		return NewConflictError("two-factor authentication is not enabled", nil)
+	}
+	required, err := s.IsTwoFactorRequired(ctx)
+	if err != nil {
+		return err
+	}
+	if required {
+		return NewForbiddenError("two-factor authentication is required by security policy", nil)
+	}
+	if err := s.checkSecondFactor(ctx, admin, code, clientIP, true); err != nil {
+		return err
+	}
+
+	if err := s.repos.Admin.DisableTOTP(ctx, admin.ID); err != nil {
+		return NewInternalServerError("failed to disable two-factor authentication", err)
+	}
+	return nil
+}
+
+// IsTwoFactorRequired сообщает, обязательна ли 2FA для всех администраторов.
+func (s *adminService) IsTwoFactorRequired(ctx context.Context) (bool, error) {
+	settings, err := s.GetSecuritySettings(ctx)
+	if err != nil {
+		return false, err
+	}
+	return settings.RequireAdmin2FA, nil
+}
+
+// GetSecuritySettings возвращает глобальные настройки безопасности.
+func (s *adminService) GetSecuritySettings(ctx context.Context) (models.SecuritySettings, error) {
+	settings, err := s.repos.Security.GetSettings(ctx)
+	if err != nil {
+		return models.SecuritySettings{}, NewInternalServerError("failed to get security settings", err)
+	}
+	return settings, nil
+}
+
+// UpdateSecuritySettings изменяет глобальные настройки безопасности. Доступно только суперадминистратору.
+func (s *adminService) UpdateSecuritySettings(ctx context.Context, actor models.Admin,
+	input UpdateSecuritySettingsInput,
+) (models.SecuritySettings, error) {
+	if !actor.Role.HasPermission(models.PermSecuritySettingsWrite) {
+		return models.SecuritySettings{}, NewForbiddenError("only superadmin can change security settings", nil)
+	}
+
+	before, err := s.GetSecuritySettings(ctx)
+	if err != nil {
+		return models.SecuritySettings{}, err
+	}
+	settings := models.SecuritySettings{
+		RequireAdmin2FA: *input.RequireAdmin2FA,
+		UpdatedBy:       sql.NullInt64{Int64: int64(actor.ID), Valid: true},
+	}
+	if err := s.repos.Security.UpdateSettings(ctx, settings); err != nil {
+		return models.SecuritySettings{}, NewInternalServerError("failed to update security settings", err)
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditSecuritySettings, EntityType: auditEntitySecurity,
+		Before: map[string]any{"requireAdmin2FA": before.RequireAdmin2FA},
+		After:  map[string]any{"requireAdmin2FA": settings.RequireAdmin2FA},
+	})
+	return s.GetSecuritySettings(ctx)
+}
+
+// issueMFAToken выдает промежуточный токен, который обменивается на access токен после ввода кода 2FA.
+func (s *adminService) issueMFAToken(ctx context.Context, adminID uint64) (map[string]string, error) {
+	randomBytes := make([]byte, 32)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return nil, NewInternalServerError("failed to generate two-factor token", err)
+	}
+	mfaToken := base64.RawURLEncoding.EncodeToString(randomBytes)
+
+	if err := s.repos.Cache.Set(ctx, adminMFATokenPrefix+mfaToken, adminID, adminMFATokenTTL); err != nil {
+		return nil, NewInternalServerError("failed to save two-factor session", err)
+	}
+	return map[string]string{
+		"mfaRequired": "true",
+		"mfaToken":    mfaToken,
+	}, nil
+}
+
+// checkSecondFactor проверяет код TOTP (и, если разрешено, резервный код) с учетом защиты от перебора.
+func (s *adminService) checkSecondFactor(ctx context.Context, admin models.Admin, code, clientIP string,
+	allowRecovery bool,
+) error {
+	if err := s.guard.Check(ctx, models.LockoutScopeAdminTwoFA, admin.Login, clientIP); err != nil {
+		return err
+	}
+
+	code = strings.TrimSpace(code)
+	ok, err := s.verifyTOTP(ctx, admin, code)
+	if err != nil {
+		return err
+	}
+	if !ok && allowRecovery {
+		ok, err = s.repos.Admin.UseRecoveryCode(ctx, admin.ID, hashRecoveryCode(code))
+		if err != nil {
+			return NewInternalServerError("failed to check recovery code", err)
+		}
+	}
+	if !ok {
+		s.guard.RegisterFailure(ctx, models.LockoutScopeAdminTwoFA, admin.Login, clientIP)
+		return NewUnauthorizedError("invalid two-factor code", nil)
+	}
+
+	s.guard.Reset(ctx, models.LockoutScopeAdminTwoFA, admin.Login)
+	return nil
+}
+
+// verifyTOTP проверяет код из приложения и запрещает повторное использование одного и того же кода.
+func (s *adminService) verifyTOTP(ctx context.Context, admin models.Admin, code string) (bool, error) {
+	if !admin.TOTPSecret.Valid {
+		return false, nil
+	}
+	step, ok := utils.ValidateTOTP(admin.TOTPSecret.String, code, time.Now())
+	if !ok {
+		return false, nil
+	}
+
+	// Отметка об использовании ставится атомарно: из двух параллельных входов с одним кодом пройдет один
+	usedKey := fmt.Sprintf("%s%d:%d", adminTOTPUsedPrefix, admin.ID, step)
+	fresh, err := s.repos.Cache.SetIfAbsent(ctx, usedKey, 1, adminTOTPUsedTTL)
+	if err != nil {
+		return false, NewInternalServerError("failed to save used totp code", err)
+	}
+	return fresh, nil
+}
+
+// generateRecoveryCodes создает набор резервных кодов и их хэши для хранения в БД.
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodesCount)
+	hashes := make([]string, 0, recoveryCodesCount)
+	for i := 0; i < recoveryCodesCount; i++ {
+		code, err := utils.GenerateRecoveryCode()
+		if err != nil {
+			return nil, nil, err
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode хэширует резервный код. Коды высокоэнтропийные, поэтому
+// достаточно SHA-256 без соли.
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(code))))
+	return hex.EncodeToString(sum[:])
+}
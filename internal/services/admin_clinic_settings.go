@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"strings"
+
+	"lk/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// --- Настройки клиники ---
+
+// GetClinicSettings возвращает действующую версию настроек клиники.
+func (s *adminService) GetClinicSettings(ctx context.Context) (models.ClinicSettingsVersion, error) {
+	version, err := s.settings.Version(ctx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ClinicSettingsVersion{Settings: models.DefaultClinicSettings()}, nil
+		}
+		return models.ClinicSettingsVersion{}, NewInternalServerError("failed to get clinic settings", err)
+	}
+	return version, nil
+}
+
+// UpdateClinicSettings сохраняет новую версию настроек. input.Version - версия, которую
+// администратор редактировал: если с тех пор настройки уже изменили, возвращается конфликт.
+func (s *adminService) UpdateClinicSettings(ctx context.Context, actor models.Admin,
+	input UpdateClinicSettingsInput,
+) (models.ClinicSettingsVersion, error) {
+	settings, err := clinicSettingsFromInput(input)
+	if err != nil {
+		return models.ClinicSettingsVersion{}, err
+	}
+	before, err := s.GetClinicSettings(ctx)
+	if err != nil {
+		return models.ClinicSettingsVersion{}, err
+	}
+	if input.Version != before.Version {
+		return models.ClinicSettingsVersion{}, NewConflictError(
+			"clinic settings have been changed by someone else; reload them and try again", nil)
+	}
+
+	version := models.ClinicSettingsVersion{
+		Version:   before.Version + 1,
+		Settings:  settings,
+		CreatedBy: sql.NullInt64{Int64: int64(actor.ID), Valid: true},
+	}
+	if err := s.settings.Save(ctx, &version); err != nil {
+		if strings.Contains(err.Error(), "duplicate key value") {
+			return models.ClinicSettingsVersion{}, NewConflictError(
+				"clinic settings have been changed by someone else; reload them and try again", err)
+		}
+		return models.ClinicSettingsVersion{}, NewInternalServerError("failed to save clinic settings", err)
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditClinicSettingsUpdate, EntityType: auditEntityClinicSettings, EntityID: version.Version,
+		Before: before.Settings, After: version.Settings,
+	})
+	return version, nil
+}
+
+// GetClinicSettingsHistory возвращает сохраненные версии настроек, новые сверху.
+func (s *adminService) GetClinicSettingsHistory(ctx context.Context, params models.PaginationParams) (
+	[]models.ClinicSettingsVersion, int64, error,
+) {
+	versions, total, err := s.repos.Settings.GetHistory(ctx, params)
+	if err != nil {
+		return nil, 0, NewInternalServerError("failed to get clinic settings history", err)
+	}
+	return versions, total, nil
+}
+
+// clinicSettingsFromInput проверяет то, что не выражается тегами валидации, и собирает настройки.
+// Напоминания упорядочиваются от самого раннего.
+func clinicSettingsFromInput(input UpdateClinicSettingsInput) (models.ClinicSettings, error) {
+	reminders := append([]int{}, input.ReminderOffsetsMinutes...)
+	sort.Sort(sort.Reverse(sort.IntSlice(reminders)))
+	for i := 1; i < len(reminders); i++ {
+		if reminders[i] == reminders[i-1] {
+			return models.ClinicSettings{}, NewBadRequestError("reminder offsets must be unique", nil)
+		}
+	}
+	hours := make([]models.WorkHours, len(input.WorkingHours))
+	for i, item := range input.WorkingHours {
+		hours[i] = models.WorkHours{Days: strings.TrimSpace(item.Days), Hours: strings.TrimSpace(item.Hours)}
+		if hours[i].Days == "" || hours[i].Hours == "" {
+			return models.ClinicSettings{}, NewBadRequestError("working hours require both days and hours", nil)
+		}
+	}
+	return models.ClinicSettings{
+		CancellationWindowHours: input.CancellationWindowHours,
+		ReminderOffsetsMinutes:  reminders,
+		SlotGranularityMinutes:  input.SlotGranularityMinutes,
+		BookingHorizonDays:      input.BookingHorizonDays,
+		SupportPhone:            strings.TrimSpace(input.SupportPhone),
+		SupportEmail:            strings.TrimSpace(input.SupportEmail),
+		WorkingHours:            hours,
+	}, nil
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"lk/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// --- Клиники ---
+
+func (s *adminService) GetAllClinics(ctx context.Context) ([]models.Clinic, error) {
+	clinics, err := s.repos.Clinic.GetAll(ctx)
+	if err != nil {
+		return nil, NewInternalServerError("failed to get clinics", err)
+	}
+	return clinics, nil
+}
+
+func (s *adminService) GetClinic(ctx context.Context, clinicID uint64) (models.Clinic, error) {
+	clinic, err := s.repos.Clinic.GetByID(ctx, clinicID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Clinic{}, NewNotFoundError("clinic not found", err)
+		}
+		return models.Clinic{}, NewInternalServerError("failed to get clinic", err)
+	}
+	return clinic, nil
+}
+
+// CreateClinic добавляет клинику в сеть. Если она отмечена основной, прежняя основная клиника
+// перестает ею быть.
+func (s *adminService) CreateClinic(ctx context.Context, input ClinicInput) (models.Clinic, error) {
+	clinic, err := clinicFromInput(input)
+	if err != nil {
+		return models.Clinic{}, err
+	}
+	id, err := s.repos.Clinic.Create(ctx, clinic)
+	if err != nil {
+		return models.Clinic{}, clinicSaveError("create", err)
+	}
+
+	created, err := s.GetClinic(ctx, id)
+	if err != nil {
+		return models.Clinic{}, err
+	}
+	s.audit.Record(ctx, auditEvent{Action: AuditClinicCreate, EntityType: auditEntityClinic, EntityID: id, After: created})
+	return created, nil
+}
+
+// UpdateClinic полностью перезаписывает данные клиники и ее часы работы.
+// Снять признак основной можно, только назначив основной другую клинику.
+func (s *adminService) UpdateClinic(ctx context.Context, clinicID uint64, input ClinicInput) (models.Clinic, error) {
+	before, err := s.GetClinic(ctx, clinicID)
+	if err != nil {
+		return models.Clinic{}, err
+	}
+	if before.IsMain && !input.IsMain {
+		return models.Clinic{}, NewConflictError("main clinic cannot be unset; mark another clinic as main instead", nil)
+	}
+
+	clinic, err := clinicFromInput(input)
+	if err != nil {
+		return models.Clinic{}, err
+	}
+	clinic.ID = clinicID
+	if err := s.repos.Clinic.Update(ctx, clinic); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Clinic{}, NewNotFoundError("clinic not found", err)
+		}
+		return models.Clinic{}, clinicSaveError("update", err)
+	}
+
+	after, err := s.GetClinic(ctx, clinicID)
+	if err != nil {
+		return models.Clinic{}, err
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditClinicUpdate, EntityType: auditEntityClinic, EntityID: clinicID, Before: before, After: after,
+	})
+	return after, nil
+}
+
+// DeleteClinic мягко удаляет клинику. Основную клинику и клинику, в которой врачи принимают
+// сегодня или позже, удалить нельзя. Назначения врачей сохраняются, но удаленная клиника
+// больше не показывается в их профилях и поиске.
+func (s *adminService) DeleteClinic(ctx context.Context, clinicID uint64) error {
+	clinic, err := s.GetClinic(ctx, clinicID)
+	if err != nil {
+		return err
+	}
+	if clinic.IsMain {
+		return NewConflictError("main clinic cannot be deleted; mark another clinic as main first", nil)
+	}
+	upcoming, err := s.repos.Clinic.HasUpcomingSchedules(ctx, clinicID)
+	if err != nil {
+		return NewInternalServerError("failed to check clinic schedules", err)
+	}
+	if upcoming {
+		return NewConflictError("clinic has upcoming schedule days", nil)
+	}
+
+	if err := s.repos.Clinic.Delete(ctx, clinicID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewNotFoundError("clinic not found", err)
+		}
+		return NewInternalServerError("failed to delete clinic", err)
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditClinicDelete, EntityType: auditEntityClinic, EntityID: clinicID, Before: clinic,
+	})
+	return nil
+}
+
+func (s *adminService) GetDeletedClinics(ctx context.Context, params models.PaginationParams) (
+	[]models.Clinic, int64, error,
+) {
+	clinics, total, err := s.repos.Clinic.GetDeleted(ctx, params)
+	if err != nil {
+		return nil, 0, NewInternalServerError("failed to get deleted clinics", err)
+	}
+	return clinics, total, nil
+}
+
+func (s *adminService) RestoreClinic(ctx context.Context, clinicID uint64) error {
+	if err := s.repos.Clinic.Restore(ctx, clinicID); err != nil {
+		return restoreError("clinic", err)
+	}
+	s.audit.Record(ctx, auditEvent{Action: AuditClinicRestore, EntityType: auditEntityClinic, EntityID: clinicID})
+	return nil
+}
+
+// clinicFromInput проверяет часы работы и собирает модель клиники.
+func clinicFromInput(input ClinicInput) (models.Clinic, error) {
+	clinic := models.Clinic{
+		Name:         strings.TrimSpace(input.Name),
+		Address:      strings.TrimSpace(input.Address),
+		Phone:        input.Phone,
+		Email:        input.Email,
+		Latitude:     input.Latitude,
+		Longitude:    input.Longitude,
+		IsMain:       input.IsMain,
+		WorkingHours: make([]models.ClinicWorkingHours, 0, len(input.WorkingHours)),
+	}
+	if input.CityID != nil {
+		clinic.CityID = sql.NullInt32{Int32: int32(*input.CityID), Valid: true}
+	}
+
+	seen := make(map[uint8]bool, len(input.WorkingHours))
+	for _, day := range input.WorkingHours {
+		if seen[day.Weekday] {
+			return models.Clinic{}, NewBadRequestError("working hours contain a duplicate weekday", nil)
+		}
+		seen[day.Weekday] = true
+		opensAt, err := time.Parse("15:04", day.OpensAt)
+		if err != nil {
+			return models.Clinic{}, NewBadRequestError("invalid opensAt format, expected HH:MM", err)
+		}
+		closesAt, err := time.Parse("15:04", day.ClosesAt)
+		if err != nil {
+			return models.Clinic{}, NewBadRequestError("invalid closesAt format, expected HH:MM", err)
+		}
+		if !opensAt.Before(closesAt) {
+			return models.Clinic{}, NewBadRequestError("opensAt must be before closesAt", nil)
+		}
+		clinic.WorkingHours = append(clinic.WorkingHours, models.ClinicWorkingHours{
+			Weekday: day.Weekday, OpensAt: day.OpensAt, ClosesAt: day.ClosesAt,
+		})
+	}
+	return clinic, nil
+}
+
+// clinicSaveError переводит ошибку сохранения клиники в ошибку приложения.
+func clinicSaveError(action string, err error) error {
+	if strings.Contains(err.Error(), "violates foreign key constraint") {
+		return NewBadRequestError("city not found", err)
+	}
+	return NewInternalServerError("failed to "+action+" clinic", err)
+}
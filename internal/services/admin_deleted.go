@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"lk/internal/models"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// --- Мягко удаленные записи ---
+
+func (s *adminService) GetDeletedUsers(ctx context.Context, params models.PaginationParams) (
+	[]models.User, int64, error,
+) {
+	items, total, err := s.repos.Admin.GetDeletedUsers(ctx, params)
+	if err != nil {
+		return nil, 0, NewInternalServerError("failed to get deleted users", err)
+	}
+	return items, total, nil
+}
+
+// RestoreUser восстанавливает мягко удаленного пациента.
+// Если его телефон уже занят другим аккаунтом, возвращается конфликт.
+func (s *adminService) RestoreUser(ctx context.Context, userID uint64) error {
+	if err := s.repos.Admin.RestoreUser(ctx, userID); err != nil {
+		return restoreError("user", err)
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditUserRestore, EntityType: auditEntityUser, EntityID: userID, TargetUserID: userID,
+	})
+	return nil
+}
+
+func (s *adminService) GetDeletedSpecialists(ctx context.Context, params models.PaginationParams) (
+	[]models.Doctor, int64, error,
+) {
+	items, total, err := s.repos.Admin.GetDeletedSpecialists(ctx, params)
+	if err != nil {
+		return nil, 0, NewInternalServerError("failed to get deleted specialists", err)
+	}
+	return items, total, nil
+}
+
+func (s *adminService) RestoreSpecialist(ctx context.Context, doctorID uint64) error {
+	if err := s.repos.Admin.RestoreDoctor(ctx, doctorID); err != nil {
+		return restoreError("specialist", err)
+	}
+	s.audit.Record(ctx, auditEvent{Action: AuditDoctorRestore, EntityType: auditEntityDoctor, EntityID: doctorID})
+	return nil
+}
+
+func (s *adminService) GetDeletedAppointments(ctx context.Context, params models.PaginationParams) (
+	[]models.Appointment, int64, error,
+) {
+	items, total, err := s.repos.Admin.GetDeletedAppointments(ctx, params)
+	if err != nil {
+		return nil, 0, NewInternalServerError("failed to get deleted appointments", err)
+	}
+	return items, total, nil
+}
+
+func (s *adminService) RestoreAppointment(ctx context.Context, appointmentID uint64) error {
+	if err := s.repos.Admin.RestoreAppointment(ctx, appointmentID); err != nil {
+		return restoreError("appointment", err)
+	}
+	event := auditEvent{Action: AuditAppointmentRestore, EntityType: auditEntityAppointment, EntityID: appointmentID}
+	if appointment, err := s.repos.Appointment.GetAppointmentByID(ctx, appointmentID); err == nil {
+		event.TargetUserID = appointment.UserID
+		event.After = appointment
+	}
+	s.audit.Record(ctx, event)
+	return nil
+}
+
+func (s *adminService) GetDeletedServices(ctx context.Context, params models.PaginationParams) (
+	[]models.Service, int64, error,
+) {
+	items, total, err := s.repos.Admin.GetDeletedServices(ctx, params)
+	if err != nil {
+		return nil, 0, NewInternalServerError("failed to get deleted services", err)
+	}
+	return items, total, nil
+}
+
+func (s *adminService) RestoreService(ctx context.Context, serviceID uint64) error {
+	if err := s.repos.Admin.RestoreService(ctx, serviceID); err != nil {
+		return restoreError("service", err)
+	}
+	s.audit.Record(ctx, auditEvent{Action: AuditServiceRestore, EntityType: auditEntityService, EntityID: serviceID})
+	return nil
+}
+
+func (s *adminService) GetDeletedDepartments(ctx context.Context, params models.PaginationParams) (
+	[]models.Department, int64, error,
+) {
+	items, total, err := s.repos.Admin.GetDeletedDepartments(ctx, params)
+	if err != nil {
+		return nil, 0, NewInternalServerError("failed to get deleted departments", err)
+	}
+	return items, total, nil
+}
+
+// RestoreDepartment восстанавливает мягко удаленное отделение.
+// Если за время удаления создано отделение с тем же названием, возвращается конфликт.
+func (s *adminService) RestoreDepartment(ctx context.Context, departmentID uint32) error {
+	if err := s.repos.Admin.RestoreDepartment(ctx, departmentID); err != nil {
+		return restoreError("department", err)
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditDepartmentRestore, EntityType: auditEntityDepartment, EntityID: departmentID,
+	})
+	return nil
+}
+
+// uniqueViolationCode - код ошибки PostgreSQL unique_violation.
+const uniqueViolationCode = "23505"
+
+// restoreError переводит ошибку восстановления записи в ошибку API.
+func restoreError(entity string, err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return NewNotFoundError("deleted "+entity+" not found", err)
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+		return NewConflictError(entity+" conflicts with an existing active record", err)
+	}
+	return NewInternalServerError("failed to restore "+entity, err)
+}
+
+// RunPurgeJob периодически окончательно удаляет записи, мягко удаленные раньше срока хранения.
+// Блокируется до отмены ctx.
+func (s *adminService) RunPurgeJob(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		s.purgeDeleted(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeDeleted выполняет один проход окончательного удаления.
+func (s *adminService) purgeDeleted(ctx context.Context) {
+	before := time.Now().Add(-s.retention.DeletedRetention)
+	results, err := s.repos.Admin.PurgeDeleted(ctx, before)
+	if err != nil {
+		log.Printf("ERROR: failed to purge soft-deleted records: %v", err)
+	}
+
+	var purged int64
+	for _, result := range results {
+		purged += result.Purged
+		if result.Kept > 0 {
+			log.Printf("WARN: %d soft-deleted records in %s are still referenced and were kept",
+				result.Kept, result.Table)
+		}
+	}
+	if purged > 0 {
+		s.audit.Record(ctx, auditEvent{
+			Action: AuditDeletedPurge, EntityType: auditEntityRetention,
+			After: map[string]any{"before": before, "results": results},
+		})
+	}
+}
@@ -0,0 +1,404 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"strconv"
+	"strings"
+	"time"
+
+	"lk/internal/mail"
+	"lk/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Разделы профиля врача.
+const (
+	DoctorSectionEducation       = "education"
+	DoctorSectionResidency       = "residency"
+	DoctorSectionCourses         = "courses"
+	DoctorSectionCertificates    = "certificates"
+	DoctorSectionSkills          = "skills"
+	DoctorSectionSpecializations = "specializations"
+)
+
+// DoctorProfileItemInput - тело запроса для записи одного из разделов профиля врача.
+type DoctorProfileItemInput interface {
+	// toModel возвращает указатель на модель раздела, заполненную из запроса.
+	toModel(doctorID, itemID uint64) (any, error)
+}
+
+// doctorProfileSection описывает раздел профиля: пустое тело запроса и пустую модель.
+type doctorProfileSection struct {
+	input func() DoctorProfileItemInput
+	model func() any
+}
+
+var doctorProfileSections = map[string]doctorProfileSection{
+	DoctorSectionEducation: {
+		input: func() DoctorProfileItemInput { return &DoctorEducationInput{} },
+		model: func() any { return &models.DoctorEducation{} },
+	},
+	DoctorSectionResidency: {
+		input: func() DoctorProfileItemInput { return &DoctorResidencyInput{} },
+		model: func() any { return &models.DoctorResidency{} },
+	},
+	DoctorSectionCourses: {
+		input: func() DoctorProfileItemInput { return &DoctorCourseInput{} },
+		model: func() any { return &models.DoctorCourse{} },
+	},
+	DoctorSectionCertificates: {
+		input: func() DoctorProfileItemInput { return &DoctorCertificateInput{} },
+		model: func() any { return &models.DoctorCertificate{} },
+	},
+	DoctorSectionSkills: {
+		input: func() DoctorProfileItemInput { return &DoctorSkillInput{} },
+		model: func() any { return &models.DoctorSkill{} },
+	},
+	DoctorSectionSpecializations: {
+		input: func() DoctorProfileItemInput { return &DoctorSpecializationInput{} },
+		model: func() any { return &models.DoctorSpecialization{} },
+	},
+}
+
+// NewDoctorProfileItemInput возвращает пустое тело запроса для раздела профиля врача.
+func NewDoctorProfileItemInput(section string) (DoctorProfileItemInput, error) {
+	s, ok := doctorProfileSections[section]
+	if !ok {
+		return nil, NewNotFoundError("unknown doctor profile section: "+section, nil)
+	}
+	return s.input(), nil
+}
+
+func (i *DoctorEducationInput) toModel(doctorID, itemID uint64) (any, error) {
+	return &models.DoctorEducation{
+		ID: itemID, DoctorID: doctorID, Institution: i.Institution, Specialty: i.Specialty,
+		StartYear: i.StartYear, EndYear: i.EndYear,
+	}, nil
+}
+
+func (i *DoctorResidencyInput) toModel(doctorID, itemID uint64) (any, error) {
+	return &models.DoctorResidency{
+		ID: itemID, DoctorID: doctorID, Institution: i.Institution, Specialty: i.Specialty,
+		StartYear: i.StartYear, EndYear: i.EndYear,
+	}, nil
+}
+
+func (i *DoctorCourseInput) toModel(doctorID, itemID uint64) (any, error) {
+	return &models.DoctorCourse{ID: itemID, DoctorID: doctorID, CourseName: i.CourseName, Year: i.Year}, nil
+}
+
+func (i *DoctorCertificateInput) toModel(doctorID, itemID uint64) (any, error) {
+	certificate := &models.DoctorCertificate{ID: itemID, DoctorID: doctorID, CertName: i.CertName}
+	if i.CertNumber != nil {
+		certificate.CertNumber.String, certificate.CertNumber.Valid = *i.CertNumber, true
+	}
+	if i.IssuedAt != nil {
+		date, err := time.Parse("2006-01-02", *i.IssuedAt)
+		if err != nil {
+			return nil, NewBadRequestError("invalid issue date format: "+*i.IssuedAt, err)
+		}
+		certificate.IssuedAt.Time, certificate.IssuedAt.Valid = date, true
+	}
+	if i.ExpiresAt != nil {
+		date, err := time.Parse("2006-01-02", *i.ExpiresAt)
+		if err != nil {
+			return nil, NewBadRequestError("invalid expiry date format: "+*i.ExpiresAt, err)
+		}
+		certificate.ExpiresAt.Time, certificate.ExpiresAt.Valid = date, true
+	}
+	if certificate.IssuedAt.Valid && certificate.ExpiresAt.Valid &&
+		certificate.ExpiresAt.Time.Before(certificate.IssuedAt.Time) {
+		return nil, NewBadRequestError("certificate expiry date is before issue date", nil)
+	}
+	return certificate, nil
+}
+
+func (i *DoctorSkillInput) toModel(doctorID, itemID uint64) (any, error) {
+	return &models.DoctorSkill{ID: itemID, DoctorID: doctorID, SkillName: i.SkillName}, nil
+}
+
+func (i *DoctorSpecializationInput) toModel(doctorID, itemID uint64) (any, error) {
+	return &models.DoctorSpecialization{ID: itemID, DoctorID: doctorID, Area: i.Area}, nil
+}
+
+// --- Разделы профиля врача ---
+
+func (s *adminService) CreateDoctorProfileItem(ctx context.Context, doctorID uint64, section string,
+	input DoctorProfileItemInput,
+) (any, error) {
+	if _, err := s.repos.Doctor.GetDoctorByID(ctx, doctorID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("specialist not found", err)
+		}
+		return nil, NewInternalServerError("failed to get specialist", err)
+	}
+	item, err := input.toModel(doctorID, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repos.Admin.CreateDoctorProfileItem(ctx, item); err != nil {
+		return nil, NewInternalServerError("failed to create doctor profile item", err)
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditDoctorUpdate, EntityType: auditEntityDoctor, EntityID: doctorID,
+		After: map[string]any{section: item},
+	})
+	return item, nil
+}
+
+func (s *adminService) UpdateDoctorProfileItem(ctx context.Context, doctorID, itemID uint64, section string,
+	input DoctorProfileItemInput,
+) (any, error) {
+	item, err := input.toModel(doctorID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repos.Admin.UpdateDoctorProfileItem(ctx, doctorID, itemID, item); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("doctor profile item not found", err)
+		}
+		return nil, NewInternalServerError("failed to update doctor profile item", err)
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditDoctorUpdate, EntityType: auditEntityDoctor, EntityID: doctorID,
+		After: map[string]any{section: item},
+	})
+	return item, nil
+}
+
+func (s *adminService) DeleteDoctorProfileItem(ctx context.Context, doctorID, itemID uint64, section string) error {
+	profileSection, ok := doctorProfileSections[section]
+	if !ok {
+		return NewNotFoundError("unknown doctor profile section: "+section, nil)
+	}
+	if err := s.repos.Admin.DeleteDoctorProfileItem(ctx, doctorID, itemID, profileSection.model()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewNotFoundError("doctor profile item not found", err)
+		}
+		return NewInternalServerError("failed to delete doctor profile item", err)
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditDoctorUpdate, EntityType: auditEntityDoctor, EntityID: doctorID,
+		Before: map[string]any{section: map[string]any{"id": itemID}},
+	})
+	return nil
+}
+
+// --- Аватар ---
+
+// UpdateSpecialistAvatar загружает новый аватар врача и удаляет из хранилища предыдущий.
+func (s *adminService) UpdateSpecialistAvatar(ctx context.Context, doctorID uint64, fileHeader *multipart.FileHeader) (
+	models.Avatar, error,
+) {
+	doctor, err := s.repos.Doctor.GetDoctorByID(ctx, doctorID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Avatar{}, NewNotFoundError("specialist not found", err)
+		}
+		return models.Avatar{}, NewInternalServerError("failed to get specialist", err)
+	}
+
+	avatar, err := uploadAvatar(ctx, s.storage, "doctors/"+strconv.FormatUint(doctorID, 10), fileHeader)
+	if err != nil {
+		return models.Avatar{}, err
+	}
+	if err := s.repos.Admin.UpdateDoctorAvatar(ctx, doctorID, avatar.URL); err != nil {
+		deleteAvatar(ctx, s.storage, avatar.URL)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Avatar{}, NewNotFoundError("specialist not found", err)
+		}
+		return models.Avatar{}, NewInternalServerError("failed to save specialist avatar", err)
+	}
+	deleteAvatar(ctx, s.storage, doctor.AvatarURL.String)
+
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditDoctorUpdate, EntityType: auditEntityDoctor, EntityID: doctorID,
+		Before: map[string]any{"avatarURL": doctor.AvatarURL.String}, After: map[string]any{"avatarURL": avatar.URL},
+	})
+	return avatar, nil
+}
+
+// DeleteSpecialistAvatar убирает аватар врача из профиля и из хранилища.
+func (s *adminService) DeleteSpecialistAvatar(ctx context.Context, doctorID uint64) error {
+	doctor, err := s.repos.Doctor.GetDoctorByID(ctx, doctorID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewNotFoundError("specialist not found", err)
+		}
+		return NewInternalServerError("failed to get specialist", err)
+	}
+	if !doctor.AvatarURL.Valid || doctor.AvatarURL.String == "" {
+		return nil
+	}
+
+	if err := s.repos.Admin.UpdateDoctorAvatar(ctx, doctorID, ""); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewNotFoundError("specialist not found", err)
+		}
+		return NewInternalServerError("failed to delete specialist avatar", err)
+	}
+	deleteAvatar(ctx, s.storage, doctor.AvatarURL.String)
+
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditDoctorUpdate, EntityType: auditEntityDoctor, EntityID: doctorID,
+		Before: map[string]any{"avatarURL": doctor.AvatarURL.String}, After: map[string]any{"avatarURL": nil},
+	})
+	return nil
+}
+
+// --- Клиники ---
+
+// SetSpecialistClinics заменяет набор клиник, в которых работает врач. Клинику нельзя снять,
+// пока в расписании врача есть дни приема в ней.
+func (s *adminService) SetSpecialistClinics(ctx context.Context, doctorID uint64, input SetDoctorClinicsInput) (
+	[]models.Clinic, error,
+) {
+	if _, err := s.repos.Doctor.GetDoctorByID(ctx, doctorID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("specialist not found", err)
+		}
+		return nil, NewInternalServerError("failed to get specialist", err)
+	}
+
+	clinicIDs := make([]uint64, 0, len(input.ClinicIDs))
+	seen := make(map[uint64]bool, len(input.ClinicIDs))
+	for _, id := range input.ClinicIDs {
+		if !seen[id] {
+			seen[id] = true
+			clinicIDs = append(clinicIDs, id)
+		}
+	}
+	clinics := []models.Clinic{}
+	if len(clinicIDs) > 0 {
+		found, err := s.repos.Admin.GetClinicsByIDs(ctx, clinicIDs)
+		if err != nil {
+			return nil, NewInternalServerError("failed to get clinics", err)
+		}
+		if len(found) != len(clinicIDs) {
+			return nil, NewBadRequestError("one or more clinics not found", nil)
+		}
+		clinics = found
+	}
+
+	before, err := s.repos.Doctor.GetDoctorClinics(ctx, doctorID)
+	if err != nil {
+		return nil, NewInternalServerError("failed to get specialist clinics", err)
+	}
+	if err := s.repos.Admin.SetDoctorClinics(ctx, doctorID, clinicIDs); err != nil {
+		if strings.Contains(err.Error(), "violates foreign key constraint") {
+			return nil, NewConflictError("specialist has schedule days in a clinic being removed", err)
+		}
+		return nil, NewInternalServerError("failed to update specialist clinics", err)
+	}
+
+	beforeIDs := make([]uint64, len(before))
+	for i, clinic := range before {
+		beforeIDs[i] = clinic.ID
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditDoctorUpdate, EntityType: auditEntityDoctor, EntityID: doctorID,
+		Before: map[string]any{"clinicIds": beforeIDs}, After: map[string]any{"clinicIds": clinicIDs},
+	})
+	return clinics, nil
+}
+
+// --- Сроки действия сертификатов ---
+
+// GetExpiringCertificates возвращает сертификаты, которые истекают в ближайшие days дней или уже истекли.
+// Если days не задан, используется период предупреждения из конфигурации.
+func (s *adminService) GetExpiringCertificates(ctx context.Context, days int, params models.PaginationParams) (
+	[]models.ExpiringCertificate, int64, error,
+) {
+	window := s.certificates.AlertBefore
+	if days > 0 {
+		window = time.Duration(days) * 24 * time.Hour
+	}
+	certificates, total, err := s.repos.Admin.GetExpiringCertificates(ctx, time.Now().Add(window), params)
+	if err != nil {
+		return nil, 0, NewInternalServerError("failed to get expiring certificates", err)
+	}
+	return certificates, total, nil
+}
+
+// RunCertificateAlertJob периодически предупреждает администраторов о сертификатах врачей,
+// срок действия которых скоро истекает. О каждом сертификате предупреждение делается один раз
+// (повторно - после изменения сертификата). Блокируется до отмены ctx.
+func (s *adminService) RunCertificateAlertJob(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		s.alertExpiringCertificates(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// alertExpiringCertificates записывает предупреждения в журнал аудита и в лог приложения
+// и отправляет сводное письмо администраторам с правом просмотра врачей.
+func (s *adminService) alertExpiringCertificates(ctx context.Context) {
+	certificates, err := s.repos.Admin.ClaimCertificateAlerts(ctx, time.Now().Add(s.certificates.AlertBefore))
+	if err != nil {
+		log.Printf("ERROR: failed to check expiring doctor certificates: %v", err)
+		return
+	}
+	for _, certificate := range certificates {
+		log.Printf("WARN: certificate %q of doctor %d (%s) expires on %s",
+			certificate.CertName, certificate.DoctorID, certificate.DoctorName,
+			certificate.ExpiresAt.Format("2006-01-02"))
+		s.audit.Record(ctx, auditEvent{
+			Action: AuditCertificateExpiring, EntityType: auditEntityCertificate, EntityID: certificate.ID,
+			After: certificate,
+		})
+	}
+	if len(certificates) > 0 {
+		s.mailCertificateAlerts(ctx, certificates)
+	}
+}
+
+// mailCertificateAlerts отправляет список истекающих сертификатов действующим администраторам
+// с правом doctors:read и указанным адресом. Ошибки отправки только логируются:
+// предупреждения уже записаны в журнал аудита.
+func (s *adminService) mailCertificateAlerts(ctx context.Context, certificates []models.ExpiringCertificate) {
+	admins, err := s.repos.Admin.GetActiveAdminsWithEmail(ctx)
+	if err != nil {
+		log.Printf("ERROR: failed to get admins for certificate alerts: %v", err)
+		return
+	}
+
+	var body strings.Builder
+	body.WriteString("Истекает или истек срок действия сертификатов врачей:\n\n")
+	for _, certificate := range certificates {
+		fmt.Fprintf(&body, "- %s: «%s»", certificate.DoctorName, certificate.CertName)
+		if certificate.CertNumber != "" {
+			fmt.Fprintf(&body, " № %s", certificate.CertNumber)
+		}
+		if certificate.Expired {
+			fmt.Fprintf(&body, ", истек %s\n", certificate.ExpiresAt.Format("02.01.2006"))
+		} else {
+			fmt.Fprintf(&body, ", действует до %s\n", certificate.ExpiresAt.Format("02.01.2006"))
+		}
+	}
+
+	for _, admin := range admins {
+		if !admin.Role.HasPermission(models.PermDoctorsRead) {
+			continue
+		}
+		err := s.mailer.Send(ctx, mail.Message{
+			To:      admin.Email.String,
+			Subject: "Истекают сертификаты врачей",
+			Body:    body.String(),
+		})
+		if err != nil {
+			log.Printf("ERROR: failed to send certificate alert to admin %d: %v", admin.ID, err)
+		}
+	}
+}
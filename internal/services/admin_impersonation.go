@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"lk/internal/models"
+	"lk/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// impersonationTokenTTL - срок жизни токена имперсонации; продлить его нельзя,
+// refresh-токен не выдается.
+const impersonationTokenTTL = 15 * time.Minute
+
+// ImpersonateUser выпускает короткоживущий пациентский access токен, помеченный
+// ID администратора (claim act). С таким токеном доступны только запросы на чтение,
+// а каждый запрос записывается в журнал имперсонации.
+func (s *adminService) ImpersonateUser(ctx context.Context, actor models.Admin, userID uint64) (
+	models.ImpersonationToken, error,
+) {
+	if _, err := s.repos.User.GetUserByID(ctx, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ImpersonationToken{}, NewNotFoundError("user not found", err)
+		}
+		return models.ImpersonationToken{}, NewInternalServerError("failed to get user by id", err)
+	}
+
+	scope := s.patientScope
+	scope.TTL = impersonationTokenTTL
+	accessToken, err := utils.GenerateToken(s.keys, scope, userID, utils.AccessClaims{
+		Actor: &utils.TokenActor{Subject: strconv.FormatUint(actor.ID, 10)},
+	})
+	if err != nil {
+		return models.ImpersonationToken{}, NewInternalServerError("failed to generate impersonation token", err)
+	}
+
+	log.Printf("INFO: admin %d (%s) started impersonation of user %d", actor.ID, actor.Login, userID)
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditUserImpersonate, EntityType: auditEntityUser, EntityID: userID, TargetUserID: userID,
+	})
+	return models.ImpersonationToken{
+		AccessToken: accessToken,
+		UserID:      userID,
+		ExpiresAt:   time.Now().Add(impersonationTokenTTL),
+		ReadOnly:    true,
+	}, nil
+}
+
+// RecordImpersonatedRequest записывает запрос, выполненный от имени пациента, в журнал.
+func (s *adminService) RecordImpersonatedRequest(ctx context.Context, entry models.ImpersonationLog) error {
+	if err := s.repos.Security.CreateImpersonationLog(ctx, entry); err != nil {
+		return NewInternalServerError("failed to save impersonation log", err)
+	}
+	return nil
+}
+
+// GetImpersonationLogs возвращает журнал запросов, выполненных администраторами от имени пациентов.
+func (s *adminService) GetImpersonationLogs(ctx context.Context, filter models.ImpersonationLogFilter,
+	params models.PaginationParams,
+) ([]models.ImpersonationLog, int64, error) {
+	entries, total, err := s.repos.Security.GetImpersonationLogs(ctx, filter, params)
+	if err != nil {
+		return nil, 0, NewInternalServerError("failed to get impersonation logs", err)
+	}
+	return entries, total, nil
+}
@@ -0,0 +1,274 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"strings"
+	"time"
+
+	"lk/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// legalDocumentMaxSize - максимальный размер PDF-файла юридического документа.
+	legalDocumentMaxSize = 20 << 20
+	// legalDocumentURL - публичный адрес действующей версии документа типа.
+	legalDocumentURL = "/api/v1/legal/documents/%s/file"
+)
+
+// pdfSignature - первые байты любого PDF-файла.
+var pdfSignature = []byte("%PDF-")
+
+// GetLegalDocumentTypes возвращает справочник типов юридических документов.
+func (s *adminService) GetLegalDocumentTypes(ctx context.Context) ([]models.LegalDocumentType, error) {
+	types, err := s.repos.Legal.GetTypes(ctx)
+	if err != nil {
+		return nil, NewInternalServerError("failed to get legal document types", err)
+	}
+	return types, nil
+}
+
+// GetLegalDocuments возвращает все версии юридических документов, включая черновики.
+func (s *adminService) GetLegalDocuments(ctx context.Context, filter models.LegalDocumentFilter,
+	params models.PaginationParams,
+) ([]models.LegalDocument, int64, error) {
+	docs, total, err := s.repos.Legal.GetAll(ctx, filter, params)
+	if err != nil {
+		return nil, 0, NewInternalServerError("failed to get legal documents", err)
+	}
+	return docs, total, nil
+}
+
+// GetLegalDocument возвращает версию юридического документа по ID.
+func (s *adminService) GetLegalDocument(ctx context.Context, id uint64) (models.LegalDocument, error) {
+	doc, err := s.repos.Legal.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.LegalDocument{}, NewNotFoundError("legal document not found", err)
+		}
+		return models.LegalDocument{}, NewInternalServerError("failed to get legal document", err)
+	}
+	return doc, nil
+}
+
+// CreateLegalDocument создает черновик новой версии документа с PDF-файлом.
+// Пациенты увидят версию только после публикации.
+func (s *adminService) CreateLegalDocument(ctx context.Context, actor models.Admin, input CreateLegalDocumentInput,
+	file *multipart.FileHeader,
+) (models.LegalDocument, error) {
+	if _, err := s.repos.Legal.GetTypeByCode(ctx, input.Type); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.LegalDocument{}, NewBadRequestError("unknown legal document type", err)
+		}
+		return models.LegalDocument{}, NewInternalServerError("failed to get legal document type", err)
+	}
+	updateDate, err := parseLegalDocumentDate(input.UpdateDate)
+	if err != nil {
+		return models.LegalDocument{}, err
+	}
+
+	fileKey, err := s.uploadLegalDocumentFile(ctx, input.Type, file)
+	if err != nil {
+		return models.LegalDocument{}, err
+	}
+
+	doc := models.LegalDocument{
+		Type:       input.Type,
+		Title:      strings.TrimSpace(input.Title),
+		URL:        fmt.Sprintf(legalDocumentURL, input.Type),
+		Version:    strings.TrimSpace(input.Version),
+		UpdateDate: updateDate,
+		Status:     models.LegalDocumentDraft,
+		FileKey:    fileKey,
+		FileName:   file.Filename,
+		FileSize:   file.Size,
+		CreatedBy:  sql.NullInt64{Int64: int64(actor.ID), Valid: true},
+	}
+	id, err := s.repos.Legal.Create(ctx, doc)
+	if err != nil {
+		s.deleteStoredFile(ctx, fileKey)
+		if strings.Contains(err.Error(), "duplicate key value") {
+			return models.LegalDocument{}, NewConflictError("this version of the document already exists", err)
+		}
+		return models.LegalDocument{}, NewInternalServerError("failed to create legal document", err)
+	}
+
+	created, err := s.GetLegalDocument(ctx, id)
+	if err != nil {
+		return models.LegalDocument{}, err
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditLegalDocumentCreate, EntityType: auditEntityLegalDocument, EntityID: id, After: created,
+	})
+	return created, nil
+}
+
+// UpdateLegalDocument изменяет черновик. Опубликованные версии неизменны - для правок
+// нужно создать новую версию. Если передан файл, он заменяет прежний.
+func (s *adminService) UpdateLegalDocument(ctx context.Context, id uint64, input UpdateLegalDocumentInput,
+	file *multipart.FileHeader,
+) (models.LegalDocument, error) {
+	doc, err := s.GetLegalDocument(ctx, id)
+	if err != nil {
+		return models.LegalDocument{}, err
+	}
+	if doc.Status != models.LegalDocumentDraft {
+		return models.LegalDocument{}, NewConflictError("published versions are immutable, create a new version", nil)
+	}
+	before := doc
+
+	if input.Title != nil {
+		doc.Title = strings.TrimSpace(*input.Title)
+	}
+	if input.Version != nil {
+		doc.Version = strings.TrimSpace(*input.Version)
+	}
+	if input.UpdateDate != nil {
+		if doc.UpdateDate, err = parseLegalDocumentDate(*input.UpdateDate); err != nil {
+			return models.LegalDocument{}, err
+		}
+	}
+	if file != nil {
+		if doc.FileKey, err = s.uploadLegalDocumentFile(ctx, doc.Type, file); err != nil {
+			return models.LegalDocument{}, err
+		}
+		doc.FileName, doc.FileSize = file.Filename, file.Size
+	}
+
+	if err := s.repos.Legal.UpdateDraft(ctx, doc); err != nil {
+		if file != nil {
+			s.deleteStoredFile(ctx, doc.FileKey)
+		}
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return models.LegalDocument{}, NewConflictError("published versions are immutable, create a new version", err)
+		case strings.Contains(err.Error(), "duplicate key value"):
+			return models.LegalDocument{}, NewConflictError("this version of the document already exists", err)
+		}
+		return models.LegalDocument{}, NewInternalServerError("failed to update legal document", err)
+	}
+	if file != nil && before.FileKey != "" {
+		s.deleteStoredFile(ctx, before.FileKey)
+	}
+
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditLegalDocumentUpdate, EntityType: auditEntityLegalDocument, EntityID: id, Before: before, After: doc,
+	})
+	return s.GetLegalDocument(ctx, id)
+}
+
+// PublishLegalDocument публикует черновик: версия становится действующей и больше не меняется.
+// Если тип документа обязательный, пациентам придется принять новую версию.
+func (s *adminService) PublishLegalDocument(ctx context.Context, id uint64) (models.LegalDocument, error) {
+	doc, err := s.GetLegalDocument(ctx, id)
+	if err != nil {
+		return models.LegalDocument{}, err
+	}
+	if doc.Status != models.LegalDocumentDraft {
+		return models.LegalDocument{}, NewConflictError("document version is already published", nil)
+	}
+	if doc.FileKey == "" {
+		return models.LegalDocument{}, NewBadRequestError("document file must be uploaded before publishing", nil)
+	}
+
+	if err := s.repos.Legal.Publish(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.LegalDocument{}, NewConflictError("document version is already published", err)
+		}
+		return models.LegalDocument{}, NewInternalServerError("failed to publish legal document", err)
+	}
+
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditLegalDocumentPublish, EntityType: auditEntityLegalDocument, EntityID: id,
+		After: map[string]any{"type": doc.Type, "version": doc.Version},
+	})
+	return s.GetLegalDocument(ctx, id)
+}
+
+// DeleteLegalDocument удаляет черновик вместе с файлом. Опубликованные версии не удаляются.
+func (s *adminService) DeleteLegalDocument(ctx context.Context, id uint64) error {
+	doc, err := s.GetLegalDocument(ctx, id)
+	if err != nil {
+		return err
+	}
+	if doc.Status != models.LegalDocumentDraft {
+		return NewConflictError("published versions cannot be deleted", nil)
+	}
+
+	if err := s.repos.Legal.DeleteDraft(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewConflictError("published versions cannot be deleted", err)
+		}
+		return NewInternalServerError("failed to delete legal document", err)
+	}
+	s.deleteStoredFile(ctx, doc.FileKey)
+
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditLegalDocumentDelete, EntityType: auditEntityLegalDocument, EntityID: id, Before: doc,
+	})
+	return nil
+}
+
+// DownloadLegalDocument возвращает PDF-файл любой версии документа, в том числе черновика.
+func (s *adminService) DownloadLegalDocument(ctx context.Context, id uint64) ([]byte, string, error) {
+	doc, err := s.GetLegalDocument(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	return readLegalDocumentFile(ctx, s.storage, doc)
+}
+
+// uploadLegalDocumentFile проверяет, что файл - PDF допустимого размера, и загружает его в хранилище.
+func (s *adminService) uploadLegalDocumentFile(ctx context.Context, docType string, fileHeader *multipart.FileHeader) (
+	string, error,
+) {
+	if fileHeader.Size > legalDocumentMaxSize {
+		return "", NewBadRequestError(fmt.Sprintf("file is too large (max %d MB)", legalDocumentMaxSize>>20), nil)
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return "", NewInternalServerError("failed to open document file", err)
+	}
+	defer file.Close()
+
+	// Проверяем содержимое, а не расширение или Content-Type от клиента
+	header := make([]byte, len(pdfSignature))
+	if _, err := io.ReadFull(file, header); err != nil || !bytes.Equal(header, pdfSignature) {
+		return "", NewBadRequestError("document file must be a PDF", err)
+	}
+
+	objectKey := fmt.Sprintf("legal/%s/%s.pdf", docType, uuid.New().String())
+	content := io.MultiReader(bytes.NewReader(header), file)
+	if err := s.storage.Upload(ctx, content, fileHeader.Size, "application/pdf", objectKey); err != nil {
+		return "", NewInternalServerError("failed to upload document file", err)
+	}
+	return objectKey, nil
+}
+
+// deleteStoredFile удаляет ненужный файл из хранилища. Ошибка только логируется:
+// осиротевший файл не мешает работе.
+func (s *adminService) deleteStoredFile(ctx context.Context, objectKey string) {
+	if objectKey == "" {
+		return
+	}
+	if err := s.storage.Delete(ctx, objectKey); err != nil {
+		log.Printf("WARN: failed to delete file %s from storage: %v", objectKey, err)
+	}
+}
+
+func parseLegalDocumentDate(value string) (time.Time, error) {
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, NewBadRequestError("invalid update date format (expected YYYY-MM-DD)", err)
+	}
+	return date, nil
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"lk/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// --- Модерация отзывов ---
+
+func (s *adminService) GetReviews(ctx context.Context, filter models.ReviewFilter, params models.PaginationParams) (
+	[]models.Review, int64, error,
+) {
+	switch filter.Status {
+	case "", models.ReviewPending, models.ReviewApproved, models.ReviewRejected:
+	default:
+		return nil, 0, NewBadRequestError("invalid review status: "+filter.Status, nil)
+	}
+	reviews, total, err := s.repos.Review.GetAll(ctx, filter, params)
+	if err != nil {
+		return nil, 0, NewInternalServerError("failed to get reviews", err)
+	}
+	return reviews, total, nil
+}
+
+// ApproveReview публикует отзыв и пересчитывает рейтинг врача.
+func (s *adminService) ApproveReview(ctx context.Context, actor models.Admin, reviewID uint64) (models.Review, error) {
+	return s.moderateReview(ctx, actor, reviewID, models.ReviewApproved, "")
+}
+
+// RejectReview отклоняет отзыв с указанием причины. Если отзыв был одобрен ранее,
+// он снимается с публикации, а рейтинг врача пересчитывается.
+func (s *adminService) RejectReview(ctx context.Context, actor models.Admin, reviewID uint64, reason string) (
+	models.Review, error,
+) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return models.Review{}, NewBadRequestError("rejection reason is required", nil)
+	}
+	return s.moderateReview(ctx, actor, reviewID, models.ReviewRejected, reason)
+}
+
+func (s *adminService) moderateReview(ctx context.Context, actor models.Admin, reviewID uint64,
+	status, reason string,
+) (models.Review, error) {
+	before, err := s.repos.Review.GetByID(ctx, reviewID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Review{}, NewNotFoundError("review not found", err)
+		}
+		return models.Review{}, NewInternalServerError("failed to get review", err)
+	}
+	if before.Status == status {
+		return models.Review{}, NewConflictError("review is already "+status, nil)
+	}
+
+	review, err := s.repos.Review.Moderate(ctx, reviewID, status, reason, actor.ID)
+	if err != nil {
+		return models.Review{}, NewInternalServerError("failed to moderate review", err)
+	}
+
+	action := AuditReviewApprove
+	if status == models.ReviewRejected {
+		action = AuditReviewReject
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: action, EntityType: auditEntityReview, EntityID: reviewID, TargetUserID: review.UserID,
+		Before: map[string]any{"status": before.Status}, After: map[string]any{"status": review.Status, "reason": reason},
+	})
+	return review, nil
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"lk/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// --- Услуги врачей ---
+
+// SetSpecialistServices заменяет набор услуг каталога, которые оказывает врач, вместе с ценой
+// и длительностью для него. Уже созданные записи сохраняют цену, по которой были оформлены.
+// Изменившиеся цены врача записываются в историю цен от имени actor.
+func (s *adminService) SetSpecialistServices(ctx context.Context, actor models.Admin, doctorID uint64,
+	input SetDoctorServicesInput,
+) ([]models.DoctorService, error) {
+	if _, err := s.repos.Doctor.GetDoctorByID(ctx, doctorID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("specialist not found", err)
+		}
+		return nil, NewInternalServerError("failed to get specialist", err)
+	}
+
+	assignments := make([]models.DoctorService, 0, len(input.Services))
+	serviceIDs := make([]uint64, 0, len(input.Services))
+	seen := make(map[uint64]bool, len(input.Services))
+	for _, item := range input.Services {
+		if seen[item.ServiceID] {
+			return nil, NewBadRequestError(fmt.Sprintf("service %d is listed more than once", item.ServiceID), nil)
+		}
+		seen[item.ServiceID] = true
+		serviceIDs = append(serviceIDs, item.ServiceID)
+		assignments = append(assignments, models.DoctorService{
+			DoctorID: doctorID, ServiceID: item.ServiceID, Price: item.Price, DurationMinutes: item.DurationMinutes,
+		})
+	}
+	if len(serviceIDs) > 0 {
+		found, err := s.repos.Admin.GetActiveServicesByIDs(ctx, serviceIDs)
+		if err != nil {
+			return nil, NewInternalServerError("failed to get services", err)
+		}
+		if len(found) != len(serviceIDs) {
+			return nil, NewBadRequestError("one or more services not found", nil)
+		}
+	}
+
+	before, err := s.repos.Admin.GetDoctorServiceAssignments(ctx, doctorID)
+	if err != nil {
+		return nil, NewInternalServerError("failed to get specialist services", err)
+	}
+	if err := s.repos.Admin.SetDoctorServices(ctx, doctorID, assignments, optionalID(actor.ID)); err != nil {
+		return nil, NewInternalServerError("failed to update specialist services", err)
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditDoctorUpdate, EntityType: auditEntityDoctor, EntityID: doctorID,
+		Before: map[string]any{"services": before}, After: map[string]any{"services": assignments},
+	})
+	return assignments, nil
+}
+
+// --- Категории услуг ---
+
+func (s *adminService) GetServiceCategories(ctx context.Context) ([]models.ServiceCategory, error) {
+	categories, err := s.repos.Admin.GetServiceCategories(ctx)
+	if err != nil {
+		return nil, NewInternalServerError("failed to get service categories", err)
+	}
+	return categories, nil
+}
+
+func (s *adminService) CreateServiceCategory(ctx context.Context, input ServiceCategoryInput) (
+	models.ServiceCategory, error,
+) {
+	category := models.ServiceCategory{Name: strings.TrimSpace(input.Name), DepartmentID: input.DepartmentID}
+	id, err := s.repos.Admin.CreateServiceCategory(ctx, category)
+	if err != nil {
+		return models.ServiceCategory{}, serviceCategorySaveError("create", err)
+	}
+	category.ID = id
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditServiceCategoryCreate, EntityType: auditEntityServiceCategory, EntityID: id,
+		After: category,
+	})
+	return category, nil
+}
+
+func (s *adminService) UpdateServiceCategory(ctx context.Context, categoryID uint32, input ServiceCategoryInput) (
+	models.ServiceCategory, error,
+) {
+	category := models.ServiceCategory{
+		ID: categoryID, Name: strings.TrimSpace(input.Name), DepartmentID: input.DepartmentID,
+	}
+	if err := s.repos.Admin.UpdateServiceCategory(ctx, category); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ServiceCategory{}, NewNotFoundError("service category not found", err)
+		}
+		return models.ServiceCategory{}, serviceCategorySaveError("update", err)
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditServiceCategoryUpdate, EntityType: auditEntityServiceCategory, EntityID: categoryID,
+		After: category,
+	})
+	return category, nil
+}
+
+// DeleteServiceCategory удаляет пустую категорию. Категорию с услугами, в том числе удаленными,
+// удалить нельзя: сначала услуги нужно перенести в другую категорию.
+func (s *adminService) DeleteServiceCategory(ctx context.Context, categoryID uint32) error {
+	if err := s.repos.Admin.DeleteServiceCategory(ctx, categoryID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewNotFoundError("service category not found", err)
+		}
+		if strings.Contains(err.Error(), "violates foreign key constraint") {
+			return NewConflictError("service category has services", err)
+		}
+		return NewInternalServerError("failed to delete service category", err)
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditServiceCategoryDelete, EntityType: auditEntityServiceCategory, EntityID: categoryID,
+	})
+	return nil
+}
+
+// serviceCategorySaveError переводит ошибку сохранения категории услуг в ошибку приложения.
+func serviceCategorySaveError(action string, err error) error {
+	if strings.Contains(err.Error(), "duplicate key value") {
+		return NewConflictError("service category with this name already exists in the department", err)
+	}
+	if strings.Contains(err.Error(), "violates foreign key constraint") {
+		return NewBadRequestError("department not found", err)
+	}
+	return NewInternalServerError("failed to "+action+" service category", err)
+}
@@ -0,0 +1,280 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"lk/internal/models"
+	"lk/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// adminRefreshTokenTTL - срок жизни сессии администратора без обновления токенов.
+const adminRefreshTokenTTL = 24 * time.Hour
+
+// issueAdminToken открывает новую сессию администратора, фиксирует время входа
+// и выдает пару токенов. Если администратор обязан сменить пароль, в ответ добавляется mustChangePassword.
+func (s *adminService) issueAdminToken(ctx context.Context, admin models.Admin, clientIP, userAgent string) (
+	map[string]string, error,
+) {
+	secret, secretHash, err := newSessionSecret()
+	if err != nil {
+		return nil, NewInternalServerError("failed to generate refresh token", err)
+	}
+
+	now := time.Now()
+	sessionID, err := s.repos.AdminSession.Create(ctx, models.AdminSession{
+		AdminID:    admin.ID,
+		TokenHash:  secretHash,
+		IPAddress:  clientIP,
+		UserAgent:  userAgent,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(adminRefreshTokenTTL),
+	})
+	if err != nil {
+		return nil, NewInternalServerError("failed to save admin session", err)
+	}
+
+	if err := s.repos.Admin.UpdateLastLogin(ctx, admin.ID); err != nil {
+		log.Printf("WARN: failed to update last login for admin %d: %v", admin.ID, err)
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditLogin, EntityType: auditEntityAdminSession, EntityID: sessionID,
+		Actor: &AuditActor{Type: models.AuditActorAdmin, ID: admin.ID, Name: admin.Login},
+	})
+
+	return s.adminTokenPair(admin, sessionID, secret)
+}
+
+// adminTokenPair подписывает access токен с привязкой к сессии и собирает ответ.
+func (s *adminService) adminTokenPair(admin models.Admin, sessionID uint64, secret string) (
+	map[string]string, error,
+) {
+	accessToken, err := utils.GenerateToken(s.keys, s.tokenScope, admin.ID, utils.AccessClaims{
+		Role:      string(admin.Role),
+		SessionID: sessionID,
+	})
+	if err != nil {
+		return nil, NewInternalServerError("failed to generate admin token", err)
+	}
+
+	result := map[string]string{
+		"accessToken":  accessToken,
+		"refreshToken": fmt.Sprintf("%d.%s", sessionID, secret),
+	}
+	if admin.MustChangePassword {
+		result["mustChangePassword"] = "true"
+	}
+	return result, nil
+}
+
+// ParseAdminToken проверяет токен админа и возвращает ID администратора и его сессии.
+// Токены пациентов отклоняются, так как у них другие издатель и аудитория;
+// токены завершенных сессий отклоняются сразу, не дожидаясь истечения срока.
+func (s *adminService) ParseAdminToken(ctx context.Context, accessToken string) (uint64, uint64, error) {
+	claims, err := utils.ParseToken(s.keys, s.tokenScope, accessToken)
+	if err != nil {
+		return 0, 0, NewUnauthorizedError("invalid admin token", err)
+	}
+
+	adminID, err := claims.SubjectID()
+	if err != nil {
+		return 0, 0, NewUnauthorizedError("invalid subject claim in admin token", err)
+	}
+	if claims.SessionID == 0 {
+		return 0, 0, NewUnauthorizedError("admin token is not bound to a session", nil)
+	}
+
+	session, err := s.repos.AdminSession.GetActive(ctx, claims.SessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, 0, NewUnauthorizedError("admin session has been terminated", nil)
+		}
+		return 0, 0, NewInternalServerError("failed to get admin session", err)
+	}
+	if session.AdminID != adminID {
+		return 0, 0, NewUnauthorizedError("admin session has been terminated", nil)
+	}
+
+	return adminID, session.ID, nil
+}
+
+// RefreshToken ротирует refresh-токен сессии и выдает новую пару токенов.
+// Повторное предъявление токена, замененного последней ротацией, считается признаком утечки:
+// сессия завершается целиком. Любой другой неверный секрет отклоняется без изменения сессии,
+// иначе перебором последовательных ID сессий можно было бы завершить чужие сессии.
+func (s *adminService) RefreshToken(ctx context.Context, refreshToken, clientIP, userAgent string) (
+	map[string]string, error,
+) {
+	sessionID, secret, err := parseSessionToken(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.repos.AdminSession.GetActive(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewUnauthorizedError("refresh token not found or expired", nil)
+		}
+		return nil, NewInternalServerError("failed to get admin session", err)
+	}
+
+	secretHash := hashSessionSecret(secret)
+	if subtle.ConstantTimeCompare([]byte(secretHash), []byte(session.TokenHash)) != 1 {
+		if session.PreviousTokenHash == "" ||
+			subtle.ConstantTimeCompare([]byte(secretHash), []byte(session.PreviousTokenHash)) != 1 {
+			return nil, NewUnauthorizedError("invalid refresh token", nil)
+		}
+		log.Printf("WARN: reuse of a rotated refresh token for admin %d, session %d revoked",
+			session.AdminID, session.ID)
+		if err := s.repos.AdminSession.Delete(ctx, session.AdminID, session.ID); err != nil &&
+			!errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("WARN: failed to revoke admin session %d: %v", session.ID, err)
+		}
+		return nil, NewUnauthorizedError("invalid refresh token", nil)
+	}
+
+	admin, err := s.repos.Admin.GetByID(ctx, session.AdminID)
+	if err != nil {
+		return nil, NewInternalServerError("failed to get admin", err)
+	}
+	if !admin.IsActive {
+		s.revokeAllSessions(ctx, admin.ID)
+		return nil, NewForbiddenError("admin account is disabled", nil)
+	}
+
+	newSecret, newHash, err := newSessionSecret()
+	if err != nil {
+		return nil, NewInternalServerError("failed to generate refresh token", err)
+	}
+	err = s.repos.AdminSession.Rotate(ctx, session.ID, session.TokenHash, newHash,
+		clientIP, userAgent, time.Now().Add(adminRefreshTokenTTL))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewUnauthorizedError("refresh token has already been used", nil)
+		}
+		return nil, NewInternalServerError("failed to rotate refresh token", err)
+	}
+
+	return s.adminTokenPair(admin, session.ID, newSecret)
+}
+
+// Logout завершает сессию, которой принадлежит refresh-токен.
+func (s *adminService) Logout(ctx context.Context, refreshToken string) error {
+	sessionID, secret, err := parseSessionToken(refreshToken)
+	if err != nil {
+		return err
+	}
+
+	session, err := s.repos.AdminSession.GetActive(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewUnauthorizedError("refresh token not found or expired", nil)
+		}
+		return NewInternalServerError("failed to get admin session", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(hashSessionSecret(secret)), []byte(session.TokenHash)) != 1 {
+		return NewUnauthorizedError("invalid refresh token", nil)
+	}
+
+	if err := s.repos.AdminSession.Delete(ctx, session.AdminID, session.ID); err != nil &&
+		!errors.Is(err, gorm.ErrRecordNotFound) {
+		return NewInternalServerError("failed to delete admin session", err)
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditLogout, EntityType: auditEntityAdminSession, EntityID: session.ID,
+		Actor: &AuditActor{Type: models.AuditActorAdmin, ID: session.AdminID},
+	})
+	return nil
+}
+
+// GetSessions возвращает активные сессии администратора; текущая сессия помечается флагом current.
+func (s *adminService) GetSessions(ctx context.Context, adminID, currentSessionID uint64) (
+	[]models.AdminSession, error,
+) {
+	sessions, err := s.repos.AdminSession.ListActive(ctx, adminID)
+	if err != nil {
+		return nil, NewInternalServerError("failed to get admin sessions", err)
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
+	return sessions, nil
+}
+
+// RevokeSession завершает одну сессию администратора.
+func (s *adminService) RevokeSession(ctx context.Context, adminID, sessionID uint64) error {
+	if err := s.repos.AdminSession.Delete(ctx, adminID, sessionID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewNotFoundError("session not found", err)
+		}
+		return NewInternalServerError("failed to delete admin session", err)
+	}
+	s.audit.Record(ctx, auditEvent{Action: AuditSessionRevoke, EntityType: auditEntityAdminSession, EntityID: sessionID})
+	return nil
+}
+
+// RevokeSessions завершает все сессии администратора, кроме exceptSessionID (0 - все),
+// и возвращает количество завершенных.
+func (s *adminService) RevokeSessions(ctx context.Context, adminID, exceptSessionID uint64) (int64, error) {
+	if _, err := s.GetAdminByID(ctx, adminID); err != nil {
+		return 0, err
+	}
+	count, err := s.repos.AdminSession.DeleteAll(ctx, adminID, exceptSessionID)
+	if err != nil {
+		return 0, NewInternalServerError("failed to delete admin sessions", err)
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditSessionRevoke, EntityType: auditEntityAdmin, EntityID: adminID,
+		After: map[string]any{"revoked": count},
+	})
+	return count, nil
+}
+
+// revokeAllSessions завершает все сессии администратора после смены учетных данных или блокировки.
+// Ошибка только логируется: основное действие уже выполнено.
+func (s *adminService) revokeAllSessions(ctx context.Context, adminID uint64) {
+	if _, err := s.repos.AdminSession.DeleteAll(ctx, adminID, 0); err != nil {
+		log.Printf("WARN: failed to revoke sessions of admin %d: %v", adminID, err)
+	}
+}
+
+// newSessionSecret генерирует секретную часть refresh-токена и ее хэш для хранения.
+func newSessionSecret() (string, string, error) {
+	randomBytes := make([]byte, 32)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", "", err
+	}
+	secret := base64.RawURLEncoding.EncodeToString(randomBytes)
+	return secret, hashSessionSecret(secret), nil
+}
+
+// hashSessionSecret возвращает sha256-хэш секрета в hex.
+func hashSessionSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// parseSessionToken разбирает refresh-токен администратора вида "<sessionID>.<secret>".
+func parseSessionToken(refreshToken string) (uint64, string, error) {
+	sessionIDStr, secret, ok := strings.Cut(refreshToken, ".")
+	if !ok || secret == "" {
+		return 0, "", NewUnauthorizedError("invalid refresh token format", nil)
+	}
+	sessionID, err := strconv.ParseUint(sessionIDStr, 10, 64)
+	if err != nil || sessionID == 0 {
+		return 0, "", NewUnauthorizedError("invalid session id in refresh token", err)
+	}
+	return sessionID, secret, nil
+}
@@ -3,38 +3,54 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
+	"slices"
 	"time"
 
 	"lk/internal/models"
 	"lk/internal/repository"
+	"lk/internal/sms"
 
 	"gorm.io/gorm"
 )
 
 // Определяем ошибки как переменные для возможности их проверки через errors.Is
 var (
-	ErrNoSchedule       = errors.New("doctor has no schedule for the selected date")
-	ErrNoAvailableSlots = errors.New("no available slots for the selected date")
+	ErrNoSchedule         = errors.New("doctor has no schedule for the selected date")
+	ErrNoAvailableSlots   = errors.New("no available slots for the selected date")
+	ErrServiceNotProvided = errors.New("doctor does not provide the selected service")
 )
 
 // appointmentService реализует интерфейс AppointmentService.
 type appointmentService struct {
 	repo       repository.AppointmentRepository
 	doctorRepo repository.DoctorRepository
+	cacheRepo  repository.CacheRepository
+	smsSender  sms.Sender
 	location   *time.Location
+	settings   *clinicSettingsStore
+	audit      *auditor
 }
 
 // NewAppointmentService создает новый сервис для управления записями на прием.
 func NewAppointmentService(
 	repo repository.AppointmentRepository,
 	doctorRepo repository.DoctorRepository,
+	cacheRepo repository.CacheRepository,
+	smsSender sms.Sender,
 	location *time.Location,
+	settings *clinicSettingsStore,
+	audit *auditor,
 ) AppointmentService {
 	return &appointmentService{
 		repo:       repo,
 		doctorRepo: doctorRepo,
+		cacheRepo:  cacheRepo,
+		smsSender:  smsSender,
 		location:   location,
+		settings:   settings,
+		audit:      audit,
 	}
 }
 
@@ -48,12 +64,52 @@ func (s *appointmentService) CreateAppointment(ctx context.Context, appointment
 		return 0, NewInternalServerError("failed to check doctor existence", err)
 	}
 
+	// Цена фиксируется по услуге врача на момент записи и не зависит от последующих изменений прайса
+	service, err := s.doctorRepo.GetDoctorService(ctx, appointment.DoctorID, appointment.ServiceID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, NewBadRequestError(ErrServiceNotProvided.Error(), err)
+		}
+		return 0, NewInternalServerError("failed to get doctor service", err)
+	}
+	appointment.PriceAtBooking = service.Price
+
+	date := appointment.AppointmentDate
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	if day.After(s.bookingHorizon(s.settings.Current(ctx))) {
+		return 0, NewBadRequestError("appointment date is beyond the booking horizon", nil)
+	}
+
+	// Клиника определяется интервалом расписания врача, в который попадает время записи, а не клиентом
+	schedules, err := s.repo.GetDoctorSchedulesForDate(ctx, appointment.DoctorID, 0, day)
+	if err != nil {
+		return 0, NewInternalServerError("could not get doctor schedule", err)
+	}
+	if len(schedules) == 0 {
+		return 0, NewBadRequestError(ErrNoSchedule.Error(), nil)
+	}
+	start, err := s.appointmentStart(appointment)
+	if err != nil {
+		return 0, NewBadRequestError("invalid appointment time format, expected HH:MM", err)
+	}
+	schedule, ok := scheduleAt(schedules, start)
+	if !ok {
+		return 0, NewBadRequestError("appointment time is outside the doctor's working hours", nil)
+	}
+	clinicID, err := s.scheduleClinic(ctx, schedule)
+	if err != nil {
+		return 0, err
+	}
+	appointment.ClinicID = clinicID
+
 	// TODO: Добавить оставшуюся бизнес-логику перед созданием записи:
 	// - Проверить, свободен ли врач в это время (самое важное).
-	// - Проверить, существует ли такой serviceID, clinicID.
 
 	id, err := s.repo.CreateAppointment(ctx, appointment)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, NewBadRequestError(ErrServiceNotProvided.Error(), err)
+		}
 		return 0, NewInternalServerError("failed to create appointment", err)
 	}
 
@@ -62,6 +118,35 @@ func (s *appointmentService) CreateAppointment(ctx context.Context, appointment
 	return id, nil
 }
 
+// scheduleAt возвращает интервал расписания, в который попадает начало приема: start_time <= t < end_time.
+func scheduleAt(schedules []models.Schedule, t time.Time) (models.Schedule, bool) {
+	minute := t.Hour()*60 + t.Minute()
+	for _, schedule := range schedules {
+		from := schedule.StartTime.Hour()*60 + schedule.StartTime.Minute()
+		to := schedule.EndTime.Hour()*60 + schedule.EndTime.Minute()
+		if from <= minute && minute < to {
+			return schedule, true
+		}
+	}
+	return models.Schedule{}, false
+}
+
+// scheduleClinic возвращает клинику, в которой врач принимает по этому расписанию.
+// Расписание без клиники (созданное до назначения врачей в клиники) относится к единственной клинике врача.
+func (s *appointmentService) scheduleClinic(ctx context.Context, schedule models.Schedule) (uint64, error) {
+	if schedule.ClinicID != nil {
+		return *schedule.ClinicID, nil
+	}
+	clinics, err := s.doctorRepo.GetDoctorClinics(ctx, schedule.DoctorID)
+	if err != nil {
+		return 0, NewInternalServerError("failed to get doctor clinics", err)
+	}
+	if len(clinics) != 1 {
+		return 0, NewConflictError("clinic is not set in the doctor's schedule for the selected date", nil)
+	}
+	return clinics[0].ID, nil
+}
+
 // GetUserAppointments возвращает все записи пользователя.
 func (s *appointmentService) GetUserAppointments(ctx context.Context, userID uint64) ([]models.Appointment, error) {
 	appointments, err := s.repo.GetAppointmentsByUserID(ctx, userID)
@@ -84,15 +169,31 @@ func (s *appointmentService) CancelAppointment(ctx context.Context, userID, appo
 	if appointment.UserID != userID {
 		return NewForbiddenError("user does not have permission for this action", nil)
 	}
+	if window := s.settings.Current(ctx).CancellationWindowHours; window > 0 {
+		start, err := s.appointmentStart(appointment)
+		if err != nil {
+			return NewInternalServerError("failed to parse appointment time", err)
+		}
+		if time.Until(start) < time.Duration(window)*time.Hour {
+			return NewConflictError(fmt.Sprintf(
+				"appointment can be cancelled online no later than %d hours before it starts", window), nil)
+		}
+	}
 
 	if err := s.repo.UpdateAppointmentStatus(ctx, appointmentID, models.StatusCancelledByPatient); err != nil {
 		return NewInternalServerError("failed to update appointment status", err)
 	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditAppointmentCancel, EntityType: auditEntityAppointment, EntityID: appointmentID, TargetUserID: userID,
+		Before: map[string]any{"statusId": appointment.StatusID},
+		After:  map[string]any{"statusId": models.StatusCancelledByPatient},
+	})
 	return nil
 }
 
-// GetAvailableDates получает доступные для записи даты в месяце.
-func (s *appointmentService) GetAvailableDates(ctx context.Context, doctorID, serviceID uint64, monthStr string) (
+// GetAvailableDates получает доступные для записи даты в месяце. Ненулевой clinicID оставляет
+// только дни, в которые врач принимает в этой клинике.
+func (s *appointmentService) GetAvailableDates(ctx context.Context, doctorID, serviceID, clinicID uint64, monthStr string) (
 	models.AvailableDatesResponse, error,
 ) {
 	month, err := time.Parse("2006-01", monthStr)
@@ -101,14 +202,18 @@ func (s *appointmentService) GetAvailableDates(ctx context.Context, doctorID, se
 			NewBadRequestError("invalid month format, expected YYYY-MM", err)
 	}
 
-	dates, err := s.repo.GetAvailableDatesForMonth(ctx, doctorID, month)
+	dates, err := s.repo.GetAvailableDatesForMonth(ctx, doctorID, clinicID, month)
 	if err != nil {
 		return models.AvailableDatesResponse{}, NewInternalServerError("failed to get available dates", err)
 	}
 
-	stringDates := make([]string, len(dates))
-	for i, d := range dates {
-		stringDates[i] = d.Format("2006-01-02")
+	horizon := s.bookingHorizon(s.settings.Current(ctx))
+	stringDates := make([]string, 0, len(dates))
+	for _, d := range dates {
+		if d.After(horizon) {
+			break
+		}
+		stringDates = append(stringDates, d.Format("2006-01-02"))
 	}
 
 	return models.AvailableDatesResponse{
@@ -118,8 +223,10 @@ func (s *appointmentService) GetAvailableDates(ctx context.Context, doctorID, se
 	}, nil
 }
 
-// GetAvailableSlots получает доступные временные слоты на конкретную дату.
-func (s *appointmentService) GetAvailableSlots(ctx context.Context, doctorID, serviceID uint64, dateStr string) (
+// GetAvailableSlots получает доступные временные слоты на конкретную дату по всем интервалам приема врача.
+// Если врач в этот день принимает не в клинике clinicID, слотов нет. Слоты каждого интервала вместе
+// с его клиникой возвращаются в Intervals; ClinicID заполняется, если все интервалы в одной клинике.
+func (s *appointmentService) GetAvailableSlots(ctx context.Context, doctorID, serviceID, clinicID uint64, dateStr string) (
 	models.AvailableSlotsResponse, error,
 ) {
 	date, err := time.Parse("2006-01-02", dateStr)
@@ -128,47 +235,74 @@ func (s *appointmentService) GetAvailableSlots(ctx context.Context, doctorID, se
 			NewBadRequestError("invalid date format, expected YYYY-MM-DD", err)
 	}
 
+	settings := s.settings.Current(ctx)
+	if date.After(s.bookingHorizon(settings)) {
+		return models.AvailableSlotsResponse{ // Запись на эту дату еще не открыта
+			SpecialistID:   doctorID,
+			Date:           dateStr,
+			AvailableSlots: []string{},
+		}, nil
+	}
+
 	// 1. Получаем данные из БД для передачи в калькулятор
-	schedule, err := s.repo.GetDoctorScheduleForDate(ctx, doctorID, date)
+	schedules, err := s.repo.GetDoctorSchedulesForDate(ctx, doctorID, clinicID, date)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return models.AvailableSlotsResponse{ // Успешный пустой ответ
-				SpecialistID:   doctorID,
-				Date:           dateStr,
-				AvailableSlots: []string{},
-			}, nil
-		}
 		return models.AvailableSlotsResponse{}, NewInternalServerError("could not get doctor schedule", err)
 	}
+	response := models.AvailableSlotsResponse{ // Без интервалов - успешный пустой ответ
+		SpecialistID:   doctorID,
+		Date:           dateStr,
+		AvailableSlots: []string{},
+	}
+	if len(schedules) == 0 {
+		return response, nil
+	}
 
 	existingAppointments, err := s.repo.GetAppointmentsByDoctorAndDate(ctx, doctorID, date)
 	if err != nil {
 		return models.AvailableSlotsResponse{}, NewInternalServerError("could not get existing appointments", err)
 	}
 
-	// 2. Вызываем калькулятор с полученными данными
-	slots, err := s.calculateAvailableSlots(ctx, serviceID, date, &schedule, existingAppointments)
-	if err != nil {
-		if errors.Is(err, ErrNoAvailableSlots) {
-			return models.AvailableSlotsResponse{ // Успешный пустой ответ
-				SpecialistID:   doctorID,
-				Date:           dateStr,
-				AvailableSlots: []string{},
-			}, nil
+	// 2. Вызываем калькулятор для каждого интервала приема
+	sameClinic := true
+	for i := range schedules {
+		slots, err := s.calculateAvailableSlots(ctx, serviceID, date, &schedules[i], existingAppointments,
+			settings.SlotGranularityMinutes)
+		if err != nil {
+			if errors.Is(err, ErrNoAvailableSlots) {
+				continue
+			}
+			return models.AvailableSlotsResponse{}, err // Пробрасываем другие ошибки (например, Internal)
 		}
-		return models.AvailableSlotsResponse{}, err // Пробрасываем другие ошибки (например, Internal)
+		if len(response.Intervals) > 0 && !sameClinicID(response.Intervals[0].ClinicID, schedules[i].ClinicID) {
+			sameClinic = false
+		}
+		response.Intervals = append(response.Intervals, models.SlotsForDay{
+			Date:           dateStr,
+			ClinicID:       schedules[i].ClinicID,
+			AvailableSlots: slots,
+		})
+		response.AvailableSlots = append(response.AvailableSlots, slots...)
+	}
+	slices.Sort(response.AvailableSlots)
+	response.AvailableSlots = slices.Compact(response.AvailableSlots)
+	if len(response.Intervals) > 0 && sameClinic {
+		response.ClinicID = response.Intervals[0].ClinicID
 	}
+	return response, nil
+}
 
-	return models.AvailableSlotsResponse{
-		SpecialistID:   doctorID,
-		Date:           dateStr,
-		AvailableSlots: slots,
-	}, nil
+// sameClinicID сообщает, относятся ли два интервала расписания к одной клинике.
+func sameClinicID(a, b *uint64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
 }
 
-// GetAvailableSlotsByRange получает доступные слоты в диапазоне дат.
+// GetAvailableSlotsByRange получает доступные слоты в диапазоне дат, при ненулевом clinicID - только в этой клинике.
 func (s *appointmentService) GetAvailableSlotsByRange(
-	ctx context.Context, doctorID, serviceID uint64, startDateStr, endDateStr string) (
+	ctx context.Context, doctorID, serviceID, clinicID uint64, startDateStr, endDateStr string) (
 	models.AvailableRangeSlotsResponse, error,
 ) {
 	startDate, err := time.Parse("2006-01-02", startDateStr)
@@ -182,8 +316,13 @@ func (s *appointmentService) GetAvailableSlotsByRange(
 	if startDate.After(endDate) {
 		return models.AvailableRangeSlotsResponse{}, NewBadRequestError("start date cannot be after end date", nil)
 	}
+	// Дни за горизонтом записи не показываются
+	settings := s.settings.Current(ctx)
+	if horizon := s.bookingHorizon(settings); endDate.After(horizon) {
+		endDate = horizon
+	}
 
-	schedules, err := s.repo.GetDoctorScheduleForDateRange(ctx, doctorID, startDate, endDate)
+	schedules, err := s.repo.GetDoctorScheduleForDateRange(ctx, doctorID, clinicID, startDate, endDate)
 	if err != nil {
 		return models.AvailableRangeSlotsResponse{}, NewInternalServerError("could not get schedules for range", err)
 	}
@@ -205,7 +344,7 @@ func (s *appointmentService) GetAvailableSlotsByRange(
 		day := time.Date(schedule.Date.Year(), schedule.Date.Month(), schedule.Date.Day(),
 			0, 0, 0, 0, time.UTC)
 		slots, err := s.calculateAvailableSlots(
-			ctx, serviceID, day, &schedule, appointmentsByDate[day])
+			ctx, serviceID, day, &schedule, appointmentsByDate[day], settings.SlotGranularityMinutes)
 		if err != nil && !errors.Is(err, ErrNoAvailableSlots) {
 			log.Printf(
 				"WARN: could not calculate slots for date %s: %v", day.Format("2006-01-02"), err)
@@ -215,6 +354,7 @@ func (s *appointmentService) GetAvailableSlotsByRange(
 		if len(slots) > 0 {
 			slotsByDay = append(slotsByDay, models.SlotsForDay{
 				Date:           day.Format("2006-01-02"),
+				ClinicID:       schedule.ClinicID,
 				AvailableSlots: slots,
 			})
 		}
@@ -236,20 +376,48 @@ func (s *appointmentService) GetUpcomingForUser(ctx context.Context, userID uint
 	return appointments, nil
 }
 
-// calculateAvailableSlots инкапсулирует логику расчета слотов.
+// bookingHorizon возвращает последний день, на который открыта запись, в том же виде,
+// что и даты расписания (полночь UTC).
+func (s *appointmentService) bookingHorizon(settings models.ClinicSettings) time.Time {
+	now := time.Now().In(s.location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return today.AddDate(0, 0, settings.BookingHorizonDays)
+}
+
+// appointmentStart возвращает время начала приема в часовом поясе клиники.
+func (s *appointmentService) appointmentStart(appointment models.Appointment) (time.Time, error) {
+	clock, err := time.Parse("15:04:05", appointment.AppointmentTime)
+	if err != nil {
+		if clock, err = time.Parse("15:04", appointment.AppointmentTime); err != nil {
+			return time.Time{}, err
+		}
+	}
+	date := appointment.AppointmentDate
+	return time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), 0, 0, s.location), nil
+}
+
+// calculateAvailableSlots инкапсулирует логику расчета слотов. Слоты длятся столько же, сколько услуга,
+// и начинаются с шагом stepMinutes (0 - шаг равен длительности услуги).
 func (s *appointmentService) calculateAvailableSlots(
 	ctx context.Context, serviceID uint64, date time.Time,
-	schedule *models.Schedule, existingAppointments []models.Appointment,
+	schedule *models.Schedule, existingAppointments []models.Appointment, stepMinutes int,
 ) ([]string, error) {
 	if schedule == nil {
 		return nil, ErrNoSchedule
 	}
 
-	requestedServiceDuration, err := s.repo.GetServiceDurationMinutes(ctx, serviceID)
+	requestedService, err := s.doctorRepo.GetDoctorService(ctx, schedule.DoctorID, serviceID)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewBadRequestError(ErrServiceNotProvided.Error(), err)
+		}
 		return nil, NewInternalServerError("could not get service duration", err)
 	}
-	slotStep := time.Duration(requestedServiceDuration) * time.Minute
+	slotLength := time.Duration(requestedService.DurationMinutes) * time.Minute
+	slotStep := slotLength
+	if stepMinutes > 0 {
+		slotStep = time.Duration(stepMinutes) * time.Minute
+	}
 
 	var existingServiceIDs []uint64
 	if len(existingAppointments) > 0 {
@@ -262,7 +430,7 @@ func (s *appointmentService) calculateAvailableSlots(
 		}
 	}
 
-	existingServices, err := s.repo.GetServicesByIDs(ctx, existingServiceIDs)
+	existingServices, err := s.repo.GetServicesByIDs(ctx, schedule.DoctorID, existingServiceIDs)
 	if err != nil {
 		return nil, NewInternalServerError("could not get existing services info", err)
 	}
@@ -278,9 +446,9 @@ func (s *appointmentService) calculateAvailableSlots(
 	endTime := time.Date(date.Year(), date.Month(), date.Day(), schedule.EndTime.Hour(),
 		schedule.EndTime.Minute(), 0, 0, s.location)
 
-	for slotStart := startTime; slotStart.Add(slotStep).Before(endTime) ||
-		slotStart.Add(slotStep).Equal(endTime); slotStart = slotStart.Add(slotStep) {
-		slotEnd := slotStart.Add(slotStep)
+	for slotStart := startTime; slotStart.Add(slotLength).Before(endTime) ||
+		slotStart.Add(slotLength).Equal(endTime); slotStart = slotStart.Add(slotStep) {
+		slotEnd := slotStart.Add(slotLength)
 		isAvailable := true
 
 		for _, app := range existingAppointments {
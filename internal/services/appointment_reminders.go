@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// appointmentReminderPrefix - ключ кэша отправленного напоминания (по записи и смещению),
+// чтобы пересекающиеся окна соседних проходов не дублировали SMS.
+const appointmentReminderPrefix = "appointment_reminder:"
+
+// RunReminderJob периодически отправляет пациентам SMS-напоминания о предстоящих приемах
+// за reminderOffsetsMinutes из настроек клиники. Блокируется до отмены ctx.
+func (s *appointmentService) RunReminderJob(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		s.sendReminders(ctx, interval)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendReminders выполняет один проход. Для каждого смещения выбираются приемы, начинающиеся
+// через это время с запасом в два интервала на случай задержки прохода. Приемы, записанные
+// позже момента напоминания, его не получают: за сутки не напоминают о приеме через час.
+func (s *appointmentService) sendReminders(ctx context.Context, interval time.Duration) {
+	now := time.Now().In(s.location)
+	lookback := 2 * interval
+	for _, offset := range s.settings.Current(ctx).ReminderOffsetsMinutes {
+		to := now.Add(time.Duration(offset) * time.Minute)
+		reminders, err := s.repo.GetAppointmentsForReminder(ctx, to.Add(-lookback), to)
+		if err != nil {
+			log.Printf("ERROR: failed to get appointments for %d-minute reminders: %v", offset, err)
+			continue
+		}
+
+		for _, reminder := range reminders {
+			key := fmt.Sprintf("%s%d:%d", appointmentReminderPrefix, reminder.ID, offset)
+			fresh, err := s.cacheRepo.SetIfAbsent(ctx, key, 1, 2*lookback)
+			if err != nil {
+				log.Printf("ERROR: failed to mark reminder for appointment %d: %v", reminder.ID, err)
+				continue
+			}
+			if !fresh {
+				continue
+			}
+
+			text := fmt.Sprintf("Напоминаем о приеме %s в %s: %s, %s",
+				reminder.AppointmentDate.Format("02.01.2006"), reminderClock(reminder.AppointmentTime),
+				reminder.DoctorName, reminder.ClinicAddress)
+			if err := s.smsSender.Send(ctx, reminder.Phone, text); err != nil {
+				log.Printf("ERROR: failed to send reminder for appointment %d: %v", reminder.ID, err)
+				// Следующий проход попробует снова, пока прием остается в окне
+				_ = s.cacheRepo.Delete(ctx, key)
+			}
+		}
+	}
+}
+
+// reminderClock сокращает время приема до часов и минут.
+func reminderClock(clock string) string {
+	if len(clock) > len("15:04") {
+		return clock[:len("15:04")]
+	}
+	return clock
+}
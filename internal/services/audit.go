@@ -0,0 +1,439 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"lk/internal/models"
+	"lk/internal/repository"
+)
+
+// Действия, фиксируемые в журнале аудита. Имя состоит из сущности и операции.
+const (
+	AuditUserRegister        = "user.register"
+	AuditUserUpdate          = "user.update"
+	AuditUserDelete          = "user.delete"
+	AuditUserImpersonate     = "user.impersonate"
+	AuditProfileUpdate       = "profile.update"
+	AuditLogin               = "auth.login"
+	AuditLoginFailed         = "auth.login_failed"
+	AuditLogout              = "auth.logout"
+	AuditPhoneVerified       = "auth.phone_verified"
+	AuditEmailVerified       = "auth.email_verified"
+	AuditPasswordReset       = "auth.password_reset"
+	AuditPasswordChange      = "auth.password_change"
+	AuditSessionRevoke       = "auth.session_revoke"
+	AuditDoctorCreate        = "doctor.create"
+	AuditDoctorUpdate        = "doctor.update"
+	AuditDoctorDelete        = "doctor.delete"
+	AuditScheduleUpdate      = "schedule.update"
+	AuditAppointmentStatus   = "appointment.status_change"
+	AuditAppointmentCancel   = "appointment.cancel"
+	AuditAppointmentDelete   = "appointment.delete"
+	AuditServiceCreate       = "service.create"
+	AuditServiceUpdate       = "service.update"
+	AuditServiceDelete       = "service.delete"
+	AuditDepartmentCreate    = "department.create"
+	AuditDepartmentUpdate    = "department.update"
+	AuditDepartmentDelete    = "department.delete"
+	AuditAdminCreate         = "admin.create"
+	AuditAdminUpdate         = "admin.update"
+	AuditAdminRoleChange     = "admin.role_change"
+	AuditAdminActivate       = "admin.activate"
+	AuditAdminDeactivate     = "admin.deactivate"
+	AuditAdminPasswordReset  = "admin.password_reset"
+	AuditAdminTwoFactorReset = "admin.2fa_reset"
+	AuditSecuritySettings    = "security.settings_update"
+	AuditSecurityUnlock      = "security.unlock"
+	AuditMedicalDataRead     = "medical_data.read"
+	AuditMedicalFileDownload = "medical_data.download"
+	AuditPrescriptionArchive = "prescription.archive"
+	AuditConsentAccept       = "consent.accept"
+	AuditConsentRevoke       = "consent.revoke"
+
+	AuditLegalDocumentCreate  = "legal_document.create"
+	AuditLegalDocumentUpdate  = "legal_document.update"
+	AuditLegalDocumentPublish = "legal_document.publish"
+	AuditLegalDocumentDelete  = "legal_document.delete"
+
+	AuditAccountExportRequest   = "account.export_request"
+	AuditAccountExportReady     = "account.export_ready"
+	AuditAccountExportDownload  = "account.export_download"
+	AuditAccountDeletionRequest = "account.deletion_request"
+	AuditAccountDeletionCancel  = "account.deletion_cancel"
+	AuditAccountAnonymize       = "account.anonymize"
+
+	AuditUserRestore        = "user.restore"
+	AuditDoctorRestore      = "doctor.restore"
+	AuditAppointmentRestore = "appointment.restore"
+	AuditServiceRestore     = "service.restore"
+	AuditDepartmentRestore  = "department.restore"
+	AuditDeletedPurge       = "deleted.purge"
+
+	AuditReviewCreate  = "review.create"
+	AuditReviewApprove = "review.approve"
+	AuditReviewReject  = "review.reject"
+
+	AuditCertificateExpiring = "certificate.expiring"
+
+	AuditClinicCreate  = "clinic.create"
+	AuditClinicUpdate  = "clinic.update"
+	AuditClinicDelete  = "clinic.delete"
+	AuditClinicRestore = "clinic.restore"
+
+	AuditClinicSettingsUpdate = "clinic_settings.update"
+
+	AuditServiceCategoryCreate = "service_category.create"
+	AuditServiceCategoryUpdate = "service_category.update"
+	AuditServiceCategoryDelete = "service_category.delete"
+)
+
+// Типы сущностей в журнале аудита.
+const (
+	auditEntityMedicalCard     = "medical_card"
+	auditEntityUser            = "user"
+	auditEntityAdmin           = "admin"
+	auditEntityAdminSession    = "admin_session"
+	auditEntityDoctor          = "doctor"
+	auditEntitySchedule        = "schedule"
+	auditEntityAppointment     = "appointment"
+	auditEntityService         = "service"
+	auditEntityDepartment      = "department"
+	auditEntitySecurity        = "security_settings"
+	auditEntityLockout         = "lockout"
+	auditEntityLabAnalysis     = "lab_analysis"
+	auditEntityPrescription    = "prescription"
+	auditEntityPatientSession  = "patient_session"
+	auditEntityConsent         = "user_consent"
+	auditEntityLegalDocument   = "legal_document"
+	auditEntityDataExport      = "data_export"
+	auditEntityUserDeletion    = "account_deletion"
+	auditEntityRetention       = "retention"
+	auditEntityReview          = "review"
+	auditEntityCertificate     = "doctor_certificate"
+	auditEntityClinic          = "clinic"
+	auditEntityClinicSettings  = "clinic_settings"
+	auditEntityServiceCategory = "service_category"
+)
+
+// auditCSVHeader - заголовок CSV-выгрузки журнала аудита.
+var auditCSVHeader = []string{
+	"id", "created_at", "actor_type", "actor_id", "actor_name", "impersonator_id",
+	"action", "entity_type", "entity_id", "target_user_id", "ip_address", "request_id", "changes",
+}
+
+type requestMetaKey struct{}
+
+type auditActorKey struct{}
+
+// RequestMeta - сведения о HTTP-запросе, которые попадают в журнал аудита.
+// UserAgent сохраняется только там, где он нужен как доказательство (например, при принятии согласий).
+type RequestMeta struct {
+	RequestID string
+	IPAddress string
+	UserAgent string
+}
+
+// AuditActor - действующее лицо запроса (администратор или пациент).
+// ImpersonatorID заполняется, если пациентский запрос выполняет администратор от его имени.
+type AuditActor struct {
+	Type           string
+	ID             uint64
+	Name           string
+	ImpersonatorID uint64
+}
+
+// WithRequestMeta сохраняет сведения о запросе в контекст.
+func WithRequestMeta(ctx context.Context, meta RequestMeta) context.Context {
+	return context.WithValue(ctx, requestMetaKey{}, meta)
+}
+
+// WithAuditActor сохраняет в контекст действующее лицо запроса.
+func WithAuditActor(ctx context.Context, actor AuditActor) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+func requestMetaFrom(ctx context.Context) RequestMeta {
+	meta, _ := ctx.Value(requestMetaKey{}).(RequestMeta)
+	return meta
+}
+
+func auditActorFrom(ctx context.Context) (AuditActor, bool) {
+	actor, ok := ctx.Value(auditActorKey{}).(AuditActor)
+	return actor, ok
+}
+
+// auditEvent описывает одно событие для журнала аудита.
+// Before и After - состояния сущности до и после; в журнал попадает только разница.
+// Actor задается явно для событий без аутентифицированного пользователя (например, вход).
+type auditEvent struct {
+	Action       string
+	EntityType   string
+	EntityID     any
+	TargetUserID uint64
+	Actor        *AuditActor
+	Before       any
+	After        any
+}
+
+// auditor записывает события в журнал аудита. Общий для всех сервисов.
+type auditor struct {
+	repo repository.AuditRepository
+}
+
+func newAuditor(repo repository.AuditRepository) *auditor {
+	return &auditor{repo: repo}
+}
+
+// Record сохраняет событие. Ошибка записи только логируется: журнал не должен
+// ломать основную операцию, которая к этому моменту уже выполнена.
+func (a *auditor) Record(ctx context.Context, event auditEvent) {
+	actor, ok := auditActorFrom(ctx)
+	if event.Actor != nil {
+		actor, ok = *event.Actor, true
+	}
+	if !ok {
+		actor = AuditActor{Type: models.AuditActorSystem}
+	}
+	meta := requestMetaFrom(ctx)
+
+	entry := models.AuditLog{
+		ActorType:      actor.Type,
+		ActorID:        optionalID(actor.ID),
+		ActorName:      actor.Name,
+		ImpersonatorID: optionalID(actor.ImpersonatorID),
+		Action:         event.Action,
+		EntityType:     event.EntityType,
+		TargetUserID:   optionalID(event.TargetUserID),
+		Changes:        auditDiff(event.Before, event.After),
+		IPAddress:      meta.IPAddress,
+		RequestID:      meta.RequestID,
+	}
+	if event.EntityID != nil {
+		entry.EntityID = fmt.Sprint(event.EntityID)
+	}
+
+	// Запись не должна теряться, если клиент оборвал соединение
+	if err := a.repo.Create(context.WithoutCancel(ctx), entry); err != nil {
+		log.Printf("ERROR: failed to write audit log %s for %s %s: %v",
+			event.Action, event.EntityType, entry.EntityID, err)
+	}
+}
+
+// optionalEntityID возвращает nil для нулевого ID, чтобы entity_id остался пустым.
+func optionalEntityID(id uint64) any {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
+
+func optionalID(id uint64) *uint64 {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
+
+// auditDiff сравнивает состояния до и после и возвращает измененные поля.
+// Состояния сериализуются в JSON, поэтому поля с тегом json:"-" (хэши паролей, секреты)
+// в журнал не попадают. Вложенные объекты разворачиваются в пути вида profile.email.
+// Для создания (before == nil) и удаления (after == nil) в журнал попадают все поля.
+func auditDiff(before, after any) models.AuditChanges {
+	beforeFields := flattenForAudit(before)
+	afterFields := flattenForAudit(after)
+
+	changes := make(models.AuditChanges)
+	for key, b := range beforeFields {
+		if a := afterFields[key]; !reflect.DeepEqual(a, b) {
+			changes[key] = models.AuditChange{Before: b, After: a}
+		}
+	}
+	for key, a := range afterFields {
+		if _, ok := beforeFields[key]; !ok && a != nil {
+			changes[key] = models.AuditChange{Before: nil, After: a}
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return changes
+}
+
+func flattenForAudit(value any) map[string]any {
+	fields := make(map[string]any)
+	if value == nil {
+		return fields
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fields
+	}
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return fields
+	}
+	if object, ok := decoded.(map[string]any); ok {
+		flattenInto(fields, "", object)
+	} else if decoded != nil {
+		fields["value"] = decoded
+	}
+	return fields
+}
+
+func flattenInto(fields map[string]any, prefix string, object map[string]any) {
+	for key, value := range object {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]any); ok && len(nested) > 0 {
+			if v, isNull := sqlNullValue(nested); isNull {
+				fields[path] = v
+				continue
+			}
+			flattenInto(fields, path, nested)
+			continue
+		}
+		fields[path] = value
+	}
+}
+
+// sqlNullValue распознает сериализованные sql.Null* ({"String": "...", "Valid": true})
+// и возвращает само значение либо nil.
+func sqlNullValue(object map[string]any) (any, bool) {
+	valid, ok := object["Valid"].(bool)
+	if !ok || len(object) != 2 {
+		return nil, false
+	}
+	for _, key := range []string{"String", "Int64", "Int32", "Int16", "Byte", "Float64", "Bool", "Time"} {
+		if value, ok := object[key]; ok {
+			if !valid {
+				return nil, true
+			}
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// auditService реализует интерфейс AuditService.
+type auditService struct {
+	repo repository.AuditRepository
+}
+
+// NewAuditService создает сервис для просмотра и выгрузки журнала аудита.
+func NewAuditService(repo repository.AuditRepository) AuditService {
+	return &auditService{repo: repo}
+}
+
+// GetAuditLogs возвращает пагинированный журнал аудита с фильтрами.
+func (s *auditService) GetAuditLogs(ctx context.Context, filter models.AuditLogFilter,
+	params models.PaginationParams,
+) ([]models.AuditLog, int64, error) {
+	if err := validateAuditLogFilter(filter); err != nil {
+		return nil, 0, err
+	}
+	entries, total, err := s.repo.GetAll(ctx, filter, params)
+	if err != nil {
+		return nil, 0, NewInternalServerError("failed to get audit logs", err)
+	}
+	return entries, total, nil
+}
+
+// ExportAuditLogsCSV выгружает журнал аудита с фильтрами в CSV, не загружая его в память целиком.
+// В w ничего не пишется, пока не проверен фильтр и не выполнен первый запрос: ошибка на этом этапе
+// возвращается до начала выгрузки, и клиент не получает обрезанный файл.
+func (s *auditService) ExportAuditLogsCSV(ctx context.Context, filter models.AuditLogFilter, w io.Writer) error {
+	if err := validateAuditLogFilter(filter); err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	headerWritten := false
+	writeHeader := func() error {
+		if headerWritten {
+			return nil
+		}
+		headerWritten = true
+		return writer.Write(auditCSVHeader)
+	}
+
+	err := s.repo.Export(ctx, filter, func(batch []models.AuditLog) error {
+		if err := writeHeader(); err != nil {
+			return err
+		}
+		for _, entry := range batch {
+			changes := ""
+			if len(entry.Changes) > 0 {
+				data, err := json.Marshal(entry.Changes)
+				if err != nil {
+					return err
+				}
+				changes = string(data)
+			}
+			record := []string{
+				strconv.FormatUint(entry.ID, 10),
+				entry.CreatedAt.UTC().Format(time.RFC3339),
+				csvSafe(entry.ActorType),
+				formatOptionalID(entry.ActorID),
+				csvSafe(entry.ActorName),
+				formatOptionalID(entry.ImpersonatorID),
+				csvSafe(entry.Action),
+				csvSafe(entry.EntityType),
+				csvSafe(entry.EntityID),
+				formatOptionalID(entry.TargetUserID),
+				csvSafe(entry.IPAddress),
+				csvSafe(entry.RequestID),
+				csvSafe(changes),
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	})
+	if err != nil {
+		return NewInternalServerError("failed to export audit logs", err)
+	}
+	if err := writeHeader(); err != nil { // Под фильтр не попало ни одной записи
+		return NewInternalServerError("failed to write audit export", err)
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// validateAuditLogFilter проверяет значения фильтра журнала аудита, которые не проверяет разбор запроса.
+func validateAuditLogFilter(filter models.AuditLogFilter) error {
+	switch filter.ActorType {
+	case "", models.AuditActorAdmin, models.AuditActorUser, models.AuditActorAnonymous, models.AuditActorSystem:
+	default:
+		return NewBadRequestError("invalid actorType", nil)
+	}
+	return nil
+}
+
+// csvSafe защищает ячейку выгрузки от CSV-инъекции: значение, которое табличный редактор
+// принял бы за формулу (например, User-Agent или логин злоумышленника), предваряется апострофом.
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func formatOptionalID(id *uint64) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatUint(*id, 10)
+}
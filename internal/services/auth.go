@@ -2,21 +2,22 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/sha256"
+	"crypto/subtle"
 	"database/sql"
 	"encoding/base64"
 	"errors"
 	"fmt"
 	"log"
-	"math/rand"
 	"strings"
 	"time"
 
 	"lk/internal/models"
 	"lk/internal/repository"
+	"lk/internal/sms"
 	"lk/internal/utils"
 
-	"github.com/golang-jwt/jwt/v5"
 	"gorm.io/gorm"
 )
 
@@ -24,16 +25,30 @@ const (
 	refreshTokenTTL = 72 * time.Hour
 	resetCodeTTL    = 5 * time.Minute
 	resetCodePrefix = "reset_code:"
+	// resetAttemptsPrefix - счетчик неверных вводов текущего кода сброса.
+	resetAttemptsPrefix = "reset_attempts:"
+	resetCodeLength     = 6
+
+	phoneVerifyCodeTTL    = 10 * time.Minute
+	phoneVerifyCodePrefix = "phone_verify:"
+	// phoneVerifyAttemptsPrefix - счетчик неверных вводов кода подтверждения телефона.
+	phoneVerifyAttemptsPrefix = "phone_verify_attempts:"
 )
 
 // authService - это конкретная реализация интерфейса Authorization.
 type authService struct {
-	userRepo   repository.UserRepository
-	tokenRepo  repository.TokenRepository
-	cacheRepo  repository.CacheRepository
-	transactor repository.Transactor
-	signingKey string
-	tokenTTL   time.Duration
+	userRepo    repository.UserRepository
+	tokenRepo   repository.TokenRepository
+	cacheRepo   repository.CacheRepository
+	infoRepo    repository.InfoRepository
+	consentRepo repository.ConsentRepository
+	transactor  repository.Transactor
+	guard       *bruteForceGuard
+	smsSender   sms.Sender
+	emailLinks  *emailLinks
+	keys        *utils.KeySet
+	tokenScope  utils.TokenScope
+	audit       *auditor
 }
 
 // NewAuthService является конструктором для сервиса авторизации.
@@ -41,35 +56,64 @@ func NewAuthService(
 	userRepo repository.UserRepository,
 	tokenRepo repository.TokenRepository,
 	cacheRepo repository.CacheRepository,
+	infoRepo repository.InfoRepository,
+	consentRepo repository.ConsentRepository,
 	transactor repository.Transactor,
-	signingKey string,
-	tokenTTL time.Duration,
+	guard *bruteForceGuard,
+	smsSender sms.Sender,
+	emailLinks *emailLinks,
+	keys *utils.KeySet,
+	tokenScope utils.TokenScope,
+	audit *auditor,
 ) Authorization {
 	return &authService{
-		userRepo:   userRepo,
-		tokenRepo:  tokenRepo,
-		cacheRepo:  cacheRepo,
-		transactor: transactor,
-		signingKey: signingKey,
-		tokenTTL:   tokenTTL,
+		userRepo:    userRepo,
+		tokenRepo:   tokenRepo,
+		cacheRepo:   cacheRepo,
+		infoRepo:    infoRepo,
+		consentRepo: consentRepo,
+		transactor:  transactor,
+		guard:       guard,
+		smsSender:   smsSender,
+		emailLinks:  emailLinks,
+		keys:        keys,
+		tokenScope:  tokenScope,
+		audit:       audit,
 	}
 }
 
-// CreateUser - бизнес-логика регистрации нового пользователя. Возвращает пару токенов.
+// CreateUser - бизнес-логика регистрации нового пользователя.
+// Аккаунт создается неактивным; на телефон отправляется код подтверждения,
+// после ввода которого (VerifyPhone) пользователь получает пару токенов.
+// Повторная регистрация номера, который еще не подтвержден, перезаписывает пароль, профиль
+// и согласия ожидающего аккаунта и выдает новый код: иначе неподтвержденную регистрацию
+// чужого номера с паролем злоумышленника активировал бы сам владелец номера.
+// acceptedDocumentIDs должны содержать действующие версии всех обязательных документов.
 func (s *authService) CreateUser(ctx context.Context, phone, password, fullName, gender,
-	birthDateStr string, cityID uint32,
-) (map[string]string, error) {
-	_, err := s.userRepo.GetUserByPhone(ctx, phone)
+	birthDateStr string, cityID uint32, acceptedDocumentIDs []uint64, clientIP string,
+) error {
+	phone, err := normalizePhone(phone)
+	if err != nil {
+		return err
+	}
+
+	var pendingUserID uint64
+	existing, err := s.userRepo.GetUserByPhone(ctx, phone)
 	if err == nil {
-		return nil, NewConflictError("user with this phone already exists", nil)
+		if existing.PhoneVerifiedAt.Valid {
+			return NewConflictError("user with this phone already exists", nil)
+		}
+		pendingUserID = existing.ID
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return NewInternalServerError("database error while checking user", err)
 	}
-	if !errors.Is(err, gorm.ErrRecordNotFound) {
-		return nil, NewInternalServerError("database error while checking user", err)
+	if err := s.guard.AllowCodeSend(ctx, phone, clientIP); err != nil {
+		return err
 	}
 
 	hashedPassword, err := utils.HashPassword(password)
 	if err != nil {
-		return nil, NewInternalServerError("failed to hash password", err)
+		return NewInternalServerError("failed to hash password", err)
 	}
 
 	nameParts := strings.Fields(fullName)
@@ -86,23 +130,30 @@ func (s *authService) CreateUser(ctx context.Context, phone, password, fullName,
 
 	birthDate, err := time.Parse("2006-01-02", birthDateStr)
 	if err != nil {
-		return nil, NewBadRequestError("invalid birth date format (expected YYYY-MM-DD)", err)
+		return NewBadRequestError("invalid birth date format (expected YYYY-MM-DD)", err)
+	}
+
+	documents, err := s.infoRepo.GetLegalDocuments(ctx)
+	if err != nil {
+		return NewInternalServerError("failed to get legal documents from db", err)
+	}
+	if err := requireMandatoryDocuments(documents, acceptedDocumentIDs); err != nil {
+		return err
+	}
+	consents, err := consentsForDocuments(ctx, documents, 0, acceptedDocumentIDs)
+	if err != nil {
+		return err
 	}
 
-	var userID uint64
+	userID := pendingUserID
 	err = s.transactor.WithinTransaction(ctx, func(tx *gorm.DB) error {
 		user := models.User{
+			ID:           pendingUserID,
 			Phone:        phone,
 			PasswordHash: hashedPassword,
+			IsActive:     false,
 		}
-		newUserID, err := s.userRepo.CreateUser(ctx, tx, user)
-		if err != nil {
-			return fmt.Errorf("failed to create user: %w", err)
-		}
-		userID = newUserID
-
 		profile := models.UserProfile{
-			UserID:     userID,
 			FirstName:  firstName,
 			LastName:   lastName,
 			Patronymic: sql.NullString{String: patronymic, Valid: patronymic != ""},
@@ -110,36 +161,100 @@ func (s *authService) CreateUser(ctx context.Context, phone, password, fullName,
 			Gender:     gender,
 			CityID:     cityID,
 		}
-		_, err = s.userRepo.CreateUserProfile(ctx, tx, profile)
-		if err != nil {
-			return fmt.Errorf("failed to create user profile: %w", err)
+
+		if pendingUserID != 0 {
+			if err := s.userRepo.ReplacePendingUser(ctx, tx, user, profile); err != nil {
+				return fmt.Errorf("failed to replace pending user: %w", err)
+			}
+			if err := s.consentRepo.RevokeAll(ctx, tx, pendingUserID); err != nil {
+				return fmt.Errorf("failed to revoke pending user consents: %w", err)
+			}
+		} else {
+			newUserID, err := s.userRepo.CreateUser(ctx, tx, user)
+			if err != nil {
+				return fmt.Errorf("failed to create user: %w", err)
+			}
+			userID = newUserID
+
+			profile.UserID = userID
+			if _, err := s.userRepo.CreateUserProfile(ctx, tx, profile); err != nil {
+				return fmt.Errorf("failed to create user profile: %w", err)
+			}
+		}
+
+		for i := range consents {
+			consents[i].UserID = userID
+		}
+		if err := s.consentRepo.Create(ctx, tx, consents); err != nil {
+			return fmt.Errorf("failed to save user consents: %w", err)
 		}
 		return nil
 	})
 	if err != nil {
-		return nil, NewInternalServerError("transaction failed on user creation", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewConflictError("user with this phone already exists", err)
+		}
+		return NewInternalServerError("transaction failed on user creation", err)
 	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditUserRegister, EntityType: auditEntityUser, EntityID: userID, TargetUserID: userID,
+		Actor: &AuditActor{Type: models.AuditActorUser, ID: userID},
+	})
 
-	return s.createSession(ctx, userID)
+	return s.sendPhoneVerificationCode(ctx, phone)
 }
 
 // GenerateToken - бизнес-логика входа пользователя. Возвращает пару токенов.
-func (s *authService) GenerateToken(ctx context.Context, phone, password string) (map[string]string, error) {
+// Неудачные попытки учитываются по телефону и IP-адресу клиента.
+func (s *authService) GenerateToken(ctx context.Context, phone, password, clientIP string) (map[string]string, error) {
+	phone, err := normalizePhone(phone)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.guard.Check(ctx, models.LockoutScopeUserLogin, phone, clientIP); err != nil {
+		return nil, err
+	}
+
 	user, err := s.userRepo.GetUserByPhone(ctx, phone)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.guard.RegisterFailure(ctx, models.LockoutScopeUserLogin, phone, clientIP)
+			s.recordLoginFailure(ctx, 0)
 			return nil, NewUnauthorizedError("invalid phone or password", nil)
 		}
 		return nil, NewInternalServerError("database error while getting user", err)
 	}
 
 	if err := utils.CheckPasswordHash(password, user.PasswordHash); err != nil {
+		s.guard.RegisterFailure(ctx, models.LockoutScopeUserLogin, phone, clientIP)
+		s.recordLoginFailure(ctx, user.ID)
 		return nil, NewUnauthorizedError("invalid phone or password", nil)
 	}
 
+	if !user.IsActive {
+		if !user.PhoneVerifiedAt.Valid {
+			return nil, NewForbiddenError("phone number is not verified", nil)
+		}
+		return nil, NewForbiddenError("account is disabled", nil)
+	}
+
+	s.guard.Reset(ctx, models.LockoutScopeUserLogin, phone)
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditLogin, EntityType: auditEntityPatientSession, EntityID: user.ID, TargetUserID: user.ID,
+		Actor: &AuditActor{Type: models.AuditActorUser, ID: user.ID},
+	})
 	return s.createSession(ctx, user.ID)
 }
 
+// recordLoginFailure записывает в журнал аудита неудачную попытку входа пациента.
+// Номер телефона в журнал не пишется; для неизвестного номера userID равен 0.
+func (s *authService) recordLoginFailure(ctx context.Context, userID uint64) {
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditLoginFailed, EntityType: auditEntityPatientSession, EntityID: optionalEntityID(userID),
+		TargetUserID: userID, Actor: &AuditActor{Type: models.AuditActorAnonymous},
+	})
+}
+
 // RefreshToken обновляет пару токенов, используя старый refresh-токен.
 func (s *authService) RefreshToken(ctx context.Context, refreshToken string) (map[string]string, error) {
 	parts := strings.Split(refreshToken, ".")
@@ -178,40 +293,105 @@ func (s *authService) Logout(ctx context.Context, refreshToken string) error {
 	if err := s.tokenRepo.Delete(ctx, userID); err != nil {
 		return NewInternalServerError("failed to logout", err)
 	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditLogout, EntityType: auditEntityPatientSession, EntityID: userID, TargetUserID: userID,
+		Actor: &AuditActor{Type: models.AuditActorUser, ID: userID},
+	})
 	return nil
 }
 
+// VerifyPhone подтверждает номер телефона кодом из SMS, активирует аккаунт
+// и возвращает пару токенов.
+func (s *authService) VerifyPhone(ctx context.Context, phone, code, clientIP string) (map[string]string, error) {
+	phone, err := normalizePhone(phone)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.verifyCode(ctx, models.LockoutScopePhoneVerify, phoneVerifyCodePrefix, phoneVerifyAttemptsPrefix,
+		phoneVerifyCodeTTL, phone, code, clientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetUserByPhone(ctx, phone)
+	if err != nil {
+		return nil, NewInternalServerError("failed to get user for phone verification", err)
+	}
+	if err := s.userRepo.MarkPhoneVerified(ctx, user.ID); err != nil {
+		return nil, NewInternalServerError("failed to mark phone as verified", err)
+	}
+
+	s.consumeCode(ctx, models.LockoutScopePhoneVerify, phoneVerifyCodePrefix, phoneVerifyAttemptsPrefix, phone)
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditPhoneVerified, EntityType: auditEntityUser, EntityID: user.ID, TargetUserID: user.ID,
+		Actor: &AuditActor{Type: models.AuditActorUser, ID: user.ID},
+	})
+	return s.createSession(ctx, user.ID)
+}
+
+// ResendVerificationCode повторно отправляет код подтверждения телефона.
+// Для несуществующих и уже подтвержденных номеров ничего не делает,
+// чтобы не раскрывать факт регистрации.
+func (s *authService) ResendVerificationCode(ctx context.Context, phone, clientIP string) error {
+	phone, err := normalizePhone(phone)
+	if err != nil {
+		return err
+	}
+	if err := s.guard.Check(ctx, models.LockoutScopePhoneVerify, phone, clientIP); err != nil {
+		return err
+	}
+	if err := s.guard.AllowCodeSend(ctx, phone, clientIP); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetUserByPhone(ctx, phone)
+	if err != nil || user.PhoneVerifiedAt.Valid {
+		return nil
+	}
+	return s.sendPhoneVerificationCode(ctx, phone)
+}
+
 // ForgotPassword инициирует сброс пароля.
-func (s *authService) ForgotPassword(ctx context.Context, phone string) error {
+func (s *authService) ForgotPassword(ctx context.Context, phone, clientIP string) error {
+	phone, err := normalizePhone(phone)
+	if err != nil {
+		return err
+	}
+	if err := s.guard.Check(ctx, models.LockoutScopePasswordReset, phone, clientIP); err != nil {
+		return err
+	}
+	if err := s.guard.AllowCodeSend(ctx, phone, clientIP); err != nil {
+		return err
+	}
+
 	if _, err := s.userRepo.GetUserByPhone(ctx, phone); err != nil {
 		log.Printf("INFO: Password reset requested for non-existent phone: %s", phone)
 		return nil
 	}
 
-	code := fmt.Sprintf("%06d", rand.Intn(1000000))
-	key := resetCodePrefix + phone
-
-	if err := s.cacheRepo.Set(ctx, key, code, resetCodeTTL); err != nil {
-		return NewInternalServerError("failed to set reset code to cache", err)
+	code, err := s.issueCode(ctx, resetCodePrefix, resetAttemptsPrefix, phone, resetCodeTTL)
+	if err != nil {
+		return err
+	}
+	if err := s.smsSender.Send(ctx, phone, "Код для сброса пароля: "+code); err != nil {
+		return NewInternalServerError("failed to send reset code", err)
 	}
-
-	log.Printf("!!! MOCK SMS !!! Password reset code for user %s is: %s", phone, code)
 	return nil
 }
 
 // ResetPassword устанавливает новый пароль с использованием кода.
-func (s *authService) ResetPassword(ctx context.Context, phone, code, newPassword string) error {
-	key := resetCodePrefix + phone
-	storedCode, err := s.cacheRepo.Get(ctx, key)
+// После MaxResetCodeAttempts неверных вводов код аннулируется.
+func (s *authService) ResetPassword(ctx context.Context, phone, code, newPassword, clientIP string) error {
+	phone, err := normalizePhone(phone)
 	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			return NewUnauthorizedError("confirmation code is incorrect or expired", err)
-		}
-		return NewInternalServerError("failed to get reset code from cache", err)
+		return err
 	}
 
-	if storedCode != code {
-		return NewUnauthorizedError("confirmation code is incorrect or expired", nil)
+	err = s.verifyCode(ctx, models.LockoutScopePasswordReset, resetCodePrefix, resetAttemptsPrefix, resetCodeTTL,
+		phone, code, clientIP)
+	if err != nil {
+		return err
 	}
 
 	user, err := s.userRepo.GetUserByPhone(ctx, phone)
@@ -228,39 +408,213 @@ func (s *authService) ResetPassword(ctx context.Context, phone, code, newPasswor
 		return NewInternalServerError("failed to update password in db", err)
 	}
 
-	_ = s.cacheRepo.Delete(ctx, key)
+	s.consumeCode(ctx, models.LockoutScopePasswordReset, resetCodePrefix, resetAttemptsPrefix, phone)
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditPasswordReset, EntityType: auditEntityUser, EntityID: user.ID, TargetUserID: user.ID,
+		Actor: &AuditActor{Type: models.AuditActorUser, ID: user.ID},
+	})
 	return nil
 }
 
-// ParseToken проверяет токен и возвращает ID пользователя из него.
-func (s *authService) ParseToken(accessToken string) (uint64, error) {
-	token, err := jwt.ParseWithClaims(accessToken, &jwt.MapClaims{},
-		func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return []byte(s.signingKey), nil
-		})
+// VerifyEmail подтверждает адрес электронной почты по токену из ссылки.
+func (s *authService) VerifyEmail(ctx context.Context, token string) error {
+	claims, err := s.emailLinks.ParseVerification(token)
 	if err != nil {
-		return 0, NewUnauthorizedError("invalid token", err)
+		return err
 	}
+	userID, _ := claims.UserID()
 
-	claims, ok := token.Claims.(*jwt.MapClaims)
-	if !ok || !token.Valid {
-		return 0, NewUnauthorizedError("invalid token claims", nil)
+	if err := s.userRepo.MarkEmailVerified(ctx, userID, claims.Email); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewBadRequestError("email address has been changed, request a new verification link", err)
+		}
+		return NewInternalServerError("failed to mark email as verified", err)
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditEmailVerified, EntityType: auditEntityUser, EntityID: userID, TargetUserID: userID,
+		Actor: &AuditActor{Type: models.AuditActorUser, ID: userID},
+	})
+	return nil
+}
+
+// ForgotPasswordByEmail отправляет ссылку для сброса пароля на подтвержденный адрес.
+// Для неизвестных и неподтвержденных адресов ничего не делает, чтобы не раскрывать их наличие.
+func (s *authService) ForgotPasswordByEmail(ctx context.Context, email, clientIP string) error {
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return err
+	}
+	if err := s.guard.Check(ctx, models.LockoutScopePasswordReset, email, clientIP); err != nil {
+		return err
+	}
+
+	profile, err := s.userRepo.GetUserProfileByEmail(ctx, email)
+	if err != nil || !profile.EmailVerifiedAt.Valid {
+		log.Printf("INFO: Password reset requested for unknown or unverified email: %s", email)
+		return nil
+	}
+
+	return s.emailLinks.SendPasswordReset(ctx, profile.UserID, email)
+}
+
+// ResetPasswordByEmail устанавливает новый пароль по одноразовой ссылке из письма.
+func (s *authService) ResetPasswordByEmail(ctx context.Context, token, newPassword string) error {
+	claims, err := s.emailLinks.ConsumePasswordReset(ctx, token)
+	if err != nil {
+		return err
+	}
+	userID, _ := claims.UserID()
+
+	profile, err := s.userRepo.GetUserProfileByUserID(ctx, userID)
+	if err != nil {
+		return NewInternalServerError("failed to get user profile for password reset", err)
+	}
+	if !profile.EmailVerifiedAt.Valid || !strings.EqualFold(profile.Email.String, claims.Email) {
+		return NewUnauthorizedError("reset link is invalid or expired", nil)
+	}
+
+	newPasswordHash, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return NewInternalServerError("failed to hash new password", err)
+	}
+	if err := s.userRepo.UpdatePassword(ctx, userID, newPasswordHash); err != nil {
+		return NewInternalServerError("failed to update password in db", err)
+	}
+
+	s.guard.Reset(ctx, models.LockoutScopePasswordReset, claims.Email)
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditPasswordReset, EntityType: auditEntityUser, EntityID: userID, TargetUserID: userID,
+		Actor: &AuditActor{Type: models.AuditActorUser, ID: userID},
+	})
+	return nil
+}
+
+// sendPhoneVerificationCode генерирует и отправляет код подтверждения телефона.
+func (s *authService) sendPhoneVerificationCode(ctx context.Context, phone string) error {
+	code, err := s.issueCode(ctx, phoneVerifyCodePrefix, phoneVerifyAttemptsPrefix, phone, phoneVerifyCodeTTL)
+	if err != nil {
+		return err
+	}
+	if err := s.smsSender.Send(ctx, phone, "Код подтверждения регистрации: "+code); err != nil {
+		return NewInternalServerError("failed to send verification code", err)
+	}
+	return nil
+}
+
+// issueCode генерирует одноразовый числовой код, сохраняет его в кэше
+// и сбрасывает счетчик неверных попыток.
+func (s *authService) issueCode(ctx context.Context, codePrefix, attemptsPrefix, phone string,
+	ttl time.Duration,
+) (string, error) {
+	code, err := utils.GenerateNumericCode(resetCodeLength)
+	if err != nil {
+		return "", NewInternalServerError("failed to generate confirmation code", err)
+	}
+	if err := s.cacheRepo.Set(ctx, codePrefix+phone, code, ttl); err != nil {
+		return "", NewInternalServerError("failed to set confirmation code to cache", err)
+	}
+	// Новый код - новый счетчик попыток.
+	_ = s.cacheRepo.Delete(ctx, attemptsPrefix+phone)
+	return code, nil
+}
+
+// verifyCode сверяет введенный код с сохраненным. Неверные вводы учитываются
+// защитой от перебора, а после MaxResetCodeAttempts попыток код аннулируется.
+// ttl - время жизни проверяемого кода, на него же заводится счетчик неверных вводов.
+func (s *authService) verifyCode(ctx context.Context, scope, codePrefix, attemptsPrefix string, ttl time.Duration,
+	phone, code, clientIP string,
+) error {
+	if err := s.guard.Check(ctx, scope, phone, clientIP); err != nil {
+		return err
+	}
+
+	storedCode, err := s.cacheRepo.Get(ctx, codePrefix+phone)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			s.guard.RegisterFailure(ctx, scope, phone, clientIP)
+			return NewUnauthorizedError("confirmation code is incorrect or expired", err)
+		}
+		return NewInternalServerError("failed to get confirmation code from cache", err)
 	}
 
-	subFloat, ok := (*claims)["sub"].(float64)
-	if !ok {
-		return 0, NewUnauthorizedError("invalid subject claim in token", nil)
+	if subtle.ConstantTimeCompare([]byte(storedCode), []byte(code)) != 1 {
+		s.guard.RegisterFailure(ctx, scope, phone, clientIP)
+		s.registerInvalidCode(ctx, codePrefix, attemptsPrefix, phone, ttl)
+		return NewUnauthorizedError("confirmation code is incorrect or expired", nil)
+	}
+	return nil
+}
+
+// registerInvalidCode учитывает неверный ввод кода и аннулирует код
+// после превышения допустимого числа попыток.
+func (s *authService) registerInvalidCode(ctx context.Context, codePrefix, attemptsPrefix, phone string,
+	ttl time.Duration,
+) {
+	attempts, err := s.cacheRepo.Increment(ctx, attemptsPrefix+phone, ttl)
+	if err != nil {
+		log.Printf("WARN: failed to count confirmation code attempts for %s: %v", phone, err)
+		return
+	}
+	if attempts >= s.guard.cfg.MaxResetCodeAttempts {
+		_ = s.cacheRepo.Delete(ctx, codePrefix+phone)
+		_ = s.cacheRepo.Delete(ctx, attemptsPrefix+phone)
 	}
+}
+
+// consumeCode удаляет использованный код и сбрасывает связанные счетчики.
+func (s *authService) consumeCode(ctx context.Context, scope, codePrefix, attemptsPrefix, phone string) {
+	_ = s.cacheRepo.Delete(ctx, codePrefix+phone)
+	_ = s.cacheRepo.Delete(ctx, attemptsPrefix+phone)
+	s.guard.Reset(ctx, scope, phone)
+}
+
+// normalizeEmail проверяет адрес электронной почты и возвращает ошибку 400 для некорректных адресов.
+func normalizeEmail(email string) (string, error) {
+	normalized, err := utils.NormalizeEmail(email)
+	if err != nil {
+		return "", NewBadRequestError("invalid email address", err)
+	}
+	return normalized, nil
+}
+
+// normalizePhone приводит номер к формату E.164 и возвращает ошибку 400 для некорректных номеров.
+func normalizePhone(phone string) (string, error) {
+	normalized, err := utils.NormalizePhone(phone)
+	if err != nil {
+		return "", NewBadRequestError("invalid phone number format", err)
+	}
+	return normalized, nil
+}
+
+// ParseToken проверяет токен и возвращает ID пользователя из него, а для токенов
+// имперсонации - еще и ID администратора, действующего от имени пользователя (иначе 0).
+// Административные токены отклоняются, так как у них другие издатель и аудитория.
+func (s *authService) ParseToken(accessToken string) (uint64, uint64, error) {
+	claims, err := utils.ParseToken(s.keys, s.tokenScope, accessToken)
+	if err != nil {
+		return 0, 0, NewUnauthorizedError("invalid token", err)
+	}
+
+	userID, err := claims.SubjectID()
+	if err != nil {
+		return 0, 0, NewUnauthorizedError("invalid subject claim in token", err)
+	}
+	impersonatorID, err := claims.ActorID()
+	if err != nil {
+		return 0, 0, NewUnauthorizedError("invalid actor claim in token", err)
+	}
+
+	return userID, impersonatorID, nil
+}
 
-	return uint64(subFloat), nil
+// JWKS возвращает открытые ключи, которыми проверяются access токены.
+func (s *authService) JWKS() utils.JWKS {
+	return s.keys.JWKS()
 }
 
 // createSession - внутренний метод для генерации и сохранения пары токенов.
 func (s *authService) createSession(ctx context.Context, userID uint64) (map[string]string, error) {
-	accessToken, err := utils.GenerateToken(userID, s.signingKey, s.tokenTTL)
+	accessToken, err := utils.GenerateToken(s.keys, s.tokenScope, userID, utils.AccessClaims{})
 	if err != nil {
 		return nil, NewInternalServerError("failed to generate access token", err)
 	}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"path"
+	"strings"
+
+	"lk/internal/imaging"
+	"lk/internal/models"
+	"lk/internal/storage"
+
+	"github.com/google/uuid"
+)
+
+// maxAvatarFileSize - максимальный размер загружаемого файла аватара.
+const maxAvatarFileSize = 5 << 20
+
+// avatarSizes - стороны миниатюр аватара в пикселях. Первая - основное изображение, ключ которого хранится в профиле.
+var avatarSizes = []int{512, 256, 128, 64}
+
+// uploadAvatar проверяет изображение, готовит миниатюры всех размеров без EXIF и загружает их
+// в хранилище под ключами avatars/<owner>/<uuid>/<size>.jpg.
+func uploadAvatar(ctx context.Context, fs storage.FileStorage, owner string, fileHeader *multipart.FileHeader) (
+	models.Avatar, error,
+) {
+	if fileHeader.Size > maxAvatarFileSize {
+		return models.Avatar{}, NewBadRequestError("avatar file is too large", nil)
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return models.Avatar{}, NewInternalServerError("failed to open avatar file", err)
+	}
+	defer file.Close()
+
+	// Размер из заголовка multipart не гарантирован, поэтому ограничиваем и само чтение
+	data, err := io.ReadAll(io.LimitReader(file, maxAvatarFileSize+1))
+	if err != nil {
+		return models.Avatar{}, NewInternalServerError("failed to read avatar file", err)
+	}
+	if len(data) > maxAvatarFileSize {
+		return models.Avatar{}, NewBadRequestError("avatar file is too large", nil)
+	}
+
+	img, err := imaging.Decode(data)
+	if err != nil {
+		if errors.Is(err, imaging.ErrTooLarge) {
+			return models.Avatar{}, NewBadRequestError("avatar image dimensions are too large", err)
+		}
+		return models.Avatar{}, NewBadRequestError("avatar must be a JPEG, PNG or GIF image", err)
+	}
+
+	avatar := models.Avatar{Thumbnails: make(map[int]string, len(avatarSizes))}
+	base := fmt.Sprintf("avatars/%s/%s", owner, uuid.New().String())
+	for _, size := range avatarSizes {
+		var buf bytes.Buffer
+		if err := imaging.EncodeJPEG(&buf, img.Thumbnail(size)); err != nil {
+			deleteAvatar(ctx, fs, avatar.URL)
+			return models.Avatar{}, NewInternalServerError("failed to encode avatar", err)
+		}
+		key := fmt.Sprintf("%s/%d.jpg", base, size)
+		if err := fs.Upload(ctx, &buf, int64(buf.Len()), "image/jpeg", key); err != nil {
+			deleteAvatar(ctx, fs, avatar.URL)
+			return models.Avatar{}, NewInternalServerError("failed to upload avatar", err)
+		}
+		if avatar.URL == "" {
+			avatar.URL = key
+		}
+		avatar.Thumbnails[size] = key
+	}
+	return avatar, nil
+}
+
+// expandAvatarKey возвращает ключи всех миниатюр для аватара, загруженного через uploadAvatar.
+// Для остальных ключей (в т.ч. аватаров, загруженных до появления миниатюр) возвращает сам ключ.
+func expandAvatarKey(key string) []string {
+	dir, file := path.Split(key)
+	if !strings.HasPrefix(key, "avatars/") || file != fmt.Sprintf("%d.jpg", avatarSizes[0]) {
+		return []string{key}
+	}
+	keys := make([]string, len(avatarSizes))
+	for i, size := range avatarSizes {
+		keys[i] = fmt.Sprintf("%s%d.jpg", dir, size)
+	}
+	return keys
+}
+
+// deleteAvatar удаляет аватар со всеми миниатюрами. Пути вне avatars/ (например, статические
+// изображения из начальных данных) не трогаются. Ошибки только логируются: профиль уже обновлен.
+func deleteAvatar(ctx context.Context, fs storage.FileStorage, key string) {
+	if !strings.HasPrefix(key, "avatars/") {
+		return
+	}
+	for _, objectKey := range expandAvatarKey(key) {
+		if err := fs.Delete(ctx, objectKey); err != nil {
+			log.Printf("WARN: failed to delete avatar %s from storage: %v", objectKey, err)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"lk/internal/models"
+	"lk/internal/repository"
+	"lk/internal/utils"
+)
+
+// ErrAdminsAlreadyExist возвращается при попытке создать первого суперадминистратора в непустой базе.
+var ErrAdminsAlreadyExist = errors.New("admins already exist")
+
+// BootstrapSuperAdmin создает первого суперадминистратора, если в базе еще нет ни одного
+// администратора. Используется CLI-командой до первого запуска админ-панели.
+// Если пароль не задан, генерируется временный. Возвращает пароль для входа.
+func BootstrapSuperAdmin(ctx context.Context, adminRepo repository.AdminRepository,
+	login, fullName, password string,
+) (string, error) {
+	login = strings.TrimSpace(login)
+	fullName = strings.TrimSpace(fullName)
+	if login == "" || fullName == "" {
+		return "", errors.New("login and full name are required")
+	}
+
+	count, err := adminRepo.CountAdmins(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to count admins: %w", err)
+	}
+	if count > 0 {
+		return "", ErrAdminsAlreadyExist
+	}
+
+	mustChange := false
+	if password == "" {
+		password, err = utils.GenerateTemporaryPassword(temporaryPasswordLength)
+		if err != nil {
+			return "", err
+		}
+		mustChange = true
+	}
+	if len(password) < 8 {
+		return "", errors.New("password must be at least 8 characters long")
+	}
+	passwordHash, err := utils.HashPassword(password)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	_, err = adminRepo.CreateAdmin(ctx, models.Admin{
+		Login:              login,
+		PasswordHash:       passwordHash,
+		FullName:           fullName,
+		Role:               models.RoleSuperAdmin,
+		IsActive:           true,
+		MustChangePassword: mustChange,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create superadmin: %w", err)
+	}
+	return password, nil
+}
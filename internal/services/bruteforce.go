@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"lk/internal/config"
+	"lk/internal/models"
+	"lk/internal/repository"
+)
+
+const (
+	bruteForceFailuresPrefix = "bf:fail:"
+	bruteForceLockPrefix     = "bf:lock:"
+	bruteForceLevelPrefix    = "bf:level:"
+	codeSendCooldownPrefix   = "code_send:cd:"
+	codeSendHourlyPrefix     = "code_send:hour:"
+	// bruteForceLevelTTL - сколько помнится "уровень" блокировки для прогрессивного увеличения.
+	bruteForceLevelTTL = 24 * time.Hour
+)
+
+// bruteForceGuard ограничивает количество неудачных попыток (вход, ввод кода)
+// по идентификатору (телефон, логин) и по IP-адресу. После превышения лимита
+// субъект блокируется, причем каждая следующая блокировка длится вдвое дольше.
+type bruteForceGuard struct {
+	cacheRepo    repository.CacheRepository
+	securityRepo repository.SecurityRepository
+	cfg          config.SecurityConfig
+}
+
+// newBruteForceGuard создает новый экземпляр защиты от перебора.
+func newBruteForceGuard(
+	cacheRepo repository.CacheRepository,
+	securityRepo repository.SecurityRepository,
+	cfg config.SecurityConfig,
+) *bruteForceGuard {
+	return &bruteForceGuard{
+		cacheRepo:    cacheRepo,
+		securityRepo: securityRepo,
+		cfg:          cfg,
+	}
+}
+
+// Check возвращает ошибку 429, если идентификатор или IP-адрес сейчас заблокированы.
+func (g *bruteForceGuard) Check(ctx context.Context, scope, identifier, clientIP string) error {
+	subjects := []string{subjectKey(scope, identifier)}
+	if clientIP != "" {
+		subjects = append(subjects, subjectKey(models.LockoutScopeIP, clientIP))
+	}
+
+	for _, subject := range subjects {
+		ttl, err := g.cacheRepo.TTL(ctx, bruteForceLockPrefix+subject)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return NewInternalServerError("failed to check lockout state", err)
+		}
+		return NewTooManyRequestsError(fmt.Sprintf(
+			"too many failed attempts, try again in %d seconds", int(ttl.Seconds())+1), nil)
+	}
+	return nil
+}
+
+// AllowCodeSend ограничивает отправку SMS-кодов: не чаще одного раза в CodeSendCooldown
+// на номер и не больше MaxCodeSendsPerHour на номер и MaxIPCodeSendsPerHour с IP-адреса в час.
+// Вызывается до проверки существования пользователя, чтобы лимит не раскрывал факт регистрации.
+func (g *bruteForceGuard) AllowCodeSend(ctx context.Context, phone, clientIP string) error {
+	acquired, err := g.cacheRepo.SetIfAbsent(ctx, codeSendCooldownPrefix+phone, 1, g.cfg.CodeSendCooldown)
+	if err != nil {
+		return NewInternalServerError("failed to check code send cooldown", err)
+	}
+	if !acquired {
+		ttl, err := g.cacheRepo.TTL(ctx, codeSendCooldownPrefix+phone)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return NewInternalServerError("failed to check code send cooldown", err)
+		}
+		return NewTooManyRequestsError(fmt.Sprintf(
+			"code was sent recently, try again in %d seconds", int(ttl.Seconds())+1), nil)
+	}
+
+	keys := []string{codeSendHourlyPrefix + phone}
+	limits := []int64{g.cfg.MaxCodeSendsPerHour}
+	if clientIP != "" {
+		keys = append(keys, codeSendHourlyPrefix+subjectKey(models.LockoutScopeIP, clientIP))
+		limits = append(limits, g.cfg.MaxIPCodeSendsPerHour)
+	}
+	for i, key := range keys {
+		sent, err := g.cacheRepo.Increment(ctx, key, time.Hour)
+		if err != nil {
+			return NewInternalServerError("failed to count sent codes", err)
+		}
+		if sent > limits[i] {
+			return NewTooManyRequestsError("too many codes requested, try again later", nil)
+		}
+	}
+	return nil
+}
+
+// RegisterFailure учитывает неудачную попытку и при превышении лимита блокирует
+// идентификатор и/или IP-адрес. Ошибки хранилища только логируются, чтобы
+// не подменять ими исходный ответ (например, 401).
+func (g *bruteForceGuard) RegisterFailure(ctx context.Context, scope, identifier, clientIP string) {
+	if err := g.registerFailure(ctx, scope, identifier, clientIP, g.cfg.MaxLoginAttempts); err != nil {
+		log.Printf("WARN: failed to register %s failure for %s: %v", scope, identifier, err)
+	}
+	if clientIP == "" {
+		return
+	}
+	if err := g.registerFailure(ctx, models.LockoutScopeIP, clientIP, clientIP, g.cfg.MaxIPAttempts); err != nil {
+		log.Printf("WARN: failed to register failure for ip %s: %v", clientIP, err)
+	}
+}
+
+// Reset сбрасывает счетчики после успешной попытки.
+func (g *bruteForceGuard) Reset(ctx context.Context, scope, identifier string) {
+	subject := subjectKey(scope, identifier)
+	_ = g.cacheRepo.Delete(ctx, bruteForceFailuresPrefix+subject)
+	_ = g.cacheRepo.Delete(ctx, bruteForceLevelPrefix+subject)
+}
+
+// Unlock досрочно снимает блокировку (используется администратором).
+func (g *bruteForceGuard) Unlock(ctx context.Context, scope, identifier string) error {
+	subject := subjectKey(scope, identifier)
+	for _, key := range []string{
+		bruteForceLockPrefix + subject,
+		bruteForceFailuresPrefix + subject,
+		bruteForceLevelPrefix + subject,
+	} {
+		if err := g.cacheRepo.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// registerFailure увеличивает счетчик неудач субъекта и блокирует его при достижении лимита.
+func (g *bruteForceGuard) registerFailure(ctx context.Context, scope, identifier, clientIP string, limit int64) error {
+	subject := subjectKey(scope, identifier)
+	failures, err := g.cacheRepo.Increment(ctx, bruteForceFailuresPrefix+subject, g.cfg.AttemptWindow)
+	if err != nil {
+		return err
+	}
+	if failures < limit {
+		return nil
+	}
+
+	level, err := g.cacheRepo.Increment(ctx, bruteForceLevelPrefix+subject, bruteForceLevelTTL)
+	if err != nil {
+		return err
+	}
+	duration := g.lockoutDuration(level)
+	if err := g.cacheRepo.Set(ctx, bruteForceLockPrefix+subject, failures, duration); err != nil {
+		return err
+	}
+	_ = g.cacheRepo.Delete(ctx, bruteForceFailuresPrefix+subject)
+
+	return g.securityRepo.CreateLockoutEvent(ctx, models.LockoutEvent{
+		Scope:          scope,
+		Identifier:     identifier,
+		IPAddress:      clientIP,
+		FailedAttempts: failures,
+		LockedUntil:    time.Now().Add(duration),
+	})
+}
+
+// lockoutDuration вычисляет длительность блокировки: base * 2^(level-1), но не больше max.
+func (g *bruteForceGuard) lockoutDuration(level int64) time.Duration {
+	duration := g.cfg.LockoutBase
+	for i := int64(1); i < level && duration < g.cfg.LockoutMax; i++ {
+		duration *= 2
+	}
+	if duration > g.cfg.LockoutMax {
+		duration = g.cfg.LockoutMax
+	}
+	return duration
+}
+
+// subjectKey формирует часть ключа кэша для пары "область + идентификатор".
+func subjectKey(scope, identifier string) string {
+	return scope + ":" + identifier
+}
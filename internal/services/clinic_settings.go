@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"lk/internal/models"
+	"lk/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+const (
+	clinicSettingsCacheKey = "clinic_settings:current"
+	// clinicSettingsCacheTTL ограничивает время, в течение которого экземпляр может видеть
+	// устаревшие настройки, если обновить кэш при сохранении не удалось.
+	clinicSettingsCacheTTL = 10 * time.Minute
+)
+
+// clinicSettingsStore читает действующие настройки клиники: сначала из Redis, затем из БД.
+// Сохранение новой версии сразу обновляет кэш, поэтому изменения применяются без перезапуска.
+type clinicSettingsStore struct {
+	repo      repository.ClinicSettingsRepository
+	cacheRepo repository.CacheRepository
+}
+
+// newClinicSettingsStore создает новое хранилище настроек клиники.
+func newClinicSettingsStore(
+	repo repository.ClinicSettingsRepository, cacheRepo repository.CacheRepository,
+) *clinicSettingsStore {
+	return &clinicSettingsStore{repo: repo, cacheRepo: cacheRepo}
+}
+
+// Version возвращает действующую версию настроек.
+func (s *clinicSettingsStore) Version(ctx context.Context) (models.ClinicSettingsVersion, error) {
+	var version models.ClinicSettingsVersion
+	cached, err := s.cacheRepo.Get(ctx, clinicSettingsCacheKey)
+	if err == nil {
+		if err := json.Unmarshal([]byte(cached), &version); err == nil {
+			return version, nil
+		}
+		log.Printf("WARN: failed to decode cached clinic settings: %v", err)
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		log.Printf("WARN: failed to read clinic settings from cache: %v", err)
+	}
+
+	version, err = s.repo.GetCurrent(ctx)
+	if err != nil {
+		return models.ClinicSettingsVersion{}, err
+	}
+	s.cache(ctx, version)
+	return version, nil
+}
+
+// Current возвращает действующие настройки. Если прочитать их не удалось, используются
+// значения по умолчанию, чтобы запись на прием продолжала работать.
+func (s *clinicSettingsStore) Current(ctx context.Context) models.ClinicSettings {
+	version, err := s.Version(ctx)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("WARN: failed to load clinic settings, using defaults: %v", err)
+		}
+		return models.DefaultClinicSettings()
+	}
+	return version.Settings
+}
+
+// Save сохраняет новую версию настроек и обновляет кэш.
+func (s *clinicSettingsStore) Save(ctx context.Context, version *models.ClinicSettingsVersion) error {
+	if err := s.repo.Create(ctx, version); err != nil {
+		return err
+	}
+	s.cache(ctx, *version)
+	return nil
+}
+
+// cache кладет версию в кэш. Если это не удалось, старое значение удаляется,
+// чтобы следующее чтение взяло настройки из БД.
+func (s *clinicSettingsStore) cache(ctx context.Context, version models.ClinicSettingsVersion) {
+	data, err := json.Marshal(version)
+	if err == nil {
+		err = s.cacheRepo.Set(ctx, clinicSettingsCacheKey, data, clinicSettingsCacheTTL)
+	}
+	if err != nil {
+		log.Printf("WARN: failed to cache clinic settings: %v", err)
+		if err := s.cacheRepo.Delete(ctx, clinicSettingsCacheKey); err != nil {
+			log.Printf("WARN: failed to drop cached clinic settings: %v", err)
+		}
+	}
+}
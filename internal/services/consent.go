@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"lk/internal/models"
+	"lk/internal/repository"
+)
+
+// consentService реализует интерфейс ConsentService.
+type consentService struct {
+	infoRepo    repository.InfoRepository
+	consentRepo repository.ConsentRepository
+	audit       *auditor
+}
+
+// NewConsentService создает сервис для учета согласий и принятия юридических документов.
+func NewConsentService(infoRepo repository.InfoRepository, consentRepo repository.ConsentRepository,
+	audit *auditor,
+) ConsentService {
+	return &consentService{infoRepo: infoRepo, consentRepo: consentRepo, audit: audit}
+}
+
+// GetConsents возвращает состояние согласий пользователя по действующим версиям всех документов.
+func (s *consentService) GetConsents(ctx context.Context, userID uint64) ([]models.ConsentStatus, error) {
+	documents, err := s.infoRepo.GetLegalDocuments(ctx)
+	if err != nil {
+		return nil, NewInternalServerError("failed to get legal documents from db", err)
+	}
+	consents, err := s.consentRepo.GetActive(ctx, userID)
+	if err != nil {
+		return nil, NewInternalServerError("failed to get user consents from db", err)
+	}
+
+	byType := make(map[string]models.UserConsent, len(consents))
+	for _, consent := range consents {
+		byType[consent.DocumentType] = consent
+	}
+
+	statuses := make([]models.ConsentStatus, 0, len(documents))
+	for _, document := range documents {
+		status := models.ConsentStatus{Document: document}
+		if consent, ok := byType[document.Type]; ok {
+			acceptedAt := consent.AcceptedAt
+			status.AcceptedVersion = consent.Version
+			status.AcceptedAt = &acceptedAt
+			status.Accepted = consent.DocumentID == document.ID
+		}
+		status.RequiresAcceptance = document.IsMandatory && !status.Accepted
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// GetHistory возвращает историю принятия и отзыва документов пользователем.
+func (s *consentService) GetHistory(ctx context.Context, userID uint64, params models.PaginationParams) (
+	[]models.UserConsent, int64, error,
+) {
+	consents, total, err := s.consentRepo.GetHistory(ctx, userID, params)
+	if err != nil {
+		return nil, 0, NewInternalServerError("failed to get consent history from db", err)
+	}
+	return consents, total, nil
+}
+
+// Accept фиксирует принятие пользователем действующих версий документов.
+// Принять можно только действующую версию; устаревшая версия означает, что клиенту нужно обновить список.
+func (s *consentService) Accept(ctx context.Context, userID uint64, documentIDs []uint64) error {
+	documents, err := s.infoRepo.GetLegalDocuments(ctx)
+	if err != nil {
+		return NewInternalServerError("failed to get legal documents from db", err)
+	}
+	consents, err := consentsForDocuments(ctx, documents, userID, documentIDs)
+	if err != nil {
+		return err
+	}
+	if err := s.consentRepo.Create(ctx, nil, consents); err != nil {
+		return NewInternalServerError("failed to save user consents", err)
+	}
+
+	for _, consent := range consents {
+		s.audit.Record(ctx, auditEvent{
+			Action: AuditConsentAccept, EntityType: auditEntityConsent, EntityID: consent.DocumentID,
+			TargetUserID: userID,
+			After:        map[string]any{"documentType": consent.DocumentType, "version": consent.Version},
+		})
+	}
+	return nil
+}
+
+// Revoke отзывает необязательное согласие (например, на рекламные рассылки).
+// Обязательные согласия отозвать нельзя: для этого нужно удалить аккаунт.
+func (s *consentService) Revoke(ctx context.Context, userID uint64, documentType string) error {
+	documents, err := s.infoRepo.GetLegalDocuments(ctx)
+	if err != nil {
+		return NewInternalServerError("failed to get legal documents from db", err)
+	}
+
+	var found bool
+	for _, document := range documents {
+		if document.Type != documentType {
+			continue
+		}
+		if document.IsMandatory {
+			return NewBadRequestError("mandatory consent cannot be revoked", nil)
+		}
+		found = true
+	}
+	if !found {
+		return NewNotFoundError("legal document type not found", nil)
+	}
+
+	revoked, err := s.consentRepo.Revoke(ctx, userID, documentType)
+	if err != nil {
+		return NewInternalServerError("failed to revoke consent", err)
+	}
+	if revoked == 0 {
+		return NewNotFoundError("consent is not given", nil)
+	}
+
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditConsentRevoke, EntityType: auditEntityConsent, EntityID: documentType, TargetUserID: userID,
+	})
+	return nil
+}
+
+// HasPendingMandatory сообщает, есть ли обязательные документы, действующую версию которых
+// пользователь еще не принял.
+func (s *consentService) HasPendingMandatory(ctx context.Context, userID uint64) (bool, error) {
+	count, err := s.consentRepo.CountPendingMandatory(ctx, userID)
+	if err != nil {
+		return false, NewInternalServerError("failed to check user consents", err)
+	}
+	return count > 0, nil
+}
+
+// consentsForDocuments проверяет, что все ID - действующие версии документов,
+// и собирает записи о принятии с IP-адресом и User-Agent из контекста запроса.
+func consentsForDocuments(ctx context.Context, documents []models.LegalDocument, userID uint64,
+	documentIDs []uint64,
+) ([]models.UserConsent, error) {
+	current := make(map[uint64]models.LegalDocument, len(documents))
+	for _, document := range documents {
+		current[document.ID] = document
+	}
+
+	meta := requestMetaFrom(ctx)
+	seen := make(map[uint64]bool, len(documentIDs))
+	consents := make([]models.UserConsent, 0, len(documentIDs))
+	for _, id := range documentIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		document, ok := current[id]
+		if !ok {
+			return nil, NewConflictError(
+				fmt.Sprintf("document %d is not a current version, reload legal documents", id), nil)
+		}
+		consents = append(consents, models.UserConsent{
+			UserID:       userID,
+			DocumentID:   document.ID,
+			DocumentType: document.Type,
+			Version:      document.Version,
+			IPAddress:    meta.IPAddress,
+			UserAgent:    meta.UserAgent,
+		})
+	}
+	return consents, nil
+}
+
+// requireMandatoryDocuments возвращает ошибку, если среди принимаемых документов
+// нет действующей версии какого-либо обязательного документа.
+func requireMandatoryDocuments(documents []models.LegalDocument, documentIDs []uint64) error {
+	accepted := make(map[uint64]bool, len(documentIDs))
+	for _, id := range documentIDs {
+		accepted[id] = true
+	}
+	var missing []string
+	for _, document := range documents {
+		if document.IsMandatory && !accepted[document.ID] {
+			missing = append(missing, document.Type)
+		}
+	}
+	if len(missing) > 0 {
+		return NewBadRequestError("mandatory documents must be accepted: "+strings.Join(missing, ", "), nil)
+	}
+	return nil
+}
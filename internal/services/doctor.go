@@ -3,6 +3,8 @@ package services
 import (
 	"context"
 	"errors"
+	"strings"
+	"unicode/utf8"
 
 	"lk/internal/models"
 	"lk/internal/repository"
@@ -20,16 +22,16 @@ func NewDoctorService(repo repository.DoctorRepository) DoctorService {
 	return &doctorService{repo: repo}
 }
 
-// GetDoctorByID получает профиль врача.
-func (s *doctorService) GetDoctorByID(ctx context.Context, doctorID uint64) (models.Doctor, error) {
-	doctor, err := s.repo.GetDoctorByID(ctx, doctorID)
+// GetDoctorByID получает полный профиль врача: образование, ординатуру, курсы, сертификаты и навыки.
+func (s *doctorService) GetDoctorByID(ctx context.Context, doctorID uint64) (models.DoctorProfile, error) {
+	profile, err := s.repo.GetDoctorProfile(ctx, doctorID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return models.Doctor{}, NewNotFoundError("doctor with this ID not found", err)
+			return models.DoctorProfile{}, NewNotFoundError("doctor with this ID not found", err)
 		}
-		return models.Doctor{}, NewInternalServerError("failed to get doctor details from db", err)
+		return models.DoctorProfile{}, NewInternalServerError("failed to get doctor details from db", err)
 	}
-	return doctor, nil
+	return profile, nil
 }
 
 // GetSpecialistRecommendations получает рекомендации от врача.
@@ -47,35 +49,62 @@ func (s *doctorService) GetSpecialistRecommendations(ctx context.Context, doctor
 	return models.Recommendation{Text: text}, nil
 }
 
-// GetDoctorsBySpecialty получает отфильтрованный, отсортированный и пагинированный список врачей.
-func (s *doctorService) GetDoctorsBySpecialty(ctx context.Context, specialtyID uint32, params models.PaginationParams) (
-	models.PaginatedDoctorsResponse, error,
-) {
-	doctors, total, err := s.repo.GetDoctorsBySpecialty(ctx, specialtyID, params)
-	if err != nil {
-		return models.PaginatedDoctorsResponse{}, NewInternalServerError(
-			"failed to get doctors by specialty from db", err)
+// maxSearchLimit - максимальный размер страницы результатов поиска врачей.
+const maxSearchLimit = 100
+
+// SearchDoctors ищет врачей по сочетанию фильтров и считает фасеты для интерфейса фильтров.
+func (s *doctorService) SearchDoctors(
+	ctx context.Context, filter models.DoctorSearchFilter, params models.PaginationParams,
+) (models.DoctorSearchResponse, error) {
+	filter.Query = strings.TrimSpace(filter.Query)
+	filter.Service = strings.TrimSpace(filter.Service)
+	if filter.MaxPrice > 0 && filter.MinPrice > filter.MaxPrice {
+		return models.DoctorSearchResponse{}, NewBadRequestError("minPrice cannot be greater than maxPrice", nil)
+	}
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	switch {
+	case params.Limit < 1:
+		params.Limit = 10
+	case params.Limit > maxSearchLimit:
+		params.Limit = maxSearchLimit
 	}
-	return models.PaginatedDoctorsResponse{
-		Items: doctors,
-		Total: total,
-	}, nil
-}
 
-// SearchDoctors ищет врачей по запросу.
-func (s *doctorService) SearchDoctors(ctx context.Context, query string) ([]models.Doctor, error) {
-	doctors, err := s.repo.SearchDoctors(ctx, query)
+	doctors, total, err := s.repo.SearchDoctors(ctx, filter, params)
 	if err != nil {
-		return nil, NewInternalServerError("failed to search doctors by query from db", err)
+		return models.DoctorSearchResponse{}, NewInternalServerError("failed to search doctors in db", err)
 	}
-	return doctors, nil
+	facets, err := s.repo.GetSearchFacets(ctx, filter)
+	if err != nil {
+		return models.DoctorSearchResponse{}, NewInternalServerError("failed to count search facets", err)
+	}
+	return models.DoctorSearchResponse{Total: total, Items: doctors, Facets: facets}, nil
 }
 
-// SearchDoctorsByService ищет врачей по названию услуги.
-func (s *doctorService) SearchDoctorsByService(ctx context.Context, serviceQuery string) ([]models.Doctor, error) {
-	doctors, err := s.repo.SearchDoctorsByService(ctx, serviceQuery)
+// Границы подсказок поиска.
+const (
+	minAutocompleteLength  = 2
+	defaultAutocompleteMax = 10
+	maxAutocompleteLimit   = 20
+)
+
+// Autocomplete возвращает подсказки для строки поиска. На слишком короткий ввод подсказок нет.
+func (s *doctorService) Autocomplete(ctx context.Context, query string, limit int) ([]models.SearchSuggestion, error) {
+	query = strings.TrimSpace(query)
+	if utf8.RuneCountInString(query) < minAutocompleteLength {
+		return []models.SearchSuggestion{}, nil
+	}
+	switch {
+	case limit < 1:
+		limit = defaultAutocompleteMax
+	case limit > maxAutocompleteLimit:
+		limit = maxAutocompleteLimit
+	}
+
+	suggestions, err := s.repo.Autocomplete(ctx, query, limit)
 	if err != nil {
-		return nil, NewInternalServerError("failed to search doctors by service from db", err)
+		return nil, NewInternalServerError("failed to get search suggestions from db", err)
 	}
-	return doctors, nil
+	return suggestions, nil
 }
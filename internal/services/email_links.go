@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"lk/internal/config"
+	"lk/internal/mail"
+	"lk/internal/repository"
+	"lk/internal/utils"
+)
+
+// emailResetLinkPrefix - ключ кэша для неиспользованных ссылок сброса пароля (по jti).
+const emailResetLinkPrefix = "email_reset:"
+
+// emailLinks формирует и отправляет письма с подписанными ссылками
+// (подтверждение адреса, сброс пароля) и проверяет токены из этих ссылок.
+type emailLinks struct {
+	mailer     mail.Sender
+	cacheRepo  repository.CacheRepository
+	cfg        config.MailConfig
+	signingKey string
+}
+
+// newEmailLinks создает новый экземпляр сервиса ссылок.
+func newEmailLinks(
+	mailer mail.Sender,
+	cacheRepo repository.CacheRepository,
+	cfg config.MailConfig,
+	signingKey string,
+) *emailLinks {
+	return &emailLinks{
+		mailer:     mailer,
+		cacheRepo:  cacheRepo,
+		cfg:        cfg,
+		signingKey: signingKey,
+	}
+}
+
+// SendVerification отправляет письмо со ссылкой для подтверждения адреса.
+func (l *emailLinks) SendVerification(ctx context.Context, userID uint64, email string) error {
+	_, token, err := utils.GenerateLinkToken(userID, email, utils.LinkPurposeVerifyEmail,
+		l.signingKey, l.cfg.VerifyLinkTTL)
+	if err != nil {
+		return NewInternalServerError("failed to generate verification link", err)
+	}
+
+	err = l.mailer.Send(ctx, mail.Message{
+		To:      email,
+		Subject: "Подтверждение адреса электронной почты",
+		Body: fmt.Sprintf("Чтобы подтвердить адрес электронной почты, перейдите по ссылке:\n\n%s\n\n"+
+			"Ссылка действительна %s. Если вы не указывали этот адрес, просто проигнорируйте письмо.\n",
+			buildLink(l.cfg.VerifyEmailURL, token), l.cfg.VerifyLinkTTL),
+	})
+	if err != nil {
+		return NewInternalServerError("failed to send verification email", err)
+	}
+	return nil
+}
+
+// SendPasswordReset отправляет письмо со ссылкой для сброса пароля.
+// Ссылка одноразовая: ее идентификатор хранится в кэше до использования.
+func (l *emailLinks) SendPasswordReset(ctx context.Context, userID uint64, email string) error {
+	claims, token, err := utils.GenerateLinkToken(userID, email, utils.LinkPurposeResetPassword,
+		l.signingKey, l.cfg.ResetLinkTTL)
+	if err != nil {
+		return NewInternalServerError("failed to generate reset link", err)
+	}
+	if err := l.cacheRepo.Set(ctx, emailResetLinkPrefix+claims.ID, userID, l.cfg.ResetLinkTTL); err != nil {
+		return NewInternalServerError("failed to save reset link to cache", err)
+	}
+
+	err = l.mailer.Send(ctx, mail.Message{
+		To:      email,
+		Subject: "Восстановление пароля",
+		Body: fmt.Sprintf("Чтобы задать новый пароль, перейдите по ссылке:\n\n%s\n\n"+
+			"Ссылка действительна %s и может быть использована один раз. "+
+			"Если вы не запрашивали восстановление пароля, просто проигнорируйте письмо.\n",
+			buildLink(l.cfg.ResetPasswordURL, token), l.cfg.ResetLinkTTL),
+	})
+	if err != nil {
+		return NewInternalServerError("failed to send reset email", err)
+	}
+	return nil
+}
+
+// ParseVerification проверяет токен из ссылки подтверждения адреса.
+func (l *emailLinks) ParseVerification(token string) (*utils.LinkClaims, error) {
+	claims, err := utils.ParseLinkToken(token, utils.LinkPurposeVerifyEmail, l.signingKey)
+	if err != nil {
+		return nil, NewBadRequestError("verification link is invalid or expired", err)
+	}
+	return claims, nil
+}
+
+// ConsumePasswordReset проверяет токен из ссылки сброса пароля и аннулирует ссылку.
+func (l *emailLinks) ConsumePasswordReset(ctx context.Context, token string) (*utils.LinkClaims, error) {
+	claims, err := utils.ParseLinkToken(token, utils.LinkPurposeResetPassword, l.signingKey)
+	if err != nil {
+		return nil, NewUnauthorizedError("reset link is invalid or expired", err)
+	}
+
+	key := emailResetLinkPrefix + claims.ID
+	if _, err := l.cacheRepo.Get(ctx, key); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, NewUnauthorizedError("reset link is invalid or expired", nil)
+		}
+		return nil, NewInternalServerError("failed to check reset link", err)
+	}
+	if err := l.cacheRepo.Delete(ctx, key); err != nil {
+		return nil, NewInternalServerError("failed to invalidate reset link", err)
+	}
+	return claims, nil
+}
+
+// buildLink добавляет токен к базовому URL в виде параметра token.
+func buildLink(baseURL, token string) string {
+	separator := "?"
+	if u, err := url.Parse(baseURL); err == nil && u.RawQuery != "" {
+		separator = "&"
+	}
+	return baseURL + separator + "token=" + url.QueryEscape(token)
+}
@@ -45,6 +45,10 @@ func NewConflictError(message string, err error) error {
 	return &AppError{StatusCode: 409, Message: message, err: err}
 }
 
+func NewTooManyRequestsError(message string, err error) error {
+	return &AppError{StatusCode: 429, Message: message, err: err}
+}
+
 func NewInternalServerError(message string, err error) error {
 	return &AppError{StatusCode: 500, Message: message, err: err}
 }
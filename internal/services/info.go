@@ -3,9 +3,11 @@ package services
 import (
 	"context"
 	"errors"
+	"io"
 
 	"lk/internal/models"
 	"lk/internal/repository"
+	"lk/internal/storage"
 
 	"gorm.io/gorm"
 )
@@ -14,13 +16,24 @@ import (
 type infoService struct {
 	serviceRepo repository.ServiceRepository
 	infoRepo    repository.InfoRepository
+	clinicRepo  repository.ClinicRepository
+	legalRepo   repository.LegalRepository
+	storage     storage.FileStorage
+	settings    *clinicSettingsStore
 }
 
 // NewInfoService создает новый сервис для получения общей информации.
-func NewInfoService(serviceRepo repository.ServiceRepository, infoRepo repository.InfoRepository) InfoService {
+func NewInfoService(serviceRepo repository.ServiceRepository, infoRepo repository.InfoRepository,
+	clinicRepo repository.ClinicRepository, legalRepo repository.LegalRepository, storage storage.FileStorage,
+	settings *clinicSettingsStore,
+) InfoService {
 	return &infoService{
 		serviceRepo: serviceRepo,
 		infoRepo:    infoRepo,
+		clinicRepo:  clinicRepo,
+		legalRepo:   legalRepo,
+		storage:     storage,
+		settings:    settings,
 	}
 }
 
@@ -38,18 +51,128 @@ func (s *infoService) GetServiceRecommendations(ctx context.Context, serviceID u
 	return models.Recommendation{Text: text}, nil
 }
 
-// GetClinicInfo получает информацию о клинике.
+// GetClinicInfo собирает сводную информацию о сети: название и контакты основной клиники,
+// контакты поддержки и часы работы из настроек клиники, адреса всех действующих клиник.
+// Если часы работы в настройках не заданы, показываются часы основной клиники.
 func (s *infoService) GetClinicInfo(ctx context.Context) (models.ClinicInfo, error) {
-	info, err := s.infoRepo.GetClinicInfo(ctx)
+	clinics, err := s.clinicRepo.GetAll(ctx)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return models.ClinicInfo{}, NewNotFoundError("clinic info not found", err)
-		}
 		return models.ClinicInfo{}, NewInternalServerError("failed to get clinic info from db", err)
 	}
+	if len(clinics) == 0 {
+		return models.ClinicInfo{}, NewNotFoundError("clinic info not found", nil)
+	}
+
+	// Основная клиника идет первой; если ее нет, сведения берутся из первой по названию
+	primary := clinics[0]
+	info := models.ClinicInfo{
+		Name:         primary.Name,
+		Contacts:     []models.Contact{{Type: "phone", Value: primary.Phone}},
+		Addresses:    make([]models.Address, len(clinics)),
+		WorkingHours: groupWorkingHours(primary.WorkingHours),
+	}
+	if primary.Email != nil {
+		info.Contacts = append(info.Contacts, models.Contact{Type: "email", Value: *primary.Email})
+	}
+	for i, clinic := range clinics {
+		info.Addresses[i] = models.Address{ID: int(clinic.ID), Address: clinic.Address, IsMain: clinic.IsMain}
+	}
+
+	settings := s.settings.Current(ctx)
+	if settings.SupportPhone != "" {
+		info.Contacts = append(info.Contacts, models.Contact{Type: "support_phone", Value: settings.SupportPhone})
+	}
+	if settings.SupportEmail != "" {
+		info.Contacts = append(info.Contacts, models.Contact{Type: "support_email", Value: settings.SupportEmail})
+	}
+	if len(settings.WorkingHours) > 0 {
+		info.WorkingHours = settings.WorkingHours
+	}
 	return info, nil
 }
 
+// weekdayNames - краткие названия дней недели по номеру ISO (1 - понедельник).
+var weekdayNames = [...]string{"", "пн", "вт", "ср", "чт", "пт", "сб", "вс"}
+
+// groupWorkingHours объединяет идущие подряд дни с одинаковыми часами работы, например "пн-пт".
+// Часы должны быть упорядочены по дню недели.
+func groupWorkingHours(hours []models.ClinicWorkingHours) []models.WorkHours {
+	groups := []models.WorkHours{}
+	for i := 0; i < len(hours); {
+		j := i
+		for j+1 < len(hours) && hours[j+1].Weekday == hours[j].Weekday+1 &&
+			hours[j+1].OpensAt == hours[i].OpensAt && hours[j+1].ClosesAt == hours[i].ClosesAt {
+			j++
+		}
+		days := weekdayNames[hours[i].Weekday]
+		if j > i {
+			days += "-" + weekdayNames[hours[j].Weekday]
+		}
+		groups = append(groups, models.WorkHours{Days: days, Hours: hours[i].OpensAt + " - " + hours[i].ClosesAt})
+		i = j + 1
+	}
+	return groups
+}
+
+// GetClinics возвращает действующие клиники сети с адресами, координатами и часами работы.
+func (s *infoService) GetClinics(ctx context.Context) ([]models.Clinic, error) {
+	clinics, err := s.clinicRepo.GetAll(ctx)
+	if err != nil {
+		return nil, NewInternalServerError("failed to get clinics from db", err)
+	}
+	return clinics, nil
+}
+
+// GetClinic возвращает действующую клинику по ID.
+func (s *infoService) GetClinic(ctx context.Context, clinicID uint64) (models.Clinic, error) {
+	clinic, err := s.clinicRepo.GetByID(ctx, clinicID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Clinic{}, NewNotFoundError("clinic not found", err)
+		}
+		return models.Clinic{}, NewInternalServerError("failed to get clinic from db", err)
+	}
+	return clinic, nil
+}
+
+// GetPriceList возвращает прейскурант: действующие услуги каталога по отделениям и категориям
+// с диапазоном цен у врачей, которые их оказывают.
+func (s *infoService) GetPriceList(ctx context.Context) ([]models.PriceListDepartment, error) {
+	rows, err := s.serviceRepo.GetPriceList(ctx)
+	if err != nil {
+		return nil, NewInternalServerError("failed to get price list", err)
+	}
+	return groupPriceList(rows), nil
+}
+
+// groupPriceList собирает строки прейскуранта в отделения и категории.
+// Строки должны быть упорядочены по отделению, затем по категории.
+func groupPriceList(rows []models.PriceListRow) []models.PriceListDepartment {
+	departments := []models.PriceListDepartment{}
+	for _, row := range rows {
+		if n := len(departments); n == 0 || departments[n-1].ID != row.DepartmentID {
+			departments = append(departments, models.PriceListDepartment{ID: row.DepartmentID, Name: row.DepartmentName})
+		}
+		department := &departments[len(departments)-1]
+		if n := len(department.Categories); n == 0 || department.Categories[n-1].ID != row.CategoryID {
+			department.Categories = append(department.Categories, models.PriceListCategory{
+				ID: row.CategoryID, Name: row.CategoryName,
+			})
+		}
+		category := &department.Categories[len(department.Categories)-1]
+		category.Services = append(category.Services, models.PriceListItem{
+			ID:              row.ServiceID,
+			Code:            row.Code,
+			Name:            row.Name,
+			Description:     row.Description.String,
+			DurationMinutes: row.DurationMinutes,
+			PriceFrom:       row.PriceFrom,
+			PriceTo:         row.PriceTo,
+		})
+	}
+	return departments
+}
+
 // GetLegalDocuments получает список юридических документов.
 func (s *infoService) GetLegalDocuments(ctx context.Context) ([]models.LegalDocument, error) {
 	docs, err := s.infoRepo.GetLegalDocuments(ctx)
@@ -58,3 +181,39 @@ func (s *infoService) GetLegalDocuments(ctx context.Context) ([]models.LegalDocu
 	}
 	return docs, nil
 }
+
+// DownloadCurrentLegalDocument возвращает PDF-файл действующей версии документа указанного типа.
+func (s *infoService) DownloadCurrentLegalDocument(ctx context.Context, docType string) ([]byte, string, error) {
+	doc, err := s.legalRepo.GetCurrentByType(ctx, docType)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", NewNotFoundError("legal document not found", err)
+		}
+		return nil, "", NewInternalServerError("failed to get legal document from db", err)
+	}
+	return readLegalDocumentFile(ctx, s.storage, doc)
+}
+
+// readLegalDocumentFile читает PDF-файл версии документа из хранилища.
+func readLegalDocumentFile(ctx context.Context, fileStorage storage.FileStorage, doc models.LegalDocument) (
+	[]byte, string, error,
+) {
+	if doc.FileKey == "" {
+		return nil, "", NewNotFoundError("file is not uploaded for this document", nil)
+	}
+	object, err := fileStorage.Download(ctx, doc.FileKey)
+	if err != nil {
+		return nil, "", NewInternalServerError("could not get file from storage", err)
+	}
+	defer object.Close()
+
+	data, err := io.ReadAll(object)
+	if err != nil {
+		return nil, "", NewInternalServerError("could not read file stream", err)
+	}
+	fileName := doc.FileName
+	if fileName == "" {
+		fileName = doc.Type + "-" + doc.Version + ".pdf"
+	}
+	return data, fileName, nil
+}
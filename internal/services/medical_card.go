@@ -19,6 +19,8 @@ type medicalCardService struct {
 	repo             repository.MedicalCardRepository
 	prescriptionRepo repository.PrescriptionRepository
 	storage          storage.FileStorage
+	auditRepo        repository.AuditRepository
+	audit            *auditor
 }
 
 // NewMedicalCardService создает новый сервис для работы с медкартой.
@@ -26,11 +28,15 @@ func NewMedicalCardService(
 	repo repository.MedicalCardRepository,
 	prescriptionRepo repository.PrescriptionRepository,
 	storage storage.FileStorage,
+	auditRepo repository.AuditRepository,
+	audit *auditor,
 ) MedicalCardService {
 	return &medicalCardService{
 		repo:             repo,
 		prescriptionRepo: prescriptionRepo,
 		storage:          storage,
+		auditRepo:        auditRepo,
+		audit:            audit,
 	}
 }
 
@@ -40,6 +46,7 @@ func (s *medicalCardService) GetVisits(ctx context.Context, userID uint64, param
 	if err != nil {
 		return models.PaginatedVisitsResponse{}, NewInternalServerError("failed to get visits from db", err)
 	}
+	s.recordRead(ctx, userID, "visits")
 
 	items := make([]models.VisitHistoryItem, 0, len(visits))
 	for _, v := range visits {
@@ -63,6 +70,7 @@ func (s *medicalCardService) GetAnalyses(ctx context.Context, userID uint64, sta
 	if err != nil {
 		return nil, NewInternalServerError("failed to get analyses from db", err)
 	}
+	s.recordRead(ctx, userID, "analyses")
 	return analyses, nil
 }
 
@@ -72,6 +80,7 @@ func (s *medicalCardService) GetArchivedPrescriptions(ctx context.Context, userI
 	if err != nil {
 		return nil, NewInternalServerError("failed to get archived prescriptions from db", err)
 	}
+	s.recordRead(ctx, userID, "prescriptions_archive")
 	return prescriptions, nil
 }
 
@@ -81,6 +90,7 @@ func (s *medicalCardService) GetSummary(ctx context.Context, userID uint64) (mod
 	if err != nil {
 		return models.MedicalCardSummary{}, NewInternalServerError("failed to get summary info from db", err)
 	}
+	s.recordRead(ctx, userID, "summary")
 	return summary, nil
 }
 
@@ -101,6 +111,9 @@ func (s *medicalCardService) ArchivePrescription(ctx context.Context, userID, pr
 	if err := s.repo.ArchivePrescription(ctx, userID, prescriptionID); err != nil {
 		return NewInternalServerError("failed to archive prescription", err)
 	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditPrescriptionArchive, EntityType: auditEntityPrescription, EntityID: prescriptionID, TargetUserID: userID,
+	})
 	return nil
 }
 
@@ -134,6 +147,10 @@ func (s *medicalCardService) DownloadFile(ctx context.Context, userID, analysisI
 	}
 	defer fileObject.Close()
 
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditMedicalFileDownload, EntityType: auditEntityLabAnalysis, EntityID: analysisID, TargetUserID: userID,
+	})
+
 	data, err := io.ReadAll(fileObject)
 	if err != nil {
 		log.Printf("ERROR: Could not read file stream for key %s: %v", objectKey, err)
@@ -142,3 +159,34 @@ func (s *medicalCardService) DownloadFile(ctx context.Context, userID, analysisI
 
 	return data, fileName, nil
 }
+
+// GetAccessLog возвращает историю обращений к медицинским данным пациента:
+// кто (сам пациент, сотрудник клиники или сотрудник в режиме имперсонации), когда и какой раздел открывал.
+// При staffOnly возвращаются только обращения сотрудников.
+func (s *medicalCardService) GetAccessLog(ctx context.Context, userID uint64, staffOnly bool,
+	params models.PaginationParams,
+) ([]models.MedicalDataAccess, int64, error) {
+	filter := models.MedicalDataAccessFilter{
+		UserID:    userID,
+		Actions:   []string{AuditMedicalDataRead, AuditMedicalFileDownload},
+		StaffOnly: staffOnly,
+	}
+	items, total, err := s.auditRepo.GetMedicalDataAccess(ctx, filter, params)
+	if err != nil {
+		return nil, 0, NewInternalServerError("failed to get medical data access log", err)
+	}
+	// Для просмотра раздела медкарты раздел хранится в entity_id, для скачивания файла - ID анализа
+	for i := range items {
+		if items[i].Section == auditEntityMedicalCard {
+			items[i].Section, items[i].ResourceID = items[i].ResourceID, ""
+		}
+	}
+	return items, total, nil
+}
+
+// recordRead фиксирует в журнале аудита просмотр раздела медкарты пациента.
+func (s *medicalCardService) recordRead(ctx context.Context, userID uint64, section string) {
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditMedicalDataRead, EntityType: auditEntityMedicalCard, EntityID: section, TargetUserID: userID,
+	})
+}
@@ -12,12 +12,13 @@ import (
 
 // prescriptionService реализует интерфейс PrescriptionService.
 type prescriptionService struct {
-	repo repository.PrescriptionRepository
+	repo  repository.PrescriptionRepository
+	audit *auditor
 }
 
 // NewPrescriptionService создает новый сервис для работы с назначениями.
-func NewPrescriptionService(repo repository.PrescriptionRepository) PrescriptionService {
-	return &prescriptionService{repo: repo}
+func NewPrescriptionService(repo repository.PrescriptionRepository, audit *auditor) PrescriptionService {
+	return &prescriptionService{repo: repo, audit: audit}
 }
 
 // GetActiveForUser получает активные назначения для пользователя.
@@ -26,6 +27,9 @@ func (s *prescriptionService) GetActiveForUser(ctx context.Context, userID uint6
 	if err != nil {
 		return nil, NewInternalServerError("failed to get active prescriptions from db", err)
 	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditMedicalDataRead, EntityType: auditEntityMedicalCard, EntityID: "prescriptions", TargetUserID: userID,
+	})
 	return prescriptions, nil
 }
 
@@ -49,6 +53,9 @@ func (s *prescriptionService) ArchiveForUser(ctx context.Context, userID, prescr
 	if err := s.repo.Archive(ctx, userID, prescriptionID); err != nil {
 		return NewInternalServerError("failed to archive prescription", err)
 	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditPrescriptionArchive, EntityType: auditEntityPrescription, EntityID: prescriptionID, TargetUserID: userID,
+	})
 
 	return nil
 }
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"lk/internal/models"
+	"lk/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// maxReviewCommentLength - максимальная длина текста отзыва в символах.
+const maxReviewCommentLength = 2000
+
+// reviewService реализует интерфейс ReviewService.
+type reviewService struct {
+	repo            repository.ReviewRepository
+	appointmentRepo repository.AppointmentRepository
+	doctorRepo      repository.DoctorRepository
+	audit           *auditor
+}
+
+// NewReviewService создает сервис отзывов пациентов о врачах.
+func NewReviewService(repo repository.ReviewRepository, appointmentRepo repository.AppointmentRepository,
+	doctorRepo repository.DoctorRepository, audit *auditor,
+) ReviewService {
+	return &reviewService{repo: repo, appointmentRepo: appointmentRepo, doctorRepo: doctorRepo, audit: audit}
+}
+
+// CreateReview сохраняет отзыв пациента о завершенном приеме. Отзыв попадает в очередь модерации
+// и не влияет на рейтинг врача до одобрения. На один прием можно оставить один отзыв.
+func (s *reviewService) CreateReview(ctx context.Context, userID, appointmentID uint64, rating uint16,
+	comment string,
+) (models.Review, error) {
+	comment = strings.TrimSpace(comment)
+	if len([]rune(comment)) > maxReviewCommentLength {
+		return models.Review{}, NewBadRequestError("review comment is too long", nil)
+	}
+
+	appointment, err := s.appointmentRepo.GetAppointmentByID(ctx, appointmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Review{}, NewNotFoundError("appointment not found", err)
+		}
+		return models.Review{}, NewInternalServerError("failed to get appointment", err)
+	}
+	if appointment.UserID != userID {
+		return models.Review{}, NewNotFoundError("appointment not found", nil)
+	}
+	if appointment.StatusID != models.StatusCompleted {
+		return models.Review{}, NewBadRequestError("review can be left only for a completed appointment", nil)
+	}
+
+	review := models.Review{
+		UserID:   userID,
+		DoctorID: appointment.DoctorID,
+		Rating:   rating,
+		Status:   models.ReviewPending,
+	}
+	review.AppointmentID.Int64, review.AppointmentID.Valid = int64(appointmentID), true
+	review.Comment.String, review.Comment.Valid = comment, comment != ""
+
+	review, err = s.repo.Create(ctx, review)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key value") {
+			return models.Review{}, NewConflictError("review for this appointment already exists", err)
+		}
+		return models.Review{}, NewInternalServerError("failed to create review", err)
+	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditReviewCreate, EntityType: auditEntityReview, EntityID: review.ID, TargetUserID: userID,
+		After: review,
+	})
+	return review, nil
+}
+
+// GetUserReviews возвращает отзывы пациента вместе со статусом модерации.
+func (s *reviewService) GetUserReviews(ctx context.Context, userID uint64, params models.PaginationParams) (
+	[]models.Review, int64, error,
+) {
+	reviews, total, err := s.repo.GetByUser(ctx, userID, params)
+	if err != nil {
+		return nil, 0, NewInternalServerError("failed to get reviews", err)
+	}
+	return reviews, total, nil
+}
+
+// GetDoctorReviews возвращает одобренные отзывы о враче.
+func (s *reviewService) GetDoctorReviews(ctx context.Context, doctorID uint64, params models.PaginationParams) (
+	[]models.DoctorReview, int64, error,
+) {
+	if _, err := s.doctorRepo.GetDoctorByID(ctx, doctorID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0, NewNotFoundError("specialist not found", err)
+		}
+		return nil, 0, NewInternalServerError("failed to get specialist", err)
+	}
+	reviews, total, err := s.repo.GetApprovedByDoctor(ctx, doctorID, params)
+	if err != nil {
+		return nil, 0, NewInternalServerError("failed to get reviews", err)
+	}
+	return reviews, total, nil
+}
@@ -3,40 +3,62 @@ package services
 
 import (
 	"context"
+	"io"
 	"mime/multipart"
 	"time"
 
+	"lk/internal/config"
+	"lk/internal/mail"
 	"lk/internal/models"
 	"lk/internal/repository"
+	"lk/internal/sms"
 	"lk/internal/storage"
+	"lk/internal/utils"
 )
 
 // Authorization определяет методы для регистрации и входа пользователя.
 type Authorization interface {
 	CreateUser(ctx context.Context, phone, password, fullName,
-		gender, birthDateStr string, cityID uint32) (map[string]string, error)
-	GenerateToken(ctx context.Context, phone, password string) (map[string]string, error)
-	ParseToken(token string) (uint64, error)
+		gender, birthDateStr string, cityID uint32, acceptedDocumentIDs []uint64, clientIP string) error
+	VerifyPhone(ctx context.Context, phone, code, clientIP string) (map[string]string, error)
+	ResendVerificationCode(ctx context.Context, phone, clientIP string) error
+	GenerateToken(ctx context.Context, phone, password, clientIP string) (map[string]string, error)
+	ParseToken(token string) (userID, impersonatorID uint64, err error)
+	JWKS() utils.JWKS
 	RefreshToken(ctx context.Context, refreshToken string) (map[string]string, error)
 	Logout(ctx context.Context, refreshToken string) error
-	ForgotPassword(ctx context.Context, phone string) error
-	ResetPassword(ctx context.Context, phone, code, newPassword string) error
+	ForgotPassword(ctx context.Context, phone, clientIP string) error
+	ResetPassword(ctx context.Context, phone, code, newPassword, clientIP string) error
+	VerifyEmail(ctx context.Context, token string) error
+	ForgotPasswordByEmail(ctx context.Context, email, clientIP string) error
+	ResetPasswordByEmail(ctx context.Context, token, newPassword string) error
 }
 
 // UserService определяет методы для работы с данными пользователя.
 type UserService interface {
 	GetFullUserProfile(ctx context.Context, userID uint64) (models.UserProfile, []models.Appointment, error)
 	UpdateUserProfile(ctx context.Context, userID uint64, input models.UserProfile) (models.UserProfile, error)
-	UpdateAvatar(ctx context.Context, userID uint64, fileHeader *multipart.FileHeader) (string, error)
+	UpdateAvatar(ctx context.Context, userID uint64, fileHeader *multipart.FileHeader) (models.Avatar, error)
+	RequestEmailVerification(ctx context.Context, userID uint64) error
+}
+
+// AccountService определяет методы выгрузки персональных данных и удаления аккаунта пациента.
+type AccountService interface {
+	RequestExport(ctx context.Context, userID uint64) (models.DataExport, error)
+	GetExports(ctx context.Context, userID uint64) ([]models.DataExport, error)
+	DownloadExport(ctx context.Context, userID, exportID uint64) (io.ReadCloser, models.DataExport, error)
+	RequestDeletion(ctx context.Context, userID uint64, password, reason string) (models.AccountDeletion, error)
+	GetDeletion(ctx context.Context, userID uint64) (models.AccountDeletion, error)
+	CancelDeletion(ctx context.Context, userID uint64) error
+	RunJobs(ctx context.Context, interval time.Duration)
 }
 
 // DoctorService определяет методы для работы с информацией о врачах.
 type DoctorService interface {
-	GetDoctorByID(ctx context.Context, doctorID uint64) (models.Doctor, error)
-	GetDoctorsBySpecialty(ctx context.Context, specialtyID uint32, params models.PaginationParams) (
-		models.PaginatedDoctorsResponse, error)
-	SearchDoctors(ctx context.Context, query string) ([]models.Doctor, error)
-	SearchDoctorsByService(ctx context.Context, serviceQuery string) ([]models.Doctor, error)
+	GetDoctorByID(ctx context.Context, doctorID uint64) (models.DoctorProfile, error)
+	SearchDoctors(ctx context.Context, filter models.DoctorSearchFilter, params models.PaginationParams) (
+		models.DoctorSearchResponse, error)
+	Autocomplete(ctx context.Context, query string, limit int) ([]models.SearchSuggestion, error)
 	GetSpecialistRecommendations(ctx context.Context, doctorID uint64) (models.Recommendation, error)
 }
 
@@ -51,20 +73,25 @@ type AppointmentService interface {
 	CreateAppointment(ctx context.Context, appointment models.Appointment) (uint64, error)
 	GetUserAppointments(ctx context.Context, userID uint64) ([]models.Appointment, error)
 	CancelAppointment(ctx context.Context, userID, appointmentID uint64) error
-	GetAvailableDates(ctx context.Context, doctorID, serviceID uint64, month string) (
+	GetAvailableDates(ctx context.Context, doctorID, serviceID, clinicID uint64, month string) (
 		models.AvailableDatesResponse, error)
-	GetAvailableSlots(ctx context.Context, doctorID, serviceID uint64, date string) (
+	GetAvailableSlots(ctx context.Context, doctorID, serviceID, clinicID uint64, date string) (
 		models.AvailableSlotsResponse, error)
-	GetAvailableSlotsByRange(ctx context.Context, doctorID, serviceID uint64, startDate, endDate string) (
+	GetAvailableSlotsByRange(ctx context.Context, doctorID, serviceID, clinicID uint64, startDate, endDate string) (
 		models.AvailableRangeSlotsResponse, error)
 	GetUpcomingForUser(ctx context.Context, userID uint64) ([]models.Appointment, error)
+	RunReminderJob(ctx context.Context, interval time.Duration)
 }
 
 // InfoService определяет методы для работы с общей информацией.
 type InfoService interface {
 	GetServiceRecommendations(ctx context.Context, serviceID uint64) (models.Recommendation, error)
 	GetClinicInfo(ctx context.Context) (models.ClinicInfo, error)
+	GetClinics(ctx context.Context) ([]models.Clinic, error)
+	GetClinic(ctx context.Context, clinicID uint64) (models.Clinic, error)
+	GetPriceList(ctx context.Context) ([]models.PriceListDepartment, error)
 	GetLegalDocuments(ctx context.Context) ([]models.LegalDocument, error)
+	DownloadCurrentLegalDocument(ctx context.Context, docType string) ([]byte, string, error)
 }
 
 // PrescriptionService определяет методы для работы с назначениями.
@@ -82,20 +109,92 @@ type MedicalCardService interface {
 	GetSummary(ctx context.Context, userID uint64) (models.MedicalCardSummary, error)
 	ArchivePrescription(ctx context.Context, userID, prescriptionID uint64) error
 	DownloadFile(ctx context.Context, userID, fileID uint64) ([]byte, string, error)
+	GetAccessLog(ctx context.Context, userID uint64, staffOnly bool, params models.PaginationParams) (
+		[]models.MedicalDataAccess, int64, error)
+}
+
+// ConsentService определяет методы для учета согласий и принятия юридических документов.
+type ConsentService interface {
+	GetConsents(ctx context.Context, userID uint64) ([]models.ConsentStatus, error)
+	GetHistory(ctx context.Context, userID uint64, params models.PaginationParams) ([]models.UserConsent, int64, error)
+	Accept(ctx context.Context, userID uint64, documentIDs []uint64) error
+	Revoke(ctx context.Context, userID uint64, documentType string) error
+	HasPendingMandatory(ctx context.Context, userID uint64) (bool, error)
+}
+
+// ReviewService определяет методы для работы пациентов с отзывами о врачах.
+type ReviewService interface {
+	CreateReview(ctx context.Context, userID, appointmentID uint64, rating uint16, comment string) (models.Review, error)
+	GetUserReviews(ctx context.Context, userID uint64, params models.PaginationParams) ([]models.Review, int64, error)
+	GetDoctorReviews(ctx context.Context, doctorID uint64, params models.PaginationParams) (
+		[]models.DoctorReview, int64, error)
+}
+
+// AuditService определяет методы для просмотра и выгрузки журнала аудита.
+type AuditService interface {
+	GetAuditLogs(ctx context.Context, filter models.AuditLogFilter, params models.PaginationParams) (
+		[]models.AuditLog, int64, error)
+	ExportAuditLogsCSV(ctx context.Context, filter models.AuditLogFilter, w io.Writer) error
 }
 
 // AdminService определяет все методы для администрирования системы.
 type AdminService interface {
 	// Auth & Dashboard
-	Login(ctx context.Context, login, password string) (map[string]string, error)
-	ParseAdminToken(token string) (uint64, error)
+	Login(ctx context.Context, login, password, clientIP, userAgent string) (map[string]string, error)
+	ParseAdminToken(ctx context.Context, token string) (adminID, sessionID uint64, err error)
 	GetDashboardStats(ctx context.Context) (models.AdminDashboardStats, error)
 
+	// Sessions
+	RefreshToken(ctx context.Context, refreshToken, clientIP, userAgent string) (map[string]string, error)
+	Logout(ctx context.Context, refreshToken string) error
+	GetSessions(ctx context.Context, adminID, currentSessionID uint64) ([]models.AdminSession, error)
+	RevokeSession(ctx context.Context, adminID, sessionID uint64) error
+	RevokeSessions(ctx context.Context, adminID, exceptSessionID uint64) (int64, error)
+
+	// Two-factor
+	LoginTwoFactor(ctx context.Context, mfaToken, code, clientIP, userAgent string) (map[string]string, error)
+	GetTwoFactorStatus(ctx context.Context, admin models.Admin) (models.AdminTwoFactorStatus, error)
+	StartTwoFactorEnrollment(ctx context.Context, admin models.Admin) (models.AdminTwoFactorEnrollment, error)
+	ConfirmTwoFactorEnrollment(ctx context.Context, admin models.Admin, code, clientIP string) ([]string, error)
+	RegenerateRecoveryCodes(ctx context.Context, admin models.Admin, code, clientIP string) ([]string, error)
+	DisableTwoFactor(ctx context.Context, admin models.Admin, code, clientIP string) error
+	IsTwoFactorRequired(ctx context.Context) (bool, error)
+
+	// Admins & Roles
+	GetRoles() []models.AdminRoleInfo
+	GetAllAdmins(ctx context.Context, params models.PaginationParams) ([]models.Admin, int64, error)
+	ChangeAdminRole(ctx context.Context, actor models.Admin, adminID uint64, role models.AdminRole) error
+	GetAdminByID(ctx context.Context, adminID uint64) (models.Admin, error)
+	CreateAdmin(ctx context.Context, input CreateAdminInput) (models.Admin, string, error)
+	UpdateAdmin(ctx context.Context, adminID uint64, input UpdateAdminInput) (models.Admin, error)
+	SetAdminActive(ctx context.Context, actor models.Admin, adminID uint64, active bool) error
+	ResetAdminPassword(ctx context.Context, adminID uint64) (string, error)
+	ResetAdminTwoFactor(ctx context.Context, adminID uint64) error
+	ChangeOwnPassword(ctx context.Context, admin models.Admin, sessionID uint64, input ChangeOwnPasswordInput) error
+
+	// Security
+	GetLockoutEvents(ctx context.Context, params models.PaginationParams) ([]models.LockoutEvent, int64, error)
+	Unlock(ctx context.Context, input UnlockInput) error
+	GetSecuritySettings(ctx context.Context) (models.SecuritySettings, error)
+	UpdateSecuritySettings(ctx context.Context, actor models.Admin, input UpdateSecuritySettingsInput) (
+		models.SecuritySettings, error)
+	GetClinicSettings(ctx context.Context) (models.ClinicSettingsVersion, error)
+	UpdateClinicSettings(ctx context.Context, actor models.Admin, input UpdateClinicSettingsInput) (
+		models.ClinicSettingsVersion, error)
+	GetClinicSettingsHistory(ctx context.Context, params models.PaginationParams) (
+		[]models.ClinicSettingsVersion, int64, error)
+
 	// User
 	GetAllUsers(ctx context.Context, params models.PaginationParams) ([]models.User, int64, error)
 	GetUserByID(ctx context.Context, userID uint64) (*models.User, *models.UserProfile, error)
+	ImpersonateUser(ctx context.Context, actor models.Admin, userID uint64) (models.ImpersonationToken, error)
+	RecordImpersonatedRequest(ctx context.Context, entry models.ImpersonationLog) error
+	GetImpersonationLogs(ctx context.Context, filter models.ImpersonationLogFilter, params models.PaginationParams) (
+		[]models.ImpersonationLog, int64, error)
 	UpdateUser(ctx context.Context, userID uint64, input UpdateUserInput) error
 	DeleteUser(ctx context.Context, userID uint64) error
+	GetDeletedUsers(ctx context.Context, params models.PaginationParams) ([]models.User, int64, error)
+	RestoreUser(ctx context.Context, userID uint64) error
 	GetUserAppointments(ctx context.Context, userID uint64, params models.PaginationParams) (
 		[]models.Appointment, int64, error)
 	GetUserAnalyses(ctx context.Context, userID uint64, params models.PaginationParams) (
@@ -104,11 +203,26 @@ type AdminService interface {
 	// Doctor
 	GetAllSpecialists(ctx context.Context, params models.PaginationParams) ([]models.Doctor, int64, error)
 	CreateSpecialist(ctx context.Context, input CreateDoctorInput) (uint64, error)
-	GetSpecialistByID(ctx context.Context, doctorID uint64) (models.Doctor, error)
+	GetSpecialistByID(ctx context.Context, doctorID uint64) (models.DoctorProfile, error)
 	UpdateSpecialist(ctx context.Context, doctorID uint64, input UpdateDoctorInput) error
 	DeleteSpecialist(ctx context.Context, doctorID uint64) error
+	GetDeletedSpecialists(ctx context.Context, params models.PaginationParams) ([]models.Doctor, int64, error)
+	RestoreSpecialist(ctx context.Context, doctorID uint64) error
 	GetSpecialistSchedule(ctx context.Context, doctorID uint64) ([]models.Schedule, error)
 	UpdateSpecialistSchedule(ctx context.Context, doctorID uint64, input UpdateScheduleInput) error
+	SetSpecialistClinics(ctx context.Context, doctorID uint64, input SetDoctorClinicsInput) ([]models.Clinic, error)
+	SetSpecialistServices(ctx context.Context, actor models.Admin, doctorID uint64, input SetDoctorServicesInput) (
+		[]models.DoctorService, error)
+	CreateDoctorProfileItem(ctx context.Context, doctorID uint64, section string, input DoctorProfileItemInput) (
+		any, error)
+	UpdateDoctorProfileItem(ctx context.Context, doctorID, itemID uint64, section string,
+		input DoctorProfileItemInput) (any, error)
+	DeleteDoctorProfileItem(ctx context.Context, doctorID, itemID uint64, section string) error
+	UpdateSpecialistAvatar(ctx context.Context, doctorID uint64, fileHeader *multipart.FileHeader) (models.Avatar, error)
+	DeleteSpecialistAvatar(ctx context.Context, doctorID uint64) error
+	GetExpiringCertificates(ctx context.Context, days int, params models.PaginationParams) (
+		[]models.ExpiringCertificate, int64, error)
+	RunCertificateAlertJob(ctx context.Context, interval time.Duration)
 
 	// Appointment
 	GetAllAppointments(ctx context.Context, params models.PaginationParams, filters map[string]interface{}) (
@@ -117,16 +231,61 @@ type AdminService interface {
 	GetAppointmentDetails(ctx context.Context, appointmentID uint64) (models.Appointment, error)
 	UpdateAppointmentStatus(ctx context.Context, appointmentID uint64, statusID uint32) error
 	DeleteAppointment(ctx context.Context, appointmentID uint64) error
+	GetDeletedAppointments(ctx context.Context, params models.PaginationParams) ([]models.Appointment, int64, error)
+	RestoreAppointment(ctx context.Context, appointmentID uint64) error
 
 	// Service & Department
 	GetAllServices(ctx context.Context) ([]models.Service, error)
-	CreateService(ctx context.Context, input CreateServiceInput) (uint64, error)
-	UpdateService(ctx context.Context, serviceID uint64, input UpdateServiceInput) error
+	GetService(ctx context.Context, serviceID uint64) (models.Service, error)
+	CreateService(ctx context.Context, actor models.Admin, input CreateServiceInput) (uint64, error)
+	UpdateService(ctx context.Context, actor models.Admin, serviceID uint64, input UpdateServiceInput) error
+	GetServicePriceHistory(ctx context.Context, serviceID uint64, params models.PaginationParams) (
+		[]models.ServicePriceChange, int64, error)
 	DeleteService(ctx context.Context, serviceID uint64) error
+	GetDeletedServices(ctx context.Context, params models.PaginationParams) ([]models.Service, int64, error)
+	RestoreService(ctx context.Context, serviceID uint64) error
+	GetServiceCategories(ctx context.Context) ([]models.ServiceCategory, error)
+	CreateServiceCategory(ctx context.Context, input ServiceCategoryInput) (models.ServiceCategory, error)
+	UpdateServiceCategory(ctx context.Context, categoryID uint32, input ServiceCategoryInput) (
+		models.ServiceCategory, error)
+	DeleteServiceCategory(ctx context.Context, categoryID uint32) error
 	GetAllDepartments(ctx context.Context) ([]models.Department, error)
 	CreateDepartment(ctx context.Context, input CreateDepartmentInput) (uint32, error)
 	UpdateDepartment(ctx context.Context, departmentID uint32, input UpdateDepartmentInput) error
 	DeleteDepartment(ctx context.Context, departmentID uint32) error
+	GetDeletedDepartments(ctx context.Context, params models.PaginationParams) ([]models.Department, int64, error)
+	RestoreDepartment(ctx context.Context, departmentID uint32) error
+
+	// Clinic
+	GetAllClinics(ctx context.Context) ([]models.Clinic, error)
+	GetClinic(ctx context.Context, clinicID uint64) (models.Clinic, error)
+	CreateClinic(ctx context.Context, input ClinicInput) (models.Clinic, error)
+	UpdateClinic(ctx context.Context, clinicID uint64, input ClinicInput) (models.Clinic, error)
+	DeleteClinic(ctx context.Context, clinicID uint64) error
+	GetDeletedClinics(ctx context.Context, params models.PaginationParams) ([]models.Clinic, int64, error)
+	RestoreClinic(ctx context.Context, clinicID uint64) error
+
+	// Мягкое удаление
+	RunPurgeJob(ctx context.Context, interval time.Duration)
+
+	// Модерация отзывов
+	GetReviews(ctx context.Context, filter models.ReviewFilter, params models.PaginationParams) (
+		[]models.Review, int64, error)
+	ApproveReview(ctx context.Context, actor models.Admin, reviewID uint64) (models.Review, error)
+	RejectReview(ctx context.Context, actor models.Admin, reviewID uint64, reason string) (models.Review, error)
+
+	// Legal documents
+	GetLegalDocumentTypes(ctx context.Context) ([]models.LegalDocumentType, error)
+	GetLegalDocuments(ctx context.Context, filter models.LegalDocumentFilter, params models.PaginationParams) (
+		[]models.LegalDocument, int64, error)
+	GetLegalDocument(ctx context.Context, id uint64) (models.LegalDocument, error)
+	CreateLegalDocument(ctx context.Context, actor models.Admin, input CreateLegalDocumentInput,
+		file *multipart.FileHeader) (models.LegalDocument, error)
+	UpdateLegalDocument(ctx context.Context, id uint64, input UpdateLegalDocumentInput,
+		file *multipart.FileHeader) (models.LegalDocument, error)
+	PublishLegalDocument(ctx context.Context, id uint64) (models.LegalDocument, error)
+	DeleteLegalDocument(ctx context.Context, id uint64) error
+	DownloadLegalDocument(ctx context.Context, id uint64) ([]byte, string, error)
 
 	// TODO: Реализовать другие методы бизнес-логики (Analyses, Prescriptions, Family, Settings, и т.д.)
 }
@@ -148,6 +307,8 @@ type CreateDoctorInput struct {
 	SpecialtyID     uint32  `json:"specialtyId" binding:"required"`
 	ExperienceYears uint16  `json:"experienceYears" binding:"required"`
 	Recommendations *string `json:"recommendations"`
+	Gender          *string `json:"gender" binding:"omitempty,oneof=male female"`
+	AcceptsDMS      bool    `json:"acceptsDMS"`
 }
 
 type UpdateDoctorInput struct {
@@ -157,36 +318,117 @@ type UpdateDoctorInput struct {
 	SpecialtyID     *uint32 `json:"specialtyId"`
 	ExperienceYears *uint16 `json:"experienceYears"`
 	Recommendations *string `json:"recommendations"`
+	Gender          *string `json:"gender" binding:"omitempty,oneof=male female"`
+	AcceptsDMS      *bool   `json:"acceptsDMS"`
 }
 
 type ScheduleItem struct {
 	Date      string `json:"date" binding:"required"`      // YYYY-MM-DD
 	StartTime string `json:"startTime" binding:"required"` // HH:MM
 	EndTime   string `json:"endTime" binding:"required"`   // HH:MM
+	ClinicID  uint64 `json:"clinicId"`                     // Можно не указывать, если врач работает в одной клинике
 }
 
 type UpdateScheduleInput struct {
 	Schedules []ScheduleItem `json:"schedules"`
 }
 
+// SetDoctorClinicsInput - полный набор клиник, в которых работает врач.
+type SetDoctorClinicsInput struct {
+	ClinicIDs []uint64 `json:"clinicIds" binding:"required"`
+}
+
+// DoctorServiceInput - услуга врача. Без price и durationMinutes действуют базовые значения услуги.
+type DoctorServiceInput struct {
+	ServiceID       uint64   `json:"serviceId" binding:"required"`
+	Price           *float64 `json:"price" binding:"omitempty,gte=0"`
+	DurationMinutes *uint16  `json:"durationMinutes" binding:"omitempty,min=1"`
+}
+
+// SetDoctorServicesInput - полный набор услуг, которые оказывает врач.
+type SetDoctorServicesInput struct {
+	Services []DoctorServiceInput `json:"services" binding:"required,dive"`
+}
+
 type CreateServiceInput struct {
+	Code            string  `json:"code" binding:"required,max=32"`
 	Name            string  `json:"name" binding:"required"`
-	Price           float64 `json:"price" binding:"required"`
-	DurationMinutes uint16  `json:"durationMinutes" binding:"required"`
+	CategoryID      uint32  `json:"categoryId" binding:"required"`
+	BasePrice       float64 `json:"basePrice" binding:"gte=0"`
+	DurationMinutes uint16  `json:"durationMinutes" binding:"required,min=1"`
 	Description     *string `json:"description"`
-	DoctorID        uint64  `json:"doctorId" binding:"required"`
 	Recommendations *string `json:"recommendations"`
 }
 
 type UpdateServiceInput struct {
+	Code            *string  `json:"code" binding:"omitempty,max=32"`
 	Name            *string  `json:"name"`
-	Price           *float64 `json:"price"`
-	DurationMinutes *uint16  `json:"durationMinutes"`
+	CategoryID      *uint32  `json:"categoryId"`
+	BasePrice       *float64 `json:"basePrice" binding:"omitempty,gte=0"`
+	DurationMinutes *uint16  `json:"durationMinutes" binding:"omitempty,min=1"`
 	Description     *string  `json:"description"`
-	DoctorID        *uint64  `json:"doctorId"`
 	Recommendations *string  `json:"recommendations"`
 }
 
+// ServiceCategoryInput - категория каталога услуг.
+type ServiceCategoryInput struct {
+	Name         string `json:"name" binding:"required,max=150"`
+	DepartmentID uint32 `json:"departmentId" binding:"required"`
+}
+
+type UnlockInput struct {
+	Scope      string `json:"scope" binding:"required,oneof=user_login admin_login admin_2fa password_reset phone_verify ip"`
+	Identifier string `json:"identifier" binding:"required"`
+}
+
+type ChangeAdminRoleInput struct {
+	Role models.AdminRole `json:"role" binding:"required,oneof=superadmin admin receptionist lab_technician"`
+}
+
+type CreateAdminInput struct {
+	Login    string `json:"login" binding:"required,min=3,max=100"`
+	FullName string `json:"fullName" binding:"required,max=255"`
+	// Email - адрес для служебных уведомлений (например, об истекающих сертификатах врачей).
+	Email string           `json:"email" binding:"omitempty,max=255"`
+	Role  models.AdminRole `json:"role" binding:"required,oneof=superadmin admin receptionist lab_technician"`
+	// Password - начальный пароль; если не задан, будет сгенерирован временный.
+	Password string `json:"password" binding:"omitempty,min=8"`
+}
+
+type UpdateAdminInput struct {
+	Login    *string `json:"login" binding:"omitempty,min=3,max=100"`
+	FullName *string `json:"fullName" binding:"omitempty,max=255"`
+	// Email - пустая строка удаляет адрес.
+	Email *string `json:"email" binding:"omitempty,max=255"`
+}
+
+type ChangeOwnPasswordInput struct {
+	CurrentPassword string `json:"currentPassword" binding:"required"`
+	NewPassword     string `json:"newPassword" binding:"required,min=8"`
+}
+
+type UpdateSecuritySettingsInput struct {
+	RequireAdmin2FA *bool `json:"requireAdmin2fa" binding:"required"`
+}
+
+// UpdateClinicSettingsInput - полный набор настроек клиники. Version - номер версии,
+// которую редактировал администратор; сохранение поверх более новой версии отклоняется.
+type UpdateClinicSettingsInput struct {
+	Version                 uint64             `json:"version" binding:"required"`
+	CancellationWindowHours int                `json:"cancellationWindowHours" binding:"min=0,max=168"`
+	ReminderOffsetsMinutes  []int              `json:"reminderOffsetsMinutes" binding:"max=5,dive,min=5,max=10080"`
+	SlotGranularityMinutes  int                `json:"slotGranularityMinutes" binding:"oneof=0 5 10 15 20 30 60"`
+	BookingHorizonDays      int                `json:"bookingHorizonDays" binding:"min=1,max=365"`
+	SupportPhone            string             `json:"supportPhone" binding:"max=20"`
+	SupportEmail            string             `json:"supportEmail" binding:"omitempty,email,max=255"`
+	WorkingHours            []models.WorkHours `json:"workingHours" binding:"max=7"`
+}
+
+// UpdateAppointmentStatusInput - новый статус записи на прием.
+type UpdateAppointmentStatusInput struct {
+	StatusID uint32 `json:"statusId" binding:"required"`
+}
+
 type CreateDepartmentInput struct {
 	Name string `json:"name" binding:"required"`
 }
@@ -195,50 +437,147 @@ type UpdateDepartmentInput struct {
 	Name *string `json:"name"`
 }
 
+// ClinicWorkingHoursInput - часы работы клиники в один день недели.
+type ClinicWorkingHoursInput struct {
+	Weekday  uint8  `json:"weekday" binding:"required,min=1,max=7"` // 1 - понедельник, 7 - воскресенье
+	OpensAt  string `json:"opensAt" binding:"required"`             // HH:MM
+	ClosesAt string `json:"closesAt" binding:"required"`            // HH:MM
+}
+
+// ClinicInput - данные клиники при создании и полном обновлении. Дни, которых нет в workingHours, - выходные.
+type ClinicInput struct {
+	Name         string                    `json:"name" binding:"required,max=150"`
+	Address      string                    `json:"address" binding:"required,max=512"`
+	Phone        string                    `json:"phone" binding:"required,max=20"`
+	Email        *string                   `json:"email" binding:"omitempty,email,max=255"`
+	CityID       *uint32                   `json:"cityID"`
+	Latitude     *float64                  `json:"latitude" binding:"required_with=Longitude,omitempty,min=-90,max=90"`
+	Longitude    *float64                  `json:"longitude" binding:"required_with=Latitude,omitempty,min=-180,max=180"`
+	IsMain       bool                      `json:"isMain"`
+	WorkingHours []ClinicWorkingHoursInput `json:"workingHours" binding:"max=7,dive"`
+}
+
+// CreateLegalDocumentInput - поля новой версии документа; файл передается отдельно в multipart-форме.
+type CreateLegalDocumentInput struct {
+	Type       string `form:"type" binding:"required,max=100"`
+	Title      string `form:"title" binding:"required,max=255"`
+	Version    string `form:"version" binding:"required,max=20"`
+	UpdateDate string `form:"updateDate" binding:"required"` // YYYY-MM-DD
+}
+
+type UpdateLegalDocumentInput struct {
+	Title      *string `form:"title" binding:"omitempty,max=255"`
+	Version    *string `form:"version" binding:"omitempty,max=20"`
+	UpdateDate *string `form:"updateDate"` // YYYY-MM-DD
+}
+
+// DoctorEducationInput - запись об образовании врача.
+type DoctorEducationInput struct {
+	Institution string `json:"institution" binding:"required,max=255"`
+	Specialty   string `json:"specialty" binding:"required,max=255"`
+	StartYear   uint16 `json:"startYear" binding:"required,min=1900,max=2100"`
+	EndYear     uint16 `json:"endYear" binding:"required,min=1900,max=2100,gtefield=StartYear"`
+}
+
+// DoctorResidencyInput - запись об ординатуре врача.
+type DoctorResidencyInput DoctorEducationInput
+
+type DoctorCourseInput struct {
+	CourseName string `json:"courseName" binding:"required,max=255"`
+	Year       uint16 `json:"year" binding:"required,min=1900,max=2100"`
+}
+
+type DoctorCertificateInput struct {
+	CertName   string  `json:"certName" binding:"required,max=255"`
+	CertNumber *string `json:"certNumber" binding:"omitempty,max=100"`
+	IssuedAt   *string `json:"issuedAt"`  // YYYY-MM-DD
+	ExpiresAt  *string `json:"expiresAt"` // YYYY-MM-DD
+}
+
+type DoctorSkillInput struct {
+	SkillName string `json:"skillName" binding:"required,max=255"`
+}
+
+type DoctorSpecializationInput struct {
+	Area string `json:"area" binding:"required,max=255"`
+}
+
 // --- Service Контейнер ---
 
 // Service - это контейнер для всех сервисов приложения.
 type Service struct {
 	Authorization Authorization
 	User          UserService
+	Account       AccountService
 	Doctor        DoctorService
 	Appointment   AppointmentService
 	Directory     DirectoryService
 	Info          InfoService
+	Consent       ConsentService
+	Review        ReviewService
 	Prescription  PrescriptionService
 	MedicalCard   MedicalCardService
 	Admin         AdminService
+	Audit         AuditService
 }
 
 // ServiceDependencies содержит все зависимости, необходимые для создания сервисов.
 type ServiceDependencies struct {
-	Repos      *repository.Repository
-	Storage    storage.FileStorage
-	Location   *time.Location
-	SigningKey string
-	TokenTTL   time.Duration
+	Repos         *repository.Repository
+	Storage       storage.FileStorage
+	Location      *time.Location
+	SigningKey    string
+	Keys          *utils.KeySet
+	PatientTokens utils.TokenScope
+	AdminTokens   utils.TokenScope
+	Security      config.SecurityConfig
+	SMS           sms.Sender
+	Mailer        mail.Sender
+	Mail          config.MailConfig
+	Account       config.AccountConfig
+	Retention     config.RetentionConfig
+	Certificates  config.CertificateConfig
 }
 
 // NewService создает новый экземпляр главного сервиса, инициализируя все реализации.
 func NewService(deps ServiceDependencies) *Service {
+	guard := newBruteForceGuard(deps.Repos.Cache, deps.Repos.Security, deps.Security)
+	links := newEmailLinks(deps.Mailer, deps.Repos.Cache, deps.Mail, deps.SigningKey)
+	audit := newAuditor(deps.Repos.Audit)
+	settings := newClinicSettingsStore(deps.Repos.Settings, deps.Repos.Cache)
+
 	authService := NewAuthService(
 		deps.Repos.User,
 		deps.Repos.Token,
 		deps.Repos.Cache,
+		deps.Repos.Info,
+		deps.Repos.Consent,
 		deps.Repos.Transactor,
-		deps.SigningKey,
-		deps.TokenTTL,
+		guard,
+		deps.SMS,
+		links,
+		deps.Keys,
+		deps.PatientTokens,
+		audit,
 	)
 
 	return &Service{
 		Authorization: authService,
-		User:          NewUserService(deps.Repos.User, deps.Repos.Appointment, deps.Storage),
+		User:          NewUserService(deps.Repos.User, deps.Repos.Appointment, deps.Storage, links, audit),
+		Account:       NewAccountService(deps.Repos.Account, deps.Repos.User, deps.Storage, deps.Account, audit),
 		Doctor:        NewDoctorService(deps.Repos.Doctor),
-		Appointment:   NewAppointmentService(deps.Repos.Appointment, deps.Repos.Doctor, deps.Location),
-		Directory:     NewDirectoryService(deps.Repos.Directory),
-		Info:          NewInfoService(deps.Repos.Service, deps.Repos.Info),
-		Prescription:  NewPrescriptionService(deps.Repos.Prescription),
-		MedicalCard:   NewMedicalCardService(deps.Repos.MedicalCard, deps.Repos.Prescription, deps.Storage),
-		Admin:         NewAdminService(deps.Repos, deps.SigningKey, deps.TokenTTL),
+		Appointment: NewAppointmentService(deps.Repos.Appointment, deps.Repos.Doctor, deps.Repos.Cache, deps.SMS,
+			deps.Location, settings, audit),
+		Directory: NewDirectoryService(deps.Repos.Directory),
+		Info: NewInfoService(deps.Repos.Service, deps.Repos.Info, deps.Repos.Clinic, deps.Repos.Legal,
+			deps.Storage, settings),
+		Consent:      NewConsentService(deps.Repos.Info, deps.Repos.Consent, audit),
+		Review:       NewReviewService(deps.Repos.Review, deps.Repos.Appointment, deps.Repos.Doctor, audit),
+		Prescription: NewPrescriptionService(deps.Repos.Prescription, audit),
+		MedicalCard: NewMedicalCardService(deps.Repos.MedicalCard, deps.Repos.Prescription, deps.Storage,
+			deps.Repos.Audit, audit),
+		Admin: NewAdminService(deps.Repos, guard, deps.Keys, deps.AdminTokens, deps.PatientTokens,
+			deps.Storage, deps.Retention, deps.Certificates, settings, audit, deps.Security.TOTPIssuer, deps.Mailer),
+		Audit: NewAuditService(deps.Repos.Audit),
 	}
 }
@@ -2,15 +2,17 @@ package services
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"log"
 	"mime/multipart"
-	"path/filepath"
+	"strconv"
+	"strings"
 
 	"lk/internal/models"
 	"lk/internal/repository"
 	"lk/internal/storage"
 
-	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // userService реализует интерфейс UserService.
@@ -18,16 +20,20 @@ type userService struct {
 	userRepo    repository.UserRepository
 	appointRepo repository.AppointmentRepository
 	storage     storage.FileStorage
+	emailLinks  *emailLinks
+	audit       *auditor
 }
 
 // NewUserService создает новый сервис для работы с данными пользователя.
 func NewUserService(userRepo repository.UserRepository, appointRepo repository.AppointmentRepository,
-	storage storage.FileStorage,
+	storage storage.FileStorage, emailLinks *emailLinks, audit *auditor,
 ) UserService {
 	return &userService{
 		userRepo:    userRepo,
 		appointRepo: appointRepo,
 		storage:     storage,
+		emailLinks:  emailLinks,
+		audit:       audit,
 	}
 }
 
@@ -51,42 +57,94 @@ func (s *userService) GetFullUserProfile(ctx context.Context, userID uint64) (
 }
 
 // UpdateUserProfile обновляет профиль пользователя.
+// При смене адреса электронной почты подтверждение сбрасывается и на новый адрес отправляется ссылка.
 func (s *userService) UpdateUserProfile(ctx context.Context, userID uint64, input models.UserProfile) (
 	models.UserProfile, error,
 ) {
 	input.UserID = userID
+
+	current, err := s.userRepo.GetUserProfileByUserID(ctx, userID)
+	if err != nil {
+		return models.UserProfile{}, NewInternalServerError("failed to get user profile from db", err)
+	}
+
+	emailChanged := false
+	if input.Email.Valid {
+		email, err := normalizeEmail(input.Email.String)
+		if err != nil {
+			return models.UserProfile{}, err
+		}
+
+		if current.Email.Valid && strings.EqualFold(current.Email.String, email) {
+			input.Email.Valid = false
+		} else {
+			owner, err := s.userRepo.GetUserProfileByEmail(ctx, email)
+			if err == nil && owner.UserID != userID {
+				return models.UserProfile{}, NewConflictError("email is already in use", nil)
+			}
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return models.UserProfile{}, NewInternalServerError("database error while checking email", err)
+			}
+			input.Email.String = email
+			emailChanged = true
+		}
+	}
+
 	updatedProfile, err := s.userRepo.UpdateUserProfile(ctx, input)
 	if err != nil {
 		return models.UserProfile{}, NewInternalServerError("failed to update user profile in db", err)
 	}
+	s.audit.Record(ctx, auditEvent{
+		Action: AuditProfileUpdate, EntityType: auditEntityUser, EntityID: userID, TargetUserID: userID,
+		Before: current, After: updatedProfile,
+	})
+
+	if emailChanged {
+		// Профиль уже сохранен; при сбое отправки пользователь может запросить ссылку повторно.
+		if err := s.emailLinks.SendVerification(ctx, userID, updatedProfile.Email.String); err != nil {
+			log.Printf("WARN: failed to send verification email to user %d: %v", userID, err)
+		}
+	}
 	return updatedProfile, nil
 }
 
-// UpdateAvatar обрабатывает логику обновления аватара.
+// RequestEmailVerification повторно отправляет ссылку для подтверждения текущего адреса.
+func (s *userService) RequestEmailVerification(ctx context.Context, userID uint64) error {
+	profile, err := s.userRepo.GetUserProfileByUserID(ctx, userID)
+	if err != nil {
+		return NewInternalServerError("failed to get user profile from db", err)
+	}
+	if !profile.Email.Valid || profile.Email.String == "" {
+		return NewBadRequestError("email address is not set", nil)
+	}
+	if profile.EmailVerifiedAt.Valid {
+		return NewConflictError("email address is already verified", nil)
+	}
+	return s.emailLinks.SendVerification(ctx, userID, profile.Email.String)
+}
+
+// UpdateAvatar загружает новый аватар пользователя и удаляет из хранилища предыдущий.
 func (s *userService) UpdateAvatar(ctx context.Context, userID uint64, fileHeader *multipart.FileHeader) (
-	string, error,
+	models.Avatar, error,
 ) {
-	file, err := fileHeader.Open()
+	profile, err := s.userRepo.GetUserProfileByUserID(ctx, userID)
 	if err != nil {
-		return "", NewInternalServerError("failed to open avatar file", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Avatar{}, NewNotFoundError("user profile not found", err)
+		}
+		return models.Avatar{}, NewInternalServerError("failed to get user profile", err)
 	}
-	defer file.Close()
 
-	ext := filepath.Ext(fileHeader.Filename)
-	objectKey := fmt.Sprintf("avatars/%d/%s%s", userID, uuid.New().String(), ext)
-
-	err = s.storage.Upload(ctx, file, fileHeader.Size, fileHeader.Header.Get(
-		"Content-Type"), objectKey)
+	avatar, err := uploadAvatar(ctx, s.storage, strconv.FormatUint(userID, 10), fileHeader)
 	if err != nil {
-		return "", NewInternalServerError("failed to upload avatar", err)
+		return models.Avatar{}, err
 	}
 
-	err = s.userRepo.UpdateAvatar(ctx, userID, objectKey)
-	if err != nil {
-		return "", NewInternalServerError("failed to save avatar url to db", err)
+	if err := s.userRepo.UpdateAvatar(ctx, userID, avatar.URL); err != nil {
+		deleteAvatar(ctx, s.storage, avatar.URL)
+		return models.Avatar{}, NewInternalServerError("failed to save avatar url to db", err)
 	}
+	deleteAvatar(ctx, s.storage, profile.AvatarURL.String)
 
-	//* Возвращаем полный "URL" или ключ, как решит фронтенд
-	// В данном случае, это ключ объекта в MinIO.
-	return objectKey, nil
+	return avatar, nil
 }
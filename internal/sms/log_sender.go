@@ -0,0 +1,26 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+
+	"lk/internal/logger"
+)
+
+// LogSender - заглушка SMS-шлюза, которая пишет сообщения в лог приложения.
+// Используется до подключения реального провайдера.
+type LogSender struct {
+	senderName string
+}
+
+// NewLogSender создает новый экземпляр отправителя-заглушки.
+func NewLogSender(senderName string) *LogSender {
+	return &LogSender{senderName: senderName}
+}
+
+// Send выводит сообщение в лог вместо реальной отправки.
+func (s *LogSender) Send(_ context.Context, phone, text string) error {
+	logger.Default().WithField("module", "SMS").Info(
+		fmt.Sprintf("!!! MOCK SMS !!! from %s to %s: %s", s.senderName, phone, text))
+	return nil
+}
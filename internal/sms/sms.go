@@ -0,0 +1,10 @@
+// Package sms предоставляет абстракцию для отправки SMS-сообщений.
+package sms
+
+import "context"
+
+// Sender определяет интерфейс для отправки SMS.
+type Sender interface {
+	// Send отправляет текстовое сообщение на номер в формате E.164.
+	Send(ctx context.Context, phone, text string) error
+}
@@ -62,3 +62,8 @@ func (m *MinIOClient) Download(ctx context.Context, objectKey string) (io.ReadCl
 	}
 	return object, nil
 }
+
+// Delete удаляет файл из MinIO.
+func (m *MinIOClient) Delete(ctx context.Context, objectKey string) error {
+	return m.client.RemoveObject(ctx, m.bucketName, objectKey, minio.RemoveObjectOptions{})
+}
@@ -15,4 +15,7 @@ type FileStorage interface {
 	// Download скачивает файл из хранилища по его ключу.
 	// Возвращает объект файла (io.ReadCloser) и ошибку.
 	Download(ctx context.Context, objectKey string) (io.ReadCloser, error)
+
+	// Delete удаляет файл из хранилища. Удаление несуществующего файла не является ошибкой.
+	Delete(ctx context.Context, objectKey string) error
 }
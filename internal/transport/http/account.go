@@ -0,0 +1,187 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"lk/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary      Запросить выгрузку своих данных
+// @Security     ApiKeyAuth
+// @Tags         account
+// @Description  Ставит в очередь сборку ZIP-архива с профилем, записями на прием, анализами (вместе с файлами),
+// @Description  назначениями и историей согласий. Состояние выгрузки - в GET /account/exports.
+// @Description  Одновременно может собираться только одна выгрузка (409).
+// @Id           request-data-export
+// @Produce      json
+// @Success      202 {object} models.DataExport
+// @Failure      401,403,409,500 {object} errorResponse
+// @Router       /account/exports [post]
+func (h *Handler) requestDataExport(c *gin.Context) {
+	userProfile, err := getUserProfile(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to get user from context", err))
+		return
+	}
+
+	export, err := h.services.Account.RequestExport(c.Request.Context(), userProfile.UserID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusAccepted, export)
+}
+
+// @Summary      Мои выгрузки данных
+// @Security     ApiKeyAuth
+// @Tags         account
+// @Description  Возвращает последние выгрузки. Готовый архив можно скачать до expiresAt.
+// @Id           get-data-exports
+// @Produce      json
+// @Success      200 {array} models.DataExport
+// @Failure      401,500 {object} errorResponse
+// @Router       /account/exports [get]
+func (h *Handler) getDataExports(c *gin.Context) {
+	userProfile, err := getUserProfile(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to get user from context", err))
+		return
+	}
+
+	exports, err := h.services.Account.GetExports(c.Request.Context(), userProfile.UserID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, exports)
+}
+
+// @Summary      Скачать выгрузку данных
+// @Security     ApiKeyAuth
+// @Tags         account
+// @Description  Скачивает готовый ZIP-архив. Недоступно в режиме просмотра кабинета администратором.
+// @Id           download-data-export
+// @Produce      application/zip
+// @Param        id path int true "ID выгрузки"
+// @Success      200 {file} file
+// @Failure      400,401,403,404,409,500 {object} errorResponse
+// @Router       /account/exports/{id}/file [get]
+func (h *Handler) downloadDataExport(c *gin.Context) {
+	if _, impersonated := c.Get(impersonatorCtx); impersonated {
+		c.Error(services.NewForbiddenError("data export is not available during impersonation", nil))
+		return
+	}
+	userProfile, err := getUserProfile(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to get user from context", err))
+		return
+	}
+	exportID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid export id format", err))
+		return
+	}
+
+	file, export, err := h.services.Account.DownloadExport(c.Request.Context(), userProfile.UserID, exportID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	defer file.Close()
+
+	fileName := fmt.Sprintf("medical-data-%s.zip", export.CreatedAt.Format("2006-01-02"))
+	c.DataFromReader(http.StatusOK, export.FileSize.Int64, "application/zip", file, map[string]string{
+		"Content-Disposition": fmt.Sprintf("attachment; filename=%q", fileName),
+	})
+}
+
+type requestAccountDeletionInput struct {
+	Password string `json:"password" binding:"required"`
+	Reason   string `json:"reason" binding:"max=1000"`
+}
+
+// @Summary      Запросить удаление аккаунта
+// @Security     ApiKeyAuth
+// @Tags         account
+// @Description  Создает запрос на удаление аккаунта с подтверждением паролем. Аккаунт удаляется
+// @Description  после периода ожидания (scheduledAt), до этого запрос можно отменить.
+// @Description  Персональные данные стираются, файлы удаляются; медицинские записи хранятся обезличенными.
+// @Id           request-account-deletion
+// @Accept       json
+// @Produce      json
+// @Param        input body requestAccountDeletionInput true "Пароль и необязательная причина"
+// @Success      201 {object} models.AccountDeletion
+// @Failure      400,401,403,409,500 {object} errorResponse
+// @Router       /account/deletion [post]
+func (h *Handler) requestAccountDeletion(c *gin.Context) {
+	userProfile, err := getUserProfile(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to get user from context", err))
+		return
+	}
+
+	var input requestAccountDeletionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+
+	deletion, err := h.services.Account.RequestDeletion(c.Request.Context(), userProfile.UserID,
+		input.Password, input.Reason)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, deletion)
+}
+
+// @Summary      Запрос на удаление аккаунта
+// @Security     ApiKeyAuth
+// @Tags         account
+// @Description  Возвращает ожидающий запрос на удаление аккаунта или 404, если его нет.
+// @Id           get-account-deletion
+// @Produce      json
+// @Success      200 {object} models.AccountDeletion
+// @Failure      401,404,500 {object} errorResponse
+// @Router       /account/deletion [get]
+func (h *Handler) getAccountDeletion(c *gin.Context) {
+	userProfile, err := getUserProfile(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to get user from context", err))
+		return
+	}
+
+	deletion, err := h.services.Account.GetDeletion(c.Request.Context(), userProfile.UserID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, deletion)
+}
+
+// @Summary      Отменить удаление аккаунта
+// @Security     ApiKeyAuth
+// @Tags         account
+// @Description  Отменяет ожидающий запрос на удаление, пока не истек период ожидания.
+// @Id           cancel-account-deletion
+// @Produce      json
+// @Success      200 {object} statusResponse
+// @Failure      401,403,404,500 {object} errorResponse
+// @Router       /account/deletion [delete]
+func (h *Handler) cancelAccountDeletion(c *gin.Context) {
+	userProfile, err := getUserProfile(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to get user from context", err))
+		return
+	}
+
+	if err := h.services.Account.CancelDeletion(c.Request.Context(), userProfile.UserID); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, statusResponse{Status: "account deletion cancelled"})
+}
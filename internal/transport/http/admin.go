@@ -19,13 +19,14 @@ type adminLoginInput struct {
 
 // @Summary      Вход для администратора
 // @Tags         Admin Auth
-// @Description  Аутентифицирует администратора и возвращает JWT.
+// @Description  Аутентифицирует администратора, открывает сессию и возвращает access и refresh токены.
+// @Description  Если у администратора включена 2FA, возвращает mfaRequired и mfaToken для шага /admin/login/2fa.
 // @Id           admin-login
 // @Accept       json
 // @Produce      json
 // @Param        input body adminLoginInput true "Учетные данные"
-// @Success      200 {object} map[string]string "accessToken"
-// @Failure      400,401,500 {object} errorResponse
+// @Success      200 {object} map[string]string "accessToken, refreshToken (и mustChangePassword) либо mfaRequired и mfaToken"
+// @Failure      400,401,403,429,500 {object} errorResponse
 // @Router       /admin/login [post]
 func (h *Handler) adminLogin(c *gin.Context) {
 	var input adminLoginInput
@@ -34,7 +35,8 @@ func (h *Handler) adminLogin(c *gin.Context) {
 		return
 	}
 
-	token, err := h.services.Admin.Login(c.Request.Context(), input.Login, input.Password)
+	token, err := h.services.Admin.Login(c.Request.Context(), input.Login, input.Password,
+		c.ClientIP(), c.Request.UserAgent())
 	if err != nil {
 		c.Error(err)
 		return
@@ -52,7 +54,7 @@ func (h *Handler) adminLogin(c *gin.Context) {
 // @Id           get-admin-dashboard
 // @Produce      json
 // @Success      200 {object} models.AdminDashboardStats
-// @Failure      401,500 {object} errorResponse
+// @Failure      401,403,500 {object} errorResponse
 // @Router       /admin/dashboard [get]
 func (h *Handler) getAdminDashboard(c *gin.Context) {
 	if _, err := getAdmin(c); err != nil {
@@ -68,6 +70,66 @@ func (h *Handler) getAdminDashboard(c *gin.Context) {
 	c.JSON(http.StatusOK, stats)
 }
 
+// --- Security ---
+
+// @Summary      Журнал блокировок
+// @Security     ApiKeyAuth
+// @Tags         Admin Security
+// @Description  Возвращает события временной блокировки после серии неудачных попыток входа или ввода кода.
+// @Id           admin-get-lockout-events
+// @Produce      json
+// @Param        page query int false "Номер страницы" default(1)
+// @Param        limit query int false "Количество на странице" default(10)
+// @Success      200 {object} map[string]interface{} "items, total"
+// @Failure      401,403,500 {object} errorResponse
+// @Router       /admin/security/lockouts [get]
+func (h *Handler) adminGetLockoutEvents(c *gin.Context) {
+	if _, err := getAdmin(c); err != nil {
+		c.Error(services.NewInternalServerError("failed to identify admin from context", err))
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	params := models.PaginationParams{Page: page, Limit: limit}
+
+	events, total, err := h.services.Admin.GetLockoutEvents(c.Request.Context(), params)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": events, "total": total})
+}
+
+// @Summary      Снять блокировку
+// @Security     ApiKeyAuth
+// @Tags         Admin Security
+// @Description  Досрочно снимает блокировку с телефона, логина администратора или IP-адреса.
+// @Id           admin-unlock
+// @Accept       json
+// @Produce      json
+// @Param        input body services.UnlockInput true "Область и идентификатор"
+// @Success      200 {object} statusResponse
+// @Failure      400,401,403,500 {object} errorResponse
+// @Router       /admin/security/unlock [post]
+func (h *Handler) adminUnlock(c *gin.Context) {
+	if _, err := getAdmin(c); err != nil {
+		c.Error(services.NewInternalServerError("failed to identify admin from context", err))
+		return
+	}
+
+	var input services.UnlockInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+	if err := h.services.Admin.Unlock(c.Request.Context(), input); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, statusResponse{Status: "lockout removed"})
+}
+
 // --- User (Пациент) ---
 
 // @Summary      Получить список всех пациентов
@@ -79,7 +141,7 @@ func (h *Handler) getAdminDashboard(c *gin.Context) {
 // @Param        page query int false "Номер страницы" default(1)
 // @Param        limit query int false "Количество на странице" default(10)
 // @Success      200 {object} map[string]interface{} "items, total"
-// @Failure      401,500 {object} errorResponse
+// @Failure      401,403,500 {object} errorResponse
 // @Router       /admin/users [get]
 func (h *Handler) adminGetAllUsers(c *gin.Context) {
 	if _, err := getAdmin(c); err != nil {
@@ -106,7 +168,7 @@ func (h *Handler) adminGetAllUsers(c *gin.Context) {
 // @Produce      json
 // @Param        id path int true "ID Пациента"
 // @Success      200 {object} map[string]interface{} "user, profile"
-// @Failure      400,401,404,500 {object} errorResponse
+// @Failure      400,401,403,404,500 {object} errorResponse
 // @Router       /admin/users/{id} [get]
 func (h *Handler) adminGetUserByID(c *gin.Context) {
 	if _, err := getAdmin(c); err != nil {
@@ -141,7 +203,7 @@ func (h *Handler) adminGetUserByID(c *gin.Context) {
 // @Param        id path int true "ID Пациента"
 // @Param        input body services.UpdateUserInput true "Обновляемые данные"
 // @Success      200 {object} statusResponse
-// @Failure      400,401,404,500 {object} errorResponse
+// @Failure      400,401,403,404,500 {object} errorResponse
 // @Router       /admin/users/{id} [patch]
 func (h *Handler) adminUpdateUser(c *gin.Context) {
 	if _, err := getAdmin(c); err != nil {
@@ -172,7 +234,7 @@ func (h *Handler) adminUpdateUser(c *gin.Context) {
 // @Id           admin-delete-user
 // @Param        id path int true "ID Пациента"
 // @Success      204 "No Content"
-// @Failure      400,401,500 {object} errorResponse
+// @Failure      400,401,403,500 {object} errorResponse
 // @Router       /admin/users/{id} [delete]
 func (h *Handler) adminDeleteUser(c *gin.Context) {
 	if _, err := getAdmin(c); err != nil {
@@ -201,7 +263,7 @@ func (h *Handler) adminDeleteUser(c *gin.Context) {
 // @Param        page query int false "Номер страницы" default(1)
 // @Param        limit query int false "Количество на странице" default(10)
 // @Success      200 {object} map[string]interface{} "items, total"
-// @Failure      400,401,500 {object} errorResponse
+// @Failure      400,401,403,500 {object} errorResponse
 // @Router       /admin/users/{id}/appointments [get]
 func (h *Handler) adminGetUserAppointments(c *gin.Context) {
 	if _, err := getAdmin(c); err != nil {
@@ -235,7 +297,7 @@ func (h *Handler) adminGetUserAppointments(c *gin.Context) {
 // @Param        page query int false "Номер страницы" default(1)
 // @Param        limit query int false "Количество на странице" default(10)
 // @Success      200 {object} map[string]interface{} "items, total"
-// @Failure      400,401,500 {object} errorResponse
+// @Failure      400,401,403,500 {object} errorResponse
 // @Router       /admin/users/{id}/analyses [get]
 func (h *Handler) adminGetUserAnalyses(c *gin.Context) {
 	if _, err := getAdmin(c); err != nil {
@@ -270,7 +332,7 @@ func (h *Handler) adminGetUserAnalyses(c *gin.Context) {
 // @Param        page query int false "Номер страницы" default(1)
 // @Param        limit query int false "Количество на странице" default(10)
 // @Success      200 {object} map[string]interface{} "items, total"
-// @Failure      401,500 {object} errorResponse
+// @Failure      401,403,500 {object} errorResponse
 // @Router       /admin/specialists [get]
 func (h *Handler) adminGetAllSpecialists(c *gin.Context) {
 	if _, err := getAdmin(c); err != nil {
@@ -298,7 +360,7 @@ func (h *Handler) adminGetAllSpecialists(c *gin.Context) {
 // @Produce      json
 // @Param        input body services.CreateDoctorInput true "Данные нового врача"
 // @Success      201 {object} map[string]uint64 "id"
-// @Failure      400,401,500 {object} errorResponse
+// @Failure      400,401,403,500 {object} errorResponse
 // @Router       /admin/specialists [post]
 func (h *Handler) adminCreateSpecialist(c *gin.Context) {
 	if _, err := getAdmin(c); err != nil {
@@ -324,11 +386,12 @@ func (h *Handler) adminCreateSpecialist(c *gin.Context) {
 // @Summary      Получить детальную информацию о враче (админ)
 // @Security     ApiKeyAuth
 // @Tags         Admin Specialists
+// @Description  Возвращает полный профиль врача со всеми разделами, включая сроки действия сертификатов.
 // @Id           admin-get-specialist-by-id
 // @Produce      json
 // @Param        id path int true "ID Врача"
-// @Success      200 {object} models.Doctor
-// @Failure      400,401,404,500 {object} errorResponse
+// @Success      200 {object} models.DoctorProfile
+// @Failure      400,401,403,404,500 {object} errorResponse
 // @Router       /admin/specialists/{id} [get]
 func (h *Handler) adminGetSpecialistByID(c *gin.Context) {
 	if _, err := getAdmin(c); err != nil {
@@ -358,7 +421,7 @@ func (h *Handler) adminGetSpecialistByID(c *gin.Context) {
 // @Param        id path int true "ID Врача"
 // @Param        input body services.UpdateDoctorInput true "Обновляемые данные"
 // @Success      200 {object} statusResponse
-// @Failure      400,401,404,500 {object} errorResponse
+// @Failure      400,401,403,404,500 {object} errorResponse
 // @Router       /admin/specialists/{id} [put]
 func (h *Handler) adminUpdateSpecialist(c *gin.Context) {
 	if _, err := getAdmin(c); err != nil {
@@ -389,7 +452,7 @@ func (h *Handler) adminUpdateSpecialist(c *gin.Context) {
 // @Id           admin-delete-specialist
 // @Param        id path int true "ID Врача"
 // @Success      204 "No Content"
-// @Failure      400,401,500 {object} errorResponse
+// @Failure      400,401,403,500 {object} errorResponse
 // @Router       /admin/specialists/{id} [delete]
 func (h *Handler) adminDeleteSpecialist(c *gin.Context) {
 	if _, err := getAdmin(c); err != nil {
@@ -416,7 +479,7 @@ func (h *Handler) adminDeleteSpecialist(c *gin.Context) {
 // @Produce      json
 // @Param        id path int true "ID Врача"
 // @Success      200 {array} models.Schedule
-// @Failure      400,401,500 {object} errorResponse
+// @Failure      400,401,403,500 {object} errorResponse
 // @Router       /admin/specialists/{id}/schedule [get]
 func (h *Handler) adminGetSpecialistSchedule(c *gin.Context) {
 	if _, err := getAdmin(c); err != nil {
@@ -446,7 +509,7 @@ func (h *Handler) adminGetSpecialistSchedule(c *gin.Context) {
 // @Param        id path int true "ID Врача"
 // @Param        input body services.UpdateScheduleInput true "Новое расписание"
 // @Success      200 {object} statusResponse
-// @Failure      400,401,500 {object} errorResponse
+// @Failure      400,401,403,500 {object} errorResponse
 // @Router       /admin/specialists/{id}/schedule [post]
 func (h *Handler) adminUpdateSpecialistSchedule(c *gin.Context) {
 	if _, err := getAdmin(c); err != nil {
@@ -486,44 +549,57 @@ func (h *Handler) adminGetAppointmentDetails(c *gin.Context) {
 	c.Error(services.NewInternalServerError("Not implemented yet", nil))
 }
 
+// @Summary      Изменить статус записи на прием
+// @Security     ApiKeyAuth
+// @Tags         Admin Appointments
+// @Description  Изменение статуса фиксируется в журнале аудита со статусом до и после.
+// @Id           admin-update-appointment-status
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "ID Записи"
+// @Param        input body services.UpdateAppointmentStatusInput true "Новый статус"
+// @Success      200 {object} statusResponse
+// @Failure      400,401,403,404,500 {object} errorResponse
+// @Router       /admin/appointments/{id} [patch]
 func (h *Handler) adminUpdateAppointmentStatus(c *gin.Context) {
-	c.Error(services.NewInternalServerError("Not implemented yet", nil))
+	appointmentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid appointment ID", err))
+		return
+	}
+	var input services.UpdateAppointmentStatusInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+	err = h.services.Admin.UpdateAppointmentStatus(c.Request.Context(), appointmentID, input.StatusID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, statusResponse{Status: "appointment status updated successfully"})
 }
 
+// @Summary      Удалить запись на прием
+// @Security     ApiKeyAuth
+// @Tags         Admin Appointments
+// @Description  Мягко удаляет запись. Удаленную запись можно восстановить до окончательного удаления.
+// @Id           admin-delete-appointment
+// @Param        id path int true "ID Записи"
+// @Success      204 "No Content"
+// @Failure      400,401,403,404,500 {object} errorResponse
+// @Router       /admin/appointments/{id} [delete]
 func (h *Handler) adminDeleteAppointment(c *gin.Context) {
-	c.Error(services.NewInternalServerError("Not implemented yet", nil))
-}
-
-func (h *Handler) adminGetAllServices(c *gin.Context) {
-	c.Error(services.NewInternalServerError("Not implemented yet", nil))
-}
-
-func (h *Handler) adminCreateService(c *gin.Context) {
-	c.Error(services.NewInternalServerError("Not implemented yet", nil))
-}
-
-func (h *Handler) adminUpdateService(c *gin.Context) {
-	c.Error(services.NewInternalServerError("Not implemented yet", nil))
-}
-
-func (h *Handler) adminDeleteService(c *gin.Context) {
-	c.Error(services.NewInternalServerError("Not implemented yet", nil))
-}
-
-func (h *Handler) adminGetAllDepartments(c *gin.Context) {
-	c.Error(services.NewInternalServerError("Not implemented yet", nil))
-}
-
-func (h *Handler) adminCreateDepartment(c *gin.Context) {
-	c.Error(services.NewInternalServerError("Not implemented yet", nil))
-}
-
-func (h *Handler) adminUpdateDepartment(c *gin.Context) {
-	c.Error(services.NewInternalServerError("Not implemented yet", nil))
-}
-
-func (h *Handler) adminDeleteDepartment(c *gin.Context) {
-	c.Error(services.NewInternalServerError("Not implemented yet", nil))
+	appointmentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid appointment ID", err))
+		return
+	}
+	if err := h.services.Admin.DeleteAppointment(c.Request.Context(), appointmentID); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
 }
 
 func (h *Handler) adminGetAllAnalyses(c *gin.Context) {
@@ -558,30 +634,6 @@ func (h *Handler) adminDeleteFamilyRelation(c *gin.Context) {
 	c.Error(services.NewInternalServerError("Not implemented yet", nil))
 }
 
-func (h *Handler) adminGetAuditLogs(c *gin.Context) {
-	c.Error(services.NewInternalServerError("Not implemented yet", nil))
-}
-
-func (h *Handler) adminGetClinicSettings(c *gin.Context) {
-	c.Error(services.NewInternalServerError("Not implemented yet", nil))
-}
-
-func (h *Handler) adminUpdateClinicSettings(c *gin.Context) {
-	c.Error(services.NewInternalServerError("Not implemented yet", nil))
-}
-
-func (h *Handler) adminGetLegalDocs(c *gin.Context) {
-	c.Error(services.NewInternalServerError("Not implemented yet", nil))
-}
-
-func (h *Handler) adminCreateLegalDoc(c *gin.Context) {
-	c.Error(services.NewInternalServerError("Not implemented yet", nil))
-}
-
-func (h *Handler) adminUpdateLegalDoc(c *gin.Context) {
-	c.Error(services.NewInternalServerError("Not implemented yet", nil))
-}
-
 func (h *Handler) adminCreateBackup(c *gin.Context) {
 	c.Error(services.NewInternalServerError("Not implemented yet", nil))
 }
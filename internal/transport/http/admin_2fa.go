@@ -0,0 +1,240 @@
+package http
+
+import (
+	"net/http"
+
+	"lk/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Двухфакторная аутентификация администраторов ---
+
+type adminLoginTwoFactorInput struct {
+	MFAToken string `json:"mfaToken" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type adminTwoFactorCodeInput struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// @Summary      Вход для администратора (шаг 2: код 2FA)
+// @Tags         Admin Auth
+// @Description  Обменивает промежуточный mfaToken и код из приложения-аутентификатора (или резервный код) на пару токенов.
+// @Id           admin-login-2fa
+// @Accept       json
+// @Produce      json
+// @Param        input body adminLoginTwoFactorInput true "Промежуточный токен и код"
+// @Success      200 {object} map[string]string "accessToken, refreshToken (и mustChangePassword)"
+// @Failure      400,401,403,429,500 {object} errorResponse
+// @Router       /admin/login/2fa [post]
+func (h *Handler) adminLoginTwoFactor(c *gin.Context) {
+	var input adminLoginTwoFactorInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+
+	token, err := h.services.Admin.LoginTwoFactor(c.Request.Context(), input.MFAToken, input.Code,
+		c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, token)
+}
+
+// @Summary      Состояние 2FA
+// @Security     ApiKeyAuth
+// @Tags         Admin 2FA
+// @Description  Возвращает, включена ли 2FA у текущего администратора, обязательна ли она и сколько осталось резервных кодов.
+// @Id           admin-get-2fa-status
+// @Produce      json
+// @Success      200 {object} models.AdminTwoFactorStatus
+// @Failure      401,500 {object} errorResponse
+// @Router       /admin/2fa [get]
+func (h *Handler) adminGetTwoFactorStatus(c *gin.Context) {
+	admin, err := getAdmin(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to identify admin from context", err))
+		return
+	}
+
+	status, err := h.services.Admin.GetTwoFactorStatus(c.Request.Context(), admin)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// @Summary      Подключение 2FA (шаг 1: секрет)
+// @Security     ApiKeyAuth
+// @Tags         Admin 2FA
+// @Description  Генерирует секрет TOTP и otpauth:// URI для QR-кода. 2FA включается после подтверждения кодом.
+// @Id           admin-enroll-2fa
+// @Produce      json
+// @Success      200 {object} models.AdminTwoFactorEnrollment
+// @Failure      401,409,500 {object} errorResponse
+// @Router       /admin/2fa/enroll [post]
+func (h *Handler) adminEnrollTwoFactor(c *gin.Context) {
+	admin, err := getAdmin(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to identify admin from context", err))
+		return
+	}
+
+	enrollment, err := h.services.Admin.StartTwoFactorEnrollment(c.Request.Context(), admin)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// @Summary      Подключение 2FA (шаг 2: подтверждение)
+// @Security     ApiKeyAuth
+// @Tags         Admin 2FA
+// @Description  Включает 2FA после ввода кода из приложения и возвращает резервные коды (показываются один раз).
+// @Id           admin-confirm-2fa
+// @Accept       json
+// @Produce      json
+// @Param        input body adminTwoFactorCodeInput true "Код из приложения"
+// @Success      200 {object} map[string][]string "recoveryCodes"
+// @Failure      400,401,409,429,500 {object} errorResponse
+// @Router       /admin/2fa/confirm [post]
+func (h *Handler) adminConfirmTwoFactor(c *gin.Context) {
+	admin, err := getAdmin(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to identify admin from context", err))
+		return
+	}
+
+	var input adminTwoFactorCodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+
+	codes, err := h.services.Admin.ConfirmTwoFactorEnrollment(c.Request.Context(), admin, input.Code, c.ClientIP())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"recoveryCodes": codes})
+}
+
+// @Summary      Новые резервные коды
+// @Security     ApiKeyAuth
+// @Tags         Admin 2FA
+// @Description  Выпускает новый набор резервных кодов; предыдущие перестают действовать. Требует код из приложения.
+// @Id           admin-regenerate-recovery-codes
+// @Accept       json
+// @Produce      json
+// @Param        input body adminTwoFactorCodeInput true "Код из приложения"
+// @Success      200 {object} map[string][]string "recoveryCodes"
+// @Failure      400,401,409,429,500 {object} errorResponse
+// @Router       /admin/2fa/recovery-codes [post]
+func (h *Handler) adminRegenerateRecoveryCodes(c *gin.Context) {
+	admin, err := getAdmin(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to identify admin from context", err))
+		return
+	}
+
+	var input adminTwoFactorCodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+
+	codes, err := h.services.Admin.RegenerateRecoveryCodes(c.Request.Context(), admin, input.Code, c.ClientIP())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"recoveryCodes": codes})
+}
+
+// @Summary      Отключить 2FA
+// @Security     ApiKeyAuth
+// @Tags         Admin 2FA
+// @Description  Отключает 2FA текущего администратора. Недоступно, если 2FA обязательна по политике безопасности.
+// @Id           admin-disable-2fa
+// @Accept       json
+// @Produce      json
+// @Param        input body adminTwoFactorCodeInput true "Код из приложения или резервный код"
+// @Success      200 {object} statusResponse
+// @Failure      400,401,403,409,429,500 {object} errorResponse
+// @Router       /admin/2fa/disable [post]
+func (h *Handler) adminDisableTwoFactor(c *gin.Context) {
+	admin, err := getAdmin(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to identify admin from context", err))
+		return
+	}
+
+	var input adminTwoFactorCodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+
+	if err := h.services.Admin.DisableTwoFactor(c.Request.Context(), admin, input.Code, c.ClientIP()); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, statusResponse{Status: "two-factor authentication disabled"})
+}
+
+// @Summary      Настройки безопасности
+// @Security     ApiKeyAuth
+// @Tags         Admin Security
+// @Description  Возвращает глобальные настройки безопасности (например, обязательность 2FA для администраторов).
+// @Id           admin-get-security-settings
+// @Produce      json
+// @Success      200 {object} models.SecuritySettings
+// @Failure      401,403,500 {object} errorResponse
+// @Router       /admin/security/settings [get]
+func (h *Handler) adminGetSecuritySettings(c *gin.Context) {
+	settings, err := h.services.Admin.GetSecuritySettings(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+// @Summary      Изменить настройки безопасности
+// @Security     ApiKeyAuth
+// @Tags         Admin Security
+// @Description  Изменяет глобальные настройки безопасности. Доступно только суперадминистратору.
+// @Id           admin-update-security-settings
+// @Accept       json
+// @Produce      json
+// @Param        input body services.UpdateSecuritySettingsInput true "Настройки"
+// @Success      200 {object} models.SecuritySettings
+// @Failure      400,401,403,500 {object} errorResponse
+// @Router       /admin/security/settings [put]
+func (h *Handler) adminUpdateSecuritySettings(c *gin.Context) {
+	admin, err := getAdmin(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to identify admin from context", err))
+		return
+	}
+
+	var input services.UpdateSecuritySettingsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+
+	settings, err := h.services.Admin.UpdateSecuritySettings(c.Request.Context(), admin, input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
@@ -0,0 +1,329 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"lk/internal/models"
+	"lk/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Управление администраторами (только суперадминистратор) ---
+
+// @Summary      Список ролей
+// @Security     ApiKeyAuth
+// @Tags         Admin Accounts
+// @Description  Возвращает роли админ-панели и права каждой из них.
+// @Id           admin-get-roles
+// @Produce      json
+// @Success      200 {array} models.AdminRoleInfo
+// @Failure      401,403 {object} errorResponse
+// @Router       /admin/admins/roles [get]
+func (h *Handler) adminGetRoles(c *gin.Context) {
+	c.JSON(http.StatusOK, h.services.Admin.GetRoles())
+}
+
+// @Summary      Список администраторов
+// @Security     ApiKeyAuth
+// @Tags         Admin Accounts
+// @Description  Возвращает пагинированный список учетных записей администраторов.
+// @Id           admin-get-admins
+// @Produce      json
+// @Param        page query int false "Номер страницы" default(1)
+// @Param        limit query int false "Количество на странице" default(10)
+// @Success      200 {object} map[string]interface{} "items, total"
+// @Failure      401,403,500 {object} errorResponse
+// @Router       /admin/admins [get]
+func (h *Handler) adminGetAdmins(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	params := models.PaginationParams{Page: page, Limit: limit}
+
+	admins, total, err := h.services.Admin.GetAllAdmins(c.Request.Context(), params)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": admins, "total": total})
+}
+
+// @Summary      Изменить роль администратора
+// @Security     ApiKeyAuth
+// @Tags         Admin Accounts
+// @Description  Назначает администратору новую роль. Нельзя менять собственную роль и понижать последнего суперадминистратора.
+// @Id           admin-change-role
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "ID администратора"
+// @Param        input body services.ChangeAdminRoleInput true "Новая роль"
+// @Success      200 {object} statusResponse
+// @Failure      400,401,403,404,409,500 {object} errorResponse
+// @Router       /admin/admins/{id}/role [patch]
+func (h *Handler) adminChangeRole(c *gin.Context) {
+	actor, err := getAdmin(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to identify admin from context", err))
+		return
+	}
+
+	adminID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid admin ID", err))
+		return
+	}
+
+	var input services.ChangeAdminRoleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+
+	if err := h.services.Admin.ChangeAdminRole(c.Request.Context(), actor, adminID, input.Role); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, statusResponse{Status: "role updated"})
+}
+
+// @Summary      Создать администратора
+// @Security     ApiKeyAuth
+// @Tags         Admin Accounts
+// @Description  Создает учетную запись администратора. Если пароль не задан, генерируется временный.
+// @Description  При первом входе администратор обязан сменить пароль. На email (необязателен) приходят
+// @Description  служебные уведомления, например об истекающих сертификатах врачей.
+// @Id           admin-create-admin
+// @Accept       json
+// @Produce      json
+// @Param        input body services.CreateAdminInput true "Данные администратора"
+// @Success      201 {object} map[string]interface{} "admin, password"
+// @Failure      400,401,403,409,500 {object} errorResponse
+// @Router       /admin/admins [post]
+func (h *Handler) adminCreateAdmin(c *gin.Context) {
+	var input services.CreateAdminInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+
+	admin, password, err := h.services.Admin.CreateAdmin(c.Request.Context(), input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"admin": admin, "password": password})
+}
+
+// @Summary      Получить администратора
+// @Security     ApiKeyAuth
+// @Tags         Admin Accounts
+// @Description  Возвращает учетную запись администратора по ID.
+// @Id           admin-get-admin
+// @Produce      json
+// @Param        id path int true "ID администратора"
+// @Success      200 {object} models.Admin
+// @Failure      400,401,403,404,500 {object} errorResponse
+// @Router       /admin/admins/{id} [get]
+func (h *Handler) adminGetAdmin(c *gin.Context) {
+	adminID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid admin ID", err))
+		return
+	}
+
+	admin, err := h.services.Admin.GetAdminByID(c.Request.Context(), adminID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, admin)
+}
+
+// @Summary      Изменить администратора
+// @Security     ApiKeyAuth
+// @Tags         Admin Accounts
+// @Description  Изменяет логин, ФИО и/или email администратора. Пустой email удаляет адрес.
+// @Id           admin-update-admin
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "ID администратора"
+// @Param        input body services.UpdateAdminInput true "Изменяемые поля"
+// @Success      200 {object} models.Admin
+// @Failure      400,401,403,404,409,500 {object} errorResponse
+// @Router       /admin/admins/{id} [patch]
+func (h *Handler) adminUpdateAdmin(c *gin.Context) {
+	adminID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid admin ID", err))
+		return
+	}
+
+	var input services.UpdateAdminInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+
+	admin, err := h.services.Admin.UpdateAdmin(c.Request.Context(), adminID, input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, admin)
+}
+
+// @Summary      Деактивировать администратора
+// @Security     ApiKeyAuth
+// @Tags         Admin Accounts
+// @Description  Блокирует вход и доступ администратора. Действует немедленно, в том числе для уже выданных токенов.
+// @Id           admin-deactivate-admin
+// @Produce      json
+// @Param        id path int true "ID администратора"
+// @Success      200 {object} statusResponse
+// @Failure      400,401,403,404,409,500 {object} errorResponse
+// @Router       /admin/admins/{id}/deactivate [post]
+func (h *Handler) adminDeactivateAdmin(c *gin.Context) {
+	h.setAdminActive(c, false)
+}
+
+// @Summary      Активировать администратора
+// @Security     ApiKeyAuth
+// @Tags         Admin Accounts
+// @Description  Восстанавливает доступ ранее деактивированного администратора.
+// @Id           admin-activate-admin
+// @Produce      json
+// @Param        id path int true "ID администратора"
+// @Success      200 {object} statusResponse
+// @Failure      400,401,403,404,500 {object} errorResponse
+// @Router       /admin/admins/{id}/activate [post]
+func (h *Handler) adminActivateAdmin(c *gin.Context) {
+	h.setAdminActive(c, true)
+}
+
+// setAdminActive - общая часть обработчиков активации и деактивации.
+func (h *Handler) setAdminActive(c *gin.Context, active bool) {
+	actor, err := getAdmin(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to identify admin from context", err))
+		return
+	}
+
+	adminID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid admin ID", err))
+		return
+	}
+
+	if err := h.services.Admin.SetAdminActive(c.Request.Context(), actor, adminID, active); err != nil {
+		c.Error(err)
+		return
+	}
+
+	status := "admin deactivated"
+	if active {
+		status = "admin activated"
+	}
+	c.JSON(http.StatusOK, statusResponse{Status: status})
+}
+
+// @Summary      Сбросить пароль администратора
+// @Security     ApiKeyAuth
+// @Tags         Admin Accounts
+// @Description  Устанавливает временный пароль; при следующем входе администратор обязан его сменить.
+// @Id           admin-reset-admin-password
+// @Produce      json
+// @Param        id path int true "ID администратора"
+// @Success      200 {object} map[string]string "password"
+// @Failure      400,401,403,404,500 {object} errorResponse
+// @Router       /admin/admins/{id}/reset-password [post]
+func (h *Handler) adminResetAdminPassword(c *gin.Context) {
+	adminID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid admin ID", err))
+		return
+	}
+
+	password, err := h.services.Admin.ResetAdminPassword(c.Request.Context(), adminID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"password": password})
+}
+
+// @Summary      Сбросить 2FA администратора
+// @Security     ApiKeyAuth
+// @Tags         Admin Accounts
+// @Description  Отключает 2FA администратора, потерявшего доступ к приложению-аутентификатору и резервным кодам.
+// @Id           admin-reset-admin-2fa
+// @Produce      json
+// @Param        id path int true "ID администратора"
+// @Success      200 {object} statusResponse
+// @Failure      400,401,403,404,500 {object} errorResponse
+// @Router       /admin/admins/{id}/reset-2fa [post]
+func (h *Handler) adminResetAdminTwoFactor(c *gin.Context) {
+	adminID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid admin ID", err))
+		return
+	}
+
+	if err := h.services.Admin.ResetAdminTwoFactor(c.Request.Context(), adminID); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, statusResponse{Status: "two-factor authentication reset"})
+}
+
+// --- Текущий администратор ---
+
+// @Summary      Текущий администратор
+// @Security     ApiKeyAuth
+// @Tags         Admin Accounts
+// @Description  Возвращает учетную запись текущего администратора и права его роли.
+// @Id           admin-get-me
+// @Produce      json
+// @Success      200 {object} map[string]interface{} "admin, permissions"
+// @Failure      401,500 {object} errorResponse
+// @Router       /admin/me [get]
+func (h *Handler) adminGetMe(c *gin.Context) {
+	admin, err := getAdmin(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to identify admin from context", err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"admin": admin, "permissions": admin.Role.Permissions()})
+}
+
+// @Summary      Сменить свой пароль
+// @Security     ApiKeyAuth
+// @Tags         Admin Accounts
+// @Description  Меняет пароль текущего администратора. Снимает требование смены пароля после первого входа
+// @Description  и завершает все остальные сессии администратора.
+// @Id           admin-change-own-password
+// @Accept       json
+// @Produce      json
+// @Param        input body services.ChangeOwnPasswordInput true "Текущий и новый пароль"
+// @Success      200 {object} statusResponse
+// @Failure      400,401,500 {object} errorResponse
+// @Router       /admin/me/password [post]
+func (h *Handler) adminChangeOwnPassword(c *gin.Context) {
+	admin, err := getAdmin(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to identify admin from context", err))
+		return
+	}
+
+	var input services.ChangeOwnPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+
+	if err := h.services.Admin.ChangeOwnPassword(c.Request.Context(), admin, getAdminSessionID(c), input); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, statusResponse{Status: "password changed"})
+}
@@ -0,0 +1,172 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"lk/internal/logger"
+	"lk/internal/models"
+	"lk/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary      Журнал аудита
+// @Security     ApiKeyAuth
+// @Tags         Admin Security
+// @Description  Возвращает журнал действий администраторов, пациентов и системы с изменениями "до/после".
+// @Description  Новые записи сверху. Даты from/to принимаются в формате RFC3339 или YYYY-MM-DD.
+// @Id           admin-get-audit-logs
+// @Produce      json
+// @Param        actorType query string false "Тип действующего лица" Enums(admin, user, anonymous, system)
+// @Param        actorId query int false "ID действующего лица"
+// @Param        action query string false "Действие, например user.update"
+// @Param        entityType query string false "Тип сущности, например doctor"
+// @Param        entityId query string false "ID сущности"
+// @Param        userId query int false "ID пациента, чьих данных касается действие"
+// @Param        requestId query string false "ID HTTP-запроса"
+// @Param        from query string false "Начало периода"
+// @Param        to query string false "Конец периода"
+// @Param        page query int false "Номер страницы" default(1)
+// @Param        limit query int false "Количество на странице" default(10)
+// @Success      200 {object} map[string]interface{} "items: []models.AuditLog, total: int"
+// @Failure      400,401,403,500 {object} errorResponse
+// @Router       /admin/audit-logs [get]
+func (h *Handler) adminGetAuditLogs(c *gin.Context) {
+	filter, err := parseAuditLogFilter(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	params := models.PaginationParams{Page: page, Limit: limit}
+
+	entries, total, err := h.services.Audit.GetAuditLogs(c.Request.Context(), filter, params)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": entries, "total": total})
+}
+
+// @Summary      Выгрузка журнала аудита в CSV
+// @Security     ApiKeyAuth
+// @Tags         Admin Security
+// @Description  Выгружает все записи журнала аудита, подходящие под фильтры, в CSV. Фильтры те же, что у списка.
+// @Id           admin-export-audit-logs
+// @Produce      text/csv
+// @Param        actorType query string false "Тип действующего лица" Enums(admin, user, anonymous, system)
+// @Param        actorId query int false "ID действующего лица"
+// @Param        action query string false "Действие"
+// @Param        entityType query string false "Тип сущности"
+// @Param        entityId query string false "ID сущности"
+// @Param        userId query int false "ID пациента"
+// @Param        requestId query string false "ID HTTP-запроса"
+// @Param        from query string false "Начало периода"
+// @Param        to query string false "Конец периода"
+// @Success      200 {file} file "CSV-файл"
+// @Failure      400,401,403,500 {object} errorResponse
+// @Router       /admin/audit-logs/export [get]
+func (h *Handler) adminExportAuditLogs(c *gin.Context) {
+	filter, err := parseAuditLogFilter(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	download := &csvDownload{
+		c:        c,
+		fileName: fmt.Sprintf("audit-log-%s.csv", time.Now().Format("20060102-150405")),
+	}
+	if err := h.services.Audit.ExportAuditLogsCSV(c.Request.Context(), filter, download); err != nil {
+		if !download.started {
+			c.Error(err)
+			return
+		}
+		// Заголовки уже отправлены, поэтому ошибку посреди выгрузки можно только залогировать
+		logger.Default().WithError(err).Error("failed to export audit logs")
+	}
+}
+
+// csvDownload отправляет заголовки CSV-файла только при первой записи в тело ответа,
+// чтобы ошибка до начала выгрузки вернулась обычным ответом с ошибкой, а не пустым файлом.
+type csvDownload struct {
+	c        *gin.Context
+	fileName string
+	started  bool
+}
+
+func (d *csvDownload) Write(p []byte) (int, error) {
+	if !d.started {
+		d.started = true
+		d.c.Header("Content-Type", "text/csv; charset=utf-8")
+		d.c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", d.fileName))
+		d.c.Status(http.StatusOK)
+	}
+	return d.c.Writer.Write(p)
+}
+
+// parseAuditLogFilter разбирает фильтры журнала аудита из query-параметров.
+func parseAuditLogFilter(c *gin.Context) (models.AuditLogFilter, error) {
+	filter := models.AuditLogFilter{
+		ActorType:  c.Query("actorType"),
+		Action:     c.Query("action"),
+		EntityType: c.Query("entityType"),
+		EntityID:   c.Query("entityId"),
+		RequestID:  c.Query("requestId"),
+	}
+
+	var err error
+	if filter.ActorID, err = parseOptionalID(c, "actorId"); err != nil {
+		return filter, err
+	}
+	if filter.TargetUserID, err = parseOptionalID(c, "userId"); err != nil {
+		return filter, err
+	}
+	if filter.From, err = parseAuditTime(c, "from", false); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseAuditTime(c, "to", true); err != nil {
+		return filter, err
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		return filter, services.NewBadRequestError("'to' must not be earlier than 'from'", nil)
+	}
+	return filter, nil
+}
+
+func parseOptionalID(c *gin.Context, key string) (uint64, error) {
+	value := c.Query(key)
+	if value == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, services.NewBadRequestError("invalid "+key, err)
+	}
+	return id, nil
+}
+
+// parseAuditTime принимает RFC3339 или дату YYYY-MM-DD. Граница "to" не включается,
+// поэтому дата в конце периода заменяется на начало следующего дня.
+func parseAuditTime(c *gin.Context, key string, endOfDay bool) (time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, services.NewBadRequestError("invalid "+key+" (expected RFC3339 or YYYY-MM-DD)", err)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
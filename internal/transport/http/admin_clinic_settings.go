@@ -0,0 +1,88 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"lk/internal/models"
+	"lk/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary      Настройки клиники
+// @Security     ApiKeyAuth
+// @Tags         Admin Settings
+// @Description  Возвращает действующую версию настроек клиники: окно отмены записи, напоминания,
+// @Description  шаг слотов, горизонт записи, контакты поддержки и часы работы для сводки о клинике.
+// @Id           admin-get-clinic-settings
+// @Produce      json
+// @Success      200 {object} models.ClinicSettingsVersion
+// @Failure      401,403,500 {object} errorResponse
+// @Router       /admin/clinic-settings [get]
+func (h *Handler) adminGetClinicSettings(c *gin.Context) {
+	settings, err := h.services.Admin.GetClinicSettings(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+// @Summary      Изменить настройки клиники
+// @Security     ApiKeyAuth
+// @Tags         Admin Settings
+// @Description  Сохраняет полный набор настроек как новую версию; изменения применяются без перезапуска.
+// @Description  В version передается номер версии, которую редактировали: если настройки за это время
+// @Description  изменил кто-то другой, возвращается 409.
+// @Id           admin-update-clinic-settings
+// @Accept       json
+// @Produce      json
+// @Param        input body services.UpdateClinicSettingsInput true "Настройки"
+// @Success      200 {object} models.ClinicSettingsVersion
+// @Failure      400,401,403,409,500 {object} errorResponse
+// @Router       /admin/clinic-settings [put]
+func (h *Handler) adminUpdateClinicSettings(c *gin.Context) {
+	admin, err := getAdmin(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to identify admin from context", err))
+		return
+	}
+
+	var input services.UpdateClinicSettingsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+
+	settings, err := h.services.Admin.UpdateClinicSettings(c.Request.Context(), admin, input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+// @Summary      История настроек клиники
+// @Security     ApiKeyAuth
+// @Tags         Admin Settings
+// @Description  Возвращает сохраненные версии настроек клиники, новые сверху.
+// @Id           admin-get-clinic-settings-history
+// @Produce      json
+// @Param        page query int false "Номер страницы" default(1)
+// @Param        limit query int false "Количество на странице" default(10)
+// @Success      200 {object} map[string]interface{} "items, total"
+// @Failure      401,403,500 {object} errorResponse
+// @Router       /admin/clinic-settings/history [get]
+func (h *Handler) adminGetClinicSettingsHistory(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	params := models.PaginationParams{Page: page, Limit: limit}
+
+	items, total, err := h.services.Admin.GetClinicSettingsHistory(c.Request.Context(), params)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "total": total})
+}
@@ -0,0 +1,179 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"lk/internal/models"
+	"lk/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary      Получить список клиник
+// @Security     ApiKeyAuth
+// @Tags         Admin Clinics
+// @Description  Возвращает действующие клиники сети с часами работы: основная первой.
+// @Id           admin-get-all-clinics
+// @Produce      json
+// @Success      200 {array} models.Clinic
+// @Failure      401,403,500 {object} errorResponse
+// @Router       /admin/clinics [get]
+func (h *Handler) adminGetAllClinics(c *gin.Context) {
+	clinics, err := h.services.Admin.GetAllClinics(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, clinics)
+}
+
+// @Summary      Получить клинику по ID
+// @Security     ApiKeyAuth
+// @Tags         Admin Clinics
+// @Id           admin-get-clinic
+// @Produce      json
+// @Param        id path int true "ID Клиники"
+// @Success      200 {object} models.Clinic
+// @Failure      400,401,403,404,500 {object} errorResponse
+// @Router       /admin/clinics/{id} [get]
+func (h *Handler) adminGetClinic(c *gin.Context) {
+	clinicID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid clinic ID", err))
+		return
+	}
+	clinic, err := h.services.Admin.GetClinic(c.Request.Context(), clinicID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, clinic)
+}
+
+// @Summary      Создать клинику
+// @Security     ApiKeyAuth
+// @Tags         Admin Clinics
+// @Description  Добавляет клинику в сеть. Клиника с isMain=true становится основной вместо прежней.
+// @Description  Дни недели, которых нет в workingHours, считаются выходными.
+// @Id           admin-create-clinic
+// @Accept       json
+// @Produce      json
+// @Param        input body services.ClinicInput true "Данные клиники"
+// @Success      201 {object} models.Clinic
+// @Failure      400,401,403,500 {object} errorResponse
+// @Router       /admin/clinics [post]
+func (h *Handler) adminCreateClinic(c *gin.Context) {
+	var input services.ClinicInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+	clinic, err := h.services.Admin.CreateClinic(c.Request.Context(), input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, clinic)
+}
+
+// @Summary      Обновить клинику
+// @Security     ApiKeyAuth
+// @Tags         Admin Clinics
+// @Description  Полностью перезаписывает данные клиники и часы работы. Снять признак основной нельзя -
+// @Description  вместо этого нужно отметить основной другую клинику.
+// @Id           admin-update-clinic
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "ID Клиники"
+// @Param        input body services.ClinicInput true "Данные клиники"
+// @Success      200 {object} models.Clinic
+// @Failure      400,401,403,404,409,500 {object} errorResponse
+// @Router       /admin/clinics/{id} [put]
+func (h *Handler) adminUpdateClinic(c *gin.Context) {
+	clinicID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid clinic ID", err))
+		return
+	}
+	var input services.ClinicInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+	clinic, err := h.services.Admin.UpdateClinic(c.Request.Context(), clinicID, input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, clinic)
+}
+
+// @Summary      Удалить клинику
+// @Security     ApiKeyAuth
+// @Tags         Admin Clinics
+// @Description  Мягко удаляет клинику. Нельзя удалить основную клинику и клинику, в которой
+// @Description  в расписании врачей есть дни приема начиная с сегодняшнего.
+// @Id           admin-delete-clinic
+// @Param        id path int true "ID Клиники"
+// @Success      204 "No Content"
+// @Failure      400,401,403,404,409,500 {object} errorResponse
+// @Router       /admin/clinics/{id} [delete]
+func (h *Handler) adminDeleteClinic(c *gin.Context) {
+	clinicID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid clinic ID", err))
+		return
+	}
+	if err := h.services.Admin.DeleteClinic(c.Request.Context(), clinicID); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary      Получить список удаленных клиник
+// @Security     ApiKeyAuth
+// @Tags         Admin Clinics
+// @Description  Возвращает мягко удаленные клиники, последние удаленные сверху.
+// @Id           admin-get-deleted-clinics
+// @Produce      json
+// @Param        page query int false "Номер страницы" default(1)
+// @Param        limit query int false "Количество на странице" default(10)
+// @Success      200 {object} map[string]interface{} "items, total"
+// @Failure      401,403,500 {object} errorResponse
+// @Router       /admin/clinics/deleted [get]
+func (h *Handler) adminGetDeletedClinics(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	params := models.PaginationParams{Page: page, Limit: limit}
+
+	items, total, err := h.services.Admin.GetDeletedClinics(c.Request.Context(), params)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "total": total})
+}
+
+// @Summary      Восстановить удаленную клинику
+// @Security     ApiKeyAuth
+// @Tags         Admin Clinics
+// @Id           admin-restore-clinic
+// @Produce      json
+// @Param        id path int true "ID Клиники"
+// @Success      200 {object} statusResponse
+// @Failure      400,401,403,404,409,500 {object} errorResponse
+// @Router       /admin/clinics/{id}/restore [post]
+func (h *Handler) adminRestoreClinic(c *gin.Context) {
+	clinicID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid clinic ID", err))
+		return
+	}
+	if err := h.services.Admin.RestoreClinic(c.Request.Context(), clinicID); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, statusResponse{Status: "clinic restored"})
+}
@@ -0,0 +1,243 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"lk/internal/models"
+	"lk/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// --- Мягко удаленные записи ---
+
+// @Summary      Получить список удаленных пациентов
+// @Security     ApiKeyAuth
+// @Tags         Admin Users
+// @Description  Возвращает мягко удаленные записи, последние удаленные сверху. До окончательного удаления их можно восстановить.
+// @Id           admin-get-deleted-users
+// @Produce      json
+// @Param        page query int false "Номер страницы" default(1)
+// @Param        limit query int false "Количество на странице" default(10)
+// @Success      200 {object} map[string]interface{} "items, total"
+// @Failure      401,403,500 {object} errorResponse
+// @Router       /admin/users/deleted [get]
+func (h *Handler) adminGetDeletedUsers(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	params := models.PaginationParams{Page: page, Limit: limit}
+
+	items, total, err := h.services.Admin.GetDeletedUsers(c.Request.Context(), params)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "total": total})
+}
+
+// @Summary      Восстановить удаленного пациента
+// @Security     ApiKeyAuth
+// @Tags         Admin Users
+// @Id           admin-restore-user
+// @Produce      json
+// @Param        id path int true "ID Пациента"
+// @Success      200 {object} statusResponse
+// @Failure      400,401,403,404,409,500 {object} errorResponse
+// @Router       /admin/users/{id}/restore [post]
+func (h *Handler) adminRestoreUser(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid user ID", err))
+		return
+	}
+	if err := h.services.Admin.RestoreUser(c.Request.Context(), userID); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, statusResponse{Status: "user restored"})
+}
+
+// @Summary      Получить список удаленных врачей
+// @Security     ApiKeyAuth
+// @Tags         Admin Specialists
+// @Description  Возвращает мягко удаленные записи, последние удаленные сверху. До окончательного удаления их можно восстановить.
+// @Id           admin-get-deleted-specialists
+// @Produce      json
+// @Param        page query int false "Номер страницы" default(1)
+// @Param        limit query int false "Количество на странице" default(10)
+// @Success      200 {object} map[string]interface{} "items, total"
+// @Failure      401,403,500 {object} errorResponse
+// @Router       /admin/specialists/deleted [get]
+func (h *Handler) adminGetDeletedSpecialists(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	params := models.PaginationParams{Page: page, Limit: limit}
+
+	items, total, err := h.services.Admin.GetDeletedSpecialists(c.Request.Context(), params)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "total": total})
+}
+
+// @Summary      Восстановить удаленного врача
+// @Security     ApiKeyAuth
+// @Tags         Admin Specialists
+// @Id           admin-restore-specialist
+// @Produce      json
+// @Param        id path int true "ID Врача"
+// @Success      200 {object} statusResponse
+// @Failure      400,401,403,404,409,500 {object} errorResponse
+// @Router       /admin/specialists/{id}/restore [post]
+func (h *Handler) adminRestoreSpecialist(c *gin.Context) {
+	specialistID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid specialist ID", err))
+		return
+	}
+	if err := h.services.Admin.RestoreSpecialist(c.Request.Context(), specialistID); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, statusResponse{Status: "specialist restored"})
+}
+
+// @Summary      Получить список удаленных записей на прием
+// @Security     ApiKeyAuth
+// @Tags         Admin Appointments
+// @Description  Возвращает мягко удаленные записи, последние удаленные сверху. До окончательного удаления их можно восстановить.
+// @Id           admin-get-deleted-appointments
+// @Produce      json
+// @Param        page query int false "Номер страницы" default(1)
+// @Param        limit query int false "Количество на странице" default(10)
+// @Success      200 {object} map[string]interface{} "items, total"
+// @Failure      401,403,500 {object} errorResponse
+// @Router       /admin/appointments/deleted [get]
+func (h *Handler) adminGetDeletedAppointments(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	params := models.PaginationParams{Page: page, Limit: limit}
+
+	items, total, err := h.services.Admin.GetDeletedAppointments(c.Request.Context(), params)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "total": total})
+}
+
+// @Summary      Восстановить удаленную запись на прием
+// @Security     ApiKeyAuth
+// @Tags         Admin Appointments
+// @Id           admin-restore-appointment
+// @Produce      json
+// @Param        id path int true "ID Записи"
+// @Success      200 {object} statusResponse
+// @Failure      400,401,403,404,409,500 {object} errorResponse
+// @Router       /admin/appointments/{id}/restore [post]
+func (h *Handler) adminRestoreAppointment(c *gin.Context) {
+	appointmentID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid appointment ID", err))
+		return
+	}
+	if err := h.services.Admin.RestoreAppointment(c.Request.Context(), appointmentID); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, statusResponse{Status: "appointment restored"})
+}
+
+// @Summary      Получить список удаленных услуг
+// @Security     ApiKeyAuth
+// @Tags         Admin Services
+// @Description  Возвращает мягко удаленные записи, последние удаленные сверху. До окончательного удаления их можно восстановить.
+// @Id           admin-get-deleted-services
+// @Produce      json
+// @Param        page query int false "Номер страницы" default(1)
+// @Param        limit query int false "Количество на странице" default(10)
+// @Success      200 {object} map[string]interface{} "items, total"
+// @Failure      401,403,500 {object} errorResponse
+// @Router       /admin/services/deleted [get]
+func (h *Handler) adminGetDeletedServices(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	params := models.PaginationParams{Page: page, Limit: limit}
+
+	items, total, err := h.services.Admin.GetDeletedServices(c.Request.Context(), params)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "total": total})
+}
+
+// @Summary      Восстановить удаленную услугу
+// @Security     ApiKeyAuth
+// @Tags         Admin Services
+// @Id           admin-restore-service
+// @Produce      json
+// @Param        id path int true "ID Услуги"
+// @Success      200 {object} statusResponse
+// @Failure      400,401,403,404,409,500 {object} errorResponse
+// @Router       /admin/services/{id}/restore [post]
+func (h *Handler) adminRestoreService(c *gin.Context) {
+	serviceID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid service ID", err))
+		return
+	}
+	if err := h.services.Admin.RestoreService(c.Request.Context(), serviceID); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, statusResponse{Status: "service restored"})
+}
+
+// @Summary      Получить список удаленных отделений
+// @Security     ApiKeyAuth
+// @Tags         Admin Departments
+// @Description  Возвращает мягко удаленные записи, последние удаленные сверху. До окончательного удаления их можно восстановить.
+// @Id           admin-get-deleted-departments
+// @Produce      json
+// @Param        page query int false "Номер страницы" default(1)
+// @Param        limit query int false "Количество на странице" default(10)
+// @Success      200 {object} map[string]interface{} "items, total"
+// @Failure      401,403,500 {object} errorResponse
+// @Router       /admin/departments/deleted [get]
+func (h *Handler) adminGetDeletedDepartments(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	params := models.PaginationParams{Page: page, Limit: limit}
+
+	items, total, err := h.services.Admin.GetDeletedDepartments(c.Request.Context(), params)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "total": total})
+}
+
+// @Summary      Восстановить удаленное отделение
+// @Security     ApiKeyAuth
+// @Tags         Admin Departments
+// @Id           admin-restore-department
+// @Produce      json
+// @Param        id path int true "ID Отделения"
+// @Success      200 {object} statusResponse
+// @Failure      400,401,403,404,409,500 {object} errorResponse
+// @Router       /admin/departments/{id}/restore [post]
+func (h *Handler) adminRestoreDepartment(c *gin.Context) {
+	departmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid department ID", err))
+		return
+	}
+	if err := h.services.Admin.RestoreDepartment(c.Request.Context(), uint32(departmentID)); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, statusResponse{Status: "department restored"})
+}
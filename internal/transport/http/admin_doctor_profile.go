@@ -0,0 +1,244 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"lk/internal/models"
+	"lk/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary      Добавить запись в раздел профиля врача
+// @Security     ApiKeyAuth
+// @Tags         Admin Specialists
+// @Description  Тело запроса зависит от раздела: education и residency - services.DoctorEducationInput,
+// @Description  courses - services.DoctorCourseInput, certificates - services.DoctorCertificateInput,
+// @Description  skills - services.DoctorSkillInput, specializations - services.DoctorSpecializationInput.
+// @Id           admin-create-doctor-profile-item
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "ID Врача"
+// @Param        section path string true "Раздел профиля" Enums(education, residency, courses, certificates, skills, specializations)
+// @Param        input body object true "Запись раздела"
+// @Success      201 {object} object "Созданная запись раздела"
+// @Failure      400,401,403,404,500 {object} errorResponse
+// @Router       /admin/specialists/{id}/profile/{section} [post]
+func (h *Handler) adminCreateDoctorProfileItem(c *gin.Context) {
+	doctorID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid specialist ID", err))
+		return
+	}
+	section := c.Param("section")
+	input, err := services.NewDoctorProfileItemInput(section)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if err := c.ShouldBindJSON(input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+
+	item, err := h.services.Admin.CreateDoctorProfileItem(c.Request.Context(), doctorID, section, input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, item)
+}
+
+// @Summary      Обновить запись раздела профиля врача
+// @Security     ApiKeyAuth
+// @Tags         Admin Specialists
+// @Description  Полностью заменяет запись раздела. Тело запроса - как при добавлении записи в раздел.
+// @Description  Изменение сертификата снимает отметку о предупреждении об истечении срока.
+// @Id           admin-update-doctor-profile-item
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "ID Врача"
+// @Param        section path string true "Раздел профиля" Enums(education, residency, courses, certificates, skills, specializations)
+// @Param        itemId path int true "ID Записи"
+// @Param        input body object true "Запись раздела"
+// @Success      200 {object} object "Обновленная запись раздела"
+// @Failure      400,401,403,404,500 {object} errorResponse
+// @Router       /admin/specialists/{id}/profile/{section}/{itemId} [put]
+func (h *Handler) adminUpdateDoctorProfileItem(c *gin.Context) {
+	doctorID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid specialist ID", err))
+		return
+	}
+	itemID, err := strconv.ParseUint(c.Param("itemId"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid item ID", err))
+		return
+	}
+	section := c.Param("section")
+	input, err := services.NewDoctorProfileItemInput(section)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if err := c.ShouldBindJSON(input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+
+	item, err := h.services.Admin.UpdateDoctorProfileItem(c.Request.Context(), doctorID, itemID, section, input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+// @Summary      Удалить запись раздела профиля врача
+// @Security     ApiKeyAuth
+// @Tags         Admin Specialists
+// @Id           admin-delete-doctor-profile-item
+// @Param        id path int true "ID Врача"
+// @Param        section path string true "Раздел профиля" Enums(education, residency, courses, certificates, skills, specializations)
+// @Param        itemId path int true "ID Записи"
+// @Success      204 "No Content"
+// @Failure      400,401,403,404,500 {object} errorResponse
+// @Router       /admin/specialists/{id}/profile/{section}/{itemId} [delete]
+func (h *Handler) adminDeleteDoctorProfileItem(c *gin.Context) {
+	doctorID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid specialist ID", err))
+		return
+	}
+	itemID, err := strconv.ParseUint(c.Param("itemId"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid item ID", err))
+		return
+	}
+
+	err = h.services.Admin.DeleteDoctorProfileItem(c.Request.Context(), doctorID, itemID, c.Param("section"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary      Загрузить аватар врача
+// @Security     ApiKeyAuth
+// @Tags         Admin Specialists
+// @Description  Принимает JPEG, PNG или GIF до 5 МБ. Изображение обрезается до квадрата,
+// @Description  сохраняется в нескольких размерах без EXIF, предыдущий аватар удаляется.
+// @Id           admin-update-specialist-avatar
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id path int true "ID Врача"
+// @Param        avatar formData file true "Файл изображения для аватара"
+// @Success      200 {object} models.Avatar
+// @Failure      400,401,403,404,500 {object} errorResponse
+// @Router       /admin/specialists/{id}/avatar [post]
+func (h *Handler) adminUpdateSpecialistAvatar(c *gin.Context) {
+	doctorID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid specialist ID", err))
+		return
+	}
+	file, err := c.FormFile("avatar")
+	if err != nil {
+		c.Error(services.NewBadRequestError("avatar file is required", err))
+		return
+	}
+
+	avatar, err := h.services.Admin.UpdateSpecialistAvatar(c.Request.Context(), doctorID, file)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, avatar)
+}
+
+// @Summary      Удалить аватар врача
+// @Security     ApiKeyAuth
+// @Tags         Admin Specialists
+// @Id           admin-delete-specialist-avatar
+// @Param        id path int true "ID Врача"
+// @Success      204 "No Content"
+// @Failure      400,401,403,404,500 {object} errorResponse
+// @Router       /admin/specialists/{id}/avatar [delete]
+func (h *Handler) adminDeleteSpecialistAvatar(c *gin.Context) {
+	doctorID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid specialist ID", err))
+		return
+	}
+	if err := h.services.Admin.DeleteSpecialistAvatar(c.Request.Context(), doctorID); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary      Назначить врача в клиники
+// @Security     ApiKeyAuth
+// @Tags         Admin Specialists
+// @Description  Заменяет набор клиник, в которых работает врач. Пустой список снимает все назначения.
+// @Description  Клинику нельзя снять, пока в расписании врача есть дни приема в ней.
+// @Id           admin-set-specialist-clinics
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "ID Врача"
+// @Param        input body services.SetDoctorClinicsInput true "Клиники врача"
+// @Success      200 {array} models.Clinic
+// @Failure      400,401,403,404,409,500 {object} errorResponse
+// @Router       /admin/specialists/{id}/clinics [put]
+func (h *Handler) adminSetSpecialistClinics(c *gin.Context) {
+	doctorID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid specialist ID", err))
+		return
+	}
+	var input services.SetDoctorClinicsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+
+	clinics, err := h.services.Admin.SetSpecialistClinics(c.Request.Context(), doctorID, input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, clinics)
+}
+
+// @Summary      Получить истекающие сертификаты врачей
+// @Security     ApiKeyAuth
+// @Tags         Admin Specialists
+// @Description  Возвращает сертификаты, срок действия которых истекает в ближайшие days дней
+// @Description  или уже истек, ближайшие сверху. По умолчанию используется период предупреждения из настроек.
+// @Id           admin-get-expiring-certificates
+// @Produce      json
+// @Param        days query int false "Горизонт в днях"
+// @Param        page query int false "Номер страницы" default(1)
+// @Param        limit query int false "Количество на странице" default(10)
+// @Success      200 {object} map[string]interface{} "items ([]models.ExpiringCertificate), total"
+// @Failure      400,401,403,500 {object} errorResponse
+// @Router       /admin/specialists/certificates/expiring [get]
+func (h *Handler) adminGetExpiringCertificates(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "0"))
+	if err != nil || days < 0 {
+		c.Error(services.NewBadRequestError("invalid days value", err))
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	params := models.PaginationParams{Page: page, Limit: limit}
+
+	certificates, total, err := h.services.Admin.GetExpiringCertificates(c.Request.Context(), days, params)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": certificates, "total": total})
+}
@@ -0,0 +1,88 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"lk/internal/models"
+	"lk/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary      Войти в кабинет пациента (имперсонация)
+// @Security     ApiKeyAuth
+// @Tags         Admin Users
+// @Description  Выпускает короткоживущий (15 минут) пациентский access токен, помеченный ID администратора.
+// @Description  С ним доступны только запросы на чтение к API пациента; изменяющие запросы отклоняются с 403.
+// @Description  Каждый запрос с таким токеном записывается в журнал имперсонации.
+// @Id           admin-impersonate-user
+// @Produce      json
+// @Param        id path int true "ID пользователя"
+// @Success      200 {object} models.ImpersonationToken
+// @Failure      400,401,403,404,500 {object} errorResponse
+// @Router       /admin/users/{id}/impersonate [post]
+func (h *Handler) adminImpersonateUser(c *gin.Context) {
+	actor, err := getAdmin(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to identify admin from context", err))
+		return
+	}
+
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid user ID", err))
+		return
+	}
+
+	token, err := h.services.Admin.ImpersonateUser(c.Request.Context(), actor, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, token)
+}
+
+// @Summary      Журнал имперсонации
+// @Security     ApiKeyAuth
+// @Tags         Admin Security
+// @Description  Возвращает запросы, выполненные администраторами от имени пациентов. Новые записи сверху.
+// @Id           admin-get-impersonation-logs
+// @Produce      json
+// @Param        adminId query int false "Фильтр по ID администратора"
+// @Param        userId query int false "Фильтр по ID пациента"
+// @Param        page query int false "Номер страницы" default(1)
+// @Param        limit query int false "Количество на странице" default(10)
+// @Success      200 {object} map[string]interface{} "items: []models.ImpersonationLog, total: int"
+// @Failure      400,401,403,500 {object} errorResponse
+// @Router       /admin/security/impersonations [get]
+func (h *Handler) adminGetImpersonationLogs(c *gin.Context) {
+	var filter models.ImpersonationLogFilter
+	if v := c.Query("adminId"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			c.Error(services.NewBadRequestError("invalid adminId", err))
+			return
+		}
+		filter.AdminID = id
+	}
+	if v := c.Query("userId"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			c.Error(services.NewBadRequestError("invalid userId", err))
+			return
+		}
+		filter.UserID = id
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	params := models.PaginationParams{Page: page, Limit: limit}
+
+	entries, total, err := h.services.Admin.GetImpersonationLogs(c.Request.Context(), filter, params)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": entries, "total": total})
+}
@@ -0,0 +1,247 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"lk/internal/models"
+	"lk/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary      Типы юридических документов
+// @Security     ApiKeyAuth
+// @Tags         Admin Legal
+// @Description  Возвращает справочник типов документов с признаком обязательности.
+// @Id           admin-get-legal-document-types
+// @Produce      json
+// @Success      200 {array} models.LegalDocumentType
+// @Failure      401,403,500 {object} errorResponse
+// @Router       /admin/legal-documents/types [get]
+func (h *Handler) adminGetLegalDocTypes(c *gin.Context) {
+	types, err := h.services.Admin.GetLegalDocumentTypes(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, types)
+}
+
+// @Summary      Версии юридических документов
+// @Security     ApiKeyAuth
+// @Tags         Admin Legal
+// @Description  Возвращает все версии документов, включая черновики. Новые сверху.
+// @Id           admin-get-legal-documents
+// @Produce      json
+// @Param        type query string false "Тип документа"
+// @Param        status query string false "Статус версии" Enums(draft, published)
+// @Param        page query int false "Номер страницы" default(1)
+// @Param        limit query int false "Количество на странице" default(10)
+// @Success      200 {object} map[string]interface{} "items: []models.LegalDocument, total: int"
+// @Failure      400,401,403,500 {object} errorResponse
+// @Router       /admin/legal-documents [get]
+func (h *Handler) adminGetLegalDocs(c *gin.Context) {
+	filter := models.LegalDocumentFilter{Type: c.Query("type"), Status: c.Query("status")}
+	if filter.Status != "" && filter.Status != models.LegalDocumentDraft &&
+		filter.Status != models.LegalDocumentPublished {
+		c.Error(services.NewBadRequestError("invalid status", nil))
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	params := models.PaginationParams{Page: page, Limit: limit}
+
+	docs, total, err := h.services.Admin.GetLegalDocuments(c.Request.Context(), filter, params)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": docs, "total": total})
+}
+
+// @Summary      Версия юридического документа
+// @Security     ApiKeyAuth
+// @Tags         Admin Legal
+// @Id           admin-get-legal-document
+// @Produce      json
+// @Param        id path int true "ID версии"
+// @Success      200 {object} models.LegalDocument
+// @Failure      400,401,403,404,500 {object} errorResponse
+// @Router       /admin/legal-documents/{id} [get]
+func (h *Handler) adminGetLegalDoc(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid document ID", err))
+		return
+	}
+
+	doc, err := h.services.Admin.GetLegalDocument(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, doc)
+}
+
+// @Summary      Создать версию документа
+// @Security     ApiKeyAuth
+// @Tags         Admin Legal
+// @Description  Загружает PDF и создает черновик новой версии. Пациенты увидят ее только после публикации.
+// @Id           admin-create-legal-document
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        type formData string true "Тип документа"
+// @Param        title formData string true "Название"
+// @Param        version formData string true "Версия (уникальна в пределах типа)"
+// @Param        updateDate formData string true "Дата редакции (YYYY-MM-DD)"
+// @Param        file formData file true "PDF-файл документа (до 20 МБ)"
+// @Success      201 {object} models.LegalDocument
+// @Failure      400,401,403,409,500 {object} errorResponse
+// @Router       /admin/legal-documents [post]
+func (h *Handler) adminCreateLegalDoc(c *gin.Context) {
+	actor, err := getAdmin(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to identify admin from context", err))
+		return
+	}
+
+	var input services.CreateLegalDocumentInput
+	if err := c.ShouldBind(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.Error(services.NewBadRequestError("document file is required", err))
+		return
+	}
+
+	doc, err := h.services.Admin.CreateLegalDocument(c.Request.Context(), actor, input, file)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, doc)
+}
+
+// @Summary      Изменить черновик документа
+// @Security     ApiKeyAuth
+// @Tags         Admin Legal
+// @Description  Изменяет поля черновика и, если передан файл, заменяет PDF. Опубликованные версии неизменны (409).
+// @Id           admin-update-legal-document
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id path int true "ID версии"
+// @Param        title formData string false "Название"
+// @Param        version formData string false "Версия"
+// @Param        updateDate formData string false "Дата редакции (YYYY-MM-DD)"
+// @Param        file formData file false "Новый PDF-файл"
+// @Success      200 {object} models.LegalDocument
+// @Failure      400,401,403,404,409,500 {object} errorResponse
+// @Router       /admin/legal-documents/{id} [put]
+func (h *Handler) adminUpdateLegalDoc(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid document ID", err))
+		return
+	}
+
+	var input services.UpdateLegalDocumentInput
+	if err := c.ShouldBind(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+	var file *multipart.FileHeader
+	if file, err = c.FormFile("file"); err != nil && !errors.Is(err, http.ErrMissingFile) {
+		c.Error(services.NewBadRequestError("invalid document file", err))
+		return
+	}
+
+	doc, err := h.services.Admin.UpdateLegalDocument(c.Request.Context(), id, input, file)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, doc)
+}
+
+// @Summary      Опубликовать версию документа
+// @Security     ApiKeyAuth
+// @Tags         Admin Legal
+// @Description  Делает черновик действующей версией. После публикации версия неизменна;
+// @Description  новую редакцию обязательного документа пациенты должны будут принять.
+// @Id           admin-publish-legal-document
+// @Produce      json
+// @Param        id path int true "ID версии"
+// @Success      200 {object} models.LegalDocument
+// @Failure      400,401,403,404,409,500 {object} errorResponse
+// @Router       /admin/legal-documents/{id}/publish [post]
+func (h *Handler) adminPublishLegalDoc(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid document ID", err))
+		return
+	}
+
+	doc, err := h.services.Admin.PublishLegalDocument(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, doc)
+}
+
+// @Summary      Удалить черновик документа
+// @Security     ApiKeyAuth
+// @Tags         Admin Legal
+// @Description  Удаляет черновик вместе с файлом. Опубликованные версии удалить нельзя (409).
+// @Id           admin-delete-legal-document
+// @Produce      json
+// @Param        id path int true "ID версии"
+// @Success      200 {object} statusResponse
+// @Failure      400,401,403,404,409,500 {object} errorResponse
+// @Router       /admin/legal-documents/{id} [delete]
+func (h *Handler) adminDeleteLegalDoc(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid document ID", err))
+		return
+	}
+
+	if err := h.services.Admin.DeleteLegalDocument(c.Request.Context(), id); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, statusResponse{Status: "draft deleted"})
+}
+
+// @Summary      Скачать файл версии документа
+// @Security     ApiKeyAuth
+// @Tags         Admin Legal
+// @Description  Скачивает PDF любой версии документа, включая черновики.
+// @Id           admin-download-legal-document
+// @Produce      application/pdf
+// @Param        id path int true "ID версии"
+// @Success      200 {file} file
+// @Failure      400,401,403,404,500 {object} errorResponse
+// @Router       /admin/legal-documents/{id}/file [get]
+func (h *Handler) adminDownloadLegalDoc(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid document ID", err))
+		return
+	}
+
+	data, fileName, err := h.services.Admin.DownloadLegalDocument(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	c.Data(http.StatusOK, "application/pdf", data)
+}
@@ -0,0 +1,125 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"lk/internal/models"
+	"lk/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type adminRejectReviewInput struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
+}
+
+// @Summary      Получить отзывы для модерации
+// @Security     ApiKeyAuth
+// @Tags         Admin Reviews
+// @Description  Возвращает отзывы с указанным статусом, старые сверху. По умолчанию - очередь модерации (pending).
+// @Id           admin-get-reviews
+// @Produce      json
+// @Param        status query string false "Статус отзыва; all - все статусы" Enums(pending, approved, rejected, all) default(pending)
+// @Param        doctorId query int false "ID врача"
+// @Param        page query int false "Номер страницы" default(1)
+// @Param        limit query int false "Количество на странице" default(10)
+// @Success      200 {object} map[string]interface{} "items, total"
+// @Failure      400,401,403,500 {object} errorResponse
+// @Router       /admin/reviews [get]
+func (h *Handler) adminGetReviews(c *gin.Context) {
+	filter := models.ReviewFilter{Status: c.DefaultQuery("status", models.ReviewPending)}
+	if filter.Status == "all" {
+		filter.Status = ""
+	}
+	if doctorID := c.Query("doctorId"); doctorID != "" {
+		id, err := strconv.ParseUint(doctorID, 10, 64)
+		if err != nil {
+			c.Error(services.NewBadRequestError("invalid doctor ID", err))
+			return
+		}
+		filter.DoctorID = id
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	params := models.PaginationParams{Page: page, Limit: limit}
+
+	reviews, total, err := h.services.Admin.GetReviews(c.Request.Context(), filter, params)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": reviews, "total": total})
+}
+
+// @Summary      Одобрить отзыв
+// @Security     ApiKeyAuth
+// @Tags         Admin Reviews
+// @Description  Публикует отзыв и пересчитывает рейтинг и число отзывов врача.
+// @Id           admin-approve-review
+// @Produce      json
+// @Param        id path int true "ID Отзыва"
+// @Success      200 {object} models.Review
+// @Failure      400,401,403,404,409,500 {object} errorResponse
+// @Router       /admin/reviews/{id}/approve [post]
+func (h *Handler) adminApproveReview(c *gin.Context) {
+	admin, err := getAdmin(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to identify admin from context", err))
+		return
+	}
+
+	reviewID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid review ID", err))
+		return
+	}
+
+	review, err := h.services.Admin.ApproveReview(c.Request.Context(), admin, reviewID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, review)
+}
+
+// @Summary      Отклонить отзыв
+// @Security     ApiKeyAuth
+// @Tags         Admin Reviews
+// @Description  Отклоняет отзыв с указанием причины, которую видит автор. Ранее одобренный отзыв
+// @Description  снимается с публикации, рейтинг врача пересчитывается.
+// @Id           admin-reject-review
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "ID Отзыва"
+// @Param        input body adminRejectReviewInput true "Причина отклонения"
+// @Success      200 {object} models.Review
+// @Failure      400,401,403,404,409,500 {object} errorResponse
+// @Router       /admin/reviews/{id}/reject [post]
+func (h *Handler) adminRejectReview(c *gin.Context) {
+	admin, err := getAdmin(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to identify admin from context", err))
+		return
+	}
+
+	reviewID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid review ID", err))
+		return
+	}
+
+	var input adminRejectReviewInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+
+	review, err := h.services.Admin.RejectReview(c.Request.Context(), admin, reviewID, input.Reason)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, review)
+}
@@ -0,0 +1,145 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	_ "lk/internal/models"
+	"lk/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary      Назначить врачу услуги
+// @Security     ApiKeyAuth
+// @Tags         Admin Specialists
+// @Description  Заменяет набор услуг каталога, которые оказывает врач. Для каждой услуги можно задать
+// @Description  цену и длительность для этого врача, иначе действуют базовые. Пустой список снимает все услуги.
+// @Description  Уже созданные записи сохраняют цену, по которой были оформлены. Изменения цен попадают в историю цен.
+// @Id           admin-set-specialist-services
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "ID Врача"
+// @Param        input body services.SetDoctorServicesInput true "Услуги врача"
+// @Success      200 {array} models.DoctorService
+// @Failure      400,401,403,404,500 {object} errorResponse
+// @Router       /admin/specialists/{id}/services [put]
+func (h *Handler) adminSetSpecialistServices(c *gin.Context) {
+	admin, err := getAdmin(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to identify admin from context", err))
+		return
+	}
+
+	doctorID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid specialist ID", err))
+		return
+	}
+	var input services.SetDoctorServicesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+
+	assignments, err := h.services.Admin.SetSpecialistServices(c.Request.Context(), admin, doctorID, input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, assignments)
+}
+
+// @Summary      Получить категории услуг
+// @Security     ApiKeyAuth
+// @Tags         Admin Services
+// @Id           admin-get-service-categories
+// @Produce      json
+// @Success      200 {array} models.ServiceCategory
+// @Failure      401,403,500 {object} errorResponse
+// @Router       /admin/service-categories [get]
+func (h *Handler) adminGetServiceCategories(c *gin.Context) {
+	categories, err := h.services.Admin.GetServiceCategories(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, categories)
+}
+
+// @Summary      Создать категорию услуг
+// @Security     ApiKeyAuth
+// @Tags         Admin Services
+// @Description  Добавляет категорию каталога в отделение. Названия категорий внутри отделения не повторяются.
+// @Id           admin-create-service-category
+// @Accept       json
+// @Produce      json
+// @Param        input body services.ServiceCategoryInput true "Категория"
+// @Success      201 {object} models.ServiceCategory
+// @Failure      400,401,403,409,500 {object} errorResponse
+// @Router       /admin/service-categories [post]
+func (h *Handler) adminCreateServiceCategory(c *gin.Context) {
+	var input services.ServiceCategoryInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+	category, err := h.services.Admin.CreateServiceCategory(c.Request.Context(), input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, category)
+}
+
+// @Summary      Обновить категорию услуг
+// @Security     ApiKeyAuth
+// @Tags         Admin Services
+// @Id           admin-update-service-category
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "ID Категории"
+// @Param        input body services.ServiceCategoryInput true "Категория"
+// @Success      200 {object} models.ServiceCategory
+// @Failure      400,401,403,404,409,500 {object} errorResponse
+// @Router       /admin/service-categories/{id} [put]
+func (h *Handler) adminUpdateServiceCategory(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid service category ID", err))
+		return
+	}
+	var input services.ServiceCategoryInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+	category, err := h.services.Admin.UpdateServiceCategory(c.Request.Context(), uint32(categoryID), input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, category)
+}
+
+// @Summary      Удалить категорию услуг
+// @Security     ApiKeyAuth
+// @Tags         Admin Services
+// @Description  Удаляет категорию без услуг. Если в категории есть услуги, в том числе удаленные, возвращается 409.
+// @Id           admin-delete-service-category
+// @Param        id path int true "ID Категории"
+// @Success      204 "No Content"
+// @Failure      400,401,403,404,409,500 {object} errorResponse
+// @Router       /admin/service-categories/{id} [delete]
+func (h *Handler) adminDeleteServiceCategory(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid service category ID", err))
+		return
+	}
+	if err := h.services.Admin.DeleteServiceCategory(c.Request.Context(), uint32(categoryID)); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,265 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"lk/internal/models"
+	"lk/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary      Получить услуги каталога
+// @Security     ApiKeyAuth
+// @Tags         Admin Services
+// @Id           admin-get-all-services
+// @Produce      json
+// @Success      200 {array} models.Service
+// @Failure      401,403,500 {object} errorResponse
+// @Router       /admin/services [get]
+func (h *Handler) adminGetAllServices(c *gin.Context) {
+	items, err := h.services.Admin.GetAllServices(c.Request.Context())
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to get services", err))
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// @Summary      Получить услугу каталога
+// @Security     ApiKeyAuth
+// @Tags         Admin Services
+// @Id           admin-get-service
+// @Produce      json
+// @Param        id path int true "ID Услуги"
+// @Success      200 {object} models.Service
+// @Failure      400,401,403,404,500 {object} errorResponse
+// @Router       /admin/services/{id} [get]
+func (h *Handler) adminGetService(c *gin.Context) {
+	serviceID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid service ID", err))
+		return
+	}
+	service, err := h.services.Admin.GetService(c.Request.Context(), serviceID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, service)
+}
+
+// @Summary      Создать услугу каталога
+// @Security     ApiKeyAuth
+// @Tags         Admin Services
+// @Description  Добавляет услугу в каталог. Код услуги уникален среди действующих услуг.
+// @Description  Базовая цена становится первой записью истории цен услуги.
+// @Id           admin-create-service
+// @Accept       json
+// @Produce      json
+// @Param        input body services.CreateServiceInput true "Данные услуги"
+// @Success      201 {object} map[string]uint64 "id"
+// @Failure      400,401,403,409,500 {object} errorResponse
+// @Router       /admin/services [post]
+func (h *Handler) adminCreateService(c *gin.Context) {
+	admin, err := getAdmin(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to identify admin from context", err))
+		return
+	}
+
+	var input services.CreateServiceInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+	serviceID, err := h.services.Admin.CreateService(c.Request.Context(), admin, input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"id": serviceID})
+}
+
+// @Summary      Обновить услугу каталога
+// @Security     ApiKeyAuth
+// @Tags         Admin Services
+// @Description  Изменяет переданные поля услуги. Изменение базовой цены попадает в историю цен
+// @Description  вместе с ценами врачей, у которых цена не переопределена. Уже созданные записи сохраняют свою цену.
+// @Id           admin-update-service
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "ID Услуги"
+// @Param        input body services.UpdateServiceInput true "Обновляемые данные"
+// @Success      200 {object} statusResponse
+// @Failure      400,401,403,404,409,500 {object} errorResponse
+// @Router       /admin/services/{id} [put]
+func (h *Handler) adminUpdateService(c *gin.Context) {
+	admin, err := getAdmin(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to identify admin from context", err))
+		return
+	}
+
+	serviceID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid service ID", err))
+		return
+	}
+	var input services.UpdateServiceInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+	if err := h.services.Admin.UpdateService(c.Request.Context(), admin, serviceID, input); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, statusResponse{Status: "service updated successfully"})
+}
+
+// @Summary      Удалить услугу каталога
+// @Security     ApiKeyAuth
+// @Tags         Admin Services
+// @Description  Удаляет услугу из каталога. Если на услугу есть запланированные записи на сегодня или позже, возвращается 409.
+// @Id           admin-delete-service
+// @Param        id path int true "ID Услуги"
+// @Success      204 "No Content"
+// @Failure      400,401,403,404,409,500 {object} errorResponse
+// @Router       /admin/services/{id} [delete]
+func (h *Handler) adminDeleteService(c *gin.Context) {
+	serviceID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid service ID", err))
+		return
+	}
+	if err := h.services.Admin.DeleteService(c.Request.Context(), serviceID); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary      Получить историю цен услуги
+// @Security     ApiKeyAuth
+// @Tags         Admin Services
+// @Description  Изменения базовой цены и цен врачей, новые сверху. Записи без doctorID относятся к базовой цене.
+// @Description  Пустая oldPrice - цены еще не было, пустая newPrice - врач перестал оказывать услугу.
+// @Id           admin-get-service-price-history
+// @Produce      json
+// @Param        id path int true "ID Услуги"
+// @Param        page query int false "Номер страницы" default(1)
+// @Param        limit query int false "Количество на странице" default(10)
+// @Success      200 {object} map[string]interface{} "items, total"
+// @Failure      400,401,403,404,500 {object} errorResponse
+// @Router       /admin/services/{id}/price-history [get]
+func (h *Handler) adminGetServicePriceHistory(c *gin.Context) {
+	serviceID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid service ID", err))
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	params := models.PaginationParams{Page: page, Limit: limit}
+
+	items, total, err := h.services.Admin.GetServicePriceHistory(c.Request.Context(), serviceID, params)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "total": total})
+}
+
+// @Summary      Получить отделения
+// @Security     ApiKeyAuth
+// @Tags         Admin Services
+// @Id           admin-get-all-departments
+// @Produce      json
+// @Success      200 {array} models.Department
+// @Failure      401,403,500 {object} errorResponse
+// @Router       /admin/departments [get]
+func (h *Handler) adminGetAllDepartments(c *gin.Context) {
+	departments, err := h.services.Admin.GetAllDepartments(c.Request.Context())
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to get departments", err))
+		return
+	}
+	c.JSON(http.StatusOK, departments)
+}
+
+// @Summary      Создать отделение
+// @Security     ApiKeyAuth
+// @Tags         Admin Services
+// @Id           admin-create-department
+// @Accept       json
+// @Produce      json
+// @Param        input body services.CreateDepartmentInput true "Отделение"
+// @Success      201 {object} map[string]uint32 "id"
+// @Failure      400,401,403,409,500 {object} errorResponse
+// @Router       /admin/departments [post]
+func (h *Handler) adminCreateDepartment(c *gin.Context) {
+	var input services.CreateDepartmentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+	departmentID, err := h.services.Admin.CreateDepartment(c.Request.Context(), input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"id": departmentID})
+}
+
+// @Summary      Обновить отделение
+// @Security     ApiKeyAuth
+// @Tags         Admin Services
+// @Id           admin-update-department
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "ID Отделения"
+// @Param        input body services.UpdateDepartmentInput true "Обновляемые данные"
+// @Success      200 {object} statusResponse
+// @Failure      400,401,403,404,409,500 {object} errorResponse
+// @Router       /admin/departments/{id} [put]
+func (h *Handler) adminUpdateDepartment(c *gin.Context) {
+	departmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid department ID", err))
+		return
+	}
+	var input services.UpdateDepartmentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+	if err := h.services.Admin.UpdateDepartment(c.Request.Context(), uint32(departmentID), input); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, statusResponse{Status: "department updated successfully"})
+}
+
+// @Summary      Удалить отделение
+// @Security     ApiKeyAuth
+// @Tags         Admin Services
+// @Description  Удаляет отделение. Если в нем остались действующие услуги или врачи, возвращается 409.
+// @Id           admin-delete-department
+// @Param        id path int true "ID Отделения"
+// @Success      204 "No Content"
+// @Failure      400,401,403,404,409,500 {object} errorResponse
+// @Router       /admin/departments/{id} [delete]
+func (h *Handler) adminDeleteDepartment(c *gin.Context) {
+	departmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid department ID", err))
+		return
+	}
+	if err := h.services.Admin.DeleteDepartment(c.Request.Context(), uint32(departmentID)); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,195 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"lk/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary      Обновление токенов администратора
+// @Tags         Admin Auth
+// @Description  Ротирует refresh токен сессии и возвращает новую пару токенов.
+// @Description  Повторное использование старого refresh токена завершает сессию.
+// @Id           admin-refresh-token
+// @Accept       json
+// @Produce      json
+// @Param        input body refreshInput true "Refresh токен"
+// @Success      200 {object} map[string]string "accessToken, refreshToken (и mustChangePassword)"
+// @Failure      400,401,403,500 {object} errorResponse
+// @Router       /admin/refresh [post]
+func (h *Handler) adminRefresh(c *gin.Context) {
+	var input refreshInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+
+	tokens, err := h.services.Admin.RefreshToken(c.Request.Context(), input.RefreshToken,
+		c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// @Summary      Выход администратора
+// @Tags         Admin Auth
+// @Description  Завершает сессию, которой принадлежит refresh токен. Access токены сессии перестают приниматься сразу.
+// @Id           admin-logout
+// @Accept       json
+// @Produce      json
+// @Param        input body refreshInput true "Refresh токен"
+// @Success      200 {object} statusResponse
+// @Failure      400,401,500 {object} errorResponse
+// @Router       /admin/logout [post]
+func (h *Handler) adminLogout(c *gin.Context) {
+	var input refreshInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+
+	if err := h.services.Admin.Logout(c.Request.Context(), input.RefreshToken); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{Status: "you have been logged out"})
+}
+
+// @Summary      Мои активные сессии
+// @Security     ApiKeyAuth
+// @Tags         Admin Accounts
+// @Description  Возвращает активные сессии текущего администратора; текущая помечена флагом current.
+// @Id           admin-get-my-sessions
+// @Produce      json
+// @Success      200 {array} models.AdminSession
+// @Failure      401,500 {object} errorResponse
+// @Router       /admin/me/sessions [get]
+func (h *Handler) adminGetMySessions(c *gin.Context) {
+	admin, err := getAdmin(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to identify admin from context", err))
+		return
+	}
+
+	sessions, err := h.services.Admin.GetSessions(c.Request.Context(), admin.ID, getAdminSessionID(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, sessions)
+}
+
+// @Summary      Завершить свою сессию
+// @Security     ApiKeyAuth
+// @Tags         Admin Accounts
+// @Description  Завершает одну из сессий текущего администратора (например, на утерянном устройстве).
+// @Id           admin-revoke-my-session
+// @Produce      json
+// @Param        id path int true "ID сессии"
+// @Success      200 {object} statusResponse
+// @Failure      400,401,404,500 {object} errorResponse
+// @Router       /admin/me/sessions/{id} [delete]
+func (h *Handler) adminRevokeMySession(c *gin.Context) {
+	admin, err := getAdmin(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to identify admin from context", err))
+		return
+	}
+
+	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid session ID", err))
+		return
+	}
+
+	if err := h.services.Admin.RevokeSession(c.Request.Context(), admin.ID, sessionID); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, statusResponse{Status: "session revoked"})
+}
+
+// @Summary      Завершить остальные свои сессии
+// @Security     ApiKeyAuth
+// @Tags         Admin Accounts
+// @Description  Завершает все сессии текущего администратора, кроме текущей.
+// @Id           admin-revoke-my-other-sessions
+// @Produce      json
+// @Success      200 {object} map[string]int64 "revoked - количество завершенных сессий"
+// @Failure      401,500 {object} errorResponse
+// @Router       /admin/me/sessions/revoke-others [post]
+func (h *Handler) adminRevokeMyOtherSessions(c *gin.Context) {
+	admin, err := getAdmin(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to identify admin from context", err))
+		return
+	}
+
+	count, err := h.services.Admin.RevokeSessions(c.Request.Context(), admin.ID, getAdminSessionID(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"revoked": count})
+}
+
+// @Summary      Сессии администратора
+// @Security     ApiKeyAuth
+// @Tags         Admin Accounts
+// @Description  Возвращает активные сессии указанного администратора.
+// @Id           admin-get-admin-sessions
+// @Produce      json
+// @Param        id path int true "ID администратора"
+// @Success      200 {array} models.AdminSession
+// @Failure      400,401,403,404,500 {object} errorResponse
+// @Router       /admin/admins/{id}/sessions [get]
+func (h *Handler) adminGetAdminSessions(c *gin.Context) {
+	adminID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid admin ID", err))
+		return
+	}
+
+	if _, err := h.services.Admin.GetAdminByID(c.Request.Context(), adminID); err != nil {
+		c.Error(err)
+		return
+	}
+	sessions, err := h.services.Admin.GetSessions(c.Request.Context(), adminID, getAdminSessionID(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, sessions)
+}
+
+// @Summary      Завершить все сессии администратора
+// @Security     ApiKeyAuth
+// @Tags         Admin Accounts
+// @Description  Принудительно завершает все сессии указанного администратора (кроме текущей, если это он сам).
+// @Id           admin-revoke-admin-sessions
+// @Produce      json
+// @Param        id path int true "ID администратора"
+// @Success      200 {object} map[string]int64 "revoked - количество завершенных сессий"
+// @Failure      400,401,403,404,500 {object} errorResponse
+// @Router       /admin/admins/{id}/revoke-sessions [post]
+func (h *Handler) adminRevokeAdminSessions(c *gin.Context) {
+	adminID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid admin ID", err))
+		return
+	}
+
+	count, err := h.services.Admin.RevokeSessions(c.Request.Context(), adminID, getAdminSessionID(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"revoked": count})
+}
@@ -15,10 +15,8 @@ import (
 type createAppointmentInput struct {
 	DoctorID        uint64    `json:"doctorID" binding:"required"`
 	ServiceID       uint64    `json:"serviceID" binding:"required"`
-	ClinicID        uint64    `json:"clinicID" binding:"required"`
 	AppointmentDate time.Time `json:"appointmentDate" binding:"required"` // Формат: "2025-09-15T10:00:00Z"
 	AppointmentTime string    `json:"appointmentTime" binding:"required"` // Формат: "10:00"
-	PriceAtBooking  float64   `json:"priceAtBooking" binding:"required"`
 	IsDMS           bool      `json:"isDms"`
 }
 
@@ -26,6 +24,9 @@ type createAppointmentInput struct {
 // @Security     ApiKeyAuth
 // @Tags         appointments
 // @Description  Создает новую запись на прием для текущего пользователя.
+// @Description  Клиника определяется интервалом расписания врача, в который попадает время записи
+// @Description  (если такого интервала нет, возвращается 400), цена - услугой врача
+// @Description  на момент записи. Врач должен оказывать выбранную услугу.
 // @ID           create-appointment
 // @Accept       json
 // @Produce      json
@@ -50,10 +51,8 @@ func (h *Handler) createAppointment(c *gin.Context) {
 		UserID:          userProfile.UserID,
 		DoctorID:        input.DoctorID,
 		ServiceID:       input.ServiceID,
-		ClinicID:        input.ClinicID,
 		AppointmentDate: input.AppointmentDate,
 		AppointmentTime: input.AppointmentTime,
-		PriceAtBooking:  input.PriceAtBooking,
 		IsDMS:           input.IsDMS,
 		StatusID:        models.StatusScheduled,
 	}
@@ -98,12 +97,13 @@ func (h *Handler) getUserAppointments(c *gin.Context) {
 // @Summary      Отменить запись на приём
 // @Security     ApiKeyAuth
 // @Tags         appointments
-// @Description  Отменяет существующую запись на прием по ее ID.
+// @Description  Отменяет существующую запись на прием по ее ID. Если до приема осталось меньше
+// @Description  окна отмены из настроек клиники, возвращается 409.
 // @ID           cancel-appointment
 // @Produce      json
 // @Param        id path int true "ID Записи"
 // @Success      200 {object} statusResponse "Статус операции"
-// @Failure      400,401,403,404,500 {object} errorResponse
+// @Failure      400,401,403,404,409,500 {object} errorResponse
 // @Router       /appointments/{id} [delete]
 func (h *Handler) cancelAppointment(c *gin.Context) {
 	userProfile, err := getUserProfile(c)
@@ -132,6 +132,7 @@ func (h *Handler) cancelAppointment(c *gin.Context) {
 type availableDatesQuery struct {
 	SpecialistID uint64 `form:"specialistId" binding:"required"`
 	ServiceID    uint64 `form:"serviceId" binding:"required"`
+	ClinicID     uint64 `form:"clinicId"`
 	Month        string `form:"month" binding:"required"` // Формат: YYYY-MM
 }
 
@@ -139,10 +140,12 @@ type availableDatesQuery struct {
 // @Security     ApiKeyAuth
 // @Tags         appointments
 // @Description  Возвращает список дней в указанном месяце, в которые у специалиста есть свободные слоты.
+// @Description  Дни за горизонтом записи из настроек клиники не возвращаются.
 // @Id           get-available-dates
 // @Produce      json
 // @Param        specialistId query int true "ID Специалиста"
 // @Param        serviceId query int true "ID Услуги"
+// @Param        clinicId query int false "ID клиники: только дни приема в ней"
 // @Param        month query string true "Месяц в формате YYYY-MM"
 // @Success      200 {object} models.AvailableDatesResponse
 // @Failure      400,401,500 {object} errorResponse
@@ -155,7 +158,7 @@ func (h *Handler) getAvailableDates(c *gin.Context) {
 	}
 
 	dates, err := h.services.Appointment.GetAvailableDates(c.Request.Context(),
-		queryParams.SpecialistID, queryParams.ServiceID, queryParams.Month)
+		queryParams.SpecialistID, queryParams.ServiceID, queryParams.ClinicID, queryParams.Month)
 	if err != nil {
 		c.Error(err)
 		return
@@ -168,6 +171,7 @@ func (h *Handler) getAvailableDates(c *gin.Context) {
 type availableSlotsQuery struct {
 	SpecialistID uint64 `form:"specialistId" binding:"required"`
 	ServiceID    uint64 `form:"serviceId" binding:"required"`
+	ClinicID     uint64 `form:"clinicId"`
 	Date         string `form:"date" binding:"required"` // Формат: YYYY-MM-DD
 }
 
@@ -175,10 +179,13 @@ type availableSlotsQuery struct {
 // @Security     ApiKeyAuth
 // @Tags         appointments
 // @Description  Возвращает список свободных временных слотов у специалиста на указанную дату.
+// @Description  Если врач принимает в этот день в нескольких интервалах или клиниках, слоты каждого интервала
+// @Description  с его клиникой перечислены в intervals.
 // @Id           get-available-slots
 // @Produce      json
 // @Param        specialistId query int true "ID Специалиста"
 // @Param        serviceId query int true "ID Услуги"
+// @Param        clinicId query int false "ID клиники: слоты только при приеме в ней"
 // @Param        date query string true "Дата в формате YYYY-MM-DD"
 // @Success      200 {object} models.AvailableSlotsResponse
 // @Failure      400,401,500 {object} errorResponse
@@ -191,7 +198,7 @@ func (h *Handler) getAvailableSlots(c *gin.Context) {
 	}
 
 	slots, err := h.services.Appointment.GetAvailableSlots(c.Request.Context(),
-		queryParams.SpecialistID, queryParams.ServiceID, queryParams.Date)
+		queryParams.SpecialistID, queryParams.ServiceID, queryParams.ClinicID, queryParams.Date)
 	if err != nil {
 		c.Error(err)
 		return
@@ -204,6 +211,7 @@ func (h *Handler) getAvailableSlots(c *gin.Context) {
 type availableRangeSlotsQuery struct {
 	SpecialistID uint64 `form:"specialistId" binding:"required"`
 	ServiceID    uint64 `form:"serviceId" binding:"required"`
+	ClinicID     uint64 `form:"clinicId"`
 	StartDate    string `form:"startDate" binding:"required"` // Формат: YYYY-MM-DD
 	EndDate      string `form:"endDate" binding:"required"`   // Формат: YYYY-MM-DD
 }
@@ -216,6 +224,7 @@ type availableRangeSlotsQuery struct {
 // @Produce      json
 // @Param        specialistId query int true "ID Специалиста"
 // @Param        serviceId query int true "ID Услуги"
+// @Param        clinicId query int false "ID клиники: только дни приема в ней"
 // @Param        startDate query string true "Начальная дата в формате YYYY-MM-DD"
 // @Param        endDate query string true "Конечная дата в формате YYYY-MM-DD"
 // @Success      200 {object} models.AvailableRangeSlotsResponse
@@ -229,7 +238,7 @@ func (h *Handler) getAvailableSlotsByRange(c *gin.Context) {
 	}
 
 	slots, err := h.services.Appointment.GetAvailableSlotsByRange(c.Request.Context(),
-		queryParams.SpecialistID, queryParams.ServiceID,
+		queryParams.SpecialistID, queryParams.ServiceID, queryParams.ClinicID,
 		queryParams.StartDate, queryParams.EndDate)
 	if err != nil {
 		c.Error(err)
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"lk/internal/services"
+	_ "lk/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -17,17 +18,24 @@ type signUpInput struct {
 	Gender          string `json:"gender" binding:"required"`
 	BirthDate       string `json:"birthDate" binding:"required"` // Ожидаемый формат: "YYYY-MM-DD"
 	CityID          uint32 `json:"cityID" binding:"required"`
+	// ID действующих версий принятых документов; обязательные документы должны быть среди них.
+	AcceptedDocuments []uint64 `json:"acceptedDocuments" binding:"required"`
 }
 
 // @Summary      Регистрация пользователя
 // @Tags         auth
-// @Description  Создает новый аккаунт пользователя и его профиль, возвращает пару токенов для автоматического входа.
+// @Description  Создает неактивный аккаунт пользователя и его профиль, отправляет SMS-код для подтверждения телефона.
+// @Description  Номер телефона приводится к формату E.164. В acceptedDocuments передаются ID действующих
+// @Description  версий всех обязательных документов из GET /legal/documents и, по желанию, необязательных.
+// @Description  Если номер зарегистрирован, но еще не подтвержден, данные ожидающего аккаунта перезаписываются
+// @Description  и отправляется новый код; 409 возвращается только для подтвержденного номера.
+// @Description  Отправка SMS-кодов ограничена по номеру и IP-адресу; при превышении возвращается 429.
 // @Id           create-account
 // @Accept       json
 // @Produce      json
 // @Param        input body signUpInput true "Информация для регистрации"
-// @Success      201 {object} map[string]string "Возвращает accessToken и refreshToken"
-// @Failure      400,409,500 {object} errorResponse
+// @Success      201 {object} statusResponse
+// @Failure      400,409,429,500 {object} errorResponse
 // @Router       /auth/register [post]
 func (h *Handler) signUp(c *gin.Context) {
 	var input signUpInput
@@ -41,14 +49,74 @@ func (h *Handler) signUp(c *gin.Context) {
 		return
 	}
 
-	tokens, err := h.services.Authorization.CreateUser(c.Request.Context(), input.Phone,
-		input.Password, input.FullName, input.Gender, input.BirthDate, input.CityID)
+	err := h.services.Authorization.CreateUser(c.Request.Context(), input.Phone,
+		input.Password, input.FullName, input.Gender, input.BirthDate, input.CityID, input.AcceptedDocuments, c.ClientIP())
 	if err != nil {
 		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, tokens)
+	c.JSON(http.StatusCreated, statusResponse{Status: "verification code has been sent"})
+}
+
+type verifyPhoneInput struct {
+	Phone string `json:"phone" binding:"required"`
+	Code  string `json:"code" binding:"required"`
+}
+
+// @Summary      Подтверждение телефона
+// @Tags         auth
+// @Description  Проверяет SMS-код, активирует аккаунт и возвращает пару токенов для автоматического входа.
+// @Id           verify-phone
+// @Accept       json
+// @Produce      json
+// @Param        input body verifyPhoneInput true "Номер телефона и код"
+// @Success      200 {object} map[string]string "Возвращает accessToken и refreshToken"
+// @Failure      400,401,429,500 {object} errorResponse
+// @Router       /auth/verify-phone [post]
+func (h *Handler) verifyPhone(c *gin.Context) {
+	var input verifyPhoneInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+
+	tokens, err := h.services.Authorization.VerifyPhone(c.Request.Context(), input.Phone, input.Code, c.ClientIP())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+type resendCodeInput struct {
+	Phone string `json:"phone" binding:"required"`
+}
+
+// @Summary      Повторная отправка кода подтверждения
+// @Tags         auth
+// @Description  Повторно отправляет SMS-код для подтверждения телефона неактивированного аккаунта.
+// @Id           resend-verification-code
+// @Accept       json
+// @Produce      json
+// @Param        input body resendCodeInput true "Номер телефона"
+// @Success      200 {object} statusResponse
+// @Failure      400,429,500 {object} errorResponse
+// @Router       /auth/resend-code [post]
+func (h *Handler) resendVerificationCode(c *gin.Context) {
+	var input resendCodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+
+	if err := h.services.Authorization.ResendVerificationCode(c.Request.Context(), input.Phone, c.ClientIP()); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{Status: "verification code has been sent"})
 }
 
 // signInInput - структура для валидации данных при входе.
@@ -65,7 +133,7 @@ type signInInput struct {
 // @Produce      json
 // @Param        input body signInInput true "Учетные данные для входа"
 // @Success      200 {object} map[string]string "Возвращает accessToken и refreshToken"
-// @Failure      400,401,500 {object} errorResponse
+// @Failure      400,401,403,429,500 {object} errorResponse
 // @Router       /auth/login [post]
 func (h *Handler) signIn(c *gin.Context) {
 	var input signInInput
@@ -75,7 +143,7 @@ func (h *Handler) signIn(c *gin.Context) {
 	}
 
 	tokens, err := h.services.Authorization.GenerateToken(c.Request.Context(),
-		input.Phone, input.Password)
+		input.Phone, input.Password, c.ClientIP())
 	if err != nil {
 		c.Error(err)
 		return
@@ -145,13 +213,13 @@ type forgotPasswordInput struct {
 
 // @Summary      Восстановление пароля (шаг 1: запрос кода)
 // @Tags         auth
-// @Description  Отправляет код подтверждения для сброса пароля (в текущей реализации код выводится в лог сервера).
+// @Description  Отправляет SMS-код подтверждения для сброса пароля.
 // @Id           forgot-password
 // @Accept       json
 // @Produce      json
 // @Param        input body forgotPasswordInput true "Номер телефона"
 // @Success      200 {object} statusResponse
-// @Failure      400,500 {object} errorResponse
+// @Failure      400,429,500 {object} errorResponse
 // @Router       /auth/forgot-password [post]
 func (h *Handler) forgotPassword(c *gin.Context) {
 	var input forgotPasswordInput
@@ -160,7 +228,7 @@ func (h *Handler) forgotPassword(c *gin.Context) {
 		return
 	}
 
-	if err := h.services.Authorization.ForgotPassword(c.Request.Context(), input.Phone); err != nil {
+	if err := h.services.Authorization.ForgotPassword(c.Request.Context(), input.Phone, c.ClientIP()); err != nil {
 		c.Error(err)
 		return
 	}
@@ -182,7 +250,7 @@ type resetPasswordInput struct {
 // @Produce      json
 // @Param        input body resetPasswordInput true "Данные для сброса"
 // @Success      200 {object} statusResponse
-// @Failure      400,401,500 {object} errorResponse
+// @Failure      400,401,429,500 {object} errorResponse
 // @Router       /auth/reset-password [post]
 func (h *Handler) resetPassword(c *gin.Context) {
 	var input resetPasswordInput
@@ -192,7 +260,91 @@ func (h *Handler) resetPassword(c *gin.Context) {
 	}
 
 	err := h.services.Authorization.ResetPassword(c.Request.Context(), input.Phone, input.Code,
-		input.NewPassword)
+		input.NewPassword, c.ClientIP())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{Status: "password has been reset successfully"})
+}
+
+// @Summary      Подтверждение адреса электронной почты
+// @Tags         auth
+// @Description  Подтверждает адрес электронной почты по подписанной ссылке из письма.
+// @Id           verify-email
+// @Produce      json
+// @Param        token query string true "Токен из ссылки"
+// @Success      200 {object} statusResponse
+// @Failure      400,500 {object} errorResponse
+// @Router       /auth/verify-email [get]
+func (h *Handler) verifyEmail(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.Error(services.NewBadRequestError("token is required", nil))
+		return
+	}
+
+	if err := h.services.Authorization.VerifyEmail(c.Request.Context(), token); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{Status: "email has been verified"})
+}
+
+type forgotPasswordByEmailInput struct {
+	Email string `json:"email" binding:"required"`
+}
+
+// @Summary      Восстановление пароля по email (шаг 1: запрос ссылки)
+// @Tags         auth
+// @Description  Отправляет ссылку для сброса пароля, если адрес подтвержден в профиле пользователя.
+// @Id           forgot-password-email
+// @Accept       json
+// @Produce      json
+// @Param        input body forgotPasswordByEmailInput true "Адрес электронной почты"
+// @Success      200 {object} statusResponse
+// @Failure      400,429,500 {object} errorResponse
+// @Router       /auth/forgot-password/email [post]
+func (h *Handler) forgotPasswordByEmail(c *gin.Context) {
+	var input forgotPasswordByEmailInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+
+	if err := h.services.Authorization.ForgotPasswordByEmail(c.Request.Context(), input.Email, c.ClientIP()); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{Status: "if the email is verified, a reset link has been sent"})
+}
+
+type resetPasswordByEmailInput struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"newPassword" binding:"required,min=8"`
+}
+
+// @Summary      Восстановление пароля по email (шаг 2: сброс)
+// @Tags         auth
+// @Description  Устанавливает новый пароль по одноразовой ссылке из письма.
+// @Id           reset-password-email
+// @Accept       json
+// @Produce      json
+// @Param        input body resetPasswordByEmailInput true "Токен из ссылки и новый пароль"
+// @Success      200 {object} statusResponse
+// @Failure      400,401,500 {object} errorResponse
+// @Router       /auth/reset-password/email [post]
+func (h *Handler) resetPasswordByEmail(c *gin.Context) {
+	var input resetPasswordByEmailInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+
+	err := h.services.Authorization.ResetPasswordByEmail(c.Request.Context(), input.Token, input.NewPassword)
 	if err != nil {
 		c.Error(err)
 		return
@@ -231,3 +383,17 @@ func (h *Handler) gosuslugiCallback(c *gin.Context) {
 	// 7. В случае успеха сгенерировать пару JWT-токенов (access/refresh) и вернуть их клиенту.
 	c.Error(services.NewInternalServerError("Not implemented yet", nil))
 }
+
+// @Summary      Открытые ключи подписи токенов (JWKS)
+// @Tags         auth
+// @Description  Возвращает открытые ключи (RFC 7517), которыми другие сервисы проверяют access токены.
+// @Description  Эндпоинт доступен в корне сервера (/.well-known/jwks.json), вне префикса /api/v1.
+// @Description  Во время ротации набор содержит и новый, и предыдущие ключи; ключ выбирается по заголовку kid.
+// @Id           get-jwks
+// @Produce      json
+// @Success      200 {object} utils.JWKS
+// @Router       /.well-known/jwks.json [get]
+func (h *Handler) getJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.services.Authorization.JWKS())
+}
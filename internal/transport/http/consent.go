@@ -0,0 +1,127 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"lk/internal/models"
+	"lk/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary      Получить состояние согласий
+// @Security     ApiKeyAuth
+// @Tags         consents
+// @Description  Возвращает действующие версии юридических документов и отметку о их принятии пользователем.
+// @Description  requiresAcceptance=true означает, что опубликована новая редакция обязательного документа
+// @Description  и до ее принятия остальные разделы кабинета отвечают 403.
+// @Id           get-consents
+// @Produce      json
+// @Success      200 {array} models.ConsentStatus
+// @Failure      401,500 {object} errorResponse
+// @Router       /consents [get]
+func (h *Handler) getConsents(c *gin.Context) {
+	userProfile, err := getUserProfile(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to get user from context", err))
+		return
+	}
+
+	statuses, err := h.services.Consent.GetConsents(c.Request.Context(), userProfile.UserID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, statuses)
+}
+
+// @Summary      История согласий
+// @Security     ApiKeyAuth
+// @Tags         consents
+// @Description  Возвращает историю принятия и отзыва документов пользователем. Новые записи сверху.
+// @Id           get-consent-history
+// @Produce      json
+// @Param        page query int false "Номер страницы" default(1)
+// @Param        limit query int false "Количество на странице" default(10)
+// @Success      200 {object} map[string]interface{} "items: []models.UserConsent, total: int"
+// @Failure      401,500 {object} errorResponse
+// @Router       /consents/history [get]
+func (h *Handler) getConsentHistory(c *gin.Context) {
+	userProfile, err := getUserProfile(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to get user from context", err))
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	params := models.PaginationParams{Page: page, Limit: limit}
+
+	items, total, err := h.services.Consent.GetHistory(c.Request.Context(), userProfile.UserID, params)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "total": total})
+}
+
+type acceptConsentsInput struct {
+	DocumentIDs []uint64 `json:"documentIds" binding:"required,min=1"`
+}
+
+// @Summary      Принять документы
+// @Security     ApiKeyAuth
+// @Tags         consents
+// @Description  Фиксирует принятие действующих версий документов с IP-адресом и User-Agent.
+// @Description  Если версия уже заменена новой, возвращается 409 и список документов нужно обновить.
+// @Id           accept-consents
+// @Accept       json
+// @Produce      json
+// @Param        input body acceptConsentsInput true "ID версий документов"
+// @Success      200 {object} statusResponse
+// @Failure      400,401,409,500 {object} errorResponse
+// @Router       /consents [post]
+func (h *Handler) acceptConsents(c *gin.Context) {
+	userProfile, err := getUserProfile(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to get user from context", err))
+		return
+	}
+	var input acceptConsentsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+
+	if err := h.services.Consent.Accept(c.Request.Context(), userProfile.UserID, input.DocumentIDs); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, statusResponse{Status: "documents accepted"})
+}
+
+// @Summary      Отозвать согласие
+// @Security     ApiKeyAuth
+// @Tags         consents
+// @Description  Отзывает необязательное согласие (например, на рекламные рассылки).
+// @Description  Обязательные согласия отозвать нельзя - для этого нужно удалить аккаунт.
+// @Id           revoke-consent
+// @Produce      json
+// @Param        type path string true "Тип документа" example(marketing_consent)
+// @Success      200 {object} statusResponse
+// @Failure      400,401,404,500 {object} errorResponse
+// @Router       /consents/{type} [delete]
+func (h *Handler) revokeConsent(c *gin.Context) {
+	userProfile, err := getUserProfile(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to get user from context", err))
+		return
+	}
+
+	if err := h.services.Consent.Revoke(c.Request.Context(), userProfile.UserID, c.Param("type")); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, statusResponse{Status: "consent revoked"})
+}
@@ -12,30 +12,51 @@ import (
 
 // findSpecialistsQuery - структура для валидации query-параметров при поиске и фильтрации врачей.
 type findSpecialistsQuery struct {
-	Query       string `form:"q"`
-	Service     string `form:"service"`
-	SpecialtyID uint32 `form:"specialtyID"`
-	Page        int    `form:"page,default=1"`
-	Limit       int    `form:"limit,default=10"`
-	SortBy      string `form:"sortBy,default=rating"`
-	SortOrder   string `form:"sortOrder,default=desc"`
+	Query           string  `form:"q"`
+	SpecialtyID     uint32  `form:"specialtyID"`
+	Service         string  `form:"service"`
+	ClinicID        uint64  `form:"clinicID"`
+	CityID          uint32  `form:"cityID"`
+	MinRating       float32 `form:"minRating" binding:"omitempty,min=0,max=5"`
+	MinExperience   uint16  `form:"minExperience"`
+	Gender          string  `form:"gender" binding:"omitempty,oneof=male female"`
+	MinPrice        float64 `form:"minPrice" binding:"omitempty,min=0"`
+	MaxPrice        float64 `form:"maxPrice" binding:"omitempty,min=0"`
+	AcceptsDMS      bool    `form:"dms"`
+	SlotsWithinDays int     `form:"slotsWithinDays" binding:"omitempty,min=1,max=90"`
+	Page            int     `form:"page,default=1"`
+	Limit           int     `form:"limit,default=10"`
+	SortBy          string  `form:"sortBy" binding:"omitempty,oneof=relevance rating experience name price"`
+	SortOrder       string  `form:"sortOrder" binding:"omitempty,oneof=asc desc"`
 }
 
 // @Summary      Найти или отфильтровать специалистов
 // @Security     ApiKeyAuth
 // @Tags         specialists
-// @Description  Находит специалистов по различным критериям. Используйте ОДИН из следующих параметров: 'q' для поиска по ФИО/специальности, 'service' для поиска по услуге, или 'specialtyID' для фильтрации с пагинацией и сортировкой.
+// @Description  Ищет специалистов по любому сочетанию фильтров. Без параметров возвращает всех врачей.
+// @Description  Вместе со страницей результатов возвращаются фасеты - количество врачей по значениям фильтров.
+// @Description  Запрос 'q' ищет по ФИО, специальности, услугам, навыкам и специализациям: слова сопоставляются
+// @Description  как префиксы, опечатки допускаются. В поле highlight совпадения обрамлены тегом <mark>,
+// @Description  остальной текст не экранирован.
 // @Id           find-specialists
 // @Produce      json
-// @Param        q query string false "Поисковый запрос (ФИО или название специальности)"
+// @Param        q query string false "Поисковый запрос"
+// @Param        specialtyID query int false "ID специальности"
 // @Param        service query string false "Название медицинской услуги"
-// @Param        specialtyID query int false "ID специальности для фильтрации"
-// @Param        page query int false "Номер страницы (используется с 'specialtyID')" default(1)
-// @Param        limit query int false "Количество элементов на странице (используется с 'specialtyID')" default(10)
-// @Param        sortBy query string false "Поле для сортировки: 'rating', 'experience', 'name' (используется с 'specialtyID')" Enums(rating, experience, name) default(rating)
-// @Param        sortOrder query string false "Порядок сортировки: 'asc', 'desc' (используется с 'specialtyID')" Enums(asc, desc) default(desc)
-// @Success      200 {object} models.PaginatedDoctorsResponse "Возвращается при использовании 'specialtyID'"
-// @Success      200 {array} models.Doctor "Возвращается при использовании 'q' или 'service'"
+// @Param        clinicID query int false "ID клиники"
+// @Param        cityID query int false "ID города клиники"
+// @Param        minRating query number false "Минимальный рейтинг"
+// @Param        minExperience query int false "Минимальный стаж в годах"
+// @Param        gender query string false "Пол врача" Enums(male, female)
+// @Param        minPrice query number false "Минимальная цена услуги"
+// @Param        maxPrice query number false "Максимальная цена услуги"
+// @Param        dms query bool false "Только врачи, принимающие по ДМС"
+// @Param        slotsWithinDays query int false "Есть свободное время в ближайшие N дней (1-90)"
+// @Param        page query int false "Номер страницы" default(1)
+// @Param        limit query int false "Количество элементов на странице (до 100)" default(10)
+// @Param        sortBy query string false "Поле для сортировки. По умолчанию relevance при заданном 'q', иначе rating" Enums(relevance, rating, experience, name, price)
+// @Param        sortOrder query string false "Порядок сортировки. По умолчанию зависит от поля" Enums(asc, desc)
+// @Success      200 {object} models.DoctorSearchResponse
 // @Failure      400,401,500 {object} errorResponse
 // @Router       /specialists [get]
 func (h *Handler) findSpecialists(c *gin.Context) {
@@ -45,56 +66,68 @@ func (h *Handler) findSpecialists(c *gin.Context) {
 		return
 	}
 
-	ctx := c.Request.Context()
-
-	// Логика приоритетов: q > service > specialtyID
-	if queryParams.Query != "" {
-		doctors, err := h.services.Doctor.SearchDoctors(ctx, queryParams.Query)
-		if err != nil {
-			c.Error(err)
-			return
-		}
-		c.JSON(http.StatusOK, doctors)
+	filter := models.DoctorSearchFilter{
+		Query:           queryParams.Query,
+		SpecialtyID:     queryParams.SpecialtyID,
+		Service:         queryParams.Service,
+		ClinicID:        queryParams.ClinicID,
+		CityID:          queryParams.CityID,
+		MinRating:       queryParams.MinRating,
+		MinExperience:   queryParams.MinExperience,
+		Gender:          queryParams.Gender,
+		MinPrice:        queryParams.MinPrice,
+		MaxPrice:        queryParams.MaxPrice,
+		AcceptsDMS:      queryParams.AcceptsDMS,
+		SlotsWithinDays: queryParams.SlotsWithinDays,
+	}
+	paginationParams := models.PaginationParams{
+		Page:      queryParams.Page,
+		Limit:     queryParams.Limit,
+		SortBy:    queryParams.SortBy,
+		SortOrder: queryParams.SortOrder,
+	}
+	response, err := h.services.Doctor.SearchDoctors(c.Request.Context(), filter, paginationParams)
+	if err != nil {
+		c.Error(err)
 		return
 	}
+	c.JSON(http.StatusOK, response)
+}
 
-	if queryParams.Service != "" {
-		doctors, err := h.services.Doctor.SearchDoctorsByService(ctx, queryParams.Service)
-		if err != nil {
-			c.Error(err)
-			return
-		}
-		c.JSON(http.StatusOK, doctors)
+// @Summary      Подсказки для строки поиска
+// @Security     ApiKeyAuth
+// @Tags         specialists
+// @Description  Возвращает врачей, специальности и услуги, похожие на введенный текст (от 2 символов).
+// @Id           autocomplete-specialists
+// @Produce      json
+// @Param        q query string true "Введенный текст"
+// @Param        limit query int false "Количество подсказок (до 20)" default(10)
+// @Success      200 {array} models.SearchSuggestion
+// @Failure      400,401,500 {object} errorResponse
+// @Router       /specialists/autocomplete [get]
+func (h *Handler) autocompleteSpecialists(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid limit value", err))
 		return
 	}
-
-	if queryParams.SpecialtyID > 0 {
-		paginationParams := models.PaginationParams{
-			Page:      queryParams.Page,
-			Limit:     queryParams.Limit,
-			SortBy:    queryParams.SortBy,
-			SortOrder: queryParams.SortOrder,
-		}
-		paginatedResponse, err := h.services.Doctor.GetDoctorsBySpecialty(ctx, queryParams.SpecialtyID, paginationParams)
-		if err != nil {
-			c.Error(err)
-			return
-		}
-		c.JSON(http.StatusOK, paginatedResponse)
+	suggestions, err := h.services.Doctor.Autocomplete(c.Request.Context(), c.Query("q"), limit)
+	if err != nil {
+		c.Error(err)
 		return
 	}
-
-	c.Error(services.NewBadRequestError("At least one query parameter ('q', 'service', or 'specialtyID') is required", nil))
+	c.JSON(http.StatusOK, suggestions)
 }
 
 // @Summary      Получить специалиста по ID
 // @Security     ApiKeyAuth
 // @Tags         specialists
-// @Description  Получает детальную информацию о специалисте по его ID.
+// @Description  Получает полный профиль специалиста: образование, ординатуру, курсы,
+// @Description  сертификаты, навыки и специализации.
 // @Id           get-specialist-by-id
 // @Produce      json
 // @Param        id path int true "ID Специалиста"
-// @Success      200 {object} models.Doctor
+// @Success      200 {object} models.DoctorProfile
 // @Failure      400,401,404,500 {object} errorResponse
 // @Router       /specialists/{id} [get]
 func (h *Handler) getSpecialistByID(c *gin.Context) {
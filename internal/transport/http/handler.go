@@ -4,6 +4,7 @@
 package http
 
 import (
+	"lk/internal/models"
 	"lk/internal/repository"
 	"lk/internal/services"
 
@@ -36,6 +37,9 @@ func (h *Handler) InitRoutes(router *gin.Engine) {
 	// Эндпоинт для Swagger UI
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Открытые ключи для проверки access токенов другими сервисами
+	router.GET("/.well-known/jwks.json", h.getJWKS)
+
 	// Группа для API версии 1
 	apiV1 := router.Group("/api/v1")
 	{
@@ -43,18 +47,30 @@ func (h *Handler) InitRoutes(router *gin.Engine) {
 		auth := apiV1.Group("/auth")
 		{
 			auth.POST("/register", h.signUp)
+			auth.POST("/verify-phone", h.verifyPhone)
+			auth.POST("/resend-code", h.resendVerificationCode)
 			auth.POST("/login", h.signIn)
 			auth.POST("/refresh", h.refresh)
 			auth.POST("/logout", h.logout)
 			auth.POST("/forgot-password", h.forgotPassword)
 			auth.POST("/reset-password", h.resetPassword)
+			auth.GET("/verify-email", h.verifyEmail)
+			auth.POST("/forgot-password/email", h.forgotPasswordByEmail)
+			auth.POST("/reset-password/email", h.resetPasswordByEmail)
 			auth.GET("/gosuslugi", h.gosuslugiLogin)
 			auth.POST("/gosuslugi/callback", h.gosuslugiCallback)
 		}
 
+		// Юридические документы нужны до регистрации, поэтому доступны без токена
+		apiV1.GET("/legal/documents", h.getLegalDocuments)
+		apiV1.GET("/legal/documents/:type/file", h.downloadLegalDocument)
+
+		// Прейскурант показывается до входа в кабинет
+		apiV1.GET("/price-list", h.getPriceList)
+
 		// --- ЗАЩИЩЕННАЯ ЧАСТЬ: ЛИЧНЫЙ КАБИНЕТ ПАЦИЕНТА ---
 		authorized := apiV1.Group("/")
-		authorized.Use(h.userIdentity)
+		authorized.Use(h.userIdentity, h.requireConsents)
 		{
 			// Профиль пользователя (FR-2.x)
 			profile := authorized.Group("/profile")
@@ -62,18 +78,42 @@ func (h *Handler) InitRoutes(router *gin.Engine) {
 				profile.GET("/", h.getProfile)
 				profile.PATCH("/", h.updateProfile)
 				profile.POST("/avatar", h.updateAvatar)
+				profile.POST("/email/verify", h.requestEmailVerification)
+			}
+
+			// Согласия и принятие юридических документов
+			consents := authorized.Group("/consents")
+			{
+				consents.GET("", h.getConsents)
+				consents.GET("/history", h.getConsentHistory)
+				consents.POST("", h.acceptConsents)
+				consents.DELETE("/:type", h.revokeConsent)
+			}
+
+			// Выгрузка своих данных и удаление аккаунта
+			account := authorized.Group("/account")
+			{
+				account.GET("/exports", h.getDataExports)
+				account.POST("/exports", h.requestDataExport)
+				account.GET("/exports/:id/file", h.downloadDataExport)
+				account.GET("/deletion", h.getAccountDeletion)
+				account.POST("/deletion", h.requestAccountDeletion)
+				account.DELETE("/deletion", h.cancelAccountDeletion)
 			}
 
 			// Справочники и общая информация
 			authorized.GET("/clinic-info", h.getClinicInfo)
-			authorized.GET("/legal/documents", h.getLegalDocuments)
+			authorized.GET("/clinics", h.getClinics)
+			authorized.GET("/clinics/:id", h.getClinic)
 			authorized.GET("/specialties", h.getSpecialties)
 			authorized.GET("/departments", h.getDepartmentsTree)
 
 			// Специалисты и услуги
 			authorized.GET("/specialists", h.findSpecialists)
+			authorized.GET("/specialists/autocomplete", h.autocompleteSpecialists)
 			authorized.GET("/specialists/:id", h.getSpecialistByID)
 			authorized.GET("/specialists/:id/recommendations", h.getSpecialistRecommendations)
+			authorized.GET("/specialists/:id/reviews", h.getSpecialistReviews)
 			authorized.GET("/services/:id/recommendations", h.getServiceRecommendations)
 
 			// Записи на прием (FR-2.x, FR-5.x)
@@ -88,6 +128,13 @@ func (h *Handler) InitRoutes(router *gin.Engine) {
 				appointments.GET("/slots-by-range", h.getAvailableSlotsByRange)
 			}
 
+			// Отзывы о врачах
+			reviews := authorized.Group("/reviews")
+			{
+				reviews.GET("", h.getUserReviews)
+				reviews.POST("", h.createReview)
+			}
+
 			// Назначения (FR-2.x)
 			prescriptions := authorized.Group("/prescriptions")
 			{
@@ -102,6 +149,7 @@ func (h *Handler) InitRoutes(router *gin.Engine) {
 				medicalCard.GET("/analyses", h.getAnalyses)
 				medicalCard.GET("/archive/prescriptions", h.getArchivedPrescriptions)
 				medicalCard.GET("/summary", h.getSummary)
+				medicalCard.GET("/access-log", h.getMedicalCardAccessLog)
 				medicalCard.POST("/archive/prescriptions", h.archivePrescriptionFromCard)
 			}
 
@@ -114,104 +162,209 @@ func (h *Handler) InitRoutes(router *gin.Engine) {
 		{
 			// Публичный эндпоинт для входа администратора
 			admin.POST("/login", h.adminLogin)
+			admin.POST("/login/2fa", h.adminLoginTwoFactor)
+			admin.POST("/refresh", h.adminRefresh)
+			admin.POST("/logout", h.adminLogout)
+
+			// Профиль текущего администратора и смена пароля доступны всегда,
+			// в том числе когда пароль требуется сменить после первого входа
+			me := admin.Group("/me")
+			me.Use(h.adminIdentity)
+			{
+				me.GET("/", h.adminGetMe)
+				me.POST("/password", h.adminChangeOwnPassword)
+				me.GET("/sessions", h.adminGetMySessions)
+				me.DELETE("/sessions/:id", h.adminRevokeMySession)
+				me.POST("/sessions/revoke-others", h.adminRevokeMyOtherSessions)
+			}
+
+			// Настройка 2FA доступна и тогда, когда политика требует 2FA, а она еще не подключена
+			twoFactor := admin.Group("/2fa")
+			twoFactor.Use(h.adminIdentity)
+			{
+				twoFactor.GET("/", h.adminGetTwoFactorStatus)
+				twoFactor.POST("/enroll", h.adminEnrollTwoFactor)
+				twoFactor.POST("/confirm", h.adminConfirmTwoFactor)
+				twoFactor.POST("/recovery-codes", h.adminRegenerateRecoveryCodes)
+				twoFactor.POST("/disable", h.adminDisableTwoFactor)
+			}
 
 			// Группа, защищенная middleware администратора
 			adminAuthorized := admin.Group("/")
-			adminAuthorized.Use(h.adminIdentity)
+			adminAuthorized.Use(h.adminIdentity, h.adminPasswordChangeEnforced, h.adminTwoFactorEnforced)
 			{
 				// 1. Управление пользователями (пациентами)
 				users := adminAuthorized.Group("/users")
 				{
-					users.GET("/", h.adminGetAllUsers)
-					users.GET("/:id", h.adminGetUserByID)
-					users.PATCH("/:id", h.adminUpdateUser)
-					users.DELETE("/:id", h.adminDeleteUser)
-					users.GET("/:id/appointments", h.adminGetUserAppointments)
-					users.GET("/:id/analyses", h.adminGetUserAnalyses)
+					users.GET("/", h.requirePermission(models.PermUsersRead), h.adminGetAllUsers)
+					users.GET("/deleted", h.requirePermission(models.PermUsersRead), h.adminGetDeletedUsers)
+					users.GET("/:id", h.requirePermission(models.PermUsersRead), h.adminGetUserByID)
+					users.PATCH("/:id", h.requirePermission(models.PermUsersWrite), h.adminUpdateUser)
+					users.DELETE("/:id", h.requirePermission(models.PermUsersDelete), h.adminDeleteUser)
+					users.POST("/:id/restore", h.requirePermission(models.PermUsersDelete), h.adminRestoreUser)
+					users.GET("/:id/appointments", h.requirePermission(models.PermAppointmentsRead), h.adminGetUserAppointments)
+					users.GET("/:id/analyses", h.requirePermission(models.PermAnalysesRead), h.adminGetUserAnalyses)
+					users.POST("/:id/impersonate", h.requirePermission(models.PermUsersImpersonate), h.adminImpersonateUser)
 				}
 
 				// 2. Управление врачами (специалистами)
 				specialists := adminAuthorized.Group("/specialists")
 				{
-					specialists.GET("/", h.adminGetAllSpecialists)
-					specialists.POST("/", h.adminCreateSpecialist)
-					specialists.GET("/:id", h.adminGetSpecialistByID)
-					specialists.PUT("/:id", h.adminUpdateSpecialist)
-					specialists.DELETE("/:id", h.adminDeleteSpecialist)
-					specialists.GET("/:id/schedule", h.adminGetSpecialistSchedule)
-					specialists.POST("/:id/schedule", h.adminUpdateSpecialistSchedule)
+					specialists.GET("/", h.requirePermission(models.PermDoctorsRead), h.adminGetAllSpecialists)
+					specialists.POST("/", h.requirePermission(models.PermDoctorsWrite), h.adminCreateSpecialist)
+					specialists.GET("/deleted", h.requirePermission(models.PermDoctorsRead), h.adminGetDeletedSpecialists)
+					specialists.GET("/:id", h.requirePermission(models.PermDoctorsRead), h.adminGetSpecialistByID)
+					specialists.PUT("/:id", h.requirePermission(models.PermDoctorsWrite), h.adminUpdateSpecialist)
+					specialists.DELETE("/:id", h.requirePermission(models.PermDoctorsWrite), h.adminDeleteSpecialist)
+					specialists.POST("/:id/restore", h.requirePermission(models.PermDoctorsWrite), h.adminRestoreSpecialist)
+					specialists.GET("/:id/schedule", h.requirePermission(models.PermSchedulesRead), h.adminGetSpecialistSchedule)
+					specialists.POST("/:id/schedule", h.requirePermission(models.PermSchedulesWrite), h.adminUpdateSpecialistSchedule)
+					specialists.PUT("/:id/clinics", h.requirePermission(models.PermDoctorsWrite), h.adminSetSpecialistClinics)
+					specialists.PUT("/:id/services", h.requirePermission(models.PermDoctorsWrite), h.adminSetSpecialistServices)
+					specialists.POST("/:id/avatar", h.requirePermission(models.PermDoctorsWrite), h.adminUpdateSpecialistAvatar)
+					specialists.DELETE("/:id/avatar", h.requirePermission(models.PermDoctorsWrite), h.adminDeleteSpecialistAvatar)
+					specialists.POST("/:id/profile/:section", h.requirePermission(models.PermDoctorsWrite), h.adminCreateDoctorProfileItem)
+					specialists.PUT("/:id/profile/:section/:itemId", h.requirePermission(models.PermDoctorsWrite), h.adminUpdateDoctorProfileItem)
+					specialists.DELETE("/:id/profile/:section/:itemId", h.requirePermission(models.PermDoctorsWrite), h.adminDeleteDoctorProfileItem)
+					specialists.GET("/certificates/expiring", h.requirePermission(models.PermDoctorsRead), h.adminGetExpiringCertificates)
 				}
 
 				// 3. Управление записями на приём
 				appointments := adminAuthorized.Group("/appointments")
 				{
-					appointments.GET("/", h.adminGetAllAppointments)
-					appointments.GET("/statistics", h.adminGetAppointmentStats)
-					appointments.GET("/:id", h.adminGetAppointmentDetails)
-					appointments.PATCH("/:id", h.adminUpdateAppointmentStatus)
-					appointments.DELETE("/:id", h.adminDeleteAppointment)
+					appointments.GET("/", h.requirePermission(models.PermAppointmentsRead), h.adminGetAllAppointments)
+					appointments.GET("/statistics", h.requirePermission(models.PermAppointmentsRead), h.adminGetAppointmentStats)
+					appointments.GET("/deleted", h.requirePermission(models.PermAppointmentsRead), h.adminGetDeletedAppointments)
+					appointments.GET("/:id", h.requirePermission(models.PermAppointmentsRead), h.adminGetAppointmentDetails)
+					appointments.PATCH("/:id", h.requirePermission(models.PermAppointmentsWrite), h.adminUpdateAppointmentStatus)
+					appointments.DELETE("/:id", h.requirePermission(models.PermAppointmentsDelete), h.adminDeleteAppointment)
+					appointments.POST("/:id/restore", h.requirePermission(models.PermAppointmentsDelete), h.adminRestoreAppointment)
 				}
 
-				// 4. Управление услугами и отделениями
+				// 4. Управление услугами, отделениями и клиниками
 				services := adminAuthorized.Group("/services")
 				{
-					services.GET("/", h.adminGetAllServices)
-					services.POST("/", h.adminCreateService)
-					services.PUT("/:id", h.adminUpdateService)
-					services.DELETE("/:id", h.adminDeleteService)
+					services.GET("/", h.requirePermission(models.PermServicesRead), h.adminGetAllServices)
+					services.POST("/", h.requirePermission(models.PermServicesWrite), h.adminCreateService)
+					services.GET("/deleted", h.requirePermission(models.PermServicesRead), h.adminGetDeletedServices)
+					services.GET("/:id", h.requirePermission(models.PermServicesRead), h.adminGetService)
+					services.GET("/:id/price-history", h.requirePermission(models.PermServicesRead), h.adminGetServicePriceHistory)
+					services.PUT("/:id", h.requirePermission(models.PermServicesWrite), h.adminUpdateService)
+					services.DELETE("/:id", h.requirePermission(models.PermServicesWrite), h.adminDeleteService)
+					services.POST("/:id/restore", h.requirePermission(models.PermServicesWrite), h.adminRestoreService)
+				}
+				serviceCategories := adminAuthorized.Group("/service-categories")
+				{
+					serviceCategories.GET("/", h.requirePermission(models.PermServicesRead), h.adminGetServiceCategories)
+					serviceCategories.POST("/", h.requirePermission(models.PermServicesWrite), h.adminCreateServiceCategory)
+					serviceCategories.PUT("/:id", h.requirePermission(models.PermServicesWrite), h.adminUpdateServiceCategory)
+					serviceCategories.DELETE("/:id", h.requirePermission(models.PermServicesWrite), h.adminDeleteServiceCategory)
 				}
 				departments := adminAuthorized.Group("/departments")
 				{
-					departments.GET("/", h.adminGetAllDepartments)
-					departments.POST("/", h.adminCreateDepartment)
-					departments.PUT("/:id", h.adminUpdateDepartment)
-					departments.DELETE("/:id", h.adminDeleteDepartment)
+					departments.GET("/", h.requirePermission(models.PermServicesRead), h.adminGetAllDepartments)
+					departments.POST("/", h.requirePermission(models.PermServicesWrite), h.adminCreateDepartment)
+					departments.GET("/deleted", h.requirePermission(models.PermServicesRead), h.adminGetDeletedDepartments)
+					departments.PUT("/:id", h.requirePermission(models.PermServicesWrite), h.adminUpdateDepartment)
+					departments.DELETE("/:id", h.requirePermission(models.PermServicesWrite), h.adminDeleteDepartment)
+					departments.POST("/:id/restore", h.requirePermission(models.PermServicesWrite), h.adminRestoreDepartment)
+				}
+				clinics := adminAuthorized.Group("/clinics")
+				{
+					clinics.GET("/", h.requirePermission(models.PermClinicsRead), h.adminGetAllClinics)
+					clinics.POST("/", h.requirePermission(models.PermClinicsWrite), h.adminCreateClinic)
+					clinics.GET("/deleted", h.requirePermission(models.PermClinicsRead), h.adminGetDeletedClinics)
+					clinics.GET("/:id", h.requirePermission(models.PermClinicsRead), h.adminGetClinic)
+					clinics.PUT("/:id", h.requirePermission(models.PermClinicsWrite), h.adminUpdateClinic)
+					clinics.DELETE("/:id", h.requirePermission(models.PermClinicsWrite), h.adminDeleteClinic)
+					clinics.POST("/:id/restore", h.requirePermission(models.PermClinicsWrite), h.adminRestoreClinic)
 				}
 
 				// 5. Управление анализами и назначениями
 				analyses := adminAuthorized.Group("/analyses")
 				{
-					analyses.GET("/", h.adminGetAllAnalyses)
-					analyses.POST("/", h.adminCreateAnalysisResult)
-					analyses.PATCH("/:id", h.adminUpdateAnalysis)
-					analyses.DELETE("/:id", h.adminDeleteAnalysis)
+					analyses.GET("/", h.requirePermission(models.PermAnalysesRead), h.adminGetAllAnalyses)
+					analyses.POST("/", h.requirePermission(models.PermAnalysesWrite), h.adminCreateAnalysisResult)
+					analyses.PATCH("/:id", h.requirePermission(models.PermAnalysesWrite), h.adminUpdateAnalysis)
+					analyses.DELETE("/:id", h.requirePermission(models.PermAnalysesWrite), h.adminDeleteAnalysis)
 				}
 				prescriptions := adminAuthorized.Group("/prescriptions")
 				{
-					prescriptions.GET("/", h.adminGetAllPrescriptions)
-					prescriptions.POST("/", h.adminCreatePrescription)
+					prescriptions.GET("/", h.requirePermission(models.PermPrescriptionsRead), h.adminGetAllPrescriptions)
+					prescriptions.POST("/", h.requirePermission(models.PermPrescriptionsWrite), h.adminCreatePrescription)
+				}
+
+				// 6. Модерация отзывов
+				reviews := adminAuthorized.Group("/reviews")
+				{
+					reviews.GET("/", h.requirePermission(models.PermReviewsRead), h.adminGetReviews)
+					reviews.POST("/:id/approve", h.requirePermission(models.PermReviewsModerate), h.adminApproveReview)
+					reviews.POST("/:id/reject", h.requirePermission(models.PermReviewsModerate), h.adminRejectReview)
 				}
 
 				// 7. Управление семьей
 				family := adminAuthorized.Group("/family-relations")
 				{
-					family.GET("/", h.adminGetFamilyRelations)
-					family.DELETE("/:id", h.adminDeleteFamilyRelation)
+					family.GET("/", h.requirePermission(models.PermFamilyRead), h.adminGetFamilyRelations)
+					family.DELETE("/:id", h.requirePermission(models.PermFamilyWrite), h.adminDeleteFamilyRelation)
 				}
 
 				// 8. Системные настройки и статистика
-				adminAuthorized.GET("/dashboard", h.getAdminDashboard)
-				adminAuthorized.GET("/audit-logs", h.adminGetAuditLogs)
+				adminAuthorized.GET("/dashboard", h.requirePermission(models.PermDashboardRead), h.getAdminDashboard)
+				adminAuthorized.GET("/audit-logs", h.requirePermission(models.PermAuditRead), h.adminGetAuditLogs)
+				adminAuthorized.GET("/audit-logs/export", h.requirePermission(models.PermAuditRead), h.adminExportAuditLogs)
+				security := adminAuthorized.Group("/security")
+				{
+					security.GET("/lockouts", h.requirePermission(models.PermSecurityRead), h.adminGetLockoutEvents)
+					security.POST("/unlock", h.requirePermission(models.PermSecurityWrite), h.adminUnlock)
+					security.GET("/impersonations", h.requirePermission(models.PermAuditRead), h.adminGetImpersonationLogs)
+					security.GET("/settings", h.requirePermission(models.PermSecurityRead), h.adminGetSecuritySettings)
+					security.PUT("/settings", h.requirePermission(models.PermSecuritySettingsWrite), h.adminUpdateSecuritySettings)
+				}
 				settings := adminAuthorized.Group("/clinic-settings")
 				{
-					settings.GET("/", h.adminGetClinicSettings)
-					settings.PUT("/", h.adminUpdateClinicSettings) // PUT для полного обновления
+					settings.GET("/", h.requirePermission(models.PermSettingsRead), h.adminGetClinicSettings)
+					settings.PUT("/", h.requirePermission(models.PermSettingsWrite), h.adminUpdateClinicSettings) // PUT для полного обновления
+					settings.GET("/history", h.requirePermission(models.PermSettingsRead), h.adminGetClinicSettingsHistory)
 				}
 
 				// 9. Управление документами
 				legal := adminAuthorized.Group("/legal-documents")
 				{
-					legal.GET("/", h.adminGetLegalDocs)
-					legal.POST("/", h.adminCreateLegalDoc)
-					legal.PUT("/:id", h.adminUpdateLegalDoc)
+					legal.GET("/", h.requirePermission(models.PermLegalRead), h.adminGetLegalDocs)
+					legal.GET("/types", h.requirePermission(models.PermLegalRead), h.adminGetLegalDocTypes)
+					legal.GET("/:id", h.requirePermission(models.PermLegalRead), h.adminGetLegalDoc)
+					legal.GET("/:id/file", h.requirePermission(models.PermLegalRead), h.adminDownloadLegalDoc)
+					legal.POST("/", h.requirePermission(models.PermLegalWrite), h.adminCreateLegalDoc)
+					legal.PUT("/:id", h.requirePermission(models.PermLegalWrite), h.adminUpdateLegalDoc)
+					legal.POST("/:id/publish", h.requirePermission(models.PermLegalWrite), h.adminPublishLegalDoc)
+					legal.DELETE("/:id", h.requirePermission(models.PermLegalWrite), h.adminDeleteLegalDoc)
+				}
+
+				// 10. Управление администраторами
+				admins := adminAuthorized.Group("/admins")
+				admins.Use(h.requirePermission(models.PermAdminsManage))
+				{
+					admins.GET("/", h.adminGetAdmins)
+					admins.POST("/", h.adminCreateAdmin)
+					admins.GET("/roles", h.adminGetRoles)
+					admins.GET("/:id", h.adminGetAdmin)
+					admins.PATCH("/:id", h.adminUpdateAdmin)
+					admins.PATCH("/:id/role", h.adminChangeRole)
+					admins.POST("/:id/deactivate", h.adminDeactivateAdmin)
+					admins.POST("/:id/activate", h.adminActivateAdmin)
+					admins.POST("/:id/reset-password", h.adminResetAdminPassword)
+					admins.POST("/:id/reset-2fa", h.adminResetAdminTwoFactor)
+					admins.GET("/:id/sessions", h.adminGetAdminSessions)
+					admins.POST("/:id/revoke-sessions", h.adminRevokeAdminSessions)
 				}
 
-				// 10. Резервное копирование
+				// 11. Резервное копирование
 				backup := adminAuthorized.Group("/backup")
 				{
-					backup.POST("/", h.adminCreateBackup)
-					backup.GET("/list", h.adminGetBackupList)
-					backup.POST("/restore", h.adminRestoreFromBackup)
+					backup.POST("/", h.requirePermission(models.PermBackupsCreate), h.adminCreateBackup)
+					backup.GET("/list", h.requirePermission(models.PermBackupsRead), h.adminGetBackupList)
+					backup.POST("/restore", h.requirePermission(models.PermBackupsRestore), h.adminRestoreFromBackup)
 				}
 			}
 		}
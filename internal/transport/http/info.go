@@ -1,9 +1,12 @@
 package http
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
 
 	_ "lk/internal/models"
+	"lk/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
@@ -11,7 +14,7 @@ import (
 // @Summary      Получить информацию о клинике
 // @Security     ApiKeyAuth
 // @Tags         info
-// @Description  Возвращает контактную информацию, адреса и часы работы клиники.
+// @Description  Возвращает название и контакты основной клиники, адреса всех клиник сети и часы работы основной.
 // @Id           get-clinic-info
 // @Produce      json
 // @Success      200 {object} models.ClinicInfo
@@ -26,14 +29,74 @@ func (h *Handler) getClinicInfo(c *gin.Context) {
 	c.JSON(http.StatusOK, info)
 }
 
-// @Summary      Получить юридические документы
+// @Summary      Получить список клиник
+// @Security     ApiKeyAuth
+// @Tags         info
+// @Description  Возвращает клиники сети с адресами, координатами, контактами и часами работы по дням недели
+// @Description  (1 - понедельник, 7 - воскресенье; отсутствующие дни - выходные). Основная клиника идет первой.
+// @Id           get-clinics
+// @Produce      json
+// @Success      200 {array} models.Clinic
+// @Failure      401,500 {object} errorResponse
+// @Router       /clinics [get]
+func (h *Handler) getClinics(c *gin.Context) {
+	clinics, err := h.services.Info.GetClinics(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, clinics)
+}
+
+// @Summary      Получить клинику по ID
 // @Security     ApiKeyAuth
 // @Tags         info
-// @Description  Возвращает список юридических документов с ссылками и версиями.
+// @Id           get-clinic
+// @Produce      json
+// @Param        id path int true "ID Клиники"
+// @Success      200 {object} models.Clinic
+// @Failure      400,401,404,500 {object} errorResponse
+// @Router       /clinics/{id} [get]
+func (h *Handler) getClinic(c *gin.Context) {
+	clinicID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("invalid clinic ID", err))
+		return
+	}
+	clinic, err := h.services.Info.GetClinic(c.Request.Context(), clinicID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, clinic)
+}
+
+// @Summary      Получить прейскурант
+// @Tags         info
+// @Description  Возвращает действующие услуги каталога по отделениям и категориям. Если врачи оказывают
+// @Description  услугу по разной цене, priceFrom и priceTo задают диапазон. Доступен без авторизации.
+// @Id           get-price-list
+// @Produce      json
+// @Success      200 {array} models.PriceListDepartment
+// @Failure      500 {object} errorResponse
+// @Router       /price-list [get]
+func (h *Handler) getPriceList(c *gin.Context) {
+	priceList, err := h.services.Info.GetPriceList(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, priceList)
+}
+
+// @Summary      Получить юридические документы
+// @Tags         info
+// @Description  Возвращает действующие версии юридических документов со ссылками.
+// @Description  Документы с isMandatory=true нужно принять при регистрации (поле acceptedDocuments).
 // @Id           get-legal-documents
 // @Produce      json
 // @Success      200 {array} models.LegalDocument
-// @Failure      401,500 {object} errorResponse
+// @Failure      500 {object} errorResponse
 // @Router       /legal/documents [get]
 func (h *Handler) getLegalDocuments(c *gin.Context) {
 	docs, err := h.services.Info.GetLegalDocuments(c.Request.Context())
@@ -43,3 +106,22 @@ func (h *Handler) getLegalDocuments(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, docs)
 }
+
+// @Summary      Скачать действующую версию документа
+// @Tags         info
+// @Description  Скачивает PDF действующей (последней опубликованной) версии документа указанного типа.
+// @Id           download-legal-document
+// @Produce      application/pdf
+// @Param        type path string true "Тип документа" example(privacy_policy)
+// @Success      200 {file} file
+// @Failure      404,500 {object} errorResponse
+// @Router       /legal/documents/{type}/file [get]
+func (h *Handler) downloadLegalDocument(c *gin.Context) {
+	data, fileName, err := h.services.Info.DownloadCurrentLegalDocument(c.Request.Context(), c.Param("type"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", fileName))
+	c.Data(http.StatusOK, "application/pdf", data)
+}
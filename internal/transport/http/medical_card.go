@@ -150,6 +150,48 @@ func (h *Handler) downloadFile(c *gin.Context) {
 	c.Data(http.StatusOK, "application/octet-stream", fileBytes)
 }
 
+// @Summary      Кто просматривал мою медкарту
+// @Security     ApiKeyAuth
+// @Tags         medical-card
+// @Description  Возвращает историю обращений к медицинским данным пациента: просмотры визитов, анализов,
+// @Description  назначений и скачивания файлов - самим пациентом, сотрудниками клиники и сотрудниками
+// @Description  в режиме просмотра кабинета от имени пациента. Новые записи сверху.
+// @Id           get-medical-card-access-log
+// @Produce      json
+// @Param        staffOnly query bool false "Только обращения сотрудников клиники"
+// @Param        page query int false "Номер страницы" default(1)
+// @Param        limit query int false "Количество на странице" default(10)
+// @Success      200 {object} map[string]interface{} "items: []models.MedicalDataAccess, total: int"
+// @Failure      400,401,500 {object} errorResponse
+// @Router       /medical-card/access-log [get]
+func (h *Handler) getMedicalCardAccessLog(c *gin.Context) {
+	userProfile, err := getUserProfile(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to get user from context", err))
+		return
+	}
+
+	staffOnly := false
+	if v := c.Query("staffOnly"); v != "" {
+		staffOnly, err = strconv.ParseBool(v)
+		if err != nil {
+			c.Error(services.NewBadRequestError("invalid staffOnly", err))
+			return
+		}
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	params := models.PaginationParams{Page: page, Limit: limit}
+
+	items, total, err := h.services.MedicalCard.GetAccessLog(c.Request.Context(), userProfile.UserID, staffOnly, params)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "total": total})
+}
+
 type archiveInput struct {
 	PrescriptionID uint64 `json:"prescriptionId" binding:"required"`
 }
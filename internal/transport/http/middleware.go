@@ -38,7 +38,7 @@ func (h *Handler) userIdentity(c *gin.Context) {
 		return
 	}
 
-	userID, err := h.services.Authorization.ParseToken(headerParts[1])
+	userID, impersonatorID, err := h.services.Authorization.ParseToken(headerParts[1])
 	if err != nil {
 		c.Error(err) // ParseToken возвращает типизированную ошибку
 		c.Abort()
@@ -55,6 +55,16 @@ func (h *Handler) userIdentity(c *gin.Context) {
 
 	// Записываем весь профиль в контекст Gin.
 	c.Set(userProfileCtx, userProfile)
+	c.Request = c.Request.WithContext(services.WithAuditActor(c.Request.Context(), services.AuditActor{
+		Type:           models.AuditActorUser,
+		ID:             userID,
+		ImpersonatorID: impersonatorID,
+	}))
+
+	// Токен выпущен администратору для просмотра кабинета от имени пациента
+	if impersonatorID != 0 {
+		h.impersonatedRequest(c, userID, impersonatorID)
+	}
 }
 
 // getUserProfile - вспомогательная функция для извлечения профиля пользователя из контекста.
@@ -10,7 +10,10 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-const adminCtx = "admin"
+const (
+	adminCtx        = "admin"
+	adminSessionCtx = "adminSessionId"
+)
 
 // adminIdentity - middleware, которое проверяет токен администратора
 // и сохраняет его модель в контекст запроса.
@@ -29,7 +32,7 @@ func (h *Handler) adminIdentity(c *gin.Context) {
 		return
 	}
 
-	adminID, err := h.services.Admin.ParseAdminToken(headerParts[1])
+	adminID, sessionID, err := h.services.Admin.ParseAdminToken(c.Request.Context(), headerParts[1])
 	if err != nil {
 		c.Error(err)
 		c.Abort()
@@ -42,8 +45,79 @@ func (h *Handler) adminIdentity(c *gin.Context) {
 		c.Abort()
 		return
 	}
+	if !admin.IsActive {
+		c.Error(services.NewUnauthorizedError("admin account is disabled", nil))
+		c.Abort()
+		return
+	}
 
 	c.Set(adminCtx, admin)
+	c.Set(adminSessionCtx, sessionID)
+	c.Request = c.Request.WithContext(services.WithAuditActor(c.Request.Context(), services.AuditActor{
+		Type: models.AuditActorAdmin,
+		ID:   admin.ID,
+		Name: admin.Login,
+	}))
+}
+
+// adminTwoFactorEnforced - middleware, которое не пускает администратора без 2FA,
+// если политика безопасности требует ее для всех. Должно идти после adminIdentity.
+func (h *Handler) adminTwoFactorEnforced(c *gin.Context) {
+	admin, err := getAdmin(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to identify admin from context", err))
+		c.Abort()
+		return
+	}
+	if admin.TOTPEnabledAt.Valid {
+		return
+	}
+
+	required, err := h.services.Admin.IsTwoFactorRequired(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		c.Abort()
+		return
+	}
+	if required {
+		c.Error(services.NewForbiddenError("two-factor authentication setup is required", nil))
+		c.Abort()
+		return
+	}
+}
+
+// adminPasswordChangeEnforced - middleware, которое не пускает администратора,
+// обязанного сменить пароль (первый вход или сброс). Должно идти после adminIdentity.
+func (h *Handler) adminPasswordChangeEnforced(c *gin.Context) {
+	admin, err := getAdmin(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to identify admin from context", err))
+		c.Abort()
+		return
+	}
+	if admin.MustChangePassword {
+		c.Error(services.NewForbiddenError("password change is required", nil))
+		c.Abort()
+		return
+	}
+}
+
+// requirePermission возвращает middleware, которое пропускает только администраторов,
+// чья роль обладает указанным правом. Должно идти после adminIdentity.
+func (h *Handler) requirePermission(permission models.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		admin, err := getAdmin(c)
+		if err != nil {
+			c.Error(services.NewInternalServerError("failed to identify admin from context", err))
+			c.Abort()
+			return
+		}
+		if !admin.Role.HasPermission(permission) {
+			c.Error(services.NewForbiddenError("insufficient permissions: "+string(permission)+" is required", nil))
+			c.Abort()
+			return
+		}
+	}
 }
 
 // getAdmin - вспомогательная функция для извлечения модели администратора из контекста.
@@ -58,3 +132,8 @@ func getAdmin(c *gin.Context) (models.Admin, error) {
 	}
 	return admin, nil
 }
+
+// getAdminSessionID возвращает ID сессии, к которой привязан токен текущего администратора.
+func getAdminSessionID(c *gin.Context) uint64 {
+	return c.GetUint64(adminSessionCtx)
+}
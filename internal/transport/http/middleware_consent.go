@@ -0,0 +1,46 @@
+package http
+
+import (
+	"strings"
+
+	"lk/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// consentExemptRoutes - разделы кабинета, доступные до принятия обновленных документов:
+// сами согласия, профиль и аккаунт (не согласный с новой редакцией пациент может выгрузить
+// свои данные и удалить аккаунт).
+var consentExemptRoutes = []string{"/api/v1/consents", "/api/v1/profile", "/api/v1/account"}
+
+// requireConsents - middleware, которое не пускает в кабинет пациента, пока он не принял
+// действующие версии всех обязательных документов (например, после публикации новой редакции).
+// Должно идти после userIdentity. Запросы администратора от имени пациента не блокируются.
+func (h *Handler) requireConsents(c *gin.Context) {
+	if _, impersonated := c.Get(impersonatorCtx); impersonated {
+		return
+	}
+	route := c.FullPath()
+	for _, prefix := range consentExemptRoutes {
+		if strings.HasPrefix(route, prefix) {
+			return
+		}
+	}
+
+	userProfile, err := getUserProfile(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to get user from context", err))
+		c.Abort()
+		return
+	}
+	pending, err := h.services.Consent.HasPendingMandatory(c.Request.Context(), userProfile.UserID)
+	if err != nil {
+		c.Error(err)
+		c.Abort()
+		return
+	}
+	if pending {
+		c.Error(services.NewForbiddenError("updated legal documents must be accepted", nil))
+		c.Abort()
+	}
+}
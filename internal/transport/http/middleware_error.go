@@ -29,3 +29,16 @@ func ErrorMiddleware() gin.HandlerFunc {
 		}
 	}
 }
+
+// responseStatus возвращает итоговый HTTP-статус запроса, в том числе когда ошибка
+// уже добавлена в контекст, но ErrorMiddleware еще не записало ее в ответ.
+func responseStatus(c *gin.Context) int {
+	if len(c.Errors) == 0 {
+		return c.Writer.Status()
+	}
+	var appErr *services.AppError
+	if errors.As(c.Errors.Last().Err, &appErr) {
+		return appErr.StatusCode
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"net/http"
+
+	"lk/internal/logger"
+	"lk/internal/models"
+	"lk/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// impersonatorCtx - ключ, по которому в контексте хранится ID администратора,
+	// действующего от имени пациента.
+	impersonatorCtx = "impersonatorId"
+	// maxImpersonationPathLength - ограничение длины пути в журнале имперсонации.
+	maxImpersonationPathLength = 2048
+)
+
+// impersonatedRequest обслуживает запрос с токеном имперсонации: проверяет, что администратор
+// по-прежнему вправе им пользоваться, пропускает только запросы на чтение и записывает
+// каждый запрос (в том числе отклоненный) в журнал. Вызывается из userIdentity.
+func (h *Handler) impersonatedRequest(c *gin.Context, userID, adminID uint64) {
+	admin, err := h.adminRepo.GetByID(c.Request.Context(), adminID)
+	if err != nil || !admin.IsActive || !admin.Role.HasPermission(models.PermUsersImpersonate) {
+		c.Error(services.NewUnauthorizedError("impersonation is no longer allowed for this admin", err))
+		c.Abort()
+		return
+	}
+	c.Set(impersonatorCtx, adminID)
+
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		c.Next()
+	default:
+		c.Error(services.NewForbiddenError("impersonation session is read-only", nil))
+		c.Abort()
+	}
+
+	path := c.Request.URL.RequestURI()
+	if len(path) > maxImpersonationPathLength {
+		path = path[:maxImpersonationPathLength]
+	}
+	entry := models.ImpersonationLog{
+		AdminID:    adminID,
+		UserID:     userID,
+		Method:     c.Request.Method,
+		Route:      c.FullPath(),
+		Path:       path,
+		StatusCode: responseStatus(c),
+		IPAddress:  c.ClientIP(),
+	}
+	if err := h.services.Admin.RecordImpersonatedRequest(c.Request.Context(), entry); err != nil {
+		logger.Default().WithError(err).Error("не удалось записать запрос в журнал имперсонации")
+	}
+}
@@ -0,0 +1,50 @@
+package http
+
+import (
+	"lk/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	requestIDHeader = "X-Request-ID"
+	// maxRequestIDLength ограничивает длину ID, пришедшего от клиента или прокси.
+	maxRequestIDLength = 64
+)
+
+// RequestContext - middleware, которое присваивает запросу ID (берет из X-Request-ID
+// или генерирует новый) и кладет сведения о запросе в context.Context для журнала аудита.
+func RequestContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := sanitizeRequestID(c.GetHeader(requestIDHeader))
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+		c.Header(requestIDHeader, requestID)
+
+		ctx := services.WithRequestMeta(c.Request.Context(), services.RequestMeta{
+			RequestID: requestID,
+			IPAddress: c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		})
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// sanitizeRequestID оставляет в ID только безопасные символы; пустая строка означает,
+// что ID нужно сгенерировать.
+func sanitizeRequestID(value string) string {
+	if len(value) > maxRequestIDLength {
+		return ""
+	}
+	for _, r := range value {
+		isAllowed := r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+			r == '-' || r == '_' || r == '.'
+		if !isAllowed {
+			return ""
+		}
+	}
+	return value
+}
@@ -0,0 +1,112 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"lk/internal/models"
+	"lk/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type createReviewInput struct {
+	AppointmentID uint64 `json:"appointmentId" binding:"required"`
+	Rating        uint16 `json:"rating" binding:"required,min=1,max=5"`
+	Comment       string `json:"comment" binding:"max=2000"`
+}
+
+// @Summary      Оставить отзыв о враче
+// @Security     ApiKeyAuth
+// @Tags         reviews
+// @Description  Создает отзыв о завершенном приеме. На один прием можно оставить один отзыв.
+// @Description  Отзыв публикуется и учитывается в рейтинге врача после модерации.
+// @Id           create-review
+// @Accept       json
+// @Produce      json
+// @Param        input body createReviewInput true "Прием, оценка от 1 до 5 и текст отзыва"
+// @Success      201 {object} models.Review
+// @Failure      400,401,404,409,500 {object} errorResponse
+// @Router       /reviews [post]
+func (h *Handler) createReview(c *gin.Context) {
+	userProfile, err := getUserProfile(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to get user from context", err))
+		return
+	}
+
+	var input createReviewInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(services.NewBadRequestError("invalid input body", err))
+		return
+	}
+
+	review, err := h.services.Review.CreateReview(c.Request.Context(), userProfile.UserID,
+		input.AppointmentID, input.Rating, input.Comment)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, review)
+}
+
+// @Summary      Получить свои отзывы
+// @Security     ApiKeyAuth
+// @Tags         reviews
+// @Description  Возвращает отзывы текущего пользователя со статусом модерации и причиной отклонения.
+// @Id           get-user-reviews
+// @Produce      json
+// @Param        page query int false "Номер страницы" default(1)
+// @Param        limit query int false "Количество на странице" default(10)
+// @Success      200 {object} map[string]interface{} "items, total"
+// @Failure      401,500 {object} errorResponse
+// @Router       /reviews [get]
+func (h *Handler) getUserReviews(c *gin.Context) {
+	userProfile, err := getUserProfile(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to get user from context", err))
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	params := models.PaginationParams{Page: page, Limit: limit}
+
+	reviews, total, err := h.services.Review.GetUserReviews(c.Request.Context(), userProfile.UserID, params)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": reviews, "total": total})
+}
+
+// @Summary      Получить отзывы о специалисте
+// @Security     ApiKeyAuth
+// @Tags         specialists
+// @Description  Возвращает одобренные модератором отзывы о специалисте, новые сверху.
+// @Id           get-specialist-reviews
+// @Produce      json
+// @Param        id path int true "ID Специалиста"
+// @Param        page query int false "Номер страницы" default(1)
+// @Param        limit query int false "Количество на странице" default(10)
+// @Success      200 {object} map[string]interface{} "items ([]models.DoctorReview), total"
+// @Failure      400,401,404,500 {object} errorResponse
+// @Router       /specialists/{id}/reviews [get]
+func (h *Handler) getSpecialistReviews(c *gin.Context) {
+	doctorID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(services.NewBadRequestError("Invalid specialist ID format", err))
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	params := models.PaginationParams{Page: page, Limit: limit}
+
+	reviews, total, err := h.services.Review.GetDoctorReviews(c.Request.Context(), doctorID, params)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": reviews, "total": total})
+}
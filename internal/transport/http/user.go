@@ -48,12 +48,13 @@ type updateUserProfileInput struct {
 // @Security     ApiKeyAuth
 // @Tags         profile
 // @Description  Обновляет изменяемые поля профиля текущего пользователя (например, email, cityID).
+// @Description  При смене email подтверждение сбрасывается и на новый адрес отправляется ссылка.
 // @Id           update-profile
 // @Accept       json
 // @Produce      json
 // @Param        input body updateUserProfileInput true "Обновляемые поля профиля"
 // @Success      200 {object} models.UserProfile
-// @Failure      400,401,500 {object} errorResponse
+// @Failure      400,401,409,500 {object} errorResponse
 // @Router       /profile [patch]
 func (h *Handler) updateProfile(c *gin.Context) {
 	userProfile, err := getUserProfile(c)
@@ -90,12 +91,13 @@ func (h *Handler) updateProfile(c *gin.Context) {
 // @Summary      Обновить аватар пользователя
 // @Security     ApiKeyAuth
 // @Tags         profile
-// @Description  Загружает новый файл аватара для текущего пользователя.
+// @Description  Загружает новый аватар текущего пользователя (JPEG, PNG или GIF до 5 МБ).
+// @Description  Изображение обрезается до квадрата, сохраняется в нескольких размерах без EXIF, предыдущий аватар удаляется.
 // @Id           update-avatar
 // @Accept       multipart/form-data
 // @Produce      json
 // @Param        avatar formData file true "Файл изображения для аватара"
-// @Success      200 {object} map[string]interface{} "message, avatarURL"
+// @Success      200 {object} map[string]interface{} "message, avatarURL, thumbnails"
 // @Failure      400,401,500 {object} errorResponse
 // @Router       /profile/avatar [post]
 func (h *Handler) updateAvatar(c *gin.Context) {
@@ -111,14 +113,39 @@ func (h *Handler) updateAvatar(c *gin.Context) {
 		return
 	}
 
-	avatarKey, err := h.services.User.UpdateAvatar(c.Request.Context(), userProfile.UserID, file)
+	avatar, err := h.services.User.UpdateAvatar(c.Request.Context(), userProfile.UserID, file)
 	if err != nil {
 		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message":   "avatar updated successfully",
-		"avatarURL": avatarKey, // Возвращаем ключ объекта, а не полный URL
+		"message":    "avatar updated successfully",
+		"avatarURL":  avatar.URL, // Возвращаем ключ объекта, а не полный URL
+		"thumbnails": avatar.Thumbnails,
 	})
 }
+
+// @Summary      Запросить подтверждение email
+// @Security     ApiKeyAuth
+// @Tags         profile
+// @Description  Повторно отправляет письмо со ссылкой для подтверждения текущего адреса электронной почты.
+// @Id           request-email-verification
+// @Produce      json
+// @Success      200 {object} statusResponse
+// @Failure      400,401,409,500 {object} errorResponse
+// @Router       /profile/email/verify [post]
+func (h *Handler) requestEmailVerification(c *gin.Context) {
+	userProfile, err := getUserProfile(c)
+	if err != nil {
+		c.Error(services.NewInternalServerError("failed to get user from context", err))
+		return
+	}
+
+	if err := h.services.User.RequestEmailVerification(c.Request.Context(), userProfile.UserID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{Status: "verification email has been sent"})
+}
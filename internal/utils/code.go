@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+// GenerateNumericCode создает криптографически стойкий цифровой код заданной длины.
+// Используется для кодов подтверждения (сброс пароля, верификация телефона).
+func GenerateNumericCode(length int) (string, error) {
+	maxValue := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(length)), nil)
+	n, err := rand.Int(rand.Reader, maxValue)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate code: %w", err)
+	}
+	return fmt.Sprintf("%0*d", length, n), nil
+}
+
+// recoveryCodeAlphabet не содержит похожих символов (0/O, 1/I/L).
+const recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+// GenerateRecoveryCode создает резервный код вида "xxxxx-xxxxx".
+func GenerateRecoveryCode() (string, error) {
+	const half = 5
+	buf := make([]byte, 0, half*2+1)
+	alphabetLen := big.NewInt(int64(len(recoveryCodeAlphabet)))
+	for i := 0; i < half*2; i++ {
+		if i == half {
+			buf = append(buf, '-')
+		}
+		n, err := rand.Int(rand.Reader, alphabetLen)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		buf = append(buf, recoveryCodeAlphabet[n.Int64()])
+	}
+	return string(buf), nil
+}
+
+// temporaryPasswordAlphabet - символы временного пароля (без похожих 0/O, 1/I/l).
+const temporaryPasswordAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZabcdefghjkmnpqrstuvwxyz23456789"
+
+// GenerateTemporaryPassword создает случайный временный пароль заданной длины.
+func GenerateTemporaryPassword(length int) (string, error) {
+	buf := make([]byte, length)
+	alphabetLen := big.NewInt(int64(len(temporaryPasswordAlphabet)))
+	for i := range buf {
+		n, err := rand.Int(rand.Reader, alphabetLen)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate password: %w", err)
+		}
+		buf[i] = temporaryPasswordAlphabet[n.Int64()]
+	}
+	return string(buf), nil
+}
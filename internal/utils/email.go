@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+)
+
+// ErrInvalidEmail возвращается, если строка не является корректным адресом электронной почты.
+var ErrInvalidEmail = errors.New("invalid email address")
+
+// NormalizeEmail проверяет адрес электронной почты и приводит его к нижнему регистру.
+// Допускается только "голый" адрес без отображаемого имени ("user@example.com").
+func NormalizeEmail(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	addr, err := mail.ParseAddress(raw)
+	if err != nil || addr.Name != "" || addr.Address != raw {
+		return "", ErrInvalidEmail
+	}
+	return strings.ToLower(addr.Address), nil
+}
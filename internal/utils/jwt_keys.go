@@ -0,0 +1,238 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRSAKeyBits - минимальный допустимый размер RSA-ключа подписи.
+const minRSAKeyBits = 2048
+
+// verificationKey - открытый ключ, которым проверяются токены с соответствующим kid.
+type verificationKey struct {
+	kid    string
+	method jwt.SigningMethod
+	public crypto.PublicKey
+}
+
+// KeySet хранит активный ключ подписи и все открытые ключи, которыми принимаются токены.
+// Предыдущие ключи остаются в наборе на время ротации, пока не истекут выданные ими токены.
+type KeySet struct {
+	signingKID string
+	signing    crypto.Signer
+	keys       map[string]verificationKey
+	order      []string
+}
+
+// JWK - открытый ключ в формате JSON Web Key (RFC 7517).
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS - набор открытых ключей для публикации на /.well-known/jwks.json.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// LoadKeySet читает приватный ключ подписи (RSA или Ed25519, PEM) и дополнительные
+// открытые ключи предыдущих поколений, которые еще нужно принимать при проверке.
+func LoadKeySet(privateKeyFile string, publicKeyFiles []string) (*KeySet, error) {
+	data, err := os.ReadFile(privateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("read jwt private key: %w", err)
+	}
+	signer, err := parsePrivateKeyPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse jwt private key %s: %w", privateKeyFile, err)
+	}
+
+	ks, err := newKeySet(signer)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range publicKeyFiles {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read jwt public key: %w", err)
+		}
+		public, err := parsePublicKeyPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("parse jwt public key %s: %w", path, err)
+		}
+		if err := ks.addVerificationKey(public); err != nil {
+			return nil, fmt.Errorf("jwt public key %s: %w", path, err)
+		}
+	}
+
+	return ks, nil
+}
+
+// GenerateKeySet создает набор с новым эфемерным ключом Ed25519.
+// Используется только в локальном окружении: после перезапуска все токены становятся недействительными.
+func GenerateKeySet() (*KeySet, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generate jwt key: %w", err)
+	}
+	return newKeySet(private)
+}
+
+func newKeySet(signer crypto.Signer) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string]verificationKey)}
+	if err := ks.addVerificationKey(signer.Public()); err != nil {
+		return nil, err
+	}
+	ks.signing = signer
+	ks.signingKID = ks.order[0]
+	return ks, nil
+}
+
+// addVerificationKey добавляет открытый ключ; kid вычисляется как отпечаток ключа (RFC 7638).
+func (ks *KeySet) addVerificationKey(public crypto.PublicKey) error {
+	var method jwt.SigningMethod
+	switch key := public.(type) {
+	case *rsa.PublicKey:
+		if key.N.BitLen() < minRSAKeyBits {
+			return fmt.Errorf("rsa key must be at least %d bits", minRSAKeyBits)
+		}
+		method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		method = jwt.SigningMethodEdDSA
+	default:
+		return fmt.Errorf("unsupported key type %T: only RSA and Ed25519 are allowed", public)
+	}
+
+	kid := keyThumbprint(public)
+	if _, exists := ks.keys[kid]; exists {
+		return nil
+	}
+	ks.keys[kid] = verificationKey{kid: kid, method: method, public: public}
+	ks.order = append(ks.order, kid)
+	return nil
+}
+
+// Sign подписывает claims активным ключом и проставляет kid в заголовок.
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	key := ks.keys[ks.signingKID]
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = ks.signingKID
+	return token.SignedString(ks.signing)
+}
+
+// keyFunc подбирает открытый ключ по kid из заголовка и сверяет алгоритм.
+func (ks *KeySet) keyFunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.public, nil
+}
+
+// JWKS возвращает открытые части всех ключей набора; активный ключ идет первым.
+func (ks *KeySet) JWKS() JWKS {
+	set := JWKS{Keys: make([]JWK, 0, len(ks.order))}
+	for _, kid := range ks.order {
+		key := ks.keys[kid]
+		jwk := JWK{Kid: kid, Use: "sig", Alg: key.method.Alg()}
+		switch public := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+// keyThumbprint вычисляет отпечаток открытого ключа по RFC 7638.
+func keyThumbprint(public crypto.PublicKey) string {
+	var canonical string
+	switch key := public.(type) {
+	case *rsa.PublicKey:
+		canonical = fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`,
+			base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			base64.RawURLEncoding.EncodeToString(key.N.Bytes()))
+	case ed25519.PublicKey:
+		canonical = fmt.Sprintf(`{"crv":"Ed25519","kty":"OKP","x":"%s"}`,
+			base64.RawURLEncoding.EncodeToString(key))
+	}
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func parsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		switch key := key.(type) {
+		case *rsa.PrivateKey:
+			return key, nil
+		case ed25519.PrivateKey:
+			return key, nil
+		default:
+			return nil, fmt.Errorf("unsupported private key type %T", key)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+}
+
+func parsePublicKeyPEM(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+}
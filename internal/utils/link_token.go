@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Назначения подписанных ссылок, отправляемых по электронной почте.
+const (
+	LinkPurposeVerifyEmail   = "verify_email"
+	LinkPurposeResetPassword = "reset_password"
+)
+
+// LinkClaims - данные, зашитые в подписанную ссылку.
+type LinkClaims struct {
+	Email   string `json:"email"`
+	Purpose string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
+// UserID возвращает ID пользователя из claim "sub".
+func (c *LinkClaims) UserID() (uint64, error) {
+	return strconv.ParseUint(c.Subject, 10, 64)
+}
+
+// GenerateLinkToken создает подписанный токен для ссылки из письма.
+// Ключ подписи выводится из secretKey и назначения ссылки, поэтому такой токен
+// нельзя использовать ни как access-токен, ни как ссылку другого назначения.
+func GenerateLinkToken(userID uint64, email, purpose, secretKey string, ttl time.Duration) (*LinkClaims, string, error) {
+	now := time.Now()
+	claims := &LinkClaims{
+		Email:   email,
+		Purpose: purpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   strconv.FormatUint(userID, 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(linkSigningKey(secretKey, purpose))
+	if err != nil {
+		return nil, "", err
+	}
+	return claims, token, nil
+}
+
+// ParseLinkToken проверяет подпись, срок действия и назначение токена из ссылки.
+func ParseLinkToken(tokenString, purpose, secretKey string) (*LinkClaims, error) {
+	claims := &LinkClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims,
+		func(token *jwt.Token) (any, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+			}
+			return linkSigningKey(secretKey, purpose), nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid || claims.Purpose != purpose {
+		return nil, ErrInvalidToken
+	}
+	if _, err := claims.UserID(); err != nil {
+		return nil, errors.Join(ErrInvalidToken, err)
+	}
+	return claims, nil
+}
+
+// linkSigningKey выводит отдельный ключ подписи для каждого назначения ссылки.
+func linkSigningKey(secretKey, purpose string) []byte {
+	mac := hmac.New(sha256.New, []byte(secretKey))
+	mac.Write([]byte("link:" + purpose))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalidPhone возвращается, если номер телефона невозможно привести к формату E.164.
+var ErrInvalidPhone = errors.New("invalid phone number")
+
+// NormalizePhone приводит номер телефона к формату E.164 (например, "+79991234567").
+// Поддерживаются российские варианты записи: "8 (999) 123-45-67", "+7 999 123 45 67",
+// "79991234567", "9991234567", а также любые номера в международном формате с "+".
+func NormalizePhone(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	hasPlus := strings.HasPrefix(raw, "+")
+
+	var digits strings.Builder
+	for _, r := range raw {
+		switch {
+		case '0' <= r && r <= '9': // Только ASCII-цифры, как в SQL-функции normalize_phone
+			digits.WriteRune(r)
+		case r == '+' || r == ' ' || r == '-' || r == '(' || r == ')':
+			// Допустимые символы форматирования
+		default:
+			return "", ErrInvalidPhone
+		}
+	}
+	d := digits.String()
+
+	switch {
+	case len(d) == 11 && (d[0] == '7' || d[0] == '8') && (!hasPlus || d[0] == '7'):
+		return "+7" + d[1:], nil
+	case len(d) == 10 && !hasPlus && d[0] == '9':
+		return "+7" + d, nil
+	case hasPlus && len(d) >= 8 && len(d) <= 15 && d[0] != '0':
+		return "+" + d, nil
+	}
+	return "", ErrInvalidPhone
+}
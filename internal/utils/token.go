@@ -2,7 +2,7 @@ package utils
 
 import (
 	"errors"
-	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -10,44 +10,76 @@ import (
 
 var ErrInvalidToken = errors.New("invalid token")
 
-// GenerateToken создает новый JWT для указанного ID пользователя.
-func GenerateToken(userID uint64, secretKey string, ttl time.Duration) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"exp": time.Now().Add(ttl).Unix(),
-		"iat": time.Now().Unix(),
-		"sub": userID,
-	})
+// TokenScope описывает класс access токенов (пациентские или административные):
+// у каждого свои издатель и аудитория, поэтому токен одного класса не принимается другим.
+type TokenScope struct {
+	Issuer   string
+	Audience string
+	TTL      time.Duration
+}
 
-	return token.SignedString([]byte(secretKey))
+// TokenActor - claim act (RFC 8693): кто фактически действует от имени субъекта токена.
+type TokenActor struct {
+	Subject string `json:"sub"`
 }
 
-// ParseToken проверяет JWT и возвращает ID пользователя, который в нем содержится.
-func ParseToken(accessToken string, secretKey string) (uint64, error) {
-	token, err := jwt.ParseWithClaims(accessToken, &jwt.MapClaims{},
-		func(token *jwt.Token) (any, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return []byte(secretKey), nil
-		})
-	if err != nil {
-		return 0, err
-	}
+// AccessClaims - полезная нагрузка access токена.
+type AccessClaims struct {
+	Role      string      `json:"role,omitempty"`
+	SessionID uint64      `json:"sid,omitempty"`
+	Actor     *TokenActor `json:"act,omitempty"`
+	jwt.RegisteredClaims
+}
 
-	claims, ok := token.Claims.(*jwt.MapClaims)
-	if !ok || !token.Valid {
+// SubjectID возвращает числовой ID владельца токена из claim sub.
+func (c *AccessClaims) SubjectID() (uint64, error) {
+	id, err := strconv.ParseUint(c.Subject, 10, 64)
+	if err != nil || id == 0 {
 		return 0, ErrInvalidToken
 	}
+	return id, nil
+}
 
-	subFloat, ok := (*claims)["sub"].(float64)
-	if !ok {
+// ActorID возвращает числовой ID действующего лица из claim act или 0, если его нет.
+func (c *AccessClaims) ActorID() (uint64, error) {
+	if c.Actor == nil {
+		return 0, nil
+	}
+	id, err := strconv.ParseUint(c.Actor.Subject, 10, 64)
+	if err != nil || id == 0 {
 		return 0, ErrInvalidToken
 	}
+	return id, nil
+}
 
-	userID := uint64(subFloat)
-	if userID == 0 {
-		return 0, ErrInvalidToken
+// GenerateToken создает новый JWT для указанного субъекта, подписанный активным ключом набора.
+// Дополнительные claims (роль, сессия) берутся из claims; стандартные заполняются по scope.
+func GenerateToken(keys *KeySet, scope TokenScope, subjectID uint64, claims AccessClaims) (string, error) {
+	now := time.Now()
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		Issuer:    scope.Issuer,
+		Subject:   strconv.FormatUint(subjectID, 10),
+		Audience:  jwt.ClaimStrings{scope.Audience},
+		ExpiresAt: jwt.NewNumericDate(now.Add(scope.TTL)),
+		IssuedAt:  jwt.NewNumericDate(now),
 	}
+	return keys.Sign(claims)
+}
 
-	return userID, nil
+// ParseToken проверяет подпись, срок действия, издателя и аудиторию JWT и возвращает его claims.
+func ParseToken(keys *KeySet, scope TokenScope, accessToken string) (*AccessClaims, error) {
+	claims := &AccessClaims{}
+	token, err := jwt.ParseWithClaims(accessToken, claims, keys.keyFunc,
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithIssuer(scope.Issuer),
+		jwt.WithAudience(scope.Audience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
 }
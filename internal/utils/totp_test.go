@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// rfc6238Secret - ключ SHA1 из тестовых векторов RFC 6238 ("12345678901234567890") в base32.
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// Тестовые векторы RFC 6238, приложение B (SHA1). В RFC коды 8-значные,
+// при 6 цифрах это их последние 6 цифр.
+var rfc6238Vectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},
+	{1111111109, "081804"},
+	{1111111111, "050471"},
+	{1234567890, "005924"},
+	{2000000000, "279037"},
+	{20000000000, "353130"},
+}
+
+func TestTOTPCodeRFC6238(t *testing.T) {
+	key := []byte("12345678901234567890")
+	for _, v := range rfc6238Vectors {
+		if got := totpCode(key, v.unix/30); got != v.code {
+			t.Errorf("totpCode at %d = %s, want %s", v.unix, got, v.code)
+		}
+	}
+}
+
+func TestValidateTOTPRFC6238(t *testing.T) {
+	for _, v := range rfc6238Vectors {
+		step, ok := ValidateTOTP(rfc6238Secret, v.code, time.Unix(v.unix, 0))
+		if !ok {
+			t.Errorf("ValidateTOTP at %d rejected valid code %s", v.unix, v.code)
+			continue
+		}
+		if step != v.unix/30 {
+			t.Errorf("ValidateTOTP at %d returned step %d, want %d", v.unix, step, v.unix/30)
+		}
+	}
+}
+
+func TestValidateTOTPSkew(t *testing.T) {
+	const codeTime = 1111111109 // шаг 37037036, код 081804
+	code := "081804"
+
+	for _, shift := range []int64{-30, 0, 30} {
+		if _, ok := ValidateTOTP(rfc6238Secret, code, time.Unix(codeTime+shift, 0)); !ok {
+			t.Errorf("code rejected with clock shift %ds", shift)
+		}
+	}
+	for _, shift := range []int64{-60, 60} {
+		if _, ok := ValidateTOTP(rfc6238Secret, code, time.Unix(codeTime+shift, 0)); ok {
+			t.Errorf("code accepted with clock shift %ds outside the allowed skew", shift)
+		}
+	}
+}
+
+func TestValidateTOTPRejectsMalformedInput(t *testing.T) {
+	now := time.Unix(59, 0)
+	cases := []struct {
+		name, secret, code string
+	}{
+		{"short code", rfc6238Secret, "28708"},
+		{"long code", rfc6238Secret, "94287082"},
+		{"wrong code", rfc6238Secret, "287083"},
+		{"invalid secret", "not base32!", "287082"},
+	}
+	for _, tc := range cases {
+		if _, ok := ValidateTOTP(tc.secret, tc.code, now); ok {
+			t.Errorf("%s: ValidateTOTP accepted %q", tc.name, tc.code)
+		}
+	}
+}
@@ -7,7 +7,10 @@ TRUNCATE TABLE
     medical_center.analysisstatuses,
     medical_center.clinics,
     medical_center.doctors,
+    medical_center.service_categories,
     medical_center.services,
+    medical_center.doctor_services,
+    medical_center.service_price_history,
     medical_center.users,
     medical_center.user_profiles,
     medical_center.schedules,
@@ -6,8 +6,14 @@ INSERT INTO medical_center.appointmentstatuses (id, name) VALUES (1, 'Запла
 INSERT INTO medical_center.analysisstatuses (id, name) VALUES (1, 'Назначено'), (2, 'В работе'), (3, 'Готов') ON CONFLICT (id) DO NOTHING;
 
 -- Создаем клинику
-INSERT INTO medical_center.clinics (id, name, address, work_hours, phone) VALUES
-(1, 'Клиника "Здоровье"', 'г. Санкт-Петербург, Невский пр., д. 1', 'Пн-Пт 08:00-20:00', '+78121234567') ON CONFLICT (id) DO NOTHING;
+INSERT INTO medical_center.clinics (id, name, address, phone, email, city_id, latitude, longitude, is_main) VALUES
+(1, 'Клиника "Здоровье"', 'г. Санкт-Петербург, Невский пр., д. 1', '+78121234567', 'info@clinic.ru', 1, 59.936046, 30.315104, true) ON CONFLICT (id) DO NOTHING;
+
+-- Часы работы клиники: пн-пт 08:00-20:00, сб 09:00-18:00
+INSERT INTO medical_center.clinic_working_hours (clinic_id, weekday, opens_at, closes_at) VALUES
+(1, 1, '08:00', '20:00'), (1, 2, '08:00', '20:00'), (1, 3, '08:00', '20:00'), (1, 4, '08:00', '20:00'),
+(1, 5, '08:00', '20:00'), (1, 6, '09:00', '18:00')
+ON CONFLICT DO NOTHING;
 
 -- Создаем тестовых докторов
 INSERT INTO medical_center.doctors (id, first_name, last_name, patronymic, specialty_id, experience_years, rating, review_count, avatar_url, recommendations) VALUES
@@ -15,17 +21,30 @@ INSERT INTO medical_center.doctors (id, first_name, last_name, patronymic, speci
 (2, 'Мария', 'Сергеева', 'Павловна', 1, 8, 4.9, 62, '/avatars/sergeeva.jpg', 'Рекомендуется не есть за 2 часа до приема. Пить воду можно.')
 ON CONFLICT (id) DO NOTHING;
 
--- Создаем услуги для докторов
-INSERT INTO medical_center.services (id, name, price, duration_minutes, description, doctor_id, recommendations) VALUES
-(1, 'Консультация кардиолога', 2500.00, 30, 'Первичная консультация ведущего кардиолога.', 1, 'При себе иметь кардиограмму (ЭКГ), сделанную не позднее месяца назад.'),
-(2, 'Первичный прием терапевта', 1800.00, 20, 'Осмотр, сбор анамнеза, назначение лечения.', 2, 'Вспомните все препараты, которые вы принимаете на постоянной основе.')
+-- Создаем каталог услуг и назначаем услуги докторам
+INSERT INTO medical_center.service_categories (id, name, department_id) VALUES (1, 'Консультации', 1), (2, 'Функциональная диагностика', 3) ON CONFLICT (id) DO NOTHING;
+INSERT INTO medical_center.services (id, code, name, base_price, duration_minutes, description, category_id, recommendations) VALUES
+(1, 'SRV-0001', 'Консультация кардиолога', 2500.00, 30, 'Первичная консультация ведущего кардиолога.', 1, 'При себе иметь кардиограмму (ЭКГ), сделанную не позднее месяца назад.'),
+(2, 'SRV-0002', 'Первичный прием терапевта', 1800.00, 20, 'Осмотр, сбор анамнеза, назначение лечения.', 1, 'Вспомните все препараты, которые вы принимаете на постоянной основе.'),
+(3, 'SRV-0003', 'Электрокардиография (ЭКГ)', 900.00, 15, 'Запись ЭКГ в 12 отведениях с расшифровкой.', 2, NULL)
 ON CONFLICT (id) DO NOTHING;
+INSERT INTO medical_center.doctor_services (doctor_id, service_id, price, duration_minutes) VALUES
+(1, 1, NULL, NULL), (1, 3, 1100.00, 20), (2, 2, NULL, NULL), (2, 3, NULL, NULL)
+ON CONFLICT DO NOTHING;
+INSERT INTO medical_center.service_price_history (service_id, doctor_id, new_price)
+SELECT s.id, NULL, s.base_price FROM medical_center.services s
+UNION ALL
+SELECT ds.service_id, ds.doctor_id, COALESCE(ds.price, s.base_price)
+FROM medical_center.doctor_services ds JOIN medical_center.services s ON s.id = ds.service_id;
+
+-- Назначаем докторов в клинику
+INSERT INTO medical_center.doctorclinics (doctor_id, clinic_id) VALUES (1, 1), (2, 1) ON CONFLICT DO NOTHING;
 
 -- Создаем расписание для доктора Иванова (id=1)
-INSERT INTO medical_center.schedules (doctor_id, date, start_time, end_time) VALUES
-(1, '2025-09-25', '09:00:00', '17:00:00'),
-(1, '2025-09-26', '09:00:00', '17:00:00'),
-(1, '2025-09-29', '10:00:00', '15:00:00')
+INSERT INTO medical_center.schedules (doctor_id, date, start_time, end_time, clinic_id) VALUES
+(1, '2025-09-25', '09:00:00', '17:00:00', 1),
+(1, '2025-09-26', '09:00:00', '17:00:00', 1),
+(1, '2025-09-29', '10:00:00', '15:00:00', 1)
 ON CONFLICT (doctor_id, date) DO NOTHING;
 
 -- Создаем тестового пользователя
@@ -48,7 +67,9 @@ ON CONFLICT (id) DO NOTHING;
 SELECT setval('medical_center.cities_id_seq', (SELECT MAX(id) FROM medical_center.cities), true);
 SELECT setval('medical_center.departments_id_seq', (SELECT MAX(id) FROM medical_center.departments), true);
 SELECT setval('medical_center.specialties_id_seq', (SELECT MAX(id) FROM medical_center.specialties), true);
+SELECT setval('medical_center.clinics_id_seq', (SELECT MAX(id) FROM medical_center.clinics), true);
 SELECT setval('medical_center.doctors_id_seq', (SELECT MAX(id) FROM medical_center.doctors), true);
+SELECT setval('medical_center.service_categories_id_seq', (SELECT MAX(id) FROM medical_center.service_categories), true);
 SELECT setval('medical_center.services_id_seq', (SELECT MAX(id) FROM medical_center.services), true);
 SELECT setval('medical_center.users_id_seq', (SELECT MAX(id) FROM medical_center.users), true);
 SELECT setval('medical_center.user_profiles_id_seq', (SELECT MAX(id) FROM medical_center.user_profiles), true);
@@ -0,0 +1 @@
+DELETE FROM medical_center.legal_documents WHERE type IN ('personal_data_consent', 'marketing_consent');
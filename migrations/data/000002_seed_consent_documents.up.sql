@@ -0,0 +1,4 @@
+-- Согласия, которые принимаются при регистрации вместе с соглашением и политикой
+INSERT INTO medical_center.legal_documents (type, title, url, version, update_date) VALUES
+('personal_data_consent', 'Согласие на обработку персональных данных', '/legal/personal-data.pdf', '1.0', '2024-01-15'),
+('marketing_consent', 'Согласие на получение рекламных рассылок', '/legal/marketing.pdf', '1.0', '2024-01-15');
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS medical_center.lockout_events;
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS medical_center.lockout_events (
+    id bigserial PRIMARY KEY,
+    scope varchar(50) NOT NULL,
+    identifier varchar(255) NOT NULL,
+    ip_address varchar(64),
+    failed_attempts bigint NOT NULL DEFAULT 0,
+    locked_until timestamp with time zone NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_lockout_events_created_at ON medical_center.lockout_events(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_lockout_events_scope_identifier ON medical_center.lockout_events(scope, identifier);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS medical_center.phone_normalization_conflicts;
+DROP FUNCTION IF EXISTS medical_center.normalize_phone(text);
+
+ALTER TABLE medical_center.users
+DROP COLUMN IF EXISTS phone_verified_at;
@@ -0,0 +1,72 @@
+-- Отметка о подтверждении номера телефона. Существующие пользователи считаются подтвержденными.
+ALTER TABLE medical_center.users
+ADD COLUMN IF NOT EXISTS phone_verified_at timestamp without time zone;
+
+UPDATE medical_center.users SET phone_verified_at = created_at WHERE phone_verified_at IS NULL;
+
+-- Функция приведения номера к формату E.164 (логика совпадает с utils.NormalizePhone).
+-- Возвращает NULL, если номер невозможно нормализовать.
+CREATE OR REPLACE FUNCTION medical_center.normalize_phone(raw text)
+RETURNS text AS $$
+DECLARE
+    digits text := regexp_replace(COALESCE(raw, ''), '\D', '', 'g');
+    has_plus boolean := left(btrim(COALESCE(raw, '')), 1) = '+';
+BEGIN
+    IF length(digits) = 11 AND left(digits, 1) = '7' THEN
+        RETURN '+7' || right(digits, 10);
+    ELSIF length(digits) = 11 AND left(digits, 1) = '8' AND NOT has_plus THEN
+        RETURN '+7' || right(digits, 10);
+    ELSIF length(digits) = 10 AND left(digits, 1) = '9' AND NOT has_plus THEN
+        RETURN '+7' || digits;
+    ELSIF has_plus AND length(digits) BETWEEN 8 AND 15 AND left(digits, 1) <> '0' THEN
+        RETURN '+' || digits;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+-- Отчет о номерах, которые не удалось нормализовать автоматически.
+CREATE TABLE IF NOT EXISTS medical_center.phone_normalization_conflicts (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES medical_center.users(id) ON DELETE CASCADE,
+    original_phone varchar(20) NOT NULL,
+    normalized_phone varchar(20),
+    reason varchar(50) NOT NULL, -- 'invalid' | 'duplicate'
+    created_at timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO medical_center.phone_normalization_conflicts (user_id, original_phone, normalized_phone, reason)
+SELECT id, phone, NULL, 'invalid'
+FROM medical_center.users
+WHERE medical_center.normalize_phone(phone) IS NULL;
+
+-- Дубликаты: несколько пользователей, чьи номера совпадают после нормализации.
+INSERT INTO medical_center.phone_normalization_conflicts (user_id, original_phone, normalized_phone, reason)
+SELECT u.id, u.phone, medical_center.normalize_phone(u.phone), 'duplicate'
+FROM medical_center.users u
+WHERE medical_center.normalize_phone(u.phone) IN (
+    SELECT medical_center.normalize_phone(phone)
+    FROM medical_center.users
+    WHERE medical_center.normalize_phone(phone) IS NOT NULL
+    GROUP BY medical_center.normalize_phone(phone)
+    HAVING COUNT(*) > 1
+);
+
+-- Нормализуем только номера без конфликтов; конфликтные разбираются вручную.
+UPDATE medical_center.users u
+SET phone = medical_center.normalize_phone(u.phone)
+WHERE medical_center.normalize_phone(u.phone) IS NOT NULL
+  AND u.phone <> medical_center.normalize_phone(u.phone)
+  AND NOT EXISTS (
+      SELECT 1 FROM medical_center.phone_normalization_conflicts c WHERE c.user_id = u.id
+  );
+
+DO $$
+DECLARE
+    conflicts integer;
+BEGIN
+    SELECT COUNT(*) INTO conflicts FROM medical_center.phone_normalization_conflicts;
+    IF conflicts > 0 THEN
+        RAISE NOTICE 'phone normalization: % user(s) require manual review, see medical_center.phone_normalization_conflicts', conflicts;
+    END IF;
+END $$;
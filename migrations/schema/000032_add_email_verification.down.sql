@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS medical_center.idx_user_profiles_email_lower;
+
+ALTER TABLE medical_center.user_profiles
+DROP COLUMN IF EXISTS email_verified_at;
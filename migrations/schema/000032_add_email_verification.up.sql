@@ -0,0 +1,6 @@
+-- Отметка о подтверждении адреса электронной почты. Существующие адреса считаются неподтвержденными.
+ALTER TABLE medical_center.user_profiles
+ADD COLUMN IF NOT EXISTS email_verified_at timestamp without time zone;
+
+-- Поиск профиля по адресу при восстановлении пароля выполняется без учета регистра.
+CREATE INDEX IF NOT EXISTS idx_user_profiles_email_lower ON medical_center.user_profiles(lower(email));
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS medical_center.security_settings;
+DROP TABLE IF EXISTS medical_center.admin_recovery_codes;
+
+ALTER TABLE medical_center.admins
+DROP COLUMN IF EXISTS totp_enabled_at,
+DROP COLUMN IF EXISTS totp_secret;
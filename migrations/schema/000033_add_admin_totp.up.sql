@@ -0,0 +1,23 @@
+ALTER TABLE medical_center.admins
+ADD COLUMN IF NOT EXISTS totp_secret varchar(64),
+ADD COLUMN IF NOT EXISTS totp_enabled_at timestamp with time zone;
+
+CREATE TABLE IF NOT EXISTS medical_center.admin_recovery_codes (
+    id bigserial PRIMARY KEY,
+    admin_id bigint NOT NULL REFERENCES medical_center.admins(id) ON DELETE CASCADE,
+    code_hash varchar(64) NOT NULL,
+    used_at timestamp with time zone,
+    created_at timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_recovery_codes_admin_id ON medical_center.admin_recovery_codes(admin_id);
+
+-- Глобальные настройки безопасности: всегда ровно одна запись.
+CREATE TABLE IF NOT EXISTS medical_center.security_settings (
+    id smallint PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    require_admin_2fa boolean NOT NULL DEFAULT false,
+    updated_by bigint REFERENCES medical_center.admins(id) ON DELETE SET NULL,
+    updated_at timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO medical_center.security_settings (id) VALUES (1) ON CONFLICT (id) DO NOTHING;
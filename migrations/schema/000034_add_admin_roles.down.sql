@@ -0,0 +1,2 @@
+ALTER TABLE medical_center.admins
+DROP CONSTRAINT IF EXISTS admins_role_check;
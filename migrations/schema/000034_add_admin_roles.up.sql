@@ -0,0 +1,4 @@
+-- Роли админ-панели: права каждой роли описаны в коде (models.rolePermissions).
+ALTER TABLE medical_center.admins
+ADD CONSTRAINT admins_role_check
+CHECK (role IN ('superadmin', 'admin', 'receptionist', 'lab_technician'));
@@ -0,0 +1,4 @@
+ALTER TABLE medical_center.admins
+DROP COLUMN IF EXISTS last_login_at,
+DROP COLUMN IF EXISTS must_change_password,
+DROP COLUMN IF EXISTS is_active;
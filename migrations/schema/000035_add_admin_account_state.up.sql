@@ -0,0 +1,4 @@
+ALTER TABLE medical_center.admins
+ADD COLUMN IF NOT EXISTS is_active boolean NOT NULL DEFAULT true,
+ADD COLUMN IF NOT EXISTS must_change_password boolean NOT NULL DEFAULT false,
+ADD COLUMN IF NOT EXISTS last_login_at timestamp with time zone;
@@ -0,0 +1,14 @@
+-- Сессии администраторов хранятся отдельно от refresh_tokens пациентов:
+-- у администратора может быть несколько активных сессий.
+CREATE TABLE IF NOT EXISTS medical_center.admin_sessions (
+    id bigserial PRIMARY KEY,
+    admin_id bigint NOT NULL REFERENCES medical_center.admins(id) ON DELETE CASCADE,
+    token_hash varchar(64) NOT NULL,
+    ip_address varchar(45),
+    user_agent text,
+    created_at timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at timestamp with time zone NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_sessions_admin_id ON medical_center.admin_sessions(admin_id);
@@ -0,0 +1,15 @@
+-- Журнал запросов, выполненных администраторами от имени пациентов.
+CREATE TABLE IF NOT EXISTS medical_center.impersonation_logs (
+    id bigserial PRIMARY KEY,
+    admin_id bigint NOT NULL REFERENCES medical_center.admins(id),
+    user_id bigint NOT NULL REFERENCES medical_center.users(id) ON DELETE CASCADE,
+    method varchar(10) NOT NULL,
+    route varchar(255) NOT NULL,
+    path varchar(2048) NOT NULL,
+    status_code integer NOT NULL,
+    ip_address varchar(64),
+    created_at timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_impersonation_logs_admin_id ON medical_center.impersonation_logs(admin_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_impersonation_logs_user_id ON medical_center.impersonation_logs(user_id, created_at DESC);
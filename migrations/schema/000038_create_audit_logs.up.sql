@@ -0,0 +1,26 @@
+-- Журнал аудита: действия администраторов, события безопасности пациентов
+-- и обращения к медицинским данным.
+CREATE TABLE IF NOT EXISTS medical_center.audit_logs (
+    id bigserial PRIMARY KEY,
+    actor_type varchar(20) NOT NULL,
+    actor_id bigint,
+    actor_name varchar(255),
+    impersonator_id bigint,
+    action varchar(100) NOT NULL,
+    entity_type varchar(50) NOT NULL,
+    entity_id varchar(64),
+    target_user_id bigint,
+    changes jsonb,
+    ip_address varchar(64),
+    request_id varchar(64),
+    created_at timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Ссылки на пользователей и администраторов намеренно не внешние ключи:
+-- записи аудита должны переживать удаление субъектов.
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON medical_center.audit_logs(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor ON medical_center.audit_logs(actor_type, actor_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_entity ON medical_center.audit_logs(entity_type, entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_target_user ON medical_center.audit_logs(target_user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_action ON medical_center.audit_logs(action);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_request_id ON medical_center.audit_logs(request_id);
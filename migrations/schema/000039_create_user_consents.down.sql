@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS medical_center.user_consents;
+
+DROP INDEX IF EXISTS medical_center.idx_legal_documents_type_published;
+
+ALTER TABLE medical_center.legal_documents
+    DROP CONSTRAINT IF EXISTS fk_legal_documents_type,
+    DROP COLUMN IF EXISTS published_at;
+
+DROP TABLE IF EXISTS medical_center.legal_document_types;
@@ -0,0 +1,46 @@
+-- Типы юридических документов: обязательные принимаются при регистрации
+-- и после публикации новой версии, необязательные (например, рассылки) можно отозвать.
+CREATE TABLE IF NOT EXISTS medical_center.legal_document_types (
+    type varchar(100) PRIMARY KEY,
+    title varchar(255) NOT NULL,
+    is_mandatory boolean NOT NULL DEFAULT false
+);
+
+INSERT INTO medical_center.legal_document_types (type, title, is_mandatory) VALUES
+('terms_of_use', 'Пользовательское соглашение', true),
+('privacy_policy', 'Политика конфиденциальности', true),
+('personal_data_consent', 'Согласие на обработку персональных данных (152-ФЗ)', true),
+('marketing_consent', 'Согласие на получение рекламных рассылок', false)
+ON CONFLICT (type) DO NOTHING;
+
+-- Уже существующие типы документов считаются необязательными, пока администратор не решит иначе
+INSERT INTO medical_center.legal_document_types (type, title)
+SELECT DISTINCT ON (type) type, title FROM medical_center.legal_documents
+ON CONFLICT (type) DO NOTHING;
+
+-- Каждая строка legal_documents - отдельная версия документа; действующая - последняя опубликованная
+ALTER TABLE medical_center.legal_documents
+    ADD COLUMN IF NOT EXISTS published_at timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ADD CONSTRAINT fk_legal_documents_type
+        FOREIGN KEY (type) REFERENCES medical_center.legal_document_types(type);
+
+CREATE INDEX IF NOT EXISTS idx_legal_documents_type_published
+    ON medical_center.legal_documents(type, published_at DESC, id DESC);
+
+-- История принятия документов пользователями. Строки не удаляются:
+-- отзыв согласия фиксируется в revoked_at, повторное принятие создает новую строку.
+CREATE TABLE IF NOT EXISTS medical_center.user_consents (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES medical_center.users(id) ON DELETE CASCADE,
+    document_id bigint NOT NULL REFERENCES medical_center.legal_documents(id),
+    document_type varchar(100) NOT NULL,
+    version varchar(20) NOT NULL,
+    accepted_at timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at timestamp with time zone,
+    ip_address varchar(64),
+    user_agent text
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_consents_user ON medical_center.user_consents(user_id, accepted_at DESC);
+CREATE INDEX IF NOT EXISTS idx_user_consents_active
+    ON medical_center.user_consents(user_id, document_id) WHERE revoked_at IS NULL;
@@ -0,0 +1,19 @@
+DROP TRIGGER IF EXISTS legal_documents_immutable ON medical_center.legal_documents;
+DROP FUNCTION IF EXISTS medical_center.forbid_published_legal_document_update();
+
+DELETE FROM medical_center.legal_documents WHERE status = 'draft';
+
+UPDATE medical_center.legal_documents SET published_at = CURRENT_TIMESTAMP WHERE published_at IS NULL;
+
+ALTER TABLE medical_center.legal_documents
+    DROP CONSTRAINT IF EXISTS chk_legal_documents_published,
+    DROP CONSTRAINT IF EXISTS uq_legal_documents_type_version,
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS created_by,
+    DROP COLUMN IF EXISTS file_size,
+    DROP COLUMN IF EXISTS file_name,
+    DROP COLUMN IF EXISTS file_key,
+    DROP COLUMN IF EXISTS status,
+    ALTER COLUMN published_at SET NOT NULL,
+    ALTER COLUMN url DROP DEFAULT,
+    ALTER COLUMN update_date TYPE varchar(20) USING to_char(update_date, 'YYYY-MM-DD');
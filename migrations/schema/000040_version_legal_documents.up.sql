@@ -0,0 +1,32 @@
+-- Версии юридических документов: черновик можно править, опубликованная версия неизменна.
+-- Файл документа (PDF) хранится в файловом хранилище, url остается для старых записей.
+ALTER TABLE medical_center.legal_documents
+    ALTER COLUMN update_date TYPE date USING update_date::date,
+    ALTER COLUMN url SET DEFAULT '',
+    ALTER COLUMN published_at DROP NOT NULL,
+    ADD COLUMN IF NOT EXISTS status varchar(20) NOT NULL DEFAULT 'published'
+        CHECK (status IN ('draft', 'published')),
+    ADD COLUMN IF NOT EXISTS file_key varchar(512) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS file_name varchar(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS file_size bigint NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS created_by bigint REFERENCES medical_center.admins(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS created_at timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ADD CONSTRAINT uq_legal_documents_type_version UNIQUE (type, version),
+    ADD CONSTRAINT chk_legal_documents_published
+        CHECK (status = 'draft' OR published_at IS NOT NULL);
+
+-- Защита от изменения опубликованных версий в обход приложения
+CREATE OR REPLACE FUNCTION medical_center.forbid_published_legal_document_update()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF OLD.status = 'published' THEN
+        RAISE EXCEPTION 'published legal document % is immutable', OLD.id;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS legal_documents_immutable ON medical_center.legal_documents;
+CREATE TRIGGER legal_documents_immutable
+BEFORE UPDATE ON medical_center.legal_documents
+FOR EACH ROW EXECUTE FUNCTION medical_center.forbid_published_legal_document_update();
@@ -0,0 +1,6 @@
+ALTER TABLE medical_center.users
+    DROP COLUMN IF EXISTS anonymized_at;
+
+DROP TABLE IF EXISTS medical_center.account_deletion_requests;
+
+DROP TABLE IF EXISTS medical_center.data_export_requests;
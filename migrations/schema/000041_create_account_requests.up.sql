@@ -0,0 +1,49 @@
+-- Выгрузки персональных данных по запросу пациента. Архив собирает фоновая задача,
+-- готовый файл хранится в MinIO ограниченное время (expires_at).
+CREATE TABLE IF NOT EXISTS medical_center.data_export_requests (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES medical_center.users(id) ON DELETE CASCADE,
+    status varchar(20) NOT NULL DEFAULT 'pending',
+    file_key varchar(512),
+    file_size bigint,
+    error text,
+    created_at timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at timestamp with time zone,
+    completed_at timestamp with time zone,
+    expires_at timestamp with time zone,
+    CONSTRAINT data_export_requests_status_check
+        CHECK (status IN ('pending', 'processing', 'ready', 'failed', 'expired'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_export_requests_user
+    ON medical_center.data_export_requests(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_data_export_requests_queue
+    ON medical_center.data_export_requests(status, created_at) WHERE status IN ('pending', 'processing', 'ready');
+-- Одновременно у пациента может собираться только одна выгрузка
+CREATE UNIQUE INDEX IF NOT EXISTS uq_data_export_requests_in_progress
+    ON medical_center.data_export_requests(user_id) WHERE status IN ('pending', 'processing');
+
+-- Запросы на удаление аккаунта. До scheduled_at пациент может передумать;
+-- после - фоновая задача обезличивает учетную запись и удаляет файлы.
+CREATE TABLE IF NOT EXISTS medical_center.account_deletion_requests (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES medical_center.users(id) ON DELETE CASCADE,
+    status varchar(20) NOT NULL DEFAULT 'pending',
+    reason text,
+    requested_at timestamp with time zone NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    scheduled_at timestamp with time zone NOT NULL,
+    cancelled_at timestamp with time zone,
+    completed_at timestamp with time zone,
+    CONSTRAINT account_deletion_requests_status_check
+        CHECK (status IN ('pending', 'cancelled', 'completed'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_account_deletion_requests_pending
+    ON medical_center.account_deletion_requests(user_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_account_deletion_requests_due
+    ON medical_center.account_deletion_requests(scheduled_at) WHERE status = 'pending';
+
+-- Медицинские записи обезличенного пациента хранятся в течение установленного срока,
+-- поэтому строка users не удаляется, а помечается.
+ALTER TABLE medical_center.users
+    ADD COLUMN IF NOT EXISTS anonymized_at timestamp without time zone;
@@ -0,0 +1,15 @@
+-- Откат возможен, только если среди удаленных записей нет дубликатов телефонов и названий
+DROP INDEX IF EXISTS medical_center.uq_departments_name;
+ALTER TABLE medical_center.departments ADD CONSTRAINT departments_name_key UNIQUE (name);
+
+DROP INDEX IF EXISTS medical_center.uq_users_gosuslugi_id;
+DROP INDEX IF EXISTS medical_center.uq_users_phone;
+ALTER TABLE medical_center.users
+    ADD CONSTRAINT users_phone_key UNIQUE (phone),
+    ADD CONSTRAINT users_gosuslugi_id_key UNIQUE (gosuslugi_id);
+
+ALTER TABLE medical_center.appointments DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE medical_center.departments DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE medical_center.services DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE medical_center.doctors DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE medical_center.users DROP COLUMN IF EXISTS deleted_at;
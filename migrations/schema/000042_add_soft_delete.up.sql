@@ -0,0 +1,29 @@
+-- Мягкое удаление: удаленные администратором записи скрываются, но остаются в базе
+-- до истечения срока хранения, после чего фоновая задача удаляет их окончательно
+-- (если на них не ссылаются медицинские записи).
+ALTER TABLE medical_center.users ADD COLUMN IF NOT EXISTS deleted_at timestamp with time zone;
+ALTER TABLE medical_center.doctors ADD COLUMN IF NOT EXISTS deleted_at timestamp with time zone;
+ALTER TABLE medical_center.services ADD COLUMN IF NOT EXISTS deleted_at timestamp with time zone;
+ALTER TABLE medical_center.departments ADD COLUMN IF NOT EXISTS deleted_at timestamp with time zone;
+ALTER TABLE medical_center.appointments ADD COLUMN IF NOT EXISTS deleted_at timestamp with time zone;
+
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON medical_center.users(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_doctors_deleted_at ON medical_center.doctors(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_services_deleted_at ON medical_center.services(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_departments_deleted_at
+    ON medical_center.departments(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_appointments_deleted_at
+    ON medical_center.appointments(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Уникальность действует только среди неудаленных записей: номер телефона удаленного пациента
+-- и название удаленного отделения можно использовать снова
+ALTER TABLE medical_center.users
+    DROP CONSTRAINT IF EXISTS users_phone_key,
+    DROP CONSTRAINT IF EXISTS users_gosuslugi_id_key;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_users_phone ON medical_center.users(phone) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_users_gosuslugi_id
+    ON medical_center.users(gosuslugi_id) WHERE deleted_at IS NULL;
+
+ALTER TABLE medical_center.departments DROP CONSTRAINT IF EXISTS departments_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_departments_name
+    ON medical_center.departments(name) WHERE deleted_at IS NULL;
@@ -0,0 +1,17 @@
+DROP INDEX IF EXISTS medical_center.idx_reviews_pending;
+DROP INDEX IF EXISTS medical_center.idx_reviews_doctor_approved;
+DROP INDEX IF EXISTS medical_center.uq_reviews_appointment_id;
+
+ALTER TABLE medical_center.reviews
+    ADD COLUMN IF NOT EXISTS is_moderated boolean NOT NULL DEFAULT false;
+
+UPDATE medical_center.reviews SET is_moderated = (status <> 'pending');
+
+ALTER TABLE medical_center.reviews
+    DROP CONSTRAINT IF EXISTS reviews_rating_check,
+    DROP CONSTRAINT IF EXISTS reviews_status_check,
+    DROP COLUMN IF EXISTS moderated_at,
+    DROP COLUMN IF EXISTS moderated_by,
+    DROP COLUMN IF EXISTS rejection_reason,
+    DROP COLUMN IF EXISTS status,
+    DROP COLUMN IF EXISTS appointment_id;
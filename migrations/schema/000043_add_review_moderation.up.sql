@@ -0,0 +1,33 @@
+-- Модерация отзывов: вместо флага is_moderated отзыв проходит статусы
+-- pending -> approved/rejected. Отзыв привязан к завершенному приему (один отзыв на визит).
+ALTER TABLE medical_center.reviews
+    ADD COLUMN IF NOT EXISTS appointment_id bigint REFERENCES medical_center.appointments(id),
+    ADD COLUMN IF NOT EXISTS status varchar(20) NOT NULL DEFAULT 'pending',
+    ADD COLUMN IF NOT EXISTS rejection_reason text,
+    ADD COLUMN IF NOT EXISTS moderated_by bigint REFERENCES medical_center.admins(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS moderated_at timestamp with time zone;
+
+UPDATE medical_center.reviews SET status = 'approved' WHERE is_moderated;
+
+ALTER TABLE medical_center.reviews
+    DROP COLUMN IF EXISTS is_moderated,
+    ADD CONSTRAINT reviews_status_check CHECK (status IN ('pending', 'approved', 'rejected')),
+    ADD CONSTRAINT reviews_rating_check CHECK (rating BETWEEN 1 AND 5);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_reviews_appointment_id
+    ON medical_center.reviews(appointment_id) WHERE appointment_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_reviews_doctor_approved
+    ON medical_center.reviews(doctor_id, created_at DESC) WHERE status = 'approved';
+CREATE INDEX IF NOT EXISTS idx_reviews_pending
+    ON medical_center.reviews(created_at) WHERE status = 'pending';
+
+-- Рейтинг и число отзывов врача считаются только по одобренным отзывам
+UPDATE medical_center.doctors d
+SET rating = r.avg_rating, review_count = r.cnt
+FROM (
+    SELECT doctor_id, ROUND(AVG(rating)::numeric, 2) AS avg_rating, COUNT(*) AS cnt
+    FROM medical_center.reviews
+    WHERE status = 'approved'
+    GROUP BY doctor_id
+) r
+WHERE r.doctor_id = d.id;
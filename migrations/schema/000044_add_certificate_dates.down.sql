@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS medical_center.idx_doctorspecializations_doctor_id;
+DROP INDEX IF EXISTS medical_center.idx_doctorskills_doctor_id;
+DROP INDEX IF EXISTS medical_center.idx_doctorresidency_doctor_id;
+DROP INDEX IF EXISTS medical_center.idx_doctoreducation_doctor_id;
+DROP INDEX IF EXISTS medical_center.idx_doctorcourses_doctor_id;
+DROP INDEX IF EXISTS medical_center.idx_doctorcertificates_doctor_id;
+DROP INDEX IF EXISTS medical_center.idx_doctorcertificates_expires_at;
+
+ALTER TABLE medical_center.doctorcertificates
+    DROP COLUMN IF EXISTS expiry_alerted_at,
+    DROP COLUMN IF EXISTS expires_at,
+    DROP COLUMN IF EXISTS issued_at;
@@ -0,0 +1,17 @@
+-- Сроки действия сертификатов врачей. За certificate_alert_before до истечения
+-- фоновая задача один раз предупреждает администраторов (expiry_alerted_at).
+ALTER TABLE medical_center.doctorcertificates
+    ADD COLUMN IF NOT EXISTS issued_at date,
+    ADD COLUMN IF NOT EXISTS expires_at date,
+    ADD COLUMN IF NOT EXISTS expiry_alerted_at timestamp with time zone;
+
+CREATE INDEX IF NOT EXISTS idx_doctorcertificates_expires_at
+    ON medical_center.doctorcertificates(expires_at) WHERE expires_at IS NOT NULL;
+
+-- Разделы профиля выбираются по врачу
+CREATE INDEX IF NOT EXISTS idx_doctorcertificates_doctor_id ON medical_center.doctorcertificates(doctor_id);
+CREATE INDEX IF NOT EXISTS idx_doctorcourses_doctor_id ON medical_center.doctorcourses(doctor_id);
+CREATE INDEX IF NOT EXISTS idx_doctoreducation_doctor_id ON medical_center.doctoreducation(doctor_id);
+CREATE INDEX IF NOT EXISTS idx_doctorresidency_doctor_id ON medical_center.doctorresidency(doctor_id);
+CREATE INDEX IF NOT EXISTS idx_doctorskills_doctor_id ON medical_center.doctorskills(doctor_id);
+CREATE INDEX IF NOT EXISTS idx_doctorspecializations_doctor_id ON medical_center.doctorspecializations(doctor_id);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS medical_center.idx_appointments_doctor_id_date;
+DROP INDEX IF EXISTS medical_center.idx_doctors_rating;
+DROP INDEX IF EXISTS medical_center.idx_clinics_city_id;
+
+ALTER TABLE medical_center.clinics DROP COLUMN IF EXISTS city_id;
+
+ALTER TABLE medical_center.doctors
+    DROP CONSTRAINT IF EXISTS doctors_gender_check,
+    DROP COLUMN IF EXISTS accepts_dms,
+    DROP COLUMN IF EXISTS gender;
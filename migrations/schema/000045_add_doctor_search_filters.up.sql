@@ -0,0 +1,15 @@
+-- Поля для фильтров поиска врачей
+ALTER TABLE medical_center.doctors
+    ADD COLUMN IF NOT EXISTS gender varchar(10),
+    ADD COLUMN IF NOT EXISTS accepts_dms boolean NOT NULL DEFAULT false;
+
+ALTER TABLE medical_center.doctors
+    ADD CONSTRAINT doctors_gender_check CHECK (gender IN ('male', 'female'));
+
+-- Город клиники: по нему фильтруются врачи, работающие в этой клинике
+ALTER TABLE medical_center.clinics
+    ADD COLUMN IF NOT EXISTS city_id integer REFERENCES medical_center.cities(id);
+
+CREATE INDEX IF NOT EXISTS idx_clinics_city_id ON medical_center.clinics(city_id);
+CREATE INDEX IF NOT EXISTS idx_doctors_rating ON medical_center.doctors(rating DESC) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_appointments_doctor_id_date ON medical_center.appointments(doctor_id, appointment_date);
@@ -0,0 +1,32 @@
+DROP INDEX IF EXISTS medical_center.idx_services_name_trgm;
+DROP INDEX IF EXISTS medical_center.idx_specialties_name_trgm;
+DROP INDEX IF EXISTS medical_center.idx_doctors_search_text_trgm;
+
+DROP TRIGGER IF EXISTS doctor_search_refresh ON medical_center.specialties;
+DROP TRIGGER IF EXISTS doctor_search_refresh ON medical_center.doctorspecializations;
+DROP TRIGGER IF EXISTS doctor_search_refresh ON medical_center.doctorskills;
+DROP TRIGGER IF EXISTS doctor_search_refresh ON medical_center.services;
+DROP FUNCTION IF EXISTS medical_center.refresh_specialty_doctors_search();
+DROP FUNCTION IF EXISTS medical_center.refresh_doctor_search();
+
+-- Возвращаем документ из ФИО и специальности (см. 000029)
+CREATE OR REPLACE FUNCTION medical_center.update_doctor_fts_document()
+RETURNS TRIGGER AS $$
+DECLARE
+    specialty_name text;
+BEGIN
+    SELECT name INTO specialty_name FROM medical_center.specialties WHERE id = NEW.specialty_id;
+    NEW.fts_document := to_tsvector('russian',
+        COALESCE(NEW.last_name, '') || ' ' ||
+        COALESCE(NEW.first_name, '') || ' ' ||
+        COALESCE(NEW.patronymic, '') || ' ' ||
+        COALESCE(specialty_name, '')
+    );
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE medical_center.doctors DROP COLUMN IF EXISTS search_text;
+UPDATE medical_center.doctors SET fts_document = NULL;
+
+-- Расширение pg_trgm не удаляем: им могут пользоваться другие объекты базы
@@ -0,0 +1,83 @@
+-- Нечеткий поиск врачей: триграммы для опечаток и подстрок, префиксный FTS для ввода "на лету"
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- search_text - исходный текст поискового документа: по нему работает триграммный поиск
+-- и из него же строятся фрагменты с подсветкой
+ALTER TABLE medical_center.doctors ADD COLUMN IF NOT EXISTS search_text text;
+
+-- Поисковый документ включает ФИО (вес A), специальность (B), услуги, навыки и специализации (C)
+CREATE OR REPLACE FUNCTION medical_center.update_doctor_fts_document()
+RETURNS TRIGGER AS $$
+DECLARE
+    specialty_name text;
+    details text;
+BEGIN
+    SELECT name INTO specialty_name FROM medical_center.specialties WHERE id = NEW.specialty_id;
+
+    SELECT concat_ws(' ',
+        (SELECT string_agg(name, ' ') FROM medical_center.services
+            WHERE doctor_id = NEW.id AND deleted_at IS NULL),
+        (SELECT string_agg(skill_name, ' ') FROM medical_center.doctorskills WHERE doctor_id = NEW.id),
+        (SELECT string_agg(area, ' ') FROM medical_center.doctorspecializations WHERE doctor_id = NEW.id)
+    ) INTO details;
+
+    NEW.fts_document :=
+        setweight(to_tsvector('russian', concat_ws(' ', NEW.last_name, NEW.first_name, NEW.patronymic)), 'A') ||
+        setweight(to_tsvector('russian', COALESCE(specialty_name, '')), 'B') ||
+        setweight(to_tsvector('russian', COALESCE(details, '')), 'C');
+    NEW.search_text := concat_ws(' ', NEW.last_name, NEW.first_name, NEW.patronymic, specialty_name, details);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Изменения связанных таблиц пересчитывают документ врача через триггер tsvector_update
+CREATE OR REPLACE FUNCTION medical_center.refresh_doctor_search()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE medical_center.doctors SET fts_document = NULL WHERE id = OLD.doctor_id;
+    END IF;
+    IF TG_OP = 'INSERT' OR (TG_OP = 'UPDATE' AND NEW.doctor_id IS DISTINCT FROM OLD.doctor_id) THEN
+        UPDATE medical_center.doctors SET fts_document = NULL WHERE id = NEW.doctor_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION medical_center.refresh_specialty_doctors_search()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE medical_center.doctors SET fts_document = NULL WHERE specialty_id = NEW.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS doctor_search_refresh ON medical_center.services;
+CREATE TRIGGER doctor_search_refresh
+AFTER INSERT OR UPDATE OR DELETE ON medical_center.services
+FOR EACH ROW EXECUTE FUNCTION medical_center.refresh_doctor_search();
+
+DROP TRIGGER IF EXISTS doctor_search_refresh ON medical_center.doctorskills;
+CREATE TRIGGER doctor_search_refresh
+AFTER INSERT OR UPDATE OR DELETE ON medical_center.doctorskills
+FOR EACH ROW EXECUTE FUNCTION medical_center.refresh_doctor_search();
+
+DROP TRIGGER IF EXISTS doctor_search_refresh ON medical_center.doctorspecializations;
+CREATE TRIGGER doctor_search_refresh
+AFTER INSERT OR UPDATE OR DELETE ON medical_center.doctorspecializations
+FOR EACH ROW EXECUTE FUNCTION medical_center.refresh_doctor_search();
+
+DROP TRIGGER IF EXISTS doctor_search_refresh ON medical_center.specialties;
+CREATE TRIGGER doctor_search_refresh
+AFTER UPDATE OF name ON medical_center.specialties
+FOR EACH ROW EXECUTE FUNCTION medical_center.refresh_specialty_doctors_search();
+
+-- Пересчитываем документы существующих врачей
+UPDATE medical_center.doctors SET fts_document = NULL;
+
+CREATE INDEX IF NOT EXISTS idx_doctors_search_text_trgm
+    ON medical_center.doctors USING gin (search_text gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_specialties_name_trgm
+    ON medical_center.specialties USING gin (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_services_name_trgm
+    ON medical_center.services USING gin (name gin_trgm_ops) WHERE deleted_at IS NULL;
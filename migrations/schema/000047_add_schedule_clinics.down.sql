@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS medical_center.idx_schedules_clinic_id;
+ALTER TABLE medical_center.schedules
+    DROP CONSTRAINT IF EXISTS schedules_doctor_clinic_fkey,
+    DROP COLUMN IF EXISTS clinic_id;
@@ -0,0 +1,26 @@
+-- Клиника, в которой врач принимает в этот день. Составной внешний ключ гарантирует,
+-- что врач назначен в эту клинику (doctorclinics); снять назначение, пока на клинику ссылается расписание, нельзя.
+ALTER TABLE medical_center.schedules ADD COLUMN IF NOT EXISTS clinic_id bigint;
+
+ALTER TABLE medical_center.schedules
+    ADD CONSTRAINT schedules_doctor_clinic_fkey FOREIGN KEY (doctor_id, clinic_id)
+        REFERENCES medical_center.doctorclinics(doctor_id, clinic_id)
+        ON UPDATE NO ACTION ON DELETE NO ACTION;
+
+CREATE INDEX IF NOT EXISTS idx_schedules_clinic_id ON medical_center.schedules(clinic_id);
+
+-- До появления назначений клиника была одна: привязываем к ней всех врачей без назначений
+INSERT INTO medical_center.doctorclinics (doctor_id, clinic_id)
+SELECT d.id, c.id
+FROM medical_center.doctors d
+CROSS JOIN medical_center.clinics c
+WHERE (SELECT COUNT(*) FROM medical_center.clinics) = 1
+    AND NOT EXISTS (SELECT 1 FROM medical_center.doctorclinics dc WHERE dc.doctor_id = d.id)
+ON CONFLICT DO NOTHING;
+
+-- Расписание врачей, работающих в одной клинике, относим к ней
+UPDATE medical_center.schedules s
+SET clinic_id = dc.clinic_id
+FROM medical_center.doctorclinics dc
+WHERE dc.doctor_id = s.doctor_id AND s.clinic_id IS NULL
+    AND (SELECT COUNT(*) FROM medical_center.doctorclinics x WHERE x.doctor_id = s.doctor_id) = 1;
@@ -0,0 +1,14 @@
+ALTER TABLE medical_center.clinics ADD COLUMN IF NOT EXISTS work_hours varchar(100) NOT NULL DEFAULT '';
+ALTER TABLE medical_center.clinics ALTER COLUMN work_hours DROP DEFAULT;
+
+DROP TABLE IF EXISTS medical_center.clinic_working_hours;
+
+DROP INDEX IF EXISTS medical_center.idx_clinics_main;
+
+ALTER TABLE medical_center.clinics
+    DROP CONSTRAINT IF EXISTS clinics_coordinates_check,
+    DROP COLUMN IF EXISTS deleted_at,
+    DROP COLUMN IF EXISTS is_main,
+    DROP COLUMN IF EXISTS longitude,
+    DROP COLUMN IF EXISTS latitude,
+    DROP COLUMN IF EXISTS email;
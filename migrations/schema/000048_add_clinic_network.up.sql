@@ -0,0 +1,50 @@
+-- Сеть клиник: контакты, координаты, признак основной клиники и мягкое удаление
+ALTER TABLE medical_center.clinics
+    ADD COLUMN IF NOT EXISTS email varchar(255),
+    ADD COLUMN IF NOT EXISTS latitude numeric(9, 6),
+    ADD COLUMN IF NOT EXISTS longitude numeric(9, 6),
+    ADD COLUMN IF NOT EXISTS is_main boolean NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS deleted_at timestamp with time zone;
+
+ALTER TABLE medical_center.clinics
+    ADD CONSTRAINT clinics_coordinates_check CHECK (
+        (latitude IS NULL) = (longitude IS NULL)
+        AND latitude BETWEEN -90 AND 90
+        AND longitude BETWEEN -180 AND 180
+    );
+
+-- Основной может быть только одна действующая клиника
+CREATE UNIQUE INDEX IF NOT EXISTS idx_clinics_main ON medical_center.clinics(is_main)
+    WHERE is_main AND deleted_at IS NULL;
+
+-- Раньше основной считалась клиника с наименьшим ID, а email был зашит в код
+UPDATE medical_center.clinics
+SET is_main = true
+WHERE id = (SELECT MIN(id) FROM medical_center.clinics);
+
+UPDATE medical_center.clinics SET email = 'info@clinic.ru' WHERE email IS NULL;
+
+-- Часы работы по дням недели (1 - понедельник, 7 - воскресенье); выходной день - отсутствие строки
+CREATE TABLE IF NOT EXISTS medical_center.clinic_working_hours (
+    clinic_id bigint NOT NULL,
+    weekday smallint NOT NULL,
+    opens_at time NOT NULL,
+    closes_at time NOT NULL,
+    PRIMARY KEY (clinic_id, weekday),
+    CONSTRAINT clinic_working_hours_clinic_id_fkey FOREIGN KEY (clinic_id)
+        REFERENCES medical_center.clinics(id)
+        ON UPDATE NO ACTION ON DELETE CASCADE,
+    CONSTRAINT clinic_working_hours_weekday_check CHECK (weekday BETWEEN 1 AND 7),
+    CONSTRAINT clinic_working_hours_range_check CHECK (opens_at < closes_at)
+);
+
+-- Переносим часы, которые раньше были зашиты в код: пн-пт 08:00-20:00, сб 09:00-18:00
+INSERT INTO medical_center.clinic_working_hours (clinic_id, weekday, opens_at, closes_at)
+SELECT c.id, d.weekday, CASE WHEN d.weekday = 6 THEN time '09:00' ELSE time '08:00' END,
+    CASE WHEN d.weekday = 6 THEN time '18:00' ELSE time '20:00' END
+FROM medical_center.clinics c
+CROSS JOIN generate_series(1, 6) AS d(weekday)
+ON CONFLICT DO NOTHING;
+
+-- Свободный текст часов работы заменен таблицей clinic_working_hours
+ALTER TABLE medical_center.clinics DROP COLUMN IF EXISTS work_hours;
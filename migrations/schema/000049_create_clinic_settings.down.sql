@@ -0,0 +1 @@
+DROP TABLE IF EXISTS medical_center.clinic_settings_versions;
@@ -0,0 +1,17 @@
+-- Версии настроек клиники. Запись не изменяется: каждое сохранение добавляет следующую версию,
+-- действует версия с наибольшим номером.
+CREATE TABLE IF NOT EXISTS medical_center.clinic_settings_versions (
+    version bigint PRIMARY KEY,
+    settings jsonb NOT NULL,
+    created_by bigint,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    CONSTRAINT clinic_settings_versions_created_by_fkey FOREIGN KEY (created_by)
+        REFERENCES medical_center.admins(id)
+        ON UPDATE NO ACTION ON DELETE SET NULL
+);
+
+-- Первая версия повторяет значения по умолчанию (models.DefaultClinicSettings)
+INSERT INTO medical_center.clinic_settings_versions (version, settings) VALUES
+(1, '{"cancellationWindowHours": 0, "reminderOffsetsMinutes": [1440, 120], "slotGranularityMinutes": 0,
+      "bookingHorizonDays": 90, "supportPhone": "", "supportEmail": "", "workingHours": []}')
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,82 @@
+DROP TRIGGER IF EXISTS doctor_search_refresh ON medical_center.doctor_services;
+DROP TRIGGER IF EXISTS doctor_search_refresh ON medical_center.services;
+DROP FUNCTION IF EXISTS medical_center.refresh_service_doctors_search();
+
+DROP INDEX IF EXISTS medical_center.uq_services_code;
+ALTER TABLE medical_center.services ADD COLUMN IF NOT EXISTS doctor_id bigint;
+
+-- Позиция каталога возвращается первому из ее врачей с его ценой и длительностью
+UPDATE medical_center.services s SET doctor_id = ds.doctor_id,
+	base_price = COALESCE(ds.price, s.base_price),
+	duration_minutes = COALESCE(ds.duration_minutes, s.duration_minutes)
+FROM (SELECT DISTINCT ON (service_id) * FROM medical_center.doctor_services ORDER BY service_id, doctor_id) ds
+WHERE ds.service_id = s.id;
+
+-- Остальным врачам создаются собственные копии услуги, записи к ним переносятся на копии.
+-- Код копии временно хранит исходную услугу: колонка code удаляется ниже.
+WITH copies AS (
+	INSERT INTO medical_center.services (name, base_price, duration_minutes, description, recommendations,
+		deleted_at, doctor_id, category_id, code)
+	SELECT s.name, COALESCE(ds.price, s.base_price), COALESCE(ds.duration_minutes, s.duration_minutes),
+		s.description, s.recommendations, s.deleted_at, ds.doctor_id, s.category_id, s.id::text
+	FROM medical_center.doctor_services ds
+	JOIN medical_center.services s ON s.id = ds.service_id
+	WHERE ds.doctor_id <> s.doctor_id
+	RETURNING id, doctor_id, code
+)
+UPDATE medical_center.appointments a SET service_id = c.id
+FROM copies c
+WHERE a.service_id = c.code::bigint AND a.doctor_id = c.doctor_id;
+
+-- Услуги без врачей достаются врачу из записей к ним, остальные удаляются
+UPDATE medical_center.services s
+SET doctor_id = (SELECT MIN(a.doctor_id) FROM medical_center.appointments a WHERE a.service_id = s.id)
+WHERE doctor_id IS NULL;
+DELETE FROM medical_center.services WHERE doctor_id IS NULL;
+
+DROP INDEX IF EXISTS medical_center.idx_services_category_id;
+ALTER TABLE medical_center.services
+	DROP CONSTRAINT IF EXISTS services_duration_check,
+	DROP CONSTRAINT IF EXISTS services_base_price_check,
+	DROP COLUMN IF EXISTS category_id,
+	DROP COLUMN IF EXISTS code,
+	ALTER COLUMN doctor_id SET NOT NULL,
+	ADD CONSTRAINT services_doctor_id_fkey FOREIGN KEY (doctor_id)
+		REFERENCES medical_center.doctors(id)
+		ON UPDATE NO ACTION ON DELETE NO ACTION;
+ALTER TABLE medical_center.services RENAME COLUMN base_price TO price;
+CREATE INDEX IF NOT EXISTS idx_services_doctor_id ON medical_center.services(doctor_id);
+
+DROP TABLE IF EXISTS medical_center.doctor_services;
+DROP TABLE IF EXISTS medical_center.service_categories;
+
+-- Возвращаем поисковый документ и триггер на услуги из 000046
+CREATE OR REPLACE FUNCTION medical_center.update_doctor_fts_document()
+RETURNS TRIGGER AS $$
+DECLARE
+    specialty_name text;
+    details text;
+BEGIN
+    SELECT name INTO specialty_name FROM medical_center.specialties WHERE id = NEW.specialty_id;
+
+    SELECT concat_ws(' ',
+        (SELECT string_agg(name, ' ') FROM medical_center.services
+            WHERE doctor_id = NEW.id AND deleted_at IS NULL),
+        (SELECT string_agg(skill_name, ' ') FROM medical_center.doctorskills WHERE doctor_id = NEW.id),
+        (SELECT string_agg(area, ' ') FROM medical_center.doctorspecializations WHERE doctor_id = NEW.id)
+    ) INTO details;
+
+    NEW.fts_document :=
+        setweight(to_tsvector('russian', concat_ws(' ', NEW.last_name, NEW.first_name, NEW.patronymic)), 'A') ||
+        setweight(to_tsvector('russian', COALESCE(specialty_name, '')), 'B') ||
+        setweight(to_tsvector('russian', COALESCE(details, '')), 'C');
+    NEW.search_text := concat_ws(' ', NEW.last_name, NEW.first_name, NEW.patronymic, specialty_name, details);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER doctor_search_refresh
+AFTER INSERT OR UPDATE OR DELETE ON medical_center.services
+FOR EACH ROW EXECUTE FUNCTION medical_center.refresh_doctor_search();
+
+UPDATE medical_center.doctors SET fts_document = NULL;
@@ -0,0 +1,139 @@
+-- Каталог услуг: услуга больше не привязана к одному врачу. Позиция каталога (код, категория,
+-- базовые цена и длительность) оказывается несколькими врачами, и у каждого врача цена
+-- и длительность могут отличаться от базовых.
+CREATE TABLE IF NOT EXISTS medical_center.service_categories (
+	id serial PRIMARY KEY,
+	name varchar(150) NOT NULL,
+	department_id integer NOT NULL,
+	CONSTRAINT service_categories_department_id_fkey FOREIGN KEY (department_id)
+		REFERENCES medical_center.departments(id)
+		ON UPDATE NO ACTION ON DELETE NO ACTION,
+	CONSTRAINT service_categories_name_key UNIQUE (department_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS medical_center.doctor_services (
+	doctor_id bigint NOT NULL,
+	service_id bigint NOT NULL,
+	price numeric(10,2),
+	duration_minutes smallint,
+	PRIMARY KEY (doctor_id, service_id),
+	CONSTRAINT doctor_services_doctor_id_fkey FOREIGN KEY (doctor_id)
+		REFERENCES medical_center.doctors(id) ON DELETE CASCADE,
+	CONSTRAINT doctor_services_service_id_fkey FOREIGN KEY (service_id)
+		REFERENCES medical_center.services(id) ON DELETE CASCADE,
+	CONSTRAINT doctor_services_price_check CHECK (price >= 0),
+	CONSTRAINT doctor_services_duration_check CHECK (duration_minutes > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_doctor_services_service_id ON medical_center.doctor_services(service_id);
+
+ALTER TABLE medical_center.services RENAME COLUMN price TO base_price;
+ALTER TABLE medical_center.services
+	ADD COLUMN IF NOT EXISTS code varchar(32),
+	ADD COLUMN IF NOT EXISTS category_id integer;
+
+-- Категории существующих услуг - специальности их врачей, в отделении специальности
+INSERT INTO medical_center.service_categories (name, department_id)
+SELECT DISTINCT sp.name, sp.department_id
+FROM medical_center.services s
+JOIN medical_center.doctors d ON d.id = s.doctor_id
+JOIN medical_center.specialties sp ON sp.id = d.specialty_id;
+
+UPDATE medical_center.services s SET category_id = sc.id
+FROM medical_center.doctors d
+JOIN medical_center.specialties sp ON sp.id = d.specialty_id
+JOIN medical_center.service_categories sc ON sc.name = sp.name AND sc.department_id = sp.department_id
+WHERE d.id = s.doctor_id;
+
+-- Одноименные услуги разных врачей сводятся в одну позицию каталога: остается самая ранняя
+-- действующая услуга (или самая ранняя удаленная, если действующих нет)
+CREATE TEMPORARY TABLE service_merge AS
+SELECT id AS old_id, doctor_id, base_price, duration_minutes, deleted_at,
+	first_value(id) OVER (PARTITION BY lower(btrim(name)) ORDER BY deleted_at IS NOT NULL, id) AS catalog_id
+FROM medical_center.services;
+
+-- Врачи действующих услуг становятся исполнителями позиции каталога. Цена и длительность,
+-- совпадающие с базовыми, не переопределяются.
+INSERT INTO medical_center.doctor_services (doctor_id, service_id, price, duration_minutes)
+SELECT DISTINCT ON (m.doctor_id, m.catalog_id) m.doctor_id, m.catalog_id,
+	NULLIF(m.base_price, c.base_price), NULLIF(m.duration_minutes, c.duration_minutes)
+FROM service_merge m
+JOIN medical_center.services c ON c.id = m.catalog_id
+WHERE m.deleted_at IS NULL
+ORDER BY m.doctor_id, m.catalog_id, m.old_id;
+
+UPDATE medical_center.appointments a SET service_id = m.catalog_id
+FROM service_merge m
+WHERE a.service_id = m.old_id AND m.old_id <> m.catalog_id;
+
+DROP TRIGGER IF EXISTS doctor_search_refresh ON medical_center.services;
+
+DELETE FROM medical_center.services s
+USING service_merge m
+WHERE s.id = m.old_id AND m.old_id <> m.catalog_id;
+
+DROP TABLE service_merge;
+
+UPDATE medical_center.services SET code = 'SRV-' || lpad(id::text, 4, '0');
+
+DROP INDEX IF EXISTS medical_center.idx_services_doctor_id;
+ALTER TABLE medical_center.services DROP COLUMN IF EXISTS doctor_id;
+ALTER TABLE medical_center.services
+	ALTER COLUMN code SET NOT NULL,
+	ALTER COLUMN category_id SET NOT NULL,
+	ADD CONSTRAINT services_category_id_fkey FOREIGN KEY (category_id)
+		REFERENCES medical_center.service_categories(id)
+		ON UPDATE NO ACTION ON DELETE NO ACTION,
+	ADD CONSTRAINT services_base_price_check CHECK (base_price >= 0),
+	ADD CONSTRAINT services_duration_check CHECK (duration_minutes > 0);
+
+CREATE INDEX IF NOT EXISTS idx_services_category_id ON medical_center.services(category_id);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_services_code
+	ON medical_center.services(code) WHERE deleted_at IS NULL;
+
+-- Поисковый документ врача берет названия услуг через назначения врача
+CREATE OR REPLACE FUNCTION medical_center.update_doctor_fts_document()
+RETURNS TRIGGER AS $$
+DECLARE
+    specialty_name text;
+    details text;
+BEGIN
+    SELECT name INTO specialty_name FROM medical_center.specialties WHERE id = NEW.specialty_id;
+
+    SELECT concat_ws(' ',
+        (SELECT string_agg(s.name, ' ') FROM medical_center.doctor_services ds
+            JOIN medical_center.services s ON s.id = ds.service_id
+            WHERE ds.doctor_id = NEW.id AND s.deleted_at IS NULL),
+        (SELECT string_agg(skill_name, ' ') FROM medical_center.doctorskills WHERE doctor_id = NEW.id),
+        (SELECT string_agg(area, ' ') FROM medical_center.doctorspecializations WHERE doctor_id = NEW.id)
+    ) INTO details;
+
+    NEW.fts_document :=
+        setweight(to_tsvector('russian', concat_ws(' ', NEW.last_name, NEW.first_name, NEW.patronymic)), 'A') ||
+        setweight(to_tsvector('russian', COALESCE(specialty_name, '')), 'B') ||
+        setweight(to_tsvector('russian', COALESCE(details, '')), 'C');
+    NEW.search_text := concat_ws(' ', NEW.last_name, NEW.first_name, NEW.patronymic, specialty_name, details);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Переименование или удаление услуги пересчитывает документы всех ее врачей
+CREATE OR REPLACE FUNCTION medical_center.refresh_service_doctors_search()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE medical_center.doctors SET fts_document = NULL
+    WHERE id IN (SELECT doctor_id FROM medical_center.doctor_services WHERE service_id = NEW.id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER doctor_search_refresh
+AFTER UPDATE OF name, deleted_at ON medical_center.services
+FOR EACH ROW EXECUTE FUNCTION medical_center.refresh_service_doctors_search();
+
+DROP TRIGGER IF EXISTS doctor_search_refresh ON medical_center.doctor_services;
+CREATE TRIGGER doctor_search_refresh
+AFTER INSERT OR UPDATE OR DELETE ON medical_center.doctor_services
+FOR EACH ROW EXECUTE FUNCTION medical_center.refresh_doctor_search();
+
+UPDATE medical_center.doctors SET fts_document = NULL;
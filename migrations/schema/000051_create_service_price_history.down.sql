@@ -0,0 +1 @@
+DROP TABLE IF EXISTS medical_center.service_price_history;
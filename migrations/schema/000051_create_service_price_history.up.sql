@@ -0,0 +1,33 @@
+-- История цен услуг. Строка без doctor_id - изменение базовой цены каталога, строка с doctor_id -
+-- изменение цены, по которой услугу оказывает врач (переопределенной или базовой). По ней видно,
+-- какая цена действовала на момент записи (appointments.price_at_booking).
+-- old_price пуст, если цены еще не было (новая услуга или новое назначение врача),
+-- new_price пуст, если врач перестал оказывать услугу.
+CREATE TABLE IF NOT EXISTS medical_center.service_price_history (
+	id bigserial PRIMARY KEY,
+	service_id bigint NOT NULL,
+	doctor_id bigint,
+	old_price numeric(10,2),
+	new_price numeric(10,2),
+	changed_by bigint,
+	changed_at timestamp with time zone NOT NULL DEFAULT now(),
+	CONSTRAINT service_price_history_service_id_fkey FOREIGN KEY (service_id)
+		REFERENCES medical_center.services(id) ON DELETE CASCADE,
+	CONSTRAINT service_price_history_doctor_id_fkey FOREIGN KEY (doctor_id)
+		REFERENCES medical_center.doctors(id) ON DELETE CASCADE,
+	CONSTRAINT service_price_history_changed_by_fkey FOREIGN KEY (changed_by)
+		REFERENCES medical_center.admins(id)
+		ON UPDATE NO ACTION ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_service_price_history_service
+	ON medical_center.service_price_history(service_id, changed_at DESC);
+
+-- Начало истории - цены, действующие на момент миграции
+INSERT INTO medical_center.service_price_history (service_id, new_price)
+SELECT id, base_price FROM medical_center.services;
+
+INSERT INTO medical_center.service_price_history (service_id, doctor_id, new_price)
+SELECT ds.service_id, ds.doctor_id, COALESCE(ds.price, s.base_price)
+FROM medical_center.doctor_services ds
+JOIN medical_center.services s ON s.id = ds.service_id;
@@ -0,0 +1,2 @@
+ALTER TABLE medical_center.admin_sessions
+DROP COLUMN IF EXISTS previous_token_hash;
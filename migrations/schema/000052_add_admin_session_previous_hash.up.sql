@@ -0,0 +1,4 @@
+-- Хэш предыдущего refresh-токена сессии: повторное предъявление именно его считается
+-- признаком утечки и завершает сессию. Любой другой неверный секрет сессию не затрагивает.
+ALTER TABLE medical_center.admin_sessions
+ADD COLUMN IF NOT EXISTS previous_token_hash varchar(64) NOT NULL DEFAULT '';
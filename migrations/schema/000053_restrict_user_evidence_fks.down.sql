@@ -0,0 +1,19 @@
+ALTER TABLE medical_center.account_deletion_requests
+    DROP CONSTRAINT IF EXISTS account_deletion_requests_user_id_fkey,
+    ADD CONSTRAINT account_deletion_requests_user_id_fkey FOREIGN KEY (user_id)
+        REFERENCES medical_center.users(id) ON DELETE CASCADE;
+
+ALTER TABLE medical_center.data_export_requests
+    DROP CONSTRAINT IF EXISTS data_export_requests_user_id_fkey,
+    ADD CONSTRAINT data_export_requests_user_id_fkey FOREIGN KEY (user_id)
+        REFERENCES medical_center.users(id) ON DELETE CASCADE;
+
+ALTER TABLE medical_center.impersonation_logs
+    DROP CONSTRAINT IF EXISTS impersonation_logs_user_id_fkey,
+    ADD CONSTRAINT impersonation_logs_user_id_fkey FOREIGN KEY (user_id)
+        REFERENCES medical_center.users(id) ON DELETE CASCADE;
+
+ALTER TABLE medical_center.user_consents
+    DROP CONSTRAINT IF EXISTS user_consents_user_id_fkey,
+    ADD CONSTRAINT user_consents_user_id_fkey FOREIGN KEY (user_id)
+        REFERENCES medical_center.users(id) ON DELETE CASCADE;
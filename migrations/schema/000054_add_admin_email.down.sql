@@ -0,0 +1,2 @@
+ALTER TABLE medical_center.admins
+DROP COLUMN IF EXISTS email;
@@ -0,0 +1,4 @@
+-- Адрес электронной почты администратора для служебных уведомлений
+-- (например, об истекающих сертификатах врачей). Необязателен.
+ALTER TABLE medical_center.admins
+ADD COLUMN IF NOT EXISTS email varchar(255);